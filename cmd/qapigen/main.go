@@ -0,0 +1,48 @@
+// Command qapigen generates Go types and command functions from the QMP
+// schema of a QEMU version (the output of query-qmp-schema).
+//
+// Usage
+//
+//	qapigen -schema schema.json -package generated -version 8.2.0 -out generated.go
+//
+// The schema can be dumped from a running QEMU instance with qapi.Load, or
+// with any QMP client.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/mikerourke/queso/qemu/qapi"
+)
+
+func main() {
+	schemaFile := flag.String("schema", "schema.json", "file with the output of query-qmp-schema")
+	pkg := flag.String("package", "generated", "package name of the generated code")
+	version := flag.String("version", "", "QEMU version the schema was taken from")
+	out := flag.String("out", "generated.go", "file the generated code is written to")
+	flag.Parse()
+
+	data, err := os.ReadFile(*schemaFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	schema, err := qapi.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := qapi.Generate(schema, qapi.GenerateOptions{
+		Package: *pkg,
+		Version: *version,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
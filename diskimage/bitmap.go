@@ -0,0 +1,175 @@
+package diskimage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// BitmapAction represents an action performed on a persistent dirty bitmap
+// of a QCOW2 image with Bitmap.
+type BitmapAction string
+
+const (
+	// BitmapActionAdd adds a new bitmap to the image.
+	BitmapActionAdd BitmapAction = "add"
+
+	// BitmapActionRemove removes the bitmap from the image.
+	BitmapActionRemove BitmapAction = "remove"
+
+	// BitmapActionClear clears all dirty bits of the bitmap.
+	BitmapActionClear BitmapAction = "clear"
+
+	// BitmapActionEnable makes the bitmap record writes to the image.
+	BitmapActionEnable BitmapAction = "enable"
+
+	// BitmapActionDisable stops the bitmap from recording writes to the image.
+	BitmapActionDisable BitmapAction = "disable"
+
+	// BitmapActionMerge merges the dirty bits of the bitmap specified with
+	// MergeSource into the bitmap.
+	BitmapActionMerge BitmapAction = "merge"
+)
+
+// BitmapOptions represent the options for Bitmap.
+type BitmapOptions struct {
+	// File is the path to the QCOW2 image that contains the bitmap.
+	File string
+
+	// Format is the format of the File. If omitted, the format is detected
+	// by qemu-img.
+	Format FileFormat
+
+	// Name is the name of the bitmap.
+	Name string
+
+	// Action is the action to perform on the bitmap.
+	Action BitmapAction
+
+	// Granularity is the granularity of a new bitmap in bytes. If omitted, the
+	// cluster size of the image is used. Only valid for BitmapActionAdd.
+	Granularity int
+
+	// Disabled adds the bitmap in the disabled state. Only valid for
+	// BitmapActionAdd.
+	Disabled bool
+
+	// MergeSource is the name of the bitmap that is merged into the bitmap.
+	// Only valid for BitmapActionMerge.
+	MergeSource string
+
+	// MergeSourceFile is the path to the image that contains the MergeSource
+	// bitmap. If omitted, the bitmap is read from File.
+	MergeSourceFile string
+
+	// MergeSourceFormat is the format of the MergeSourceFile.
+	MergeSourceFormat FileFormat
+
+	// History records the qemu-img command if it is performed successfully.
+	History *History
+}
+
+// Bitmap performs an action on a persistent dirty bitmap of an image using
+// qemu-img. The image must not be in use by a running QEMU instance (use the
+// QMP bitmap commands for running VMs instead).
+//
+// Example
+//
+//	diskimage.Bitmap(diskimage.BitmapOptions{
+//		File:   "disk.qcow2",
+//		Name:   "nightly",
+//		Action: diskimage.BitmapActionAdd,
+//	})
+//
+// Invocation
+//
+//	qemu-img bitmap --add disk.qcow2 nightly
+func Bitmap(opts BitmapOptions) error {
+	args := []string{"bitmap"}
+
+	switch opts.Action {
+	case BitmapActionAdd:
+		args = append(args, "--add")
+
+		if opts.Disabled {
+			args = append(args, "--disable")
+		}
+
+		if opts.Granularity != 0 {
+			args = append(args, "-g", strconv.Itoa(opts.Granularity))
+		}
+
+	case BitmapActionMerge:
+		if opts.MergeSource == "" {
+			return fmt.Errorf("a merge source is required to merge bitmap %s", opts.Name)
+		}
+
+		args = append(args, "--merge", opts.MergeSource)
+
+		if opts.MergeSourceFile != "" {
+			args = append(args, "-b", opts.MergeSourceFile)
+
+			if opts.MergeSourceFormat != "" {
+				args = append(args, "-F", string(opts.MergeSourceFormat))
+			}
+		}
+
+	case BitmapActionRemove, BitmapActionClear, BitmapActionEnable, BitmapActionDisable:
+		args = append(args, "--"+string(opts.Action))
+
+	default:
+		return fmt.Errorf("invalid bitmap action %q", opts.Action)
+	}
+
+	if opts.Format != "" {
+		args = append(args, "-f", string(opts.Format))
+	}
+
+	args = append(args, opts.File, opts.Name)
+
+	return runQEMUImg(opts.History, args...)
+}
+
+// BitmapInfo represents a persistent dirty bitmap stored in an image.
+type BitmapInfo struct {
+	// Name is the name of the bitmap.
+	Name string `json:"name"`
+
+	// Granularity is the granularity of the bitmap in bytes.
+	Granularity int `json:"granularity"`
+
+	// Flags are the flags of the bitmap, such as "auto" (the bitmap is
+	// enabled) or "in-use" (the bitmap is in use by QEMU, or QEMU exited
+	// without storing it, in which case it is inconsistent).
+	Flags []string `json:"flags"`
+}
+
+// Bitmaps returns the persistent dirty bitmaps stored in the specified QCOW2
+// image. The image is opened with shared access, so it can be in use by a
+// running QEMU instance (although the bitmaps are only stored when QEMU exits).
+func Bitmaps(file string) ([]BitmapInfo, error) {
+	output, err := exec.Command("qemu-img", "info", "--output=json", "-U", file).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info for %s: %w", file, err)
+	}
+
+	var info struct {
+		FormatSpecific struct {
+			Data struct {
+				Bitmaps []BitmapInfo `json:"bitmaps"`
+			} `json:"data"`
+		} `json:"format-specific"`
+	}
+
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("invalid info for %s: %w", file, err)
+	}
+
+	bitmaps := info.FormatSpecific.Data.Bitmaps
+	if bitmaps == nil {
+		bitmaps = make([]BitmapInfo, 0)
+	}
+
+	return bitmaps, nil
+}
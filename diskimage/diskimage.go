@@ -7,6 +7,8 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"sync"
+	"time"
 )
 
 // FileFormat represents disk image file formats that can be mounted/edited with
@@ -77,12 +79,38 @@ type CreateOptions struct {
 	File      string
 	Size      string
 	Overwrite bool
+
+	// BackingFile is the path to an existing image that the new image is
+	// layered on top of (i.e. an overlay). The Size can be omitted when a
+	// BackingFile is specified, in which case the size of the backing image
+	// is used.
+	BackingFile string
+
+	// BackingFormat is the format of the BackingFile.
+	BackingFormat FileFormat
+
+	// History records the qemu-img command if it is performed successfully.
+	History *History
 }
 
 // Create creates a new disk image using qemu-img.
 // TODO: Add better error handling.
 func Create(opts CreateOptions) error {
-	args := []string{"create", "-f", string(opts.Format), opts.File, opts.Size}
+	args := []string{"create", "-f", string(opts.Format)}
+
+	if opts.BackingFile != "" {
+		args = append(args, "-b", opts.BackingFile)
+
+		if opts.BackingFormat != "" {
+			args = append(args, "-F", string(opts.BackingFormat))
+		}
+	}
+
+	args = append(args, opts.File)
+
+	if opts.Size != "" {
+		args = append(args, opts.Size)
+	}
 
 	exists := true
 	if _, err := os.Stat(opts.File); os.IsNotExist(err) {
@@ -99,10 +127,51 @@ func Create(opts CreateOptions) error {
 		}
 	}
 
-	return runQEMUImg(args...)
+	return runQEMUImg(opts.History, args...)
 }
 
-func runQEMUImg(args ...string) error {
+// Command represents a qemu-img command that was performed successfully.
+type Command struct {
+	// Tool is the name of the executable that was run (e.g. "qemu-img").
+	Tool string
+
+	// Args are the args that were passed to the Tool.
+	Args []string
+
+	// Time is the time at which the command finished.
+	Time time.Time
+}
+
+// History records the qemu-img commands that were performed successfully, in
+// the order they were performed. Pass the same History to the functions that
+// prepare the disk images of a VM to reproduce them later (see
+// qemu.ScriptOptions). The zero value is an empty History.
+type History struct {
+	mu       sync.Mutex
+	commands []Command
+}
+
+// Commands returns the commands that were recorded.
+func (h *History) Commands() []Command {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	commands := make([]Command, len(h.commands))
+	copy(commands, h.commands)
+
+	return commands
+}
+
+func (h *History) record(command Command) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.commands = append(h.commands, command)
+}
+
+// runQEMUImg runs qemu-img with the specified args and records the command in
+// the History (if any).
+func runQEMUImg(history *History, args ...string) error {
 	output, err := exec.Command("qemu-img", args...).CombinedOutput()
 
 	if err != nil {
@@ -111,5 +180,13 @@ func runQEMUImg(args ...string) error {
 
 	log.Printf("%s", output)
 
+	if history != nil {
+		history.record(Command{
+			Tool: "qemu-img",
+			Args: args,
+			Time: time.Now(),
+		})
+	}
+
 	return nil
 }
@@ -0,0 +1,114 @@
+// Package queue provides an unbounded queue that delivers values to a single
+// receiver, so a producer never blocks on a slow receiver.
+package queue
+
+import "sync"
+
+// Queue queues values without limit and delivers them in order with Run.
+type Queue struct {
+	quit   chan struct{}
+	signal chan struct{}
+
+	mu       sync.Mutex
+	items    []interface{}
+	finished bool
+	canceled bool
+}
+
+// New returns a new, empty Queue.
+func New() *Queue {
+	return &Queue{
+		quit:   make(chan struct{}),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// Push adds a value to the queue. Values pushed after Finish or Cancel are
+// dropped.
+func (q *Queue) Push(value interface{}) {
+	q.mu.Lock()
+	if !q.finished {
+		q.items = append(q.items, value)
+	}
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// Finish stops accepting new values. The values that are already queued are
+// still delivered before Run returns.
+func (q *Queue) Finish() {
+	q.mu.Lock()
+	q.finished = true
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// Cancel stops delivering values, which makes Run return.
+func (q *Queue) Cancel() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.finished = true
+
+	if !q.canceled {
+		q.canceled = true
+		close(q.quit)
+	}
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Run calls deliver with each value until the queue is finished and empty, or
+// canceled. The quit channel passed to deliver is closed when the queue is
+// canceled, and deliver returns false if it gave up on delivering the value
+// because of that, e.g.:
+//
+//	go func() {
+//		defer close(out)
+//
+//		q.Run(func(value interface{}, quit <-chan struct{}) bool {
+//			select {
+//			case out <- value.(Event):
+//				return true
+//
+//			case <-quit:
+//				return false
+//			}
+//		})
+//	}()
+func (q *Queue) Run(deliver func(value interface{}, quit <-chan struct{}) bool) {
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 {
+			finished := q.finished
+			q.mu.Unlock()
+
+			if finished {
+				return
+			}
+
+			select {
+			case <-q.signal:
+				continue
+
+			case <-q.quit:
+				return
+			}
+		}
+
+		value := q.items[0]
+		q.items = q.items[1:]
+		q.mu.Unlock()
+
+		if !deliver(value, q.quit) {
+			return
+		}
+	}
+}
@@ -2,23 +2,167 @@ package queso
 
 import (
 	"fmt"
+	"path"
+	"runtime"
 	"strings"
+	"sync/atomic"
 )
 
+const modulePath = "github.com/mikerourke/queso"
+
 // Option represents an option passed into the command line.
 type Option struct {
 	Flag       string
 	Name       string
 	Properties []*Property
+
+	// Builder is the name of the queso function that created the Option, such
+	// as "device.Use" or "qemu.Memory". It is used to map a flag back to the
+	// code that produced it (e.g. in exported scripts or error messages). It
+	// is only set if TrackBuilders is enabled.
+	Builder string
+}
+
+var trackBuilders int32
+
+// TrackBuilders enables or disables setting the Builder of new options. It is
+// disabled by default, since finding the builder requires walking the call
+// stack every time an option is created.
+func TrackBuilders(enabled bool) {
+	value := int32(0)
+	if enabled {
+		value = 1
+	}
+
+	atomic.StoreInt32(&trackBuilders, value)
 }
 
 // NewOption returns a new instance of Option.
 func NewOption(flag string, name string, properties ...*Property) *Option {
-	return &Option{
+	option := &Option{
 		Flag:       flag,
 		Name:       name,
 		Properties: properties,
 	}
+
+	if atomic.LoadInt32(&trackBuilders) != 0 {
+		option.Builder = callerBuilder()
+	}
+
+	return option
+}
+
+// callerBuilder returns the name of the outermost queso function in the call
+// stack of NewOption. For example, if device.IPMIBMC calls device.Use, which
+// calls NewOption, the builder is "device.IPMIBMC". The search stops at the
+// first method, since options created by a method (such as the options of a
+// runtime directory) are attributed to that method.
+func callerBuilder() string {
+	pcs := make([]uintptr, 16)
+	count := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+
+	builder := "queso.NewOption"
+
+	for {
+		frame, more := frames.Next()
+
+		if !isBuilderFrame(frame) {
+			break
+		}
+
+		name := path.Base(frame.Function)
+		builder = name
+
+		if strings.Contains(name, ").") {
+			break
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return builder
+}
+
+func isBuilderFrame(frame runtime.Frame) bool {
+	if frame.Function != modulePath && !strings.HasPrefix(frame.Function, modulePath+".") &&
+		!strings.HasPrefix(frame.Function, modulePath+"/") {
+		return false
+	}
+
+	if strings.HasPrefix(frame.Function, modulePath+"/cmd/") {
+		return false
+	}
+
+	return !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// ParseArgs parses the args passed to QEMU back into options. Each arg that
+// starts with "-" is a flag, and the following arg (if it doesn't start with
+// "-") is its value. The comma-separated items of the value that contain "="
+// become properties, and the first item without "=" becomes the name. Doubled
+// commas (",,") are kept as-is, so the Args of the parsed options are the same
+// as the parsed args. Values are always strings.
+func ParseArgs(args []string) []*Option {
+	options := make([]*Option, 0)
+
+	for index := 0; index < len(args); index++ {
+		if !strings.HasPrefix(args[index], "-") {
+			continue
+		}
+
+		option := &Option{Flag: strings.TrimPrefix(args[index], "-")}
+
+		if index+1 < len(args) && !strings.HasPrefix(args[index+1], "-") {
+			index++
+
+			for _, item := range splitValue(args[index]) {
+				parts := strings.SplitN(item, "=", 2)
+
+				switch {
+				case len(parts) == 2:
+					option.Properties = append(option.Properties, NewProperty(parts[0], parts[1]))
+
+				case option.Name == "" && len(option.Properties) == 0:
+					option.Name = item
+
+				default:
+					// An item without "=" that isn't the first item is kept
+					// as a property with an empty key, so it is written back.
+					option.Properties = append(option.Properties, &Property{Value: item})
+				}
+			}
+		}
+
+		options = append(options, option)
+	}
+
+	return options
+}
+
+// splitValue splits the value of an arg at single commas.
+func splitValue(value string) []string {
+	items := make([]string, 0)
+	start := 0
+
+	for index := 0; index < len(value); index++ {
+		if value[index] != ',' {
+			continue
+		}
+
+		if index+1 < len(value) && value[index+1] == ',' {
+			index++
+
+			continue
+		}
+
+		items = append(items, value[start:index])
+		start = index + 1
+	}
+
+	return append(items, value[start:])
 }
 
 // Args converts the Option to a string that can be passed into a QEMU tool via
@@ -32,6 +32,11 @@ func (p *Property) Arg() string {
 		}
 	}
 
+	// Properties without a key (see ParseArgs) are written as a plain value.
+	if p.Key == "" {
+		return stringVal
+	}
+
 	return fmt.Sprintf("%s=%s", p.Key, stringVal)
 }
 
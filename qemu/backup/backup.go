@@ -0,0 +1,232 @@
+// Package backup is used to back up the disks of a running QEMU instance with
+// incremental backups based on persistent dirty bitmaps. Each backup is stored
+// as a generation in a Catalog. See
+// https://qemu-project.gitlab.io/qemu/interop/bitmaps.html for more details.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/mikerourke/queso/qemu/blockdev"
+	"github.com/mikerourke/queso/qemu/blockjob"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// DefaultBitmap is the name of the dirty bitmap used by Run if no bitmap name
+// is specified.
+const DefaultBitmap = "queso-backup"
+
+// Options represent the options for Run.
+type Options struct {
+	// Dir is the directory in which the images of the generations are created.
+	Dir string
+
+	// Bitmap is the name of the persistent dirty bitmap that tracks the
+	// changes since the latest generation. The default is DefaultBitmap.
+	Bitmap string
+
+	// Full forces a full backup, even if an incremental backup is possible.
+	Full bool
+
+	// Speed is the maximum speed of the backup job in bytes per second. If
+	// zero, the speed is unlimited.
+	Speed int64
+}
+
+// Run backs up the node with the specified name (see blockdev.WithNodeName) to
+// a new generation in the catalog. The node must be a QCOW2 node, so the dirty
+// bitmap can be stored in its image.
+//
+// A full backup is made if the disk has no generations yet, if the Full option
+// is used, or if the bitmap is missing or inconsistent (e.g. because QEMU
+// crashed). The full backup (re)creates the bitmap at the same point in time.
+// Otherwise, an incremental backup copies only the data marked dirty in the
+// bitmap into an image on top of the latest generation, and clears the bitmap
+// once the backup succeeds.
+//
+// Example
+//
+//	catalog, err := backup.OpenCatalog("/backups/vm.json")
+//	if err != nil {
+//		return err
+//	}
+//
+//	generation, err := backup.Run(ctx, q.QMP(), catalog, "disk", backup.Options{
+//		Dir: "/backups",
+//	})
+func Run(
+	ctx context.Context,
+	client *qmp.Client,
+	catalog *Catalog,
+	node string,
+	opts Options,
+) (Generation, error) {
+	bitmap := opts.Bitmap
+	if bitmap == "" {
+		bitmap = DefaultBitmap
+	}
+
+	size, bitmaps, err := nodeInfo(ctx, client, node)
+	if err != nil {
+		return Generation{}, err
+	}
+
+	latest, hasLatest := catalog.Latest(node)
+
+	bitmapInfo, hasBitmap := findBitmap(bitmaps, bitmap)
+	usable := hasBitmap && bitmapInfo.Persistent && bitmapInfo.Recording && !bitmapInfo.Inconsistent
+
+	generation := Generation{
+		Number:      latest.Number + 1,
+		Incremental: hasLatest && usable && !opts.Full && latest.Bitmap == bitmap,
+		Bitmap:      bitmap,
+		Time:        time.Now(),
+	}
+
+	generation.File = filepath.Join(opts.Dir, fmt.Sprintf("%s.%d.qcow2", node, generation.Number))
+
+	createOpts := diskimage.CreateOptions{
+		Format: diskimage.FileFormatQCOW2,
+		File:   generation.File,
+	}
+
+	backupOpts := blockjob.BackupOptions{
+		Options: blockjob.Options{
+			ID:    fmt.Sprintf("backup-%s-%d", node, generation.Number),
+			Speed: opts.Speed,
+		},
+	}
+
+	if generation.Incremental {
+		createOpts.BackingFile = latest.File
+		createOpts.BackingFormat = diskimage.FileFormatQCOW2
+		backupOpts.Sync = blockjob.SyncModeIncremental
+		backupOpts.Bitmap = bitmap
+	} else {
+		createOpts.Size = strconv.FormatInt(size, 10)
+		backupOpts.Sync = blockjob.SyncModeFull
+
+		// The bitmap must track the writes made after the point in time of the
+		// full backup, so it is reset in the same transaction.
+		if hasBitmap {
+			if !usable {
+				if err := blockjob.RemoveBitmap(ctx, client, node, bitmap); err != nil {
+					return Generation{}, err
+				}
+
+				hasBitmap = false
+			} else {
+				backupOpts.Actions = append(backupOpts.Actions, blockjob.ClearBitmapAction(node, bitmap))
+			}
+		}
+
+		if !hasBitmap {
+			backupOpts.Actions = append(backupOpts.Actions, blockjob.AddBitmapAction(node, bitmap,
+				blockjob.BitmapOptions{Persistent: true}))
+		}
+	}
+
+	if err := diskimage.Create(createOpts); err != nil {
+		return Generation{}, err
+	}
+
+	target := fmt.Sprintf("%s-backup-%d", node, generation.Number)
+
+	if err := addTarget(ctx, client, target, generation.File); err != nil {
+		_ = os.Remove(generation.File)
+
+		return Generation{}, err
+	}
+
+	job, err := blockjob.Backup(ctx, client, node, target, backupOpts)
+	if err == nil {
+		err = job.Wait(ctx)
+
+		if ctx.Err() != nil {
+			cancelJob(job)
+		}
+	}
+
+	// The target is removed with a new context, so it is also removed if the
+	// context was cancelled.
+	removeErr := blockjob.RemoveNode(context.Background(), client, target)
+
+	if err != nil {
+		_ = os.Remove(generation.File)
+
+		return Generation{}, fmt.Errorf("failed to back up %s: %w", node, err)
+	}
+
+	if removeErr != nil {
+		return Generation{}, removeErr
+	}
+
+	if err := catalog.Add(node, generation); err != nil {
+		return Generation{}, err
+	}
+
+	return generation, nil
+}
+
+// cancelJob cancels a job that is still running because the context of Run was
+// cancelled, and waits for it to conclude, so the target isn't in use when it
+// is removed.
+func cancelJob(job *blockjob.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := job.Cancel(ctx); err != nil {
+		return
+	}
+
+	_ = job.Wait(ctx)
+}
+
+// addTarget adds a QCOW2 node for the image of a generation.
+func addTarget(ctx context.Context, client *qmp.Client, name string, file string) error {
+	return blockjob.AddNode(ctx, client, blockdev.QCOW2Driver(
+		blockdev.WithNodeName(name),
+		blockdev.NewDriverProperty("file.driver", "file"),
+		blockdev.NewDriverProperty("file.filename", file)))
+}
+
+// nodeInfo returns the virtual size and the dirty bitmaps of the node.
+func nodeInfo(ctx context.Context, client *qmp.Client, node string) (int64, []blockjob.BitmapInfo, error) {
+	var nodes []struct {
+		NodeName string `json:"node-name"`
+		Image    struct {
+			VirtualSize int64 `json:"virtual-size"`
+		} `json:"image"`
+		DirtyBitmaps []blockjob.BitmapInfo `json:"dirty-bitmaps"`
+	}
+
+	args := map[string]interface{}{"flat": true}
+
+	if err := client.Execute(ctx, "query-named-block-nodes", args, &nodes); err != nil {
+		return 0, nil, err
+	}
+
+	for _, n := range nodes {
+		if n.NodeName == node {
+			return n.Image.VirtualSize, n.DirtyBitmaps, nil
+		}
+	}
+
+	return 0, nil, fmt.Errorf("node %s not found", node)
+}
+
+func findBitmap(bitmaps []blockjob.BitmapInfo, name string) (blockjob.BitmapInfo, bool) {
+	for _, bitmap := range bitmaps {
+		if bitmap.Name == name {
+			return bitmap, true
+		}
+	}
+
+	return blockjob.BitmapInfo{}, false
+}
@@ -0,0 +1,65 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/blockjob"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := qmptest.NewServer()
+
+	emitStatus := func(status blockjob.Status) {
+		_ = server.Emit("JOB_STATUS_CHANGE", map[string]interface{}{"id": "backup-disk-1", "status": status})
+	}
+
+	server.Handle("blockdev-backup", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(blockjob.StatusRunning)
+
+		return nil, nil
+	})
+
+	server.Handle("job-cancel", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(blockjob.StatusAborting)
+		emitStatus(blockjob.StatusConcluded)
+
+		return nil, nil
+	})
+
+	server.Respond("query-jobs", []map[string]interface{}{
+		{"id": "backup-disk-1", "type": "backup", "status": "concluded", "error": "Operation canceled"},
+	})
+
+	server.Handle("job-dismiss", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(blockjob.StatusNull)
+
+		return nil, nil
+	})
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	job, err := blockjob.Backup(ctx, client, "disk", "disk-backup-1", blockjob.BackupOptions{
+		Options: blockjob.Options{ID: "backup-disk-1"},
+		Sync:    blockjob.SyncModeFull,
+	})
+	assert.NoError(t, err)
+
+	cancelJob(job)
+
+	names := make([]string, 0)
+	for _, command := range server.Commands() {
+		names = append(names, command.Name)
+	}
+
+	assert.Equal(t, []string{"blockdev-backup", "job-cancel", "query-jobs", "job-dismiss"}, names)
+}
@@ -0,0 +1,219 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Generation represents a backup of a disk stored in a QCOW2 image. The image
+// of an incremental generation only contains the data that changed since the
+// previous generation, which is its backing file, so the image of the latest
+// generation can be used to restore the complete disk.
+type Generation struct {
+	// Number is the sequence number of the generation, starting at 1 for each
+	// disk.
+	Number int `json:"number"`
+
+	// File is the path to the image of the generation.
+	File string `json:"file"`
+
+	// Incremental indicates whether the generation only contains the data that
+	// changed since the previous generation.
+	Incremental bool `json:"incremental"`
+
+	// Bitmap is the name of the dirty bitmap that tracks the changes since
+	// this generation.
+	Bitmap string `json:"bitmap"`
+
+	// Time is the time at which the backup was started.
+	Time time.Time `json:"time"`
+}
+
+// Catalog keeps track of the backup generations of each disk in a JSON file.
+type Catalog struct {
+	path string
+
+	mu    sync.Mutex
+	disks map[string][]Generation
+}
+
+// OpenCatalog opens the catalog stored in the specified file. If the file
+// doesn't exist, an empty catalog is returned (the file is created when the
+// first generation is added).
+func OpenCatalog(path string) (*Catalog, error) {
+	catalog := &Catalog{
+		path:  path,
+		disks: make(map[string][]Generation),
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return catalog, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup catalog: %w", err)
+	}
+
+	if err := json.Unmarshal(contents, &catalog.disks); err != nil {
+		return nil, fmt.Errorf("invalid backup catalog %s: %w", path, err)
+	}
+
+	return catalog, nil
+}
+
+// Path returns the path to the file of the catalog.
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+// Disks returns the names of the disks (i.e. node names) in the catalog.
+func (c *Catalog) Disks() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	disks := make([]string, 0, len(c.disks))
+	for disk := range c.disks {
+		disks = append(disks, disk)
+	}
+
+	return disks
+}
+
+// Generations returns the generations of the specified disk, from oldest to
+// newest.
+func (c *Catalog) Generations(disk string) []Generation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generations := make([]Generation, len(c.disks[disk]))
+	copy(generations, c.disks[disk])
+
+	return generations
+}
+
+// Latest returns the newest generation of the specified disk. The second
+// return value is false if the disk has no generations.
+func (c *Catalog) Latest(disk string) (Generation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generations := c.disks[disk]
+	if len(generations) == 0 {
+		return Generation{}, false
+	}
+
+	return generations[len(generations)-1], true
+}
+
+// Chain returns the generations that are required to restore the specified
+// generation of the disk, from the full backup to the generation itself.
+func (c *Catalog) Chain(disk string, number int) ([]Generation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chain := make([]Generation, 0)
+
+	for _, generation := range c.disks[disk] {
+		if generation.Number > number {
+			break
+		}
+
+		if !generation.Incremental {
+			chain = chain[:0]
+		}
+
+		chain = append(chain, generation)
+	}
+
+	if len(chain) == 0 || chain[len(chain)-1].Number != number {
+		return nil, fmt.Errorf("generation %d of disk %s not found", number, disk)
+	}
+
+	return chain, nil
+}
+
+// Add adds the generation to the specified disk and saves the catalog.
+func (c *Catalog) Add(disk string, generation Generation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.disks[disk] = append(c.disks[disk], generation)
+
+	return c.save()
+}
+
+// Prune removes the generations of the specified disk that aren't required to
+// restore the newest keep full backups (and their incremental generations),
+// deletes their images, and saves the catalog.
+func (c *Catalog) Prune(disk string, keep int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generations := c.disks[disk]
+
+	fulls := 0
+	cutoff := 0
+
+	for index := len(generations) - 1; index >= 0; index-- {
+		if generations[index].Incremental {
+			continue
+		}
+
+		fulls++
+
+		if fulls == keep {
+			cutoff = index
+
+			break
+		}
+	}
+
+	if fulls < keep {
+		return nil
+	}
+
+	for _, generation := range generations[:cutoff] {
+		if err := os.Remove(generation.File); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	c.disks[disk] = append([]Generation{}, generations[cutoff:]...)
+
+	return c.save()
+}
+
+// save writes the catalog to a temporary file first, so the catalog isn't
+// corrupted if writing fails.
+func (c *Catalog) save() error {
+	contents, err := json.MarshalIndent(c.disks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save backup catalog: %w", err)
+	}
+
+	if _, err := tmp.Write(contents); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to save backup catalog: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to save backup catalog: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
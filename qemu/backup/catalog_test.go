@@ -0,0 +1,57 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.json")
+
+	catalog, err := OpenCatalog(path)
+	assert.NoError(t, err)
+
+	_, ok := catalog.Latest("disk")
+	assert.False(t, ok)
+
+	for number, incremental := range []bool{false, true, true, false, true} {
+		file := filepath.Join(dir, fmt.Sprintf("disk.%d.qcow2", number+1))
+		assert.NoError(t, os.WriteFile(file, nil, 0o644))
+
+		err := catalog.Add("disk", Generation{
+			Number:      number + 1,
+			File:        file,
+			Incremental: incremental,
+			Bitmap:      DefaultBitmap,
+		})
+		assert.NoError(t, err)
+	}
+
+	catalog, err = OpenCatalog(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"disk"}, catalog.Disks())
+
+	latest, ok := catalog.Latest("disk")
+	assert.True(t, ok)
+	assert.Equal(t, 5, latest.Number)
+
+	chain, err := catalog.Chain("disk", 3)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 3)
+	assert.Equal(t, 1, chain[0].Number)
+
+	chain, err = catalog.Chain("disk", 5)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+	assert.Equal(t, 4, chain[0].Number)
+
+	assert.NoError(t, catalog.Prune("disk", 1))
+	assert.Len(t, catalog.Generations("disk"), 2)
+	assert.NoFileExists(t, filepath.Join(dir, "disk.1.qcow2"))
+	assert.FileExists(t, filepath.Join(dir, "disk.4.qcow2"))
+}
@@ -0,0 +1,177 @@
+// Package balloon is used to control the memory balloon of a running QEMU
+// instance over QMP. The balloon device is added with device.VirtioBalloon.
+// Inflating the balloon reclaims memory from the guest, deflating it returns
+// the memory to the guest.
+package balloon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// SetTarget sets the target size of the guest memory in bytes (see balloon).
+// The guest adjusts the balloon asynchronously, so the actual size (see
+// Actual) approaches the target over time.
+func SetTarget(ctx context.Context, client *qmp.Client, bytes int64) error {
+	return client.Execute(ctx, "balloon", map[string]interface{}{"value": bytes}, nil)
+}
+
+// Actual returns the current size of the guest memory in bytes (see
+// query-balloon).
+func Actual(ctx context.Context, client *qmp.Client) (int64, error) {
+	var info struct {
+		Actual int64 `json:"actual"`
+	}
+
+	if err := client.Execute(ctx, "query-balloon", nil, &info); err != nil {
+		return 0, err
+	}
+
+	return info.Actual, nil
+}
+
+// WaitForTarget waits until the actual size of the guest memory is within
+// tolerance bytes of the specified target, based on the BALLOON_CHANGE events
+// reported by QEMU. It returns the actual size.
+func WaitForTarget(ctx context.Context, client *qmp.Client, target int64, tolerance int64) (int64, error) {
+	events, unsubscribe := client.Subscribe("BALLOON_CHANGE")
+	defer unsubscribe()
+
+	actual, err := Actual(ctx, client)
+
+	for {
+		if err != nil {
+			return actual, err
+		}
+
+		if withinTolerance(actual, target, tolerance) {
+			return actual, nil
+		}
+
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return actual, client.Err()
+			}
+
+			var data struct {
+				Actual int64 `json:"actual"`
+			}
+
+			err = event.DecodeData(&data)
+			actual = data.Actual
+
+		case <-ctx.Done():
+			return actual, ctx.Err()
+		}
+	}
+}
+
+func withinTolerance(actual int64, target int64, tolerance int64) bool {
+	difference := actual - target
+	if difference < 0 {
+		difference = -difference
+	}
+
+	return difference <= tolerance
+}
+
+// Stats represent the memory statistics reported by the guest. A value of -1
+// indicates that the guest doesn't report the statistic.
+type Stats struct {
+	// SwapIn is the amount of memory swapped in, in bytes.
+	SwapIn int64 `json:"stat-swap-in"`
+
+	// SwapOut is the amount of memory swapped out, in bytes.
+	SwapOut int64 `json:"stat-swap-out"`
+
+	// MajorFaults is the number of major page faults.
+	MajorFaults int64 `json:"stat-major-faults"`
+
+	// MinorFaults is the number of minor page faults.
+	MinorFaults int64 `json:"stat-minor-faults"`
+
+	// FreeMemory is the amount of memory that isn't used at all, in bytes.
+	FreeMemory int64 `json:"stat-free-memory"`
+
+	// TotalMemory is the total amount of memory available to the guest, in
+	// bytes.
+	TotalMemory int64 `json:"stat-total-memory"`
+
+	// AvailableMemory is an estimate of the memory available for starting
+	// new applications without swapping, in bytes.
+	AvailableMemory int64 `json:"stat-available-memory"`
+
+	// DiskCaches is the amount of memory used for disk caches that can be
+	// reclaimed quickly, in bytes.
+	DiskCaches int64 `json:"stat-disk-caches"`
+
+	// HugeTLBAllocations is the number of successful huge page allocations.
+	HugeTLBAllocations int64 `json:"stat-htlb-pgalloc"`
+
+	// HugeTLBFailures is the number of failed huge page allocations.
+	HugeTLBFailures int64 `json:"stat-htlb-pgfail"`
+
+	// LastUpdate is the time at which the guest last updated the statistics.
+	// It is zero if the guest hasn't reported any statistics yet.
+	LastUpdate time.Time `json:"-"`
+}
+
+// devicePath returns the QOM path of the balloon device with the specified ID.
+func devicePath(id string) string {
+	return "/machine/peripheral/" + id
+}
+
+// SetStatsInterval sets the interval in seconds at which the guest updates the
+// memory statistics of the balloon device with the specified ID. A value of 0
+// disables the statistics.
+func SetStatsInterval(ctx context.Context, client *qmp.Client, id string, seconds int) error {
+	args := map[string]interface{}{
+		"path":     devicePath(id),
+		"property": "guest-stats-polling-interval",
+		"value":    seconds,
+	}
+
+	return client.Execute(ctx, "qom-set", args, nil)
+}
+
+// GuestStats returns the latest memory statistics reported by the guest for
+// the balloon device with the specified ID. The statistics are only reported
+// if an interval was set with SetStatsInterval (or with the
+// device.WithGuestStatsPollingInterval property).
+func GuestStats(ctx context.Context, client *qmp.Client, id string) (Stats, error) {
+	args := map[string]interface{}{
+		"path":     devicePath(id),
+		"property": "guest-stats",
+	}
+
+	var raw json.RawMessage
+
+	if err := client.Execute(ctx, "qom-get", args, &raw); err != nil {
+		return Stats{}, err
+	}
+
+	return decodeStats(raw)
+}
+
+func decodeStats(raw json.RawMessage) (Stats, error) {
+	var value struct {
+		Stats      Stats `json:"stats"`
+		LastUpdate int64 `json:"last-update"`
+	}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return Stats{}, err
+	}
+
+	stats := value.Stats
+
+	if value.LastUpdate != 0 {
+		stats.LastUpdate = time.Unix(value.LastUpdate, 0)
+	}
+
+	return stats, nil
+}
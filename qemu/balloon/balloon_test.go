@@ -0,0 +1,31 @@
+package balloon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeStats(t *testing.T) {
+	stats, err := decodeStats([]byte(`{
+		"stats": {
+			"stat-swap-out": 0,
+			"stat-available-memory": 1816317952,
+			"stat-free-memory": 1549664256,
+			"stat-minor-faults": 114871,
+			"stat-major-faults": 411,
+			"stat-total-memory": 2084499456,
+			"stat-swap-in": 0,
+			"stat-disk-caches": 263077888,
+			"stat-htlb-pgalloc": -1,
+			"stat-htlb-pgfail": -1
+		},
+		"last-update": 1700000000
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1549664256), stats.FreeMemory)
+	assert.Equal(t, int64(2084499456), stats.TotalMemory)
+	assert.Equal(t, int64(-1), stats.HugeTLBAllocations)
+	assert.Equal(t, time.Unix(1700000000, 0), stats.LastUpdate)
+}
@@ -45,7 +45,7 @@ func WithDiskImageFile(file string) *DriveProperty {
 
 // WithDiskImageFormat defines the format of the associated image file.
 func WithDiskImageFormat(format diskimage.FileFormat) *DriveProperty {
-	return NewDriveProperty("file", format)
+	return NewDriveProperty("format", format)
 }
 
 // DriveInterface represents an interface that can be used with a Drive and is
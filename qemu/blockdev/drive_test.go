@@ -0,0 +1,19 @@
+package blockdev
+
+import (
+	"testing"
+
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrive(t *testing.T) {
+	expected := "-drive file=disk.qcow2,format=qcow2"
+
+	result := Drive(
+		WithDiskImageFile("disk.qcow2"),
+		WithDiskImageFormat(diskimage.FileFormatQCOW2),
+	).ArgsString()
+
+	assert.Equal(t, result, expected)
+}
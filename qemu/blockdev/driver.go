@@ -108,6 +108,70 @@ func QCOW2Driver(properties ...*DriverProperty) *queso.Option {
 	return Driver("qcow2", properties...)
 }
 
+// ThrottleDriver is a filter block Driver that throttles the I/O of the node it
+// is stacked on top of (specified with WithFile) using the limits of the
+// throttle group with the specified ID (see object.ThrottleGroup).
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		object.ThrottleGroup("limits0",
+//			object.WithThrottleBandwidth(blockdev.IOOperationAll, 10485760)),
+//		blockdev.QCOW2Driver(
+//			blockdev.WithNodeName("disk_format"),
+//			blockdev.WithDriverProperty("file", blockdev.WithDriverType("file")),
+//			blockdev.WithDriverProperty("file", blockdev.WithImageFile("disk.qcow2"))),
+//		blockdev.ThrottleDriver("limits0",
+//			blockdev.WithNodeName("disk"),
+//			blockdev.WithFile("disk_format")))
+//
+// Invocation
+//
+//	qemu-system-x86_64 \
+//		-object throttle-group,id=limits0,x-bps-total=10485760 \
+//		-blockdev driver=qcow2,node-name=disk_format,file.driver=file,file.filename=disk.qcow2 \
+//		-blockdev driver=throttle,throttle-group=limits0,node-name=disk,file=disk_format
+func ThrottleDriver(group string, properties ...*DriverProperty) *queso.Option {
+	props := []*DriverProperty{NewDriverProperty("throttle-group", group)}
+
+	if properties != nil {
+		props = append(props, properties...)
+	}
+
+	return Driver("throttle", props...)
+}
+
+// BlkReplayDriver is a filter block Driver that records or replays the I/O of
+// the node it is stacked on top of (specified with the image parameter) for
+// deterministic record/replay (see debug.WithReplayMode). Every disk of a
+// recorded VM must be attached to the guest through a BlkReplayDriver.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		blockdev.QCOW2Driver(
+//			blockdev.WithNodeName("disk_format"),
+//			blockdev.WithDriverProperty("file", blockdev.WithDriverType("file")),
+//			blockdev.WithDriverProperty("file", blockdev.WithImageFile("disk.qcow2"))),
+//		blockdev.BlkReplayDriver("disk_format", blockdev.WithNodeName("disk")),
+//		device.Use("virtio-blk-pci", device.NewProperty("drive", "disk")))
+//
+// Invocation
+//
+//	qemu-system-x86_64 \
+//		-blockdev driver=qcow2,node-name=disk_format,file.driver=file,file.filename=disk.qcow2 \
+//		-blockdev driver=blkreplay,image=disk_format,node-name=disk \
+//		-device virtio-blk-pci,drive=disk
+func BlkReplayDriver(image string, properties ...*DriverProperty) *queso.Option {
+	props := []*DriverProperty{NewDriverProperty("image", image)}
+
+	if properties != nil {
+		props = append(props, properties...)
+	}
+
+	return Driver("blkreplay", props...)
+}
+
 // DriverProperty represents a property that can be passed to a Driver option.
 type DriverProperty struct {
 	*queso.Property
@@ -156,6 +220,13 @@ func WithNodeName(name string) *DriverProperty {
 	return NewDriverProperty("node-name", name)
 }
 
+// NodeName returns the name of the block Driver node defined by the specified
+// option (set with WithNodeName), or an empty string if the option has no node
+// name. It is used to reference the node at runtime (e.g. in a block job).
+func NodeName(option *queso.Option) string {
+	return option.Table()["node-name"]
+}
+
 // IsReadOnly opens the node read-only if enabled is true. Guest write attempts
 // will fail.
 //
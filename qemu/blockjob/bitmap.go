@@ -0,0 +1,165 @@
+package blockjob
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// Action represents an action performed in a QMP transaction, in which all
+// actions either succeed or fail together (see BackupOptions.Actions).
+type Action struct {
+	// Type is the type of the action, which is usually the name of the
+	// corresponding QMP command.
+	Type string `json:"type"`
+
+	// Data are the arguments of the action.
+	Data map[string]interface{} `json:"data"`
+}
+
+// BitmapOptions represent the options for AddBitmap.
+type BitmapOptions struct {
+	// Persistent stores the bitmap in the QCOW2 image of the node when QEMU
+	// exits, so it survives a restart of the VM.
+	Persistent bool
+
+	// Disabled adds the bitmap in the disabled state, so it doesn't record
+	// writes until it is enabled with EnableBitmap.
+	Disabled bool
+
+	// Granularity is the granularity of the bitmap in bytes. It must be a
+	// power of 2. If omitted, the cluster size of the image is used.
+	Granularity int
+}
+
+// AddBitmapAction returns an Action that adds a dirty bitmap with the specified
+// name to the node with the specified name.
+func AddBitmapAction(node string, name string, opts BitmapOptions) Action {
+	data := bitmapArgs(node, name)
+
+	if opts.Persistent {
+		data["persistent"] = true
+	}
+
+	if opts.Disabled {
+		data["disabled"] = true
+	}
+
+	if opts.Granularity != 0 {
+		data["granularity"] = opts.Granularity
+	}
+
+	return Action{Type: "block-dirty-bitmap-add", Data: data}
+}
+
+// ClearBitmapAction returns an Action that clears all dirty bits of the bitmap
+// with the specified name on the node with the specified name.
+func ClearBitmapAction(node string, name string) Action {
+	return Action{Type: "block-dirty-bitmap-clear", Data: bitmapArgs(node, name)}
+}
+
+// AddBitmap adds a dirty bitmap with the specified name to the node with the
+// specified name (see block-dirty-bitmap-add). The bitmap records the areas of
+// the node that are written to, which are copied by a backup that uses
+// SyncModeIncremental.
+func AddBitmap(ctx context.Context, client *qmp.Client, node string, name string, opts BitmapOptions) error {
+	action := AddBitmapAction(node, name, opts)
+
+	return client.Execute(ctx, action.Type, action.Data, nil)
+}
+
+// RemoveBitmap removes the bitmap with the specified name from the node with
+// the specified name. A persistent bitmap is also removed from the image.
+func RemoveBitmap(ctx context.Context, client *qmp.Client, node string, name string) error {
+	return client.Execute(ctx, "block-dirty-bitmap-remove", bitmapArgs(node, name), nil)
+}
+
+// ClearBitmap clears all dirty bits of the bitmap with the specified name on
+// the node with the specified name.
+func ClearBitmap(ctx context.Context, client *qmp.Client, node string, name string) error {
+	action := ClearBitmapAction(node, name)
+
+	return client.Execute(ctx, action.Type, action.Data, nil)
+}
+
+// EnableBitmap makes the bitmap with the specified name record writes to the
+// node with the specified name.
+func EnableBitmap(ctx context.Context, client *qmp.Client, node string, name string) error {
+	return client.Execute(ctx, "block-dirty-bitmap-enable", bitmapArgs(node, name), nil)
+}
+
+// DisableBitmap stops the bitmap with the specified name from recording writes
+// to the node with the specified name.
+func DisableBitmap(ctx context.Context, client *qmp.Client, node string, name string) error {
+	return client.Execute(ctx, "block-dirty-bitmap-disable", bitmapArgs(node, name), nil)
+}
+
+// MergeBitmaps merges the dirty bits of the source bitmaps into the target
+// bitmap. All bitmaps must belong to the node with the specified name.
+func MergeBitmaps(ctx context.Context, client *qmp.Client, node string, target string, sources ...string) error {
+	args := bitmapArgs(node, target)
+	args["bitmaps"] = sources
+
+	return client.Execute(ctx, "block-dirty-bitmap-merge", args, nil)
+}
+
+// BitmapInfo represents the details QEMU reports for a dirty bitmap.
+type BitmapInfo struct {
+	// Name is the name of the bitmap.
+	Name string `json:"name"`
+
+	// Count is the number of dirty bytes.
+	Count int64 `json:"count"`
+
+	// Granularity is the granularity of the bitmap in bytes.
+	Granularity int `json:"granularity"`
+
+	// Recording indicates whether the bitmap records writes.
+	Recording bool `json:"recording"`
+
+	// Busy indicates whether the bitmap is in use by a job.
+	Busy bool `json:"busy"`
+
+	// Persistent indicates whether the bitmap is stored in the image.
+	Persistent bool `json:"persistent"`
+
+	// Inconsistent indicates that the bitmap was not saved correctly (e.g.
+	// because QEMU crashed), so it can't be used for incremental backups.
+	Inconsistent bool `json:"inconsistent"`
+}
+
+// Bitmaps returns the dirty bitmaps of the node with the specified name.
+func Bitmaps(ctx context.Context, client *qmp.Client, node string) ([]BitmapInfo, error) {
+	var nodes []struct {
+		NodeName     string       `json:"node-name"`
+		DirtyBitmaps []BitmapInfo `json:"dirty-bitmaps"`
+	}
+
+	args := map[string]interface{}{"flat": true}
+
+	if err := client.Execute(ctx, "query-named-block-nodes", args, &nodes); err != nil {
+		return nil, err
+	}
+
+	for _, n := range nodes {
+		if n.NodeName != node {
+			continue
+		}
+
+		if n.DirtyBitmaps == nil {
+			return make([]BitmapInfo, 0), nil
+		}
+
+		return n.DirtyBitmaps, nil
+	}
+
+	return nil, fmt.Errorf("node %s not found", node)
+}
+
+func bitmapArgs(node string, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"node": node,
+		"name": name,
+	}
+}
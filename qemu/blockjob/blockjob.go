@@ -0,0 +1,557 @@
+// Package blockjob is used to run block jobs (such as backups and mirrors) on
+// the block nodes of a running QEMU instance over QMP. The nodes are referenced
+// by the names given to them with blockdev.WithNodeName. See
+// https://qemu-project.gitlab.io/qemu/interop/live-block-operations.html for
+// more details.
+package blockjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// Type represents the type of block job.
+type Type string
+
+const (
+	// TypeBackup is a point-in-time copy of a node started with Backup.
+	TypeBackup Type = "backup"
+
+	// TypeMirror is a continuous copy of a node started with Mirror.
+	TypeMirror Type = "mirror"
+
+	// TypeStream copies data from the backing files of a node into the node,
+	// and is started with Stream.
+	TypeStream Type = "stream"
+
+	// TypeCommit copies the data of a node into one of its backing files, and
+	// is started with Commit.
+	TypeCommit Type = "commit"
+)
+
+// Status represents the status of a block job, as reported by the
+// JOB_STATUS_CHANGE event.
+type Status string
+
+const (
+	// StatusUndefined is the status of a job that hasn't reported its status
+	// yet.
+	StatusUndefined Status = "undefined"
+
+	// StatusCreated indicates the job was created, but not started yet.
+	StatusCreated Status = "created"
+
+	// StatusRunning indicates the job is running.
+	StatusRunning Status = "running"
+
+	// StatusPaused indicates the job was paused with Pause.
+	StatusPaused Status = "paused"
+
+	// StatusReady indicates a mirror or active commit job has copied all data
+	// and is waiting for Complete. The job keeps copying new writes until then.
+	StatusReady Status = "ready"
+
+	// StatusStandby indicates a ready job was paused.
+	StatusStandby Status = "standby"
+
+	// StatusWaiting indicates the job finished its work and is waiting for
+	// the other jobs in the same transaction.
+	StatusWaiting Status = "waiting"
+
+	// StatusPending indicates the job is waiting for Finalize. Only jobs
+	// started with ManualFinalize enter this status.
+	StatusPending Status = "pending"
+
+	// StatusAborting indicates the job failed or was cancelled.
+	StatusAborting Status = "aborting"
+
+	// StatusConcluded indicates the job has finished and is waiting for
+	// Dismiss.
+	StatusConcluded Status = "concluded"
+
+	// StatusNull indicates the job was dismissed and no longer exists.
+	StatusNull Status = "null"
+)
+
+// Options represent the options that are common to all block jobs.
+type Options struct {
+	// ID is the ID of the job. If omitted, the node name of the device is used.
+	ID string
+
+	// Speed is the maximum speed of the job in bytes per second. If zero, the
+	// speed is unlimited. It can be changed with Job.SetSpeed.
+	Speed int64
+
+	// ManualFinalize stops the job in StatusPending once its work is done, so
+	// it has to be finalized with Job.Finalize. This is used to finalize a
+	// group of jobs at the same time.
+	ManualFinalize bool
+}
+
+// args returns the QMP arguments for the options of a job on the specified
+// device. Jobs are never dismissed automatically, so Job.Wait can read the
+// error of a failed job before it is dismissed.
+func (o Options) args(device string) map[string]interface{} {
+	args := map[string]interface{}{
+		"job-id":        o.jobID(device),
+		"device":        device,
+		"auto-finalize": !o.ManualFinalize,
+		"auto-dismiss":  false,
+	}
+
+	if o.Speed != 0 {
+		args["speed"] = o.Speed
+	}
+
+	return args
+}
+
+// jobID returns the ID of a job on the specified device.
+func (o Options) jobID(device string) string {
+	if o.ID == "" {
+		return device
+	}
+
+	return o.ID
+}
+
+// SyncMode represents what data is copied by a backup or mirror job.
+type SyncMode string
+
+const (
+	// SyncModeFull copies all data of the device (including its backing files).
+	SyncModeFull SyncMode = "full"
+
+	// SyncModeTop copies only the data of the top node of the device, so the
+	// target must use the same backing file.
+	SyncModeTop SyncMode = "top"
+
+	// SyncModeNone only copies the data that the guest overwrites while the
+	// job is running (i.e. a point-in-time snapshot for backups).
+	SyncModeNone SyncMode = "none"
+
+	// SyncModeBitmap copies the data that is marked dirty in a bitmap.
+	SyncModeBitmap SyncMode = "bitmap"
+
+	// SyncModeIncremental copies the data that is marked dirty in a bitmap,
+	// and clears the bitmap if the job succeeds.
+	SyncModeIncremental SyncMode = "incremental"
+)
+
+// BackupOptions represent the options for Backup.
+type BackupOptions struct {
+	Options
+
+	// Sync is the data that is copied to the target. The default is
+	// SyncModeFull.
+	Sync SyncMode
+
+	// Bitmap is the name of the dirty bitmap used with SyncModeBitmap or
+	// SyncModeIncremental.
+	Bitmap string
+
+	// Compress compresses the data written to the target (which must support
+	// compressed writes, such as qcow2).
+	Compress bool
+
+	// Actions are performed atomically with starting the backup (i.e. in a
+	// transaction). For example, AddBitmapAction creates a bitmap that tracks
+	// exactly the writes made after the point in time of the backup.
+	Actions []Action
+}
+
+// Backup starts a job that copies the data of the node named device to the
+// node named target as it was when the job started (see blockdev-backup). The
+// target node must already exist (see AddNode).
+//
+// Example
+//
+//	job, err := blockjob.Backup(ctx, q.QMP(), "disk", "backup", blockjob.BackupOptions{
+//		Sync: blockjob.SyncModeFull,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	err = job.Wait(ctx)
+func Backup(ctx context.Context, client *qmp.Client, device string, target string, opts BackupOptions) (*Job, error) {
+	args := opts.args(device)
+	args["target"] = target
+	args["sync"] = syncMode(opts.Sync)
+
+	if opts.Bitmap != "" {
+		args["bitmap"] = opts.Bitmap
+	}
+
+	if opts.Compress {
+		args["compress"] = true
+	}
+
+	if len(opts.Actions) != 0 {
+		actions := make([]Action, 0, len(opts.Actions)+1)
+		actions = append(actions, opts.Actions...)
+		actions = append(actions, Action{Type: "blockdev-backup", Data: args})
+
+		return start(ctx, client, TypeBackup, opts.jobID(device), "transaction",
+			map[string]interface{}{"actions": actions})
+	}
+
+	return start(ctx, client, TypeBackup, opts.jobID(device), "blockdev-backup", args)
+}
+
+// MirrorOptions represent the options for Mirror.
+type MirrorOptions struct {
+	Options
+
+	// Sync is the data that is copied to the target. The default is
+	// SyncModeFull.
+	Sync SyncMode
+
+	// WriteBlocking makes guest writes wait until they are also written to the
+	// target once the job is ready, so the target never falls behind.
+	WriteBlocking bool
+}
+
+// Mirror starts a job that copies the data of the node named device to the
+// node named target, and keeps copying new writes (see blockdev-mirror). Once
+// all data is copied, the job enters StatusReady. Job.Complete then switches
+// the device to the target, while Job.Cancel leaves the target as a consistent
+// copy of the device.
+func Mirror(ctx context.Context, client *qmp.Client, device string, target string, opts MirrorOptions) (*Job, error) {
+	args := opts.args(device)
+	args["target"] = target
+	args["sync"] = syncMode(opts.Sync)
+
+	if opts.WriteBlocking {
+		args["copy-mode"] = "write-blocking"
+	}
+
+	return start(ctx, client, TypeMirror, opts.jobID(device), "blockdev-mirror", args)
+}
+
+// StreamOptions represent the options for Stream.
+type StreamOptions struct {
+	Options
+
+	// Base is the name of the node down to which the backing chain is copied
+	// (exclusive). If omitted, the entire backing chain is copied, so the node
+	// no longer needs any backing file.
+	Base string
+}
+
+// Stream starts a job that copies the data of the backing files of the node
+// named device into the node, and then removes those backing files from the
+// chain (see block-stream).
+func Stream(ctx context.Context, client *qmp.Client, device string, opts StreamOptions) (*Job, error) {
+	args := opts.args(device)
+
+	if opts.Base != "" {
+		args["base-node"] = opts.Base
+	}
+
+	return start(ctx, client, TypeStream, opts.jobID(device), "block-stream", args)
+}
+
+// CommitOptions represent the options for Commit.
+type CommitOptions struct {
+	Options
+
+	// Top is the name of the node whose data is committed. If omitted, the
+	// active (top) node of the device is committed, in which case the job
+	// enters StatusReady and must be completed with Job.Complete.
+	Top string
+
+	// Base is the name of the node the data is committed to. If omitted, the
+	// deepest backing file is used.
+	Base string
+}
+
+// Commit starts a job that copies the data of a node in the backing chain of
+// the node named device into one of its backing files, and then removes the
+// intermediate nodes from the chain (see block-commit).
+func Commit(ctx context.Context, client *qmp.Client, device string, opts CommitOptions) (*Job, error) {
+	args := opts.args(device)
+
+	if opts.Top != "" {
+		args["top-node"] = opts.Top
+	}
+
+	if opts.Base != "" {
+		args["base-node"] = opts.Base
+	}
+
+	return start(ctx, client, TypeCommit, opts.jobID(device), "block-commit", args)
+}
+
+func syncMode(mode SyncMode) SyncMode {
+	if mode == "" {
+		return SyncModeFull
+	}
+
+	return mode
+}
+
+// Job represents a block job running in QEMU.
+type Job struct {
+	// ID is the ID of the job.
+	ID string
+
+	// Type is the type of the job.
+	Type Type
+
+	client      *qmp.Client
+	unsubscribe func()
+	mu          sync.Mutex
+	status      Status
+	changed     chan struct{}
+}
+
+// start subscribes to the status changes of the job with the specified ID and
+// starts the job with the specified command. The subscription is created first,
+// because the job may report its first status changes before the command
+// returns.
+func start(
+	ctx context.Context,
+	client *qmp.Client,
+	jobType Type,
+	id string,
+	command string,
+	args map[string]interface{},
+) (*Job, error) {
+	events, unsubscribe := client.Subscribe("JOB_STATUS_CHANGE")
+
+	job := &Job{
+		ID:          id,
+		Type:        jobType,
+		client:      client,
+		unsubscribe: unsubscribe,
+		status:      StatusUndefined,
+		changed:     make(chan struct{}),
+	}
+
+	go job.watch(events)
+
+	if err := client.Execute(ctx, command, args, nil); err != nil {
+		unsubscribe()
+
+		return nil, fmt.Errorf("failed to start %s job %s: %w", jobType, job.ID, err)
+	}
+
+	return job, nil
+}
+
+// watch updates the status of the job whenever QEMU reports a status change.
+func (j *Job) watch(events <-chan qmp.Event) {
+	for event := range events {
+		var data struct {
+			ID     string `json:"id"`
+			Status Status `json:"status"`
+		}
+
+		if err := event.DecodeData(&data); err != nil || data.ID != j.ID {
+			continue
+		}
+
+		j.setStatus(data.Status)
+
+		if data.Status == StatusNull {
+			j.unsubscribe()
+		}
+	}
+}
+
+func (j *Job) setStatus(status Status) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status = status
+
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// Status returns the last status QEMU reported for the job.
+func (j *Job) Status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.status
+}
+
+// WaitForStatus waits until the job enters one of the specified statuses and
+// returns the status. An error is returned if the job concludes (or is
+// dismissed) without entering one of the statuses.
+func (j *Job) WaitForStatus(ctx context.Context, statuses ...Status) (Status, error) {
+	for {
+		j.mu.Lock()
+		status := j.status
+		changed := j.changed
+		j.mu.Unlock()
+
+		waitingForNull := false
+
+		for _, s := range statuses {
+			if status == s {
+				return status, nil
+			}
+
+			waitingForNull = waitingForNull || s == StatusNull
+		}
+
+		// A concluded job can only change to StatusNull once it is dismissed.
+		if status == StatusNull || (status == StatusConcluded && !waitingForNull) {
+			return status, fmt.Errorf("job %s concluded", j.ID)
+		}
+
+		select {
+		case <-changed:
+
+		case <-j.client.Done():
+			return status, j.client.Err()
+
+		case <-ctx.Done():
+			return status, ctx.Err()
+		}
+	}
+}
+
+// Info represents the details QEMU reports for a job (see query-jobs).
+type Info struct {
+	// ID is the ID of the job.
+	ID string `json:"id"`
+
+	// Type is the type of the job.
+	Type Type `json:"type"`
+
+	// Status is the current status of the job.
+	Status Status `json:"status"`
+
+	// CurrentProgress is the amount of work that is done. The unit is
+	// arbitrary, and is only meaningful relative to TotalProgress.
+	CurrentProgress int64 `json:"current-progress"`
+
+	// TotalProgress is the estimated total amount of work. The estimate may
+	// change while the job is running.
+	TotalProgress int64 `json:"total-progress"`
+
+	// Error is the error message of a failed job.
+	Error string `json:"error,omitempty"`
+}
+
+// Percent returns the progress of the job as a percentage.
+func (i Info) Percent() float64 {
+	if i.TotalProgress == 0 {
+		return 0
+	}
+
+	return float64(i.CurrentProgress) / float64(i.TotalProgress) * 100
+}
+
+// Jobs returns the details of all jobs in QEMU.
+func Jobs(ctx context.Context, client *qmp.Client) ([]Info, error) {
+	infos := make([]Info, 0)
+
+	if err := client.Execute(ctx, "query-jobs", nil, &infos); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Info returns the current details (including the progress) of the job.
+func (j *Job) Info(ctx context.Context) (Info, error) {
+	infos, err := Jobs(ctx, j.client)
+	if err != nil {
+		return Info{}, err
+	}
+
+	for _, info := range infos {
+		if info.ID == j.ID {
+			return info, nil
+		}
+	}
+
+	return Info{}, fmt.Errorf("job %s not found", j.ID)
+}
+
+// Pause pauses the job. The job enters StatusPaused (or StatusStandby if it
+// is ready) once it reaches a point where it can be paused.
+func (j *Job) Pause(ctx context.Context) error {
+	return j.execute(ctx, "job-pause")
+}
+
+// Resume resumes a job paused with Pause.
+func (j *Job) Resume(ctx context.Context) error {
+	return j.execute(ctx, "job-resume")
+}
+
+// Cancel cancels the job. A mirror job that is ready is completed without
+// switching the device to the target, so the target is a consistent copy.
+func (j *Job) Cancel(ctx context.Context) error {
+	return j.execute(ctx, "job-cancel")
+}
+
+// Complete completes a job in StatusReady (i.e. a mirror or active commit),
+// which switches the device to the target (or base) node.
+func (j *Job) Complete(ctx context.Context) error {
+	return j.execute(ctx, "job-complete")
+}
+
+// Finalize finalizes a job in StatusPending, which was started with
+// ManualFinalize.
+func (j *Job) Finalize(ctx context.Context) error {
+	return j.execute(ctx, "job-finalize")
+}
+
+// Dismiss removes a concluded job from QEMU.
+func (j *Job) Dismiss(ctx context.Context) error {
+	return j.execute(ctx, "job-dismiss")
+}
+
+// SetSpeed changes the maximum speed of the job in bytes per second. A speed
+// of zero removes the limit.
+func (j *Job) SetSpeed(ctx context.Context, speed int64) error {
+	args := map[string]interface{}{
+		"device": j.ID,
+		"speed":  speed,
+	}
+
+	return j.client.Execute(ctx, "block-job-set-speed", args, nil)
+}
+
+// Wait waits for the job to conclude, dismisses it, and returns the error the
+// job failed with (if any). Mirror and active commit jobs must be completed
+// with Complete (or cancelled) first, and jobs started with ManualFinalize must
+// be finalized.
+func (j *Job) Wait(ctx context.Context) error {
+	if _, err := j.WaitForStatus(ctx, StatusConcluded, StatusNull); err != nil {
+		return err
+	}
+
+	if j.Status() == StatusNull {
+		return nil
+	}
+
+	info, err := j.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := j.Dismiss(ctx); err != nil {
+		return err
+	}
+
+	if info.Error != "" {
+		return errors.New(info.Error)
+	}
+
+	return nil
+}
+
+func (j *Job) execute(ctx context.Context, command string) error {
+	return j.client.Execute(ctx, command, map[string]interface{}{"id": j.ID}, nil)
+}
@@ -0,0 +1,98 @@
+package blockjob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/blockdev"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// testServer returns a server that emulates the status changes of a mirror job
+// with the ID "disk".
+func testServer() *qmptest.Server {
+	server := qmptest.NewServer()
+
+	emitStatus := func(statuses ...Status) {
+		for _, status := range statuses {
+			_ = server.Emit("JOB_STATUS_CHANGE", map[string]interface{}{"id": "disk", "status": status})
+		}
+	}
+
+	server.Handle("blockdev-mirror", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(StatusCreated, StatusRunning, StatusReady)
+
+		return nil, nil
+	})
+
+	server.Handle("job-complete", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(StatusWaiting, StatusPending, StatusConcluded)
+
+		return nil, nil
+	})
+
+	server.Respond("query-jobs", []map[string]interface{}{
+		{"id": "disk", "type": "mirror", "status": "concluded", "current-progress": 1024, "total-progress": 1024},
+	})
+
+	server.Handle("job-dismiss", func(map[string]interface{}) (interface{}, error) {
+		emitStatus(StatusNull)
+
+		return nil, nil
+	})
+
+	return server
+}
+
+func TestMirror(t *testing.T) {
+	server := testServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	source := blockdev.QCOW2Driver(blockdev.WithNodeName("disk"))
+
+	job, err := Mirror(ctx, client, blockdev.NodeName(source), "mirror", MirrorOptions{})
+	assert.NoError(t, err)
+
+	command, err := server.WaitForCommand(ctx, "blockdev-mirror")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"job-id":        "disk",
+		"device":        "disk",
+		"target":        "mirror",
+		"sync":          "full",
+		"auto-finalize": true,
+		"auto-dismiss":  false,
+	}, command.Arguments)
+
+	status, err := job.WaitForStatus(ctx, StatusReady)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReady, status)
+
+	assert.NoError(t, job.Complete(ctx))
+	assert.NoError(t, job.Wait(ctx))
+
+	_, err = job.WaitForStatus(ctx, StatusNull)
+	assert.NoError(t, err)
+}
+
+func TestNodeArgs(t *testing.T) {
+	option := blockdev.FileDriver("backup.qcow2",
+		blockdev.WithNodeName("backup-file"),
+		blockdev.IsCacheDirect(true))
+
+	assert.Equal(t, map[string]interface{}{
+		"driver":    "file",
+		"filename":  "backup.qcow2",
+		"node-name": "backup-file",
+		"cache":     map[string]interface{}{"direct": true},
+	}, nodeArgs(option))
+}
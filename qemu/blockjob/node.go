@@ -0,0 +1,66 @@
+package blockjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/blockdev"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// AddNode adds the block Driver node defined by the specified option (created
+// with blockdev.Driver or one of its variants) to the running QEMU instance
+// (see blockdev-add). This is typically used to add the target of a backup or
+// mirror job. The option must have a node name (see blockdev.WithNodeName).
+//
+// Example
+//
+//	err := blockjob.AddNode(ctx, q.QMP(),
+//		blockdev.FileDriver("backup.qcow2",
+//			blockdev.WithNodeName("backup-file")))
+func AddNode(ctx context.Context, client *qmp.Client, option *queso.Option) error {
+	if option.Flag != "blockdev" {
+		return fmt.Errorf("option -%s is not a block driver node", option.Flag)
+	}
+
+	if blockdev.NodeName(option) == "" {
+		return errors.New("a node name is required to add a block driver node")
+	}
+
+	return client.Execute(ctx, "blockdev-add", nodeArgs(option), nil)
+}
+
+// RemoveNode removes the block Driver node with the specified name from the
+// running QEMU instance (see blockdev-del).
+func RemoveNode(ctx context.Context, client *qmp.Client, nodeName string) error {
+	return client.Execute(ctx, "blockdev-del", map[string]interface{}{"node-name": nodeName}, nil)
+}
+
+// nodeArgs converts the properties of a block driver option to the arguments
+// of blockdev-add. Dotted keys (such as "cache.direct") are converted to nested
+// objects.
+func nodeArgs(option *queso.Option) map[string]interface{} {
+	args := make(map[string]interface{})
+
+	for _, property := range option.Properties {
+		keys := strings.Split(property.Key, ".")
+		parent := args
+
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[key] = child
+			}
+
+			parent = child
+		}
+
+		parent[keys[len(keys)-1]] = property.Value
+	}
+
+	return args
+}
@@ -0,0 +1,530 @@
+package qemu
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// CloneOptions represent the options for Clone.
+type CloneOptions struct {
+	// Count is the number of clones to start.
+	Count int
+
+	// Dir is the directory in which the saved VM state is stored, along with
+	// the overlays the source VM writes to after it is cloned. Each call to
+	// Clone stores its files in a new subdirectory of Dir, which must be kept
+	// for as long as the source VM and the clones are running.
+	Dir string
+
+	// Name returns the guest name of the clone with the specified index. If
+	// omitted, the name of the source VM (or "clone") is used with the index
+	// appended (e.g. "test-vm-0").
+	Name func(index int) string
+
+	// Compression is the compression used for the saved VM state.
+	Compression StateCompression
+
+	// Paused leaves the clones paused once they are started.
+	Paused bool
+}
+
+// Clone represents a VM started by Clone.
+type Clone struct {
+	// QEMU is the QEMU instance running the clone.
+	QEMU *QEMU
+
+	// Name is the guest name of the clone.
+	Name string
+
+	// UUID is the system UUID of the clone.
+	UUID string
+
+	// MACAddresses are the MAC addresses assigned to the NICs of the clone,
+	// in the order of the options.
+	MACAddresses []string
+
+	// ForwardedPorts maps the host ports of the forward rules of the source VM
+	// to the corresponding host ports of the clone.
+	ForwardedPorts map[int]int
+}
+
+// cloneDisk represents a writable disk of the VM that is cloned.
+type cloneDisk struct {
+	device   string
+	nodeName string
+	file     string
+	format   diskimage.FileFormat
+
+	// image is the disk image as it is specified in the options of the VM,
+	// which differs from file once the VM has been cloned.
+	image string
+}
+
+// Clone snapshots the memory and disks of the running VM and starts the
+// specified number of copies of it. Each clone gets a RuntimeDirectory
+// containing a QCOW2 overlay for each writable disk, a new name and UUID,
+// fresh MAC addresses for its NICs, and unique host ports for the forward
+// rules of user mode network backends (see network.WithForwardRule).
+//
+// The VM is paused while its state is saved. Its disk images then become the
+// read-only backing files of the clones: the source VM switches to overlays in
+// the Dir specified in opts and resumes. Options that bind host resources
+// other than forwarded ports (such as chardev sockets) are copied as-is, so
+// they must not be used by the source VM. The guest OS has to renew its
+// network configuration if it depends on the MAC address it saw when the VM
+// was cloned.
+//
+// If a clone fails to start, the clones that were already started are killed.
+func (q *QEMU) Clone(ctx context.Context, opts CloneOptions) ([]*Clone, error) {
+	if opts.Count < 1 {
+		return nil, errors.New("the number of clones must be at least 1")
+	}
+
+	if opts.Dir == "" {
+		return nil, errors.New("a directory for the saved VM state is required")
+	}
+
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	disks, err := q.cloneDisks(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	name := q.GuestName()
+	if name == "" {
+		name = "clone"
+	}
+
+	// The source VM switches to new overlays every time it is cloned, so
+	// each generation gets its own directory.
+	dir, err := os.MkdirTemp(opts.Dir, name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clone directory: %w", err)
+	}
+
+	statePath := filepath.Join(dir, name+".state")
+
+	resume, err := q.saveState(ctx, statePath, opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	// The disk images must not change once the state is saved, so the source
+	// VM continues on top of new overlays and the images become the backing
+	// files of the clones.
+	if err := q.snapshotDisks(ctx, client, dir, disks); err != nil {
+		resume()
+
+		return nil, err
+	}
+
+	resume()
+
+	clones := make([]*Clone, 0, opts.Count)
+
+	fail := func(err error) ([]*Clone, error) {
+		for _, clone := range clones {
+			_ = clone.QEMU.Kill()
+			_ = clone.QEMU.Wait()
+		}
+
+		return nil, err
+	}
+
+	for index := 0; index < opts.Count; index++ {
+		cloneName := fmt.Sprintf("%s-%d", name, index)
+		if opts.Name != nil {
+			cloneName = opts.Name(index)
+		}
+
+		clone, err := q.newClone(cloneName, disks)
+		if err != nil {
+			return fail(err)
+		}
+
+		// The options of the clone are derived from the source VM, but the
+		// MAC addresses differ on purpose, so the compatibility check would
+		// reject them.
+		restoreOpts := RestoreStateOptions{Paused: opts.Paused, IgnoreIncompatible: true}
+
+		if err := clone.QEMU.RestoreState(ctx, statePath, restoreOpts); err != nil {
+			_ = clone.QEMU.runtimeDir.Remove()
+
+			return fail(fmt.Errorf("failed to start clone %s: %w", cloneName, err))
+		}
+
+		clones = append(clones, clone)
+	}
+
+	return clones, nil
+}
+
+// cloneDisks returns the writable disks of the running VM.
+func (q *QEMU) cloneDisks(ctx context.Context, client *qmp.Client) ([]cloneDisk, error) {
+	var devices []struct {
+		Device   string `json:"device"`
+		Inserted *struct {
+			NodeName string `json:"node-name"`
+			ReadOnly bool   `json:"ro"`
+			Image    struct {
+				Filename string `json:"filename"`
+				Format   string `json:"format"`
+			} `json:"image"`
+		} `json:"inserted"`
+	}
+
+	if err := client.Execute(ctx, "query-block", nil, &devices); err != nil {
+		return nil, err
+	}
+
+	disks := make([]cloneDisk, 0)
+
+	for _, device := range devices {
+		if device.Inserted == nil || device.Inserted.ReadOnly {
+			continue
+		}
+
+		disk := cloneDisk{
+			device:   device.Device,
+			nodeName: device.Inserted.NodeName,
+			file:     device.Inserted.Image.Filename,
+			format:   diskimage.FileFormat(device.Inserted.Image.Format),
+			image:    device.Inserted.Image.Filename,
+		}
+
+		for image, overlay := range q.diskImages {
+			if overlay == disk.file {
+				disk.image = image
+			}
+		}
+
+		disks = append(disks, disk)
+	}
+
+	return disks, nil
+}
+
+// snapshotDisks switches the specified disks of the running VM to new overlays
+// in dir and records them, so the disks can be found in the options of the VM
+// when it is cloned again.
+func (q *QEMU) snapshotDisks(ctx context.Context, client *qmp.Client, dir string, disks []cloneDisk) error {
+	for index, disk := range disks {
+		overlay := filepath.Join(dir, fmt.Sprintf("disk%d.qcow2", index))
+
+		args := map[string]interface{}{
+			"snapshot-file": overlay,
+			"format":        string(diskimage.FileFormatQCOW2),
+		}
+
+		if disk.nodeName != "" {
+			args["node-name"] = disk.nodeName
+		} else {
+			args["device"] = disk.device
+		}
+
+		if err := client.Execute(ctx, "blockdev-snapshot-sync", args, nil); err != nil {
+			return fmt.Errorf("failed to snapshot disk %s: %w", disk.file, err)
+		}
+
+		if q.diskImages == nil {
+			q.diskImages = make(map[string]string)
+		}
+
+		q.diskImages[disk.image] = overlay
+	}
+
+	return nil
+}
+
+// newClone returns a clone of the VM with the specified name and overlays for
+// the specified disks. The clone isn't started.
+func (q *QEMU) newClone(name string, disks []cloneDisk) (*Clone, error) {
+	dirOpts := RuntimeDirectoryOptions{}
+	if q.runtimeDir != nil {
+		dirOpts = q.runtimeDir.opts
+	}
+
+	dirOpts.Name = name
+
+	dir, err := NewRuntimeDirectory(dirOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	overlays := make(map[string]string)
+
+	for index, disk := range disks {
+		overlay, err := dir.CreateOverlay(fmt.Sprintf("disk%d", index), disk.file, disk.format)
+		if err != nil {
+			_ = dir.Remove()
+
+			return nil, err
+		}
+
+		overlays[disk.image] = overlay
+	}
+
+	uuid, err := randomUUID()
+	if err != nil {
+		_ = dir.Remove()
+
+		return nil, err
+	}
+
+	clone := &Clone{
+		Name:           name,
+		UUID:           uuid,
+		MACAddresses:   make([]string, 0),
+		ForwardedPorts: make(map[int]int),
+	}
+
+	options, err := clone.options(q.options, overlays)
+	if err != nil {
+		_ = dir.Remove()
+
+		return nil, err
+	}
+
+	clone.QEMU = New(q.exePath)
+	clone.QEMU.env = q.env
+	clone.QEMU.SetOptions(options...)
+	clone.QEMU.SetRuntimeDirectory(dir)
+
+	return clone, nil
+}
+
+// options returns the options of the clone based on the options of the source
+// VM. The disk images in overlays are replaced with the corresponding overlay,
+// and the name, UUID, MAC addresses and forwarded host ports are replaced with
+// the values of the clone. An error is returned if a disk image in overlays
+// isn't found in the options, since the clone would write to it.
+func (c *Clone) options(source []*queso.Option, overlays map[string]string) ([]*queso.Option, error) {
+	options := make([]*queso.Option, 0, len(source)+2)
+
+	// Protocol nodes (-blockdev driver=file) whose file is replaced, so the
+	// format nodes on top of them can be switched to QCOW2.
+	overlayNodes := make(map[string]bool)
+
+	// Disk images that were replaced with their overlay.
+	replaced := make(map[string]bool)
+
+	for _, option := range source {
+		switch option.Flag {
+		case "incoming", "loadvm", "pidfile", "daemonize", "qmp", "name", "uuid":
+			continue
+		}
+
+		clone := *option
+		clone.Properties = make([]*queso.Property, 0, len(option.Properties))
+
+		// Disks specified with shortcut options (such as -hda) use the file as
+		// the name of the option.
+		if overlay, ok := overlays[option.Name]; ok {
+			clone.Name = overlay
+			replaced[option.Name] = true
+		}
+
+		// The key of the property whose file was replaced, such as "file" for
+		// -drive or "file.filename" for -blockdev.
+		overlayKey := ""
+
+		for _, property := range option.Properties {
+			value := fmt.Sprint(property.Value)
+
+			switch lastKeySegment(property.Key) {
+			case "file", "filename":
+				if overlay, ok := overlays[value]; ok {
+					property = queso.NewProperty(property.Key, overlay)
+					overlayKey = property.Key
+					replaced[value] = true
+				}
+
+			case "hostfwd":
+				rule, err := c.forwardRule(value)
+				if err != nil {
+					return nil, err
+				}
+
+				property = queso.NewProperty(property.Key, rule)
+
+			case "mac":
+				continue
+			}
+
+			clone.Properties = append(clone.Properties, property)
+		}
+
+		switch {
+		case overlayKey == "":
+
+		case option.Flag != "blockdev":
+			// -drive and its shortcuts specify the format with "format".
+			clone.Properties = setProperty(clone.Properties, "format", string(diskimage.FileFormatQCOW2))
+
+		case overlayKey == "filename":
+			// A standalone protocol node has no format, the format node that
+			// references it is switched below.
+			overlayNodes[option.Table()["node-name"]] = true
+
+		default:
+			// The protocol node is nested (e.g. "file.filename"), so the
+			// option itself is the format node.
+			clone.Properties = setProperty(clone.Properties, "driver", string(diskimage.FileFormatQCOW2))
+		}
+
+		if isNIC(option) {
+			mac, err := randomMACAddress()
+			if err != nil {
+				return nil, err
+			}
+
+			c.MACAddresses = append(c.MACAddresses, mac)
+			clone.Properties = append(clone.Properties, queso.NewProperty("mac", mac))
+		}
+
+		options = append(options, &clone)
+	}
+
+	for image := range overlays {
+		if !replaced[image] {
+			return nil, fmt.Errorf("disk %s not found in the options of the VM", image)
+		}
+	}
+
+	for _, option := range options {
+		if option.Flag == "blockdev" && overlayNodes[option.Table()["file"]] {
+			option.Properties = setProperty(option.Properties, "driver", string(diskimage.FileFormatQCOW2))
+		}
+	}
+
+	options = append(options, Name(c.Name), UUID(c.UUID))
+
+	return options, nil
+}
+
+// lastKeySegment returns the last segment of a dotted property key, such as
+// "filename" for "file.filename".
+func lastKeySegment(key string) string {
+	return key[strings.LastIndex(key, ".")+1:]
+}
+
+var hostForwardRulePattern = regexp.MustCompile(`^(tcp|udp)?:([^:]*):(\d+)-(.*)$`)
+
+// forwardRule returns the specified host forward rule (in the format of
+// network.HostForwardRule) with the host port replaced by a free port.
+func (c *Clone) forwardRule(rule string) (string, error) {
+	match := hostForwardRulePattern.FindStringSubmatch(rule)
+	if match == nil {
+		return "", fmt.Errorf("invalid host forward rule %q", rule)
+	}
+
+	hostPort, _ := strconv.Atoi(match[3])
+
+	port, ok := c.ForwardedPorts[hostPort]
+	if !ok {
+		var err error
+
+		port, err = freePort(match[1], match[2])
+		if err != nil {
+			return "", err
+		}
+
+		c.ForwardedPorts[hostPort] = port
+	}
+
+	return fmt.Sprintf("%s:%s:%d-%s", match[1], match[2], port, match[4]), nil
+}
+
+// isNIC returns true if the option defines a guest NIC.
+func isNIC(option *queso.Option) bool {
+	switch option.Flag {
+	case "nic":
+		return true
+
+	case "net":
+		return option.Name == "nic"
+
+	case "device":
+		_, ok := option.Table()["netdev"]
+
+		return ok
+	}
+
+	return false
+}
+
+// setProperty returns the properties with the value of the property with the
+// specified key replaced (or the property added if it doesn't exist).
+func setProperty(properties []*queso.Property, key string, value interface{}) []*queso.Property {
+	for index, property := range properties {
+		if property.Key == key {
+			properties[index] = queso.NewProperty(key, value)
+
+			return properties
+		}
+	}
+
+	return append(properties, queso.NewProperty(key, value))
+}
+
+// freePort returns a port on the specified host address that isn't in use.
+func freePort(portType string, host string) (int, error) {
+	if portType == "udp" {
+		conn, err := net.ListenPacket("udp", net.JoinHostPort(host, "0"))
+		if err != nil {
+			return 0, fmt.Errorf("failed to find free port: %w", err)
+		}
+
+		defer conn.Close()
+
+		return conn.LocalAddr().(*net.UDPAddr).Port, nil
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find free port: %w", err)
+	}
+
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// randomMACAddress returns a random MAC address with the QEMU OUI (52:54:00).
+func randomMACAddress() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", b[0], b[1], b[2]), nil
+}
+
+// randomUUID returns a random (version 4) UUID.
+func randomUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,156 @@
+package qemu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/mikerourke/queso/qemu/blockdev"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/mikerourke/queso/qemu/network"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneOptions(t *testing.T) {
+	clone := &Clone{
+		Name:           "test-vm-0",
+		UUID:           "5b1e8a3c-4f7d-4a2e-9c61-0d2f6b7e8a90",
+		MACAddresses:   make([]string, 0),
+		ForwardedPorts: make(map[int]int),
+	}
+
+	options, err := clone.options([]*queso.Option{
+		Name("test-vm"),
+		blockdev.Drive(
+			blockdev.WithDiskImageFile("/images/base.raw"),
+			blockdev.WithDiskImageFormat(diskimage.FileFormatRaw)),
+		network.UserBackend("n",
+			network.WithForwardRule(network.NewHostForwardRule(network.PortTypeTCP, 2222, 22).
+				WithHostIP("127.0.0.1"))),
+		device.Use("virtio-net-pci",
+			device.NewProperty("netdev", "n"),
+			device.NewProperty("mac", "52:54:00:12:34:56")),
+		debug.UsePIDFile("/tmp/test-vm.pid"),
+	}, map[string]string{"/images/base.raw": "/tmp/test-vm-0/disk0.qcow2"})
+	assert.NoError(t, err)
+
+	assert.Len(t, options, 5)
+	assert.Equal(t, "-drive file=/tmp/test-vm-0/disk0.qcow2,format=qcow2", options[0].ArgsString())
+
+	port := clone.ForwardedPorts[2222]
+	assert.NotZero(t, port)
+	assert.Equal(t, map[string]string{"id": "n", "hostfwd": fmt.Sprintf("tcp:127.0.0.1:%d-:22", port)},
+		options[1].Table())
+
+	assert.Len(t, clone.MACAddresses, 1)
+	assert.Equal(t, "-device virtio-net-pci,netdev=n,mac="+clone.MACAddresses[0], options[2].ArgsString())
+	assert.NotEqual(t, "52:54:00:12:34:56", clone.MACAddresses[0])
+
+	assert.Equal(t, "-name test-vm-0", options[3].ArgsString())
+	assert.Equal(t, "-uuid "+clone.UUID, options[4].ArgsString())
+}
+
+func TestCloneOptionsBlockdev(t *testing.T) {
+	clone := &Clone{
+		Name:           "test-vm-0",
+		UUID:           "5b1e8a3c-4f7d-4a2e-9c61-0d2f6b7e8a90",
+		MACAddresses:   make([]string, 0),
+		ForwardedPorts: make(map[int]int),
+	}
+
+	options, err := clone.options([]*queso.Option{
+		blockdev.QCOW2Driver(
+			blockdev.WithNodeName("disk0"),
+			blockdev.WithDriverProperty("file", blockdev.WithDriverType("file")),
+			blockdev.WithDriverProperty("file", blockdev.WithImageFile("/images/disk0.qcow2"))),
+		blockdev.RawDriver(
+			blockdev.WithNodeName("disk1"),
+			blockdev.WithFile("disk1_file")),
+		blockdev.FileDriver("/images/disk1.raw",
+			blockdev.WithNodeName("disk1_file")),
+	}, map[string]string{
+		"/images/disk0.qcow2": "/tmp/test-vm-0/disk0.qcow2",
+		"/images/disk1.raw":   "/tmp/test-vm-0/disk1.qcow2",
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, options, 5)
+	assert.Equal(t, "-blockdev driver=qcow2,node-name=disk0,file.driver=file,file.filename=/tmp/test-vm-0/disk0.qcow2",
+		options[0].ArgsString())
+	assert.Equal(t, "-blockdev driver=qcow2,node-name=disk1,file=disk1_file", options[1].ArgsString())
+	assert.Equal(t, "-blockdev driver=file,filename=/tmp/test-vm-0/disk1.qcow2,node-name=disk1_file",
+		options[2].ArgsString())
+}
+
+func TestCloneOptionsMissingDisk(t *testing.T) {
+	clone := &Clone{Name: "test-vm-0", MACAddresses: make([]string, 0), ForwardedPorts: make(map[int]int)}
+
+	_, err := clone.options([]*queso.Option{
+		blockdev.Drive(blockdev.WithDiskImageFile("/images/base.raw")),
+	}, map[string]string{"/images/other.raw": "/tmp/test-vm-0/disk0.qcow2"})
+	assert.EqualError(t, err, "disk /images/other.raw not found in the options of the VM")
+}
+
+func TestCloneTwoGenerations(t *testing.T) {
+	// The overlays are created with qemu-img, which only has to succeed.
+	bin := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(bin, "qemu-img"), []byte("#!/bin/sh\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	top, format := "/images/base.raw", "raw"
+
+	server := qmptest.NewServer()
+	server.Handle("query-block", func(map[string]interface{}) (interface{}, error) {
+		return []interface{}{map[string]interface{}{
+			"device": "ide0-hd0",
+			"inserted": map[string]interface{}{
+				"ro":    false,
+				"image": map[string]interface{}{"filename": top, "format": format},
+			},
+		}}, nil
+	})
+	server.Handle("blockdev-snapshot-sync", func(args map[string]interface{}) (interface{}, error) {
+		top, format = args["snapshot-file"].(string), "qcow2"
+
+		return map[string]interface{}{}, nil
+	})
+
+	ctx := context.Background()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	q := New("qemu-system-x86_64")
+	q.qmp = client
+	q.SetOptions(blockdev.Drive(
+		blockdev.WithDiskImageFile("/images/base.raw"),
+		blockdev.WithDiskImageFormat(diskimage.FileFormatRaw)))
+	q.SetRuntimeDirectory(&RuntimeDirectory{opts: RuntimeDirectoryOptions{Parent: t.TempDir()}})
+
+	for generation := 0; generation < 2; generation++ {
+		disks, err := q.cloneDisks(ctx, client)
+		assert.NoError(t, err)
+		assert.Len(t, disks, 1)
+		assert.Equal(t, "/images/base.raw", disks[0].image)
+
+		assert.NoError(t, q.snapshotDisks(ctx, client, t.TempDir(), disks))
+
+		for index := 0; index < 2; index++ {
+			clone, err := q.newClone(fmt.Sprintf("test-vm-%d", index), disks)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			overlay := clone.QEMU.RuntimeDirectory().Overlay("disk0")
+			assert.Equal(t, "-drive file="+overlay+",format=qcow2", clone.QEMU.options[0].ArgsString())
+		}
+	}
+}
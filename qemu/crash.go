@@ -0,0 +1,189 @@
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikerourke/queso/qemu/dump"
+	"github.com/mikerourke/queso/qemu/lifecycle"
+)
+
+// CrashCaptureOptions represent the options for CaptureCrashes.
+type CrashCaptureOptions struct {
+	// Dir is the directory the artifacts are written to. If omitted, the
+	// runtime directory is used.
+	Dir string
+
+	// Kinds are the kinds of lifecycle events that trigger a capture. The
+	// default is lifecycle.KindPanicked.
+	Kinds []lifecycle.Kind
+
+	// MemoryDump writes an ELF dump of the guest memory (see dump.Dump), which
+	// can be analyzed with crash or gdb, or read with dump.Open.
+	MemoryDump bool
+
+	// SerialLog copies the serial log of the guest, which usually contains
+	// the kernel messages that led to the panic. The serial log must be
+	// written to a file (see RuntimeDirectoryOptions.SerialLog).
+	SerialLog bool
+
+	// OnCapture is called with the report of each capture.
+	OnCapture func(report CrashReport)
+}
+
+// CrashReport describes the artifacts captured by CaptureCrashes for a
+// lifecycle event.
+type CrashReport struct {
+	// Event is the event that triggered the capture.
+	Event lifecycle.Event
+
+	// MemoryDump is the path to the guest memory dump, if one was written.
+	MemoryDump string
+
+	// SerialLog is the path to the copy of the serial log, if one was made.
+	SerialLog string
+
+	// Err is the error that occurred while capturing the artifacts, if any.
+	Err error
+}
+
+// CaptureCrashes captures a guest memory dump and/or the serial log whenever
+// one of the specified lifecycle events occurs, until the returned stop
+// function is called or the QMP connection is closed. QEMU must keep running
+// after a panic to capture the memory dump, so use the "pause" panic action.
+//
+// Example
+//
+//	q := qemu.New("qemu-system-x86_64")
+//	q.SetOptions(
+//		device.PVPanic(),
+//		debug.WithAction(debug.NewAction("panic", "pause")))
+//
+//	stop, err := q.CaptureCrashes(ctx, qemu.CrashCaptureOptions{
+//		Dir:        "/var/crash",
+//		MemoryDump: true,
+//		SerialLog:  true,
+//		OnCapture: func(report qemu.CrashReport) {
+//			log.Printf("guest panicked, memory dump in %s", report.MemoryDump)
+//		},
+//	})
+func (q *QEMU) CaptureCrashes(ctx context.Context, opts CrashCaptureOptions) (func(), error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		if q.runtimeDir == nil {
+			return nil, errors.New("a directory is required to capture crashes without a runtime directory")
+		}
+
+		dir = q.runtimeDir.Path()
+	}
+
+	if opts.SerialLog && q.serialLogFile() == "" {
+		return nil, errors.New("capturing the serial log requires a serial port that writes to a file")
+	}
+
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		kinds = []lifecycle.Kind{lifecycle.KindPanicked}
+	}
+
+	events, cancel := lifecycle.Subscribe(client, kinds...)
+
+	go func() {
+		count := 0
+
+		for event := range events {
+			count++
+
+			report := q.captureCrash(context.Background(), event, dir, count, opts)
+
+			if opts.OnCapture != nil {
+				opts.OnCapture(report)
+			}
+		}
+	}()
+
+	return cancel, nil
+}
+
+// captureCrash writes the artifacts for a single event. The name of each
+// artifact includes the number of the capture, so later captures don't
+// overwrite earlier ones.
+func (q *QEMU) captureCrash(
+	ctx context.Context,
+	event lifecycle.Event,
+	dir string,
+	number int,
+	opts CrashCaptureOptions,
+) CrashReport {
+	report := CrashReport{Event: event}
+	prefix := filepath.Join(dir, fmt.Sprintf("crash-%d", number))
+
+	if opts.MemoryDump {
+		path := prefix + ".core"
+
+		if err := dump.Dump(ctx, q.QMP(), path, dump.Options{}); err != nil {
+			report.Err = fmt.Errorf("failed to dump guest memory: %w", err)
+
+			return report
+		}
+
+		report.MemoryDump = path
+	}
+
+	if opts.SerialLog {
+		path := prefix + "-serial.log"
+
+		if err := copyFile(q.serialLogFile(), path); err != nil {
+			report.Err = fmt.Errorf("failed to copy serial log: %w", err)
+
+			return report
+		}
+
+		report.SerialLog = path
+	}
+
+	return report
+}
+
+// serialLogFile returns the path to the file the first serial port writes to,
+// or an empty string if it doesn't write to a file.
+func (q *QEMU) serialLogFile() string {
+	option := q.FindOption("serial")
+	if option == nil || !strings.HasPrefix(option.Name, "file:") {
+		return ""
+	}
+
+	return strings.TrimPrefix(option.Name, "file:")
+}
+
+func copyFile(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+
+		return err
+	}
+
+	return out.Close()
+}
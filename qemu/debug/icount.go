@@ -0,0 +1,103 @@
+package debug
+
+import "github.com/mikerourke/queso"
+
+// InstructionCount enables the virtual instruction counter, which makes the
+// virtual CPU execute one instruction every 2^N ns of virtual time (see
+// WithShift). The instruction counter is also required for deterministic
+// record/replay (see WithReplayMode). This option is only available with the
+// TCG accelerator.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		debug.InstructionCount(
+//			debug.WithAutoShift(),
+//			debug.WithReplayMode(debug.ReplayModeRecord),
+//			debug.WithReplayFile("replay.bin")))
+//
+// Invocation
+//
+//	qemu-system-x86_64 -icount shift=auto,rr=record,rrfile=replay.bin
+func InstructionCount(properties ...*InstructionCountProperty) *queso.Option {
+	props := make([]*queso.Property, 0)
+
+	for _, property := range properties {
+		props = append(props, property.Property)
+	}
+
+	return queso.NewOption("icount", "", props...)
+}
+
+// InstructionCountProperty represents a property that can be passed to the
+// InstructionCount option.
+type InstructionCountProperty struct {
+	*queso.Property
+}
+
+// NewInstructionCountProperty returns a new instance of InstructionCountProperty.
+func NewInstructionCountProperty(key string, value interface{}) *InstructionCountProperty {
+	return &InstructionCountProperty{
+		Property: queso.NewProperty(key, value),
+	}
+}
+
+// WithShift specifies that the virtual CPU executes one instruction every
+// 2^shift ns of virtual time for InstructionCount.
+func WithShift(shift int) *InstructionCountProperty {
+	return NewInstructionCountProperty("shift", shift)
+}
+
+// WithAutoShift makes the speed of the virtual CPU adapt to the host, so that
+// virtual time stays within a few seconds of real time, for InstructionCount.
+func WithAutoShift() *InstructionCountProperty {
+	return NewInstructionCountProperty("shift", "auto")
+}
+
+// IsAligned specifies whether the virtual CPU is delayed when it runs ahead of
+// the host for InstructionCount. Delays are only possible when the virtual CPU
+// is faster than the host, so this is meant to be used with a fixed WithShift.
+func IsAligned(enabled bool) *InstructionCountProperty {
+	return NewInstructionCountProperty("align", enabled)
+}
+
+// IsSleepEnabled specifies whether the virtual CPU sleeps when it is idle, so
+// virtual time advances with real time, for InstructionCount. If disabled,
+// virtual time jumps to the next timer deadline instead, which makes the guest
+// independent of the speed of the host.
+func IsSleepEnabled(enabled bool) *InstructionCountProperty {
+	return NewInstructionCountProperty("sleep", enabled)
+}
+
+// ReplayMode represents the mode of deterministic record/replay.
+type ReplayMode string
+
+const (
+	// ReplayModeRecord records all non-deterministic events (such as input,
+	// network packets and the results of timer reads) to the replay file.
+	ReplayModeRecord ReplayMode = "record"
+
+	// ReplayModeReplay replays the events from the replay file, so the guest
+	// executes exactly as it did when it was recorded.
+	ReplayModeReplay ReplayMode = "replay"
+)
+
+// WithReplayMode enables deterministic record/replay in the specified mode for
+// InstructionCount. The replay file must be specified with WithReplayFile.
+func WithReplayMode(mode ReplayMode) *InstructionCountProperty {
+	return NewInstructionCountProperty("rr", mode)
+}
+
+// WithReplayFile specifies the file the events are recorded to or replayed
+// from for InstructionCount.
+func WithReplayFile(file string) *InstructionCountProperty {
+	return NewInstructionCountProperty("rrfile", file)
+}
+
+// WithReplaySnapshot specifies the name of the VM snapshot that is created when
+// recording starts, and loaded when replaying starts, for InstructionCount.
+// This allows the recording to start from a VM that is already running, and
+// requires a QCOW2 disk to store the snapshot.
+func WithReplaySnapshot(name string) *InstructionCountProperty {
+	return NewInstructionCountProperty("rrsnapshot", name)
+}
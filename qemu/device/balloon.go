@@ -0,0 +1,53 @@
+package device
+
+import "github.com/mikerourke/queso"
+
+// VirtioBalloon adds a virtio memory balloon device with the specified id,
+// which is used to reclaim memory from the guest at runtime and to query the
+// memory statistics of the guest (see the balloon package).
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		device.VirtioBalloon("balloon0",
+//			device.IsFreePageReporting(true),
+//			device.IsDeflateOnOOM(true)))
+//
+// Invocation
+//
+//	qemu-system-x86_64 -device virtio-balloon-pci,id=balloon0,free-page-reporting=on,deflate-on-oom=on
+func VirtioBalloon(id string, properties ...*Property) *queso.Option {
+	props := []*Property{NewProperty("id", id)}
+
+	if properties != nil {
+		props = append(props, properties...)
+	}
+
+	return Use("virtio-balloon-pci", props...)
+}
+
+// IsFreePageReporting specifies whether the guest reports free pages to the
+// host for a VirtioBalloon, so the host can reclaim the memory without
+// inflating the balloon.
+func IsFreePageReporting(enabled bool) *Property {
+	return NewProperty("free-page-reporting", enabled)
+}
+
+// IsFreePageHinting specifies whether the guest hints free pages to the host
+// for a VirtioBalloon, so they are skipped during live migration.
+func IsFreePageHinting(enabled bool) *Property {
+	return NewProperty("free-page-hint", enabled)
+}
+
+// IsDeflateOnOOM specifies whether the guest deflates the VirtioBalloon when
+// it runs out of memory, instead of invoking the OOM killer.
+func IsDeflateOnOOM(enabled bool) *Property {
+	return NewProperty("deflate-on-oom", enabled)
+}
+
+// WithGuestStatsPollingInterval sets the interval in seconds at which the
+// guest updates the memory statistics of a VirtioBalloon. A value of 0
+// disables the statistics.
+func WithGuestStatsPollingInterval(seconds int) *Property {
+	return NewProperty("guest-stats-polling-interval", seconds)
+}
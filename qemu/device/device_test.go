@@ -30,3 +30,15 @@ func TestUse(t *testing.T) {
 	result = Use("nec-usb-xhci", WithID("usb-controller-0")).ArgsString()
 	assert.Equal(t, result, "-device nec-usb-xhci,id=usb-controller-0")
 }
+
+func TestVirtioBalloon(t *testing.T) {
+	result := VirtioBalloon("balloon0",
+		IsFreePageReporting(true),
+		IsDeflateOnOOM(true)).ArgsString()
+	assert.Equal(t, result, "-device virtio-balloon-pci,id=balloon0,free-page-reporting=on,deflate-on-oom=on")
+}
+
+func TestPVPanic(t *testing.T) {
+	result := PVPanicPCI(WithPanicEvents(PanicEventPanicked, PanicEventCrashLoaded)).ArgsString()
+	assert.Equal(t, result, "-device pvpanic-pci,events=3")
+}
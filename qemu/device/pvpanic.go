@@ -0,0 +1,70 @@
+package device
+
+import "github.com/mikerourke/queso"
+
+// PanicEvent represents an event a pvpanic device reports to the host. The
+// host is notified with a GUEST_PANICKED or GUEST_CRASHLOADED QMP event (see
+// the lifecycle package).
+type PanicEvent int
+
+const (
+	// PanicEventPanicked is reported when the guest kernel panics.
+	PanicEventPanicked PanicEvent = 1
+
+	// PanicEventCrashLoaded is reported when the guest kernel has loaded a
+	// crash kernel (e.g. kdump) after a panic.
+	PanicEventCrashLoaded PanicEvent = 2
+)
+
+// PVPanic adds an ISA pvpanic device, which allows the guest kernel to notify
+// the host when it panics. This requires a guest with the pvpanic driver, which
+// is part of Linux since version 3.10. Use debug.WithAction to specify what
+// QEMU does when the guest panics.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		device.PVPanic(device.WithPanicEvents(device.PanicEventPanicked)),
+//		debug.WithAction(debug.NewAction("panic", "pause")))
+//
+// Invocation
+//
+//	qemu-system-x86_64 -device pvpanic,events=1 -action panic=pause
+func PVPanic(properties ...*Property) *queso.Option {
+	return Use("pvpanic", properties...)
+}
+
+// PVPanicPCI adds a PCI pvpanic device, which is used on machines without an
+// ISA bus (such as the "virt" machine on ARM).
+//
+// Example
+//
+//	qemu.New("qemu-system-aarch64").SetOptions(
+//		device.PVPanicPCI(device.WithPanicEvents(
+//			device.PanicEventPanicked,
+//			device.PanicEventCrashLoaded)))
+//
+// Invocation
+//
+//	qemu-system-aarch64 -device pvpanic-pci,events=3
+func PVPanicPCI(properties ...*Property) *queso.Option {
+	return Use("pvpanic-pci", properties...)
+}
+
+// WithPanicEvents specifies the events a PVPanic or PVPanicPCI device reports
+// to the host. By default, both events are reported.
+func WithPanicEvents(events ...PanicEvent) *Property {
+	mask := 0
+
+	for _, event := range events {
+		mask |= int(event)
+	}
+
+	return NewProperty("events", mask)
+}
+
+// WithPanicIOPort specifies the I/O port of a PVPanic device. The default is
+// 0x505.
+func WithPanicIOPort(port int) *Property {
+	return NewProperty("ioport", port)
+}
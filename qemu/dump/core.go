@@ -0,0 +1,317 @@
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Segment is a memory segment (a PT_LOAD program header) of an ELF core.
+type Segment struct {
+	// PhysicalAddress is the guest physical address of the segment.
+	PhysicalAddress uint64
+
+	// VirtualAddress is the guest virtual address of the segment. It is only
+	// meaningful if the dump was made with the Paging option.
+	VirtualAddress uint64
+
+	// Offset is the offset of the segment data in the file.
+	Offset uint64
+
+	// Size is the number of bytes of the segment in the file.
+	Size uint64
+
+	// MemorySize is the number of bytes of the segment in memory. It can be
+	// larger than Size if the rest of the segment wasn't dumped.
+	MemorySize uint64
+}
+
+// Note is an ELF note of a core.
+type Note struct {
+	// Name is the owner of the note, such as "CORE", "QEMU" or "VMCOREINFO".
+	Name string
+
+	// Type is the type of the note (e.g. elf.NT_PRSTATUS).
+	Type uint32
+
+	// Data is the descriptor of the note.
+	Data []byte
+}
+
+// CPU contains the registers of a vCPU at the time of the dump, which QEMU
+// writes as an NT_PRSTATUS note for each vCPU.
+type CPU struct {
+	// Index is the index of the vCPU.
+	Index int
+
+	// PID is the ID QEMU assigns to the vCPU (the index plus one).
+	PID int
+
+	// Registers maps the names of the general purpose registers (such as
+	// "rip" or "pc") to their values. It is only populated for the x86_64 and
+	// aarch64 architectures.
+	Registers map[string]uint64
+
+	// Raw contains the raw register data of the note.
+	Raw []byte
+}
+
+// Core is an ELF core written by dump-guest-memory with FormatELF.
+type Core struct {
+	file   *os.File
+	order  binary.ByteOrder
+	loaded []Segment
+	progs  []*elf.Prog
+
+	// Machine is the architecture of the guest.
+	Machine elf.Machine
+
+	// Notes are all the ELF notes of the core.
+	Notes []Note
+
+	// CPUs are the registers of each vCPU.
+	CPUs []CPU
+
+	// VMCoreInfo contains the entries of the VMCOREINFO note, which the guest
+	// kernel provides through the vmcoreinfo device (see the crash and
+	// makedumpfile tools). It is empty if the note is missing.
+	VMCoreInfo map[string]string
+}
+
+// Open opens the ELF core at the specified path and reads its notes. The Core
+// must be closed once it is no longer needed.
+func Open(path string) (*Core, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	core, err := newCore(file, info.Size())
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("failed to read core %s: %w", path, err)
+	}
+
+	core.file = file
+
+	return core, nil
+}
+
+// newCore reads the ELF core from r, whose size is used to reject segments that
+// extend beyond the end of the file.
+func newCore(r io.ReaderAt, size int64) (*Core, error) {
+	ef, err := elf.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if ef.Type != elf.ET_CORE {
+		return nil, fmt.Errorf("file type is %s instead of %s", ef.Type, elf.ET_CORE)
+	}
+
+	core := &Core{
+		order:      ef.ByteOrder,
+		Machine:    ef.Machine,
+		VMCoreInfo: make(map[string]string),
+	}
+
+	for _, prog := range ef.Progs {
+		switch prog.Type {
+		case elf.PT_LOAD:
+			core.loaded = append(core.loaded, Segment{
+				PhysicalAddress: prog.Paddr,
+				VirtualAddress:  prog.Vaddr,
+				Offset:          prog.Off,
+				Size:            prog.Filesz,
+				MemorySize:      prog.Memsz,
+			})
+			core.progs = append(core.progs, prog)
+
+		case elf.PT_NOTE:
+			if prog.Off > uint64(size) || prog.Filesz > uint64(size)-prog.Off {
+				return nil, fmt.Errorf("note segment at offset %d with size %d exceeds the file size %d",
+					prog.Off, prog.Filesz, size)
+			}
+
+			data := make([]byte, prog.Filesz)
+			if _, err := prog.ReadAt(data, 0); err != nil {
+				return nil, fmt.Errorf("failed to read notes: %w", err)
+			}
+
+			notes, err := parseNotes(data, core.order)
+			if err != nil {
+				return nil, err
+			}
+
+			core.Notes = append(core.Notes, notes...)
+		}
+	}
+
+	for _, note := range core.Notes {
+		switch {
+		case note.Name == "CORE" && note.Type == uint32(elf.NT_PRSTATUS):
+			core.CPUs = append(core.CPUs, core.parseCPU(len(core.CPUs), note.Data))
+
+		case note.Name == "VMCOREINFO":
+			core.VMCoreInfo = parseVMCoreInfo(note.Data)
+		}
+	}
+
+	return core, nil
+}
+
+// Close closes the core file.
+func (c *Core) Close() error {
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Close()
+}
+
+// Segments returns the memory segments of the core.
+func (c *Core) Segments() []Segment {
+	return c.loaded
+}
+
+// ReadPhysical reads len(p) bytes of guest memory starting at the specified
+// guest physical address. An error is returned if the range isn't contained in
+// a single segment of the core.
+func (c *Core) ReadPhysical(p []byte, address uint64) (int, error) {
+	for index, segment := range c.loaded {
+		if address < segment.PhysicalAddress || address >= segment.PhysicalAddress+segment.Size {
+			continue
+		}
+
+		offset := address - segment.PhysicalAddress
+		if offset+uint64(len(p)) > segment.Size {
+			return 0, fmt.Errorf("range 0x%x-0x%x exceeds segment", address, address+uint64(len(p)))
+		}
+
+		return c.progs[index].ReadAt(p, int64(offset))
+	}
+
+	return 0, fmt.Errorf("address 0x%x is not in the core", address)
+}
+
+// parseNotes parses the notes of a PT_NOTE segment. The name and descriptor of
+// each note are aligned to 4 bytes, which is what QEMU uses for both ELF
+// classes.
+func parseNotes(data []byte, order binary.ByteOrder) ([]Note, error) {
+	notes := make([]Note, 0)
+
+	for len(data) >= 12 {
+		nameSize := order.Uint32(data[0:4])
+		dataSize := order.Uint32(data[4:8])
+		noteType := order.Uint32(data[8:12])
+		data = data[12:]
+
+		nameEnd := align4(nameSize)
+		if uint64(len(data)) < nameEnd {
+			return nil, errors.New("truncated note name")
+		}
+
+		name := string(bytes.TrimRight(data[:nameSize], "\x00"))
+		data = data[nameEnd:]
+
+		dataEnd := align4(dataSize)
+		if uint64(len(data)) < uint64(dataSize) {
+			return nil, fmt.Errorf("truncated %s note", name)
+		}
+
+		notes = append(notes, Note{
+			Name: name,
+			Type: noteType,
+			Data: data[:dataSize],
+		})
+
+		if uint64(len(data)) < dataEnd {
+			break
+		}
+
+		data = data[dataEnd:]
+	}
+
+	return notes, nil
+}
+
+func align4(size uint32) uint64 {
+	return (uint64(size) + 3) &^ 3
+}
+
+// prStatusRegisters is the offset of the registers in an elf_prstatus struct
+// of a 64-bit architecture.
+const prStatusRegisters = 112
+
+// prStatusPID is the offset of the PID in an elf_prstatus struct.
+const prStatusPID = 32
+
+var registerNames = map[elf.Machine][]string{
+	elf.EM_X86_64: {
+		"r15", "r14", "r13", "r12", "rbp", "rbx", "r11", "r10", "r9", "r8",
+		"rax", "rcx", "rdx", "rsi", "rdi", "orig_rax", "rip", "cs", "eflags",
+		"rsp", "ss", "fs_base", "gs_base", "ds", "es", "fs", "gs",
+	},
+	elf.EM_AARCH64: aarch64Registers(),
+}
+
+func aarch64Registers() []string {
+	names := make([]string, 0, 34)
+
+	for index := 0; index <= 30; index++ {
+		names = append(names, fmt.Sprintf("x%d", index))
+	}
+
+	return append(names, "sp", "pc", "pstate")
+}
+
+func (c *Core) parseCPU(index int, data []byte) CPU {
+	cpu := CPU{
+		Index:     index,
+		Registers: make(map[string]uint64),
+		Raw:       data,
+	}
+
+	if len(data) >= prStatusPID+4 {
+		cpu.PID = int(c.order.Uint32(data[prStatusPID:]))
+	}
+
+	names := registerNames[c.Machine]
+
+	if len(data) >= prStatusRegisters+len(names)*8 {
+		for i, name := range names {
+			cpu.Registers[name] = c.order.Uint64(data[prStatusRegisters+i*8:])
+		}
+	}
+
+	return cpu
+}
+
+// parseVMCoreInfo parses the "KEY=VALUE" lines of a VMCOREINFO note.
+func parseVMCoreInfo(data []byte) map[string]string {
+	info := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimRight(data, "\x00")))
+
+	for scanner.Scan() {
+		if parts := strings.SplitN(scanner.Text(), "=", 2); len(parts) == 2 {
+			info[parts[0]] = parts[1]
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,100 @@
+package dump
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCore(t *testing.T) {
+	order := binary.LittleEndian
+
+	prstatus := make([]byte, prStatusRegisters+27*8)
+	order.PutUint32(prstatus[prStatusPID:], 1)
+	order.PutUint64(prstatus[prStatusRegisters+16*8:], 0xffffffff81000000)
+
+	notes := new(bytes.Buffer)
+	writeNote(notes, "CORE", uint32(elf.NT_PRSTATUS), prstatus)
+	writeNote(notes, "VMCOREINFO", 0, []byte("OSRELEASE=6.1.0\nPAGESIZE=4096\n"))
+
+	memory := []byte("guest memory")
+
+	const headerSize = 64 + 2*56
+
+	header := elf.Header64{
+		Type:      uint16(elf.ET_CORE),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     64,
+		Ehsize:    64,
+		Phentsize: 56,
+		Phnum:     2,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	progs := []elf.Prog64{
+		{Type: uint32(elf.PT_NOTE), Off: headerSize, Filesz: uint64(notes.Len())},
+		{
+			Type:   uint32(elf.PT_LOAD),
+			Off:    headerSize + uint64(notes.Len()),
+			Paddr:  0x1000,
+			Filesz: uint64(len(memory)),
+			Memsz:  uint64(len(memory)),
+		},
+	}
+
+	file := new(bytes.Buffer)
+	assert.NoError(t, binary.Write(file, order, header))
+	assert.NoError(t, binary.Write(file, order, progs))
+	file.Write(notes.Bytes())
+	file.Write(memory)
+
+	core, err := newCore(bytes.NewReader(file.Bytes()), int64(file.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, elf.EM_X86_64, core.Machine)
+	assert.Len(t, core.Notes, 2)
+	assert.Len(t, core.CPUs, 1)
+	assert.Equal(t, 1, core.CPUs[0].PID)
+	assert.Equal(t, uint64(0xffffffff81000000), core.CPUs[0].Registers["rip"])
+	assert.Equal(t, "6.1.0", core.VMCoreInfo["OSRELEASE"])
+	assert.Equal(t, []Segment{{PhysicalAddress: 0x1000, Offset: progs[1].Off, Size: 12, MemorySize: 12}},
+		core.Segments())
+
+	buf := make([]byte, 6)
+	_, err = core.ReadPhysical(buf, 0x1006)
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", string(buf))
+
+	_, err = core.ReadPhysical(buf, 0x2000)
+	assert.Error(t, err)
+
+	// A note segment that is larger than the file is rejected before its data
+	// is allocated.
+	progs[0].Filesz = 1 << 40
+
+	corrupt := new(bytes.Buffer)
+	assert.NoError(t, binary.Write(corrupt, order, header))
+	assert.NoError(t, binary.Write(corrupt, order, progs))
+	corrupt.Write(notes.Bytes())
+	corrupt.Write(memory)
+
+	_, err = newCore(bytes.NewReader(corrupt.Bytes()), int64(corrupt.Len()))
+	assert.ErrorContains(t, err, "exceeds the file size")
+}
+
+func writeNote(buf *bytes.Buffer, name string, noteType uint32, data []byte) {
+	order := binary.LittleEndian
+	nameData := append([]byte(name), 0)
+
+	_ = binary.Write(buf, order, []uint32{uint32(len(nameData)), uint32(len(data)), noteType})
+	buf.Write(nameData)
+	buf.Write(make([]byte, align4(uint32(len(nameData)))-uint64(len(nameData))))
+	buf.Write(data)
+	buf.Write(make([]byte, align4(uint32(len(data)))-uint64(len(data))))
+}
@@ -0,0 +1,233 @@
+// Package dump is used to dump the memory of a running guest to a file with
+// dump-guest-memory, and to read the resulting ELF core (see Open). This is
+// different from qemu.IsDumpGuestCore, which controls whether the guest memory
+// is included in a core dump of the QEMU process itself.
+package dump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// Format represents the format of a guest memory dump.
+type Format string
+
+const (
+	// FormatELF is an ELF core file, which can be analyzed with gdb or crash
+	// and read with Open.
+	FormatELF Format = "elf"
+
+	// FormatKdumpZlib is a kdump-compressed file with zlib compression, which
+	// can be analyzed with crash.
+	FormatKdumpZlib Format = "kdump-zlib"
+
+	// FormatKdumpLZO is a kdump-compressed file with LZO compression.
+	FormatKdumpLZO Format = "kdump-lzo"
+
+	// FormatKdumpSnappy is a kdump-compressed file with Snappy compression.
+	FormatKdumpSnappy Format = "kdump-snappy"
+
+	// FormatKdumpRawZlib is a raw kdump-compressed file with zlib compression,
+	// which requires QEMU 8.2 or newer.
+	FormatKdumpRawZlib Format = "kdump-raw-zlib"
+
+	// FormatWindows is a Windows complete memory dump, which requires a
+	// Windows guest with the vmcoreinfo device.
+	FormatWindows Format = "win-dmp"
+)
+
+// Status represents the status of a dump.
+type Status string
+
+const (
+	// StatusNone indicates that no dump was started.
+	StatusNone Status = "none"
+
+	// StatusActive indicates that the dump is in progress.
+	StatusActive Status = "active"
+
+	// StatusCompleted indicates that the dump completed successfully.
+	StatusCompleted Status = "completed"
+
+	// StatusFailed indicates that the dump failed.
+	StatusFailed Status = "failed"
+)
+
+// Options represent the options for Start and Dump.
+type Options struct {
+	// Format is the format of the dump. The default is FormatELF.
+	Format Format
+
+	// Paging uses the guest page tables to map guest virtual addresses to
+	// physical addresses, so the ELF core contains virtual addresses. Paging
+	// can't be used with the kdump formats.
+	Paging bool
+
+	// Begin is the guest physical address the dump starts at. If Length is
+	// zero, the whole guest memory is dumped.
+	Begin int64
+
+	// Length is the number of bytes to dump, starting at Begin.
+	Length int64
+}
+
+// Progress represents the progress of a dump.
+type Progress struct {
+	// Status is the status of the dump.
+	Status Status `json:"status"`
+
+	// Completed is the number of bytes that have been written.
+	Completed int64 `json:"completed"`
+
+	// Total is the total number of bytes to write.
+	Total int64 `json:"total"`
+}
+
+// Percent returns the progress as a percentage between 0 and 100.
+func (p Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+
+	return float64(p.Completed) / float64(p.Total) * 100
+}
+
+// Formats returns the dump formats supported by QEMU.
+func Formats(ctx context.Context, client *qmp.Client) ([]Format, error) {
+	var result struct {
+		Formats []Format `json:"formats"`
+	}
+
+	if err := client.Execute(ctx, "query-dump-guest-memory-capability", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Formats, nil
+}
+
+// Start starts dumping the guest memory to the specified file in the
+// background (see dump-guest-memory). Use Query to track the progress, or Wait
+// to wait for the dump to finish. Only one dump can run at a time.
+func Start(ctx context.Context, client *qmp.Client, file string, opts Options) error {
+	args := opts.args(file)
+	args["detach"] = true
+
+	if err := client.Execute(ctx, "dump-guest-memory", args, nil); err != nil {
+		return fmt.Errorf("failed to start dump: %w", err)
+	}
+
+	return nil
+}
+
+// Dump dumps the guest memory to the specified file and waits for the dump to
+// finish. The guest is paused while its memory is dumped.
+//
+// Example
+//
+//	err := dump.Dump(ctx, q.QMP(), "/var/crash/vm.core", dump.Options{})
+//	if err != nil {
+//		return err
+//	}
+//
+//	core, err := dump.Open("/var/crash/vm.core")
+func Dump(ctx context.Context, client *qmp.Client, file string, opts Options) error {
+	// The subscription is made before the dump is started, so the completion
+	// event can't be missed.
+	events, cancel := client.Subscribe("DUMP_COMPLETED")
+	defer cancel()
+
+	if err := Start(ctx, client, file, opts); err != nil {
+		return err
+	}
+
+	return waitForCompletion(ctx, client, events)
+}
+
+// Query returns the progress of the current (or last) dump (see query-dump).
+func Query(ctx context.Context, client *qmp.Client) (Progress, error) {
+	var progress Progress
+
+	if err := client.Execute(ctx, "query-dump", nil, &progress); err != nil {
+		return Progress{}, err
+	}
+
+	return progress, nil
+}
+
+// Wait waits for the dump started with Start to finish and returns an error
+// if it failed (or if no dump was started).
+func Wait(ctx context.Context, client *qmp.Client) error {
+	events, cancel := client.Subscribe("DUMP_COMPLETED")
+	defer cancel()
+
+	// The dump may have finished before the subscription was made.
+	progress, err := Query(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	switch progress.Status {
+	case StatusCompleted:
+		return nil
+
+	case StatusNone:
+		return errors.New("no dump was started")
+
+	case StatusFailed:
+		return errors.New("dump failed")
+	}
+
+	return waitForCompletion(ctx, client, events)
+}
+
+func waitForCompletion(ctx context.Context, client *qmp.Client, events <-chan qmp.Event) error {
+	select {
+	case event, ok := <-events:
+		if !ok {
+			return client.Err()
+		}
+
+		var data struct {
+			Result Progress `json:"result"`
+			Error  string   `json:"error"`
+		}
+
+		if err := event.DecodeData(&data); err != nil {
+			return err
+		}
+
+		if data.Error != "" {
+			return fmt.Errorf("dump failed: %s", data.Error)
+		}
+
+		if data.Result.Status == StatusFailed {
+			return errors.New("dump failed")
+		}
+
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o Options) args(file string) map[string]interface{} {
+	args := map[string]interface{}{
+		"paging":   o.Paging,
+		"protocol": "file:" + file,
+	}
+
+	if o.Format != "" && o.Format != FormatELF {
+		args["format"] = string(o.Format)
+	}
+
+	if o.Length != 0 {
+		args["begin"] = o.Begin
+		args["length"] = o.Length
+	}
+
+	return args
+}
@@ -0,0 +1,28 @@
+package dump
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := qmptest.NewServer()
+	server.Respond("query-dump", map[string]interface{}{"status": "none", "completed": 0, "total": 0})
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	assert.EqualError(t, Wait(ctx, client), "no dump was started")
+
+	server.Respond("query-dump", map[string]interface{}{"status": "completed", "completed": 1024, "total": 1024})
+	assert.NoError(t, Wait(ctx, client))
+}
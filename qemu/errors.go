@@ -0,0 +1,343 @@
+package qemu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso"
+)
+
+// Severity represents the severity of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError represents an error, which QEMU prints without a prefix.
+	SeverityError Severity = "error"
+
+	// SeverityWarning represents a warning, which QEMU prints with a
+	// "warning: " prefix.
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo represents an informational message, which QEMU prints with
+	// an "info: " prefix.
+	SeverityInfo Severity = "info"
+)
+
+// Diagnostic represents an error message QEMU printed to stderr, such as:
+//
+//	qemu-system-x86_64: -device foo: 'foo' is not a valid device model name
+type Diagnostic struct {
+	// Timestamp is the time at which the message was printed. It is only set
+	// if the debug.UseErrorMessageFormat option was used with TimeStamp.
+	Timestamp time.Time
+
+	// GuestName is the name of the guest the message refers to. It is only set
+	// if the debug.UseErrorMessageFormat option was used with GuestName.
+	GuestName string
+
+	// Program is the name of the QEMU executable that printed the message.
+	Program string
+
+	// Flag is the command line flag (without the leading "-") the message
+	// refers to, if any.
+	Flag string
+
+	// Value is the value passed to the Flag, if any.
+	Value string
+
+	// Message is the actual error message, without the severity prefix.
+	Message string
+
+	// Severity is the severity of the message.
+	Severity Severity
+
+	// Hints contains additional lines QEMU printed after the message.
+	Hints []string
+
+	// Option is the queso.Option that produced the Flag, if it could be found.
+	// Use Option.Builder to get the name of the function that created it.
+	Option *queso.Option
+}
+
+// String returns the Diagnostic in the format QEMU printed it, along with the
+// builder of the Option that caused it (if known).
+func (d Diagnostic) String() string {
+	var b strings.Builder
+
+	if d.Flag != "" {
+		b.WriteString("-" + d.Flag)
+
+		if d.Value != "" {
+			b.WriteString(" " + d.Value)
+		}
+
+		b.WriteString(": ")
+	}
+
+	if d.Severity != "" && d.Severity != SeverityError {
+		b.WriteString(string(d.Severity) + ": ")
+	}
+
+	b.WriteString(d.Message)
+
+	if d.Option != nil && d.Option.Builder != "" {
+		fmt.Fprintf(&b, " (from %s)", d.Option.Builder)
+	}
+
+	return b.String()
+}
+
+// Error is returned when QEMU exits with an error. It contains the diagnostics
+// QEMU printed to stderr.
+type Error struct {
+	// Err is the underlying error (typically an *exec.ExitError).
+	Err error
+
+	// Diagnostics are the error messages parsed from stderr.
+	Diagnostics []Diagnostic
+
+	// Stderr is the (possibly truncated) output QEMU wrote to stderr.
+	Stderr string
+}
+
+// Error returns the first diagnostic with SeverityError, or the underlying error
+// if there are no such diagnostics. Warnings printed before the actual error
+// are skipped.
+func (e *Error) Error() string {
+	for _, diagnostic := range e.Diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return fmt.Sprintf("qemu: %s: %s", e.Err, diagnostic)
+		}
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var (
+	timestampPrefix = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?Z) `)
+	locationPrefix  = regexp.MustCompile(`^-([A-Za-z0-9._-]+)(?: (.*?))?: (.*)$`)
+)
+
+// ParseDiagnostics parses the error messages in the specified stderr output of
+// the QEMU executable with the specified name (e.g. "qemu-system-x86_64"). The
+// guestName is required to detect the guest name prefix that is printed if the
+// debug.UseErrorMessageFormat option is used with GuestName. Each diagnostic is
+// mapped back to the option in options that produced the offending flag.
+func ParseDiagnostics(
+	stderr string,
+	program string,
+	guestName string,
+	options []*queso.Option,
+) []Diagnostic {
+	program = filepath.Base(program)
+
+	diagnostics := make([]Diagnostic, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(stderr))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		diagnostic, ok := parseDiagnostic(line, program, guestName, options)
+		if ok {
+			diagnostics = append(diagnostics, diagnostic)
+
+			continue
+		}
+
+		// Lines without the program prefix are hints appended to the previous
+		// message (see error_append_hint in QEMU).
+		if len(diagnostics) != 0 {
+			last := &diagnostics[len(diagnostics)-1]
+			last.Hints = append(last.Hints, line)
+		}
+	}
+
+	return diagnostics
+}
+
+// parseDiagnostic parses a line QEMU printed to stderr and sets the severity of
+// the resulting Diagnostic.
+func parseDiagnostic(
+	line string,
+	program string,
+	guestName string,
+	options []*queso.Option,
+) (Diagnostic, bool) {
+	diagnostic, ok := parseDiagnosticLocation(line, program, guestName, options)
+	if !ok {
+		return diagnostic, false
+	}
+
+	diagnostic.Severity = SeverityError
+
+	for _, severity := range []Severity{SeverityWarning, SeverityInfo} {
+		prefix := string(severity) + ": "
+
+		if strings.HasPrefix(diagnostic.Message, prefix) {
+			diagnostic.Severity = severity
+			diagnostic.Message = strings.TrimPrefix(diagnostic.Message, prefix)
+
+			break
+		}
+	}
+
+	return diagnostic, true
+}
+
+// parseDiagnosticLocation parses the prefixes and the location (i.e. the flag
+// and its value) of a line QEMU printed to stderr.
+func parseDiagnosticLocation(
+	line string,
+	program string,
+	guestName string,
+	options []*queso.Option,
+) (Diagnostic, bool) {
+	diagnostic := Diagnostic{}
+
+	if match := timestampPrefix.FindStringSubmatch(line); match != nil {
+		if timestamp, err := time.Parse(time.RFC3339Nano, match[1]); err == nil {
+			diagnostic.Timestamp = timestamp
+		}
+
+		line = line[len(match[0]):]
+	}
+
+	if guestName != "" && strings.HasPrefix(line, guestName+" ") {
+		diagnostic.GuestName = guestName
+		line = line[len(guestName)+1:]
+	}
+
+	if !strings.HasPrefix(line, program+":") {
+		return diagnostic, false
+	}
+
+	diagnostic.Program = program
+	line = strings.TrimPrefix(line[len(program)+1:], " ")
+
+	// QEMU prints the flag exactly as it was passed on the command line, so
+	// the options are checked first, because their values may contain ": ".
+	for _, option := range options {
+		location := strings.Join(option.Args(), " ")
+
+		if strings.HasPrefix(line, location+": ") {
+			diagnostic.Flag = option.Flag
+			diagnostic.Value = strings.TrimPrefix(location, "-"+option.Flag+" ")
+			diagnostic.Message = line[len(location)+2:]
+			diagnostic.Option = option
+
+			if diagnostic.Value == location {
+				diagnostic.Value = ""
+			}
+
+			return diagnostic, true
+		}
+	}
+
+	if match := locationPrefix.FindStringSubmatch(line); match != nil {
+		diagnostic.Flag = match[1]
+		diagnostic.Value = match[2]
+		diagnostic.Message = match[3]
+
+		return diagnostic, true
+	}
+
+	diagnostic.Message = line
+
+	return diagnostic, true
+}
+
+// GuestName returns the guest name set with the Name option (if any).
+func (q *QEMU) GuestName() string {
+	option := q.FindOption("name")
+	if option == nil {
+		return ""
+	}
+
+	// Options parsed with queso.ParseArgs (e.g. by Attach) keep "guest=" as a
+	// property.
+	if guest, ok := option.Table()["guest"]; ok {
+		return guest
+	}
+
+	for _, part := range strings.Split(option.Name, ",") {
+		if strings.HasPrefix(part, "guest=") {
+			return strings.TrimPrefix(part, "guest=")
+		}
+
+		if !strings.Contains(part, "=") {
+			return part
+		}
+	}
+
+	return ""
+}
+
+// Stderr returns the output QEMU wrote to stderr. Only the last 64 KiB are
+// retained.
+func (q *QEMU) Stderr() string {
+	return q.stderr.String()
+}
+
+// wrapError wraps an error returned while running QEMU in an Error if QEMU
+// printed any diagnostics to stderr.
+func (q *QEMU) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	stderr := q.Stderr()
+
+	diagnostics := ParseDiagnostics(stderr, q.exePath, q.GuestName(), q.Options())
+	if len(diagnostics) == 0 {
+		return err
+	}
+
+	return &Error{
+		Err:         err,
+		Diagnostics: diagnostics,
+		Stderr:      stderr,
+	}
+}
+
+const maxStderrSize = 64 * 1024
+
+// tailBuffer is an io.Writer that retains the last maxStderrSize bytes written
+// to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf.Write(p)
+
+	if overflow := b.buf.Len() - maxStderrSize; overflow > 0 {
+		b.buf.Next(overflow)
+	}
+
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
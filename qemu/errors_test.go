@@ -0,0 +1,90 @@
+package qemu
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/mikerourke/queso/qemu/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	queso.TrackBuilders(true)
+	defer queso.TrackBuilders(false)
+
+	options := []*queso.Option{
+		Name("guest=vm1"),
+		network.UserBackend("n",
+			network.WithForwardRule(network.NewHostForwardRule(network.PortTypeTCP, 9000, 22))),
+		device.Use("e1000", device.NewProperty("netdev", "n"), device.NewProperty("foo", "bar")),
+	}
+
+	stderr := "2023-01-02T03:04:05.123456Z vm1 qemu-system-x86_64: -device e1000,netdev=n,foo=bar: Property 'e1000.foo' not found\n" +
+		"qemu-system-x86_64: -netdev user,id=n,hostfwd=tcp::9000-:22: Could not set up host forwarding rule 'tcp::9000-:22'\n" +
+		"qemu-system-x86_64: -machine bogus: unsupported machine type\n" +
+		"Use -machine help to list supported machines\n"
+
+	diagnostics := ParseDiagnostics(stderr, "/usr/bin/qemu-system-x86_64", "vm1", options)
+	assert.Len(t, diagnostics, 3)
+
+	assert.Equal(t, "vm1", diagnostics[0].GuestName)
+	assert.Equal(t, 2023, diagnostics[0].Timestamp.Year())
+	assert.Equal(t, "device", diagnostics[0].Flag)
+	assert.Equal(t, "e1000,netdev=n,foo=bar", diagnostics[0].Value)
+	assert.Equal(t, "Property 'e1000.foo' not found", diagnostics[0].Message)
+	assert.Equal(t, "device.Use", diagnostics[0].Option.Builder)
+
+	assert.Equal(t, "netdev", diagnostics[1].Flag)
+	assert.Equal(t, "network.UserBackend", diagnostics[1].Option.Builder)
+
+	assert.Equal(t, "machine", diagnostics[2].Flag)
+	assert.Equal(t, "bogus", diagnostics[2].Value)
+	assert.Nil(t, diagnostics[2].Option)
+	assert.Equal(t, []string{"Use -machine help to list supported machines"}, diagnostics[2].Hints)
+}
+
+func TestWaitReturnsError(t *testing.T) {
+	q := New("sh")
+	q.SetOptions(queso.NewOption("c", `echo "sh: -device foo: 'foo' is not a valid device model name" >&2; exit 1`))
+
+	err := q.Run()
+
+	var qemuErr *Error
+	assert.True(t, errors.As(err, &qemuErr))
+	assert.Equal(t, "'foo' is not a valid device model name", qemuErr.Diagnostics[0].Message)
+
+	var exitErr *exec.ExitError
+	assert.True(t, errors.As(err, &exitErr))
+}
+
+func TestErrorSkipsWarnings(t *testing.T) {
+	stderr := "qemu-system-x86_64: warning: host doesn't support requested feature: CPUID.01H:ECX.vmx [bit 5]\n" +
+		"qemu-system-x86_64: -machine bogus: unsupported machine type\n"
+
+	diagnostics := ParseDiagnostics(stderr, "qemu-system-x86_64", "", nil)
+	assert.Len(t, diagnostics, 2)
+
+	assert.Equal(t, SeverityWarning, diagnostics[0].Severity)
+	assert.Equal(t, "host doesn't support requested feature: CPUID.01H:ECX.vmx [bit 5]", diagnostics[0].Message)
+	assert.Equal(t, SeverityError, diagnostics[1].Severity)
+
+	err := &Error{Err: errors.New("exit status 1"), Diagnostics: diagnostics}
+	assert.Equal(t, "qemu: exit status 1: -machine bogus: unsupported machine type", err.Error())
+}
+
+func TestGuestName(t *testing.T) {
+	q := New("qemu-system-x86_64")
+	assert.Equal(t, "", q.GuestName())
+
+	q.SetOptions(Name("vm1"))
+	assert.Equal(t, "vm1", q.GuestName())
+
+	q.SetOptions(queso.ParseArgs([]string{"-name", "guest=vm1,process=qemu-vm1", "-m", "1G"})...)
+	assert.Equal(t, "vm1", q.GuestName())
+
+	q.SetOptions(queso.ParseArgs([]string{"-name", "vm2,debug-threads=on"})...)
+	assert.Equal(t, "vm2", q.GuestName())
+}
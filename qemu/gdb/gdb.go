@@ -0,0 +1,674 @@
+// Package gdb is a client for the GDB remote serial protocol, which is used to
+// debug a guest through the gdbstub of QEMU (see debug.OpenGDBOnTCPPort and
+// debug.AcceptGDBConnectionOnDevice). Combined with
+// debug.SkipCPUStartAtStartup, it allows tests to inspect the CPU state from
+// the first instruction. See
+// https://sourceware.org/gdb/current/onlinedocs/gdb.html/Remote-Protocol.html
+// for more details.
+package gdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when a request is sent on a Client whose connection
+// is closed.
+var ErrClosed = errors.New("gdb: connection closed")
+
+// Error is returned when the stub responds to a request with an error
+// ("Exx").
+type Error struct {
+	// Code is the error number.
+	Code int
+}
+
+// Error returns the error number.
+func (e *Error) Error() string {
+	return fmt.Sprintf("gdb: error %02x", e.Code)
+}
+
+// BreakpointType represents the type of a breakpoint or watchpoint.
+type BreakpointType int
+
+const (
+	// BreakpointSoftware is a software breakpoint, which replaces the
+	// instruction at the address.
+	BreakpointSoftware BreakpointType = 0
+
+	// BreakpointHardware is a hardware breakpoint.
+	BreakpointHardware BreakpointType = 1
+
+	// WatchpointWrite stops when the memory is written.
+	WatchpointWrite BreakpointType = 2
+
+	// WatchpointRead stops when the memory is read.
+	WatchpointRead BreakpointType = 3
+
+	// WatchpointAccess stops when the memory is read or written.
+	WatchpointAccess BreakpointType = 4
+)
+
+// Client is a client for the GDB remote serial protocol. A Client is safe for
+// concurrent use, but requests are serialized, so Interrupt must be used to
+// stop a guest that was resumed with Continue.
+type Client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	noAck   bool
+
+	// Features are the features the stub reported in response to qSupported,
+	// such as "PacketSize=1000" or "qXfer:features:read+".
+	Features []string
+}
+
+// Dial connects to the gdbstub at the specified address (e.g. "tcp",
+// "localhost:1234") and negotiates the features of the connection.
+//
+// Example
+//
+//	q := qemu.New("qemu-system-x86_64")
+//	q.SetOptions(
+//		debug.OpenGDBOnTCPPort(),
+//		debug.SkipCPUStartAtStartup())
+//
+//	client, err := gdb.Dial(ctx, "tcp", "localhost:1234")
+//	if err != nil {
+//		return err
+//	}
+//
+//	if err := client.SetBreakpoint(ctx, gdb.BreakpointHardware, 0x7c00, 1); err != nil {
+//		return err
+//	}
+//
+//	stop, err := client.Continue(ctx)
+func Dial(ctx context.Context, network string, address string) (*Client, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(ctx, conn)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// NewClient returns a new Client that communicates over the specified
+// connection. The features are negotiated (see qSupported) and the
+// acknowledgements are disabled if the stub supports it.
+func NewClient(ctx context.Context, conn net.Conn) (*Client, error) {
+	c := &Client{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+
+	reply, err := c.Request(ctx, "qSupported:swbreak+;hwbreak+")
+	if err != nil {
+		return nil, err
+	}
+
+	c.Features = strings.Split(reply, ";")
+
+	if c.supports("QStartNoAckMode+") {
+		if _, err := c.Request(ctx, "QStartNoAckMode"); err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.noAck = true
+		c.mu.Unlock()
+	}
+
+	return c, nil
+}
+
+func (c *Client) supports(feature string) bool {
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close closes the connection. The guest keeps its current state, so use
+// Detach to resume it first.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Request sends a packet with the specified data and returns the data of the
+// reply. Replies of the form "Exx" are returned as an *Error. An empty reply
+// means the request isn't supported by the stub.
+func (c *Client) Request(ctx context.Context, data string) (string, error) {
+	return c.request(ctx, data, false, nil)
+}
+
+// recoverTimeout is how long the reply of a cancelled request is waited for
+// before the connection is closed.
+const recoverTimeout = 5 * time.Second
+
+// request sends a request and returns its reply. If the context is cancelled
+// while the reply is pending, the reply is discarded (after interrupting the
+// guest if the request resumed it), so it isn't mistaken for the reply of the
+// next request. The console output sent before the reply is written to output
+// (if not nil).
+func (c *Client) request(ctx context.Context, data string, resume bool, output io.Writer) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	reply, err := c.roundTripContext(ctx, []byte(data), output)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, os.ErrDeadlineExceeded) {
+			c.recover(resume)
+
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+
+			return "", context.DeadlineExceeded
+		}
+
+		return "", err
+	}
+
+	if len(reply) == 3 && reply[0] == 'E' {
+		if code, err := strconv.ParseUint(reply[1:], 16, 8); err == nil {
+			return "", &Error{Code: int(code)}
+		}
+	}
+
+	return reply, nil
+}
+
+// roundTripContext is roundTrip with the deadline of the context. The
+// connection is interrupted when the context is cancelled, so a request
+// waiting for a stop doesn't block forever. The deadline is reset afterwards.
+func (c *Client) roundTripContext(ctx context.Context, data []byte, output io.Writer) (string, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetDeadline(deadline)
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		select {
+		case <-ctx.Done():
+			_ = c.conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	reply, err := c.roundTrip(data, output)
+
+	close(stop)
+	<-stopped
+
+	_ = c.conn.SetDeadline(time.Time{})
+
+	return reply, err
+}
+
+// recover reads and discards the pending reply of a cancelled request. If the
+// request resumed the guest, the guest is interrupted first, so the stub sends
+// the stop reply. If the reply can't be read, the connection is closed, so
+// later requests fail with ErrClosed instead of reading the wrong reply.
+func (c *Client) recover(interrupt bool) {
+	_ = c.conn.SetDeadline(time.Now().Add(recoverTimeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	if interrupt {
+		if err := c.write([]byte{0x03}); err != nil {
+			_ = c.conn.Close()
+
+			return
+		}
+	}
+
+	for {
+		reply, err := readPacket(c.reader)
+		if err != nil {
+			_ = c.conn.Close()
+
+			return
+		}
+
+		if !c.noAck {
+			if err := c.write([]byte{'+'}); err != nil {
+				_ = c.conn.Close()
+
+				return
+			}
+		}
+
+		if len(reply) > 1 && reply[0] == 'O' && string(reply) != "OK" {
+			continue
+		}
+
+		return
+	}
+}
+
+// roundTrip writes a packet and reads the reply. Packets are retransmitted if
+// the stub doesn't acknowledge them, and corrupt replies are requested again.
+// The console output of the stub is written to output (if not nil).
+func (c *Client) roundTrip(data []byte, output io.Writer) (string, error) {
+	packet := encodePacket(data)
+
+	// An invalid "O" packet is only reported once the reply is read, so the
+	// connection stays in sync.
+	var outputErr error
+
+	for attempt := 0; ; attempt++ {
+		if err := c.write(packet); err != nil {
+			return "", err
+		}
+
+		if !c.noAck {
+			ack, err := c.reader.ReadByte()
+			if err != nil {
+				return "", c.wrapError(err)
+			}
+
+			if ack == '-' && attempt < 3 {
+				continue
+			}
+		}
+
+		for {
+			reply, err := readPacket(c.reader)
+			if errors.Is(err, errChecksum) && !c.noAck {
+				if err := c.write([]byte{'-'}); err != nil {
+					return "", err
+				}
+
+				continue
+			}
+
+			if err != nil {
+				return "", c.wrapError(err)
+			}
+
+			if !c.noAck {
+				if err := c.write([]byte{'+'}); err != nil {
+					return "", err
+				}
+			}
+
+			// Console output of the stub ("O" packets) isn't the reply, but
+			// "OK" is.
+			if len(reply) > 1 && reply[0] == 'O' && string(reply) != "OK" {
+				if output != nil && outputErr == nil {
+					text, err := hex.DecodeString(string(reply[1:]))
+					if err != nil {
+						outputErr = fmt.Errorf("gdb: invalid console output %q", reply)
+					}
+
+					_, _ = output.Write(text)
+				}
+
+				continue
+			}
+
+			if outputErr != nil {
+				return "", outputErr
+			}
+
+			return string(reply), nil
+		}
+	}
+}
+
+func (c *Client) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := c.conn.Write(data); err != nil {
+		return c.wrapError(err)
+	}
+
+	return nil
+}
+
+func (c *Client) wrapError(err error) error {
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+		return ErrClosed
+	}
+
+	return err
+}
+
+// Interrupt stops the guest while it is running (after Continue). The pending
+// Continue returns the stop reply.
+func (c *Client) Interrupt() error {
+	return c.write([]byte{0x03})
+}
+
+// requestOK sends a request that is expected to be answered with "OK".
+func (c *Client) requestOK(ctx context.Context, data string) error {
+	reply, err := c.Request(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	if reply == "" {
+		return fmt.Errorf("gdb: request %s is not supported", strings.SplitN(data, ":", 2)[0])
+	}
+
+	if reply != "OK" {
+		return fmt.Errorf("gdb: unexpected reply %q", reply)
+	}
+
+	return nil
+}
+
+// ReadRegisters reads all general registers of the current thread (see
+// SelectThread) in target byte order. The layout depends on the architecture
+// (see X86_64Registers).
+func (c *Client) ReadRegisters(ctx context.Context) ([]byte, error) {
+	reply, err := c.Request(ctx, "g")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHex(reply)
+}
+
+// WriteRegisters writes all general registers of the current thread.
+func (c *Client) WriteRegisters(ctx context.Context, data []byte) error {
+	return c.requestOK(ctx, "G"+hex.EncodeToString(data))
+}
+
+// ReadRegister reads the register with the specified number (in the numbering
+// of the target description) in target byte order.
+func (c *Client) ReadRegister(ctx context.Context, number int) ([]byte, error) {
+	reply, err := c.Request(ctx, fmt.Sprintf("p%x", number))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHex(reply)
+}
+
+// WriteRegister writes the register with the specified number.
+func (c *Client) WriteRegister(ctx context.Context, number int, value []byte) error {
+	return c.requestOK(ctx, fmt.Sprintf("P%x=%s", number, hex.EncodeToString(value)))
+}
+
+// ReadMemory reads length bytes of guest memory at the specified virtual
+// address of the current thread. Large reads are split into several requests,
+// so each reply fits in a packet (see PacketSize in Features).
+func (c *Client) ReadMemory(ctx context.Context, address uint64, length int) ([]byte, error) {
+	// The reply encodes each byte as two hex digits.
+	chunk := c.packetSize() / 2
+
+	memory := make([]byte, 0, length)
+
+	for len(memory) < length {
+		size := length - len(memory)
+		if size > chunk {
+			size = chunk
+		}
+
+		reply, err := c.Request(ctx, fmt.Sprintf("m%x,%x", address+uint64(len(memory)), size))
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := decodeHex(reply)
+		if err != nil {
+			return nil, err
+		}
+
+		// The stub may return fewer bytes than requested.
+		if len(data) == 0 {
+			return nil, fmt.Errorf("gdb: no memory read at %#x", address+uint64(len(memory)))
+		}
+
+		memory = append(memory, data...)
+	}
+
+	return memory, nil
+}
+
+// WriteMemory writes the data to guest memory at the specified virtual
+// address. Large writes are split into several requests, so each request fits
+// in a packet (see PacketSize in Features).
+func (c *Client) WriteMemory(ctx context.Context, address uint64, data []byte) error {
+	maxSize := c.packetSize()
+
+	for offset := 0; offset < len(data); {
+		// The length in the header is at most the packet size, so the header
+		// isn't longer than the one of the actual request.
+		header := fmt.Sprintf("M%x,%x:", address+uint64(offset), maxSize)
+
+		size := (maxSize - len(header)) / 2
+		if size < 1 {
+			size = 1
+		}
+
+		if size > len(data)-offset {
+			size = len(data) - offset
+		}
+
+		request := fmt.Sprintf("M%x,%x:%s", address+uint64(offset), size, hex.EncodeToString(data[offset:offset+size]))
+		if err := c.requestOK(ctx, request); err != nil {
+			return err
+		}
+
+		offset += size
+	}
+
+	return nil
+}
+
+// defaultPacketSize is the packet size that is assumed if the stub doesn't
+// report one.
+const defaultPacketSize = 0x190
+
+// packetSize returns the maximum length of a packet the stub accepts, as
+// reported with the PacketSize feature.
+func (c *Client) packetSize() int {
+	for _, feature := range c.Features {
+		if !strings.HasPrefix(feature, "PacketSize=") {
+			continue
+		}
+
+		size, err := strconv.ParseUint(strings.TrimPrefix(feature, "PacketSize="), 16, 31)
+		if err == nil && size >= 2 {
+			return int(size)
+		}
+	}
+
+	return defaultPacketSize
+}
+
+// SetBreakpoint sets a breakpoint or watchpoint of the specified type at the
+// address. For breakpoints, kind is the size of the breakpoint instruction
+// (e.g. 1 on x86); for watchpoints, it is the number of bytes to watch.
+func (c *Client) SetBreakpoint(ctx context.Context, breakpointType BreakpointType, address uint64, kind int) error {
+	return c.requestOK(ctx, fmt.Sprintf("Z%d,%x,%x", breakpointType, address, kind))
+}
+
+// RemoveBreakpoint removes a breakpoint or watchpoint set with SetBreakpoint.
+func (c *Client) RemoveBreakpoint(
+	ctx context.Context,
+	breakpointType BreakpointType,
+	address uint64,
+	kind int,
+) error {
+	return c.requestOK(ctx, fmt.Sprintf("z%d,%x,%x", breakpointType, address, kind))
+}
+
+// Continue resumes the guest and waits until it stops (e.g. at a breakpoint,
+// or after Interrupt). If the context is cancelled first, the guest is
+// interrupted and the stop is discarded.
+func (c *Client) Continue(ctx context.Context) (Stop, error) {
+	return c.resume(ctx, "c")
+}
+
+// Step executes a single instruction on the current thread and returns the
+// resulting stop.
+func (c *Client) Step(ctx context.Context) (Stop, error) {
+	return c.resume(ctx, "s")
+}
+
+func (c *Client) resume(ctx context.Context, command string) (Stop, error) {
+	reply, err := c.request(ctx, command, true, nil)
+	if err != nil {
+		return Stop{}, err
+	}
+
+	return ParseStop(reply)
+}
+
+// StopReason returns the reason the guest last stopped (see "?").
+func (c *Client) StopReason(ctx context.Context) (Stop, error) {
+	reply, err := c.Request(ctx, "?")
+	if err != nil {
+		return Stop{}, err
+	}
+
+	return ParseStop(reply)
+}
+
+// Threads returns the IDs of the threads, which are the vCPUs of the guest
+// (the first vCPU is thread 1).
+func (c *Client) Threads(ctx context.Context) ([]int, error) {
+	threads := make([]int, 0)
+
+	request := "qfThreadInfo"
+
+	for {
+		reply, err := c.Request(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		if reply == "l" || reply == "" {
+			return threads, nil
+		}
+
+		if !strings.HasPrefix(reply, "m") {
+			return nil, fmt.Errorf("gdb: unexpected thread info %q", reply)
+		}
+
+		for _, id := range strings.Split(reply[1:], ",") {
+			thread, err := parseThreadID(id)
+			if err != nil {
+				return nil, err
+			}
+
+			threads = append(threads, thread)
+		}
+
+		request = "qsThreadInfo"
+	}
+}
+
+// CurrentThread returns the ID of the current thread.
+func (c *Client) CurrentThread(ctx context.Context) (int, error) {
+	reply, err := c.Request(ctx, "qC")
+	if err != nil {
+		return 0, err
+	}
+
+	if !strings.HasPrefix(reply, "QC") {
+		return 0, fmt.Errorf("gdb: unexpected current thread %q", reply)
+	}
+
+	return parseThreadID(reply[2:])
+}
+
+// SelectThread selects the thread (vCPU) used for register and memory access,
+// as well as for Step and Continue.
+func (c *Client) SelectThread(ctx context.Context, thread int) error {
+	if err := c.requestOK(ctx, fmt.Sprintf("Hg%x", thread)); err != nil {
+		return err
+	}
+
+	return c.requestOK(ctx, fmt.Sprintf("Hc%x", thread))
+}
+
+// Detach resumes the guest and ends the debugging session. The connection is
+// closed.
+func (c *Client) Detach(ctx context.Context) error {
+	if err := c.requestOK(ctx, "D"); err != nil {
+		return err
+	}
+
+	return c.Close()
+}
+
+// Monitor executes a QEMU monitor command (such as "info registers") through
+// the stub and returns its output. If the context is cancelled, the remaining
+// output is discarded.
+func (c *Client) Monitor(ctx context.Context, command string) (string, error) {
+	output := new(strings.Builder)
+
+	// The output is sent as "O" packets before the final "OK".
+	reply, err := c.request(ctx, "qRcmd,"+hex.EncodeToString([]byte(command)), false, output)
+	if err != nil {
+		return "", err
+	}
+
+	if reply != "OK" {
+		return "", fmt.Errorf("gdb: unexpected monitor reply %q", reply)
+	}
+
+	return output.String(), nil
+}
+
+func parseThreadID(id string) (int, error) {
+	// Multiprocess thread IDs have the form "pPID.TID".
+	if strings.HasPrefix(id, "p") {
+		if dot := strings.Index(id, "."); dot != -1 {
+			id = id[dot+1:]
+		}
+	}
+
+	thread, err := strconv.ParseInt(id, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("gdb: invalid thread ID %q", id)
+	}
+
+	return int(thread), nil
+}
+
+func decodeHex(reply string) ([]byte, error) {
+	// Registers that are unavailable are reported as "xx".
+	data, err := hex.DecodeString(strings.ReplaceAll(reply, "x", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("gdb: invalid hex data: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,252 @@
+package gdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serve emulates a gdbstub with two vCPUs that stops at a breakpoint. If
+// running is true, the guest only stops when it is interrupted, and the monitor
+// is slow to finish its output.
+func serve(t *testing.T, conn net.Conn, running bool) {
+	t.Helper()
+
+	registers := make([]byte, 17*8+7*4)
+	binary.LittleEndian.PutUint64(registers[RegisterRIP*8:], 0x7c00)
+
+	replies := map[string]string{
+		"qSupported:swbreak+;hwbreak+": "PacketSize=1000;qXfer:features:read+;QStartNoAckMode+",
+		"QStartNoAckMode":              "OK",
+		"g":                            hex.EncodeToString(registers),
+		"m7c00,4":                      "fa31c08e",
+		"Z1,7c00,1":                    "OK",
+		"c":                            "T05thread:02;hwbreak:;",
+		"qfThreadInfo":                 "m01,02",
+		"qsThreadInfo":                 "l",
+		"m0,4":                         "E14",
+	}
+
+	go func() {
+		reader := bufio.NewReader(conn)
+		noAck := false
+
+		for {
+			if b, err := reader.Peek(1); err == nil && b[0] == 0x03 {
+				_, _ = reader.ReadByte()
+				_, _ = conn.Write(encodePacket([]byte("T02thread:01;")))
+
+				continue
+			}
+
+			request, err := readPacket(reader)
+			if err != nil {
+				return
+			}
+
+			if !noAck {
+				_, _ = conn.Write([]byte{'+'})
+			}
+
+			if string(request) == "c" && running {
+				continue
+			}
+
+			reply := replies[string(request)]
+
+			if strings.HasPrefix(string(request), "qRcmd,") {
+				_, _ = conn.Write(encodePacket([]byte("O" + hex.EncodeToString([]byte("RIP=0000000000007c00\n")))))
+
+				if running {
+					time.Sleep(200 * time.Millisecond)
+				}
+
+				reply = "OK"
+			}
+
+			_, _ = conn.Write(encodePacket([]byte(reply)))
+
+			if string(request) == "QStartNoAckMode" {
+				// The ack of the reply is the last one.
+				_, _ = reader.ReadByte()
+				noAck = true
+			}
+		}
+	}()
+}
+
+func TestClient(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+
+	serve(t, server, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, conn)
+	assert.NoError(t, err)
+	assert.Contains(t, client.Features, "qXfer:features:read+")
+
+	data, err := client.ReadRegisters(ctx)
+	assert.NoError(t, err)
+
+	registers, err := X86_64Registers(data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x7c00), registers["rip"])
+
+	memory, err := client.ReadMemory(ctx, 0x7c00, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfa, 0x31, 0xc0, 0x8e}, memory)
+
+	_, err = client.ReadMemory(ctx, 0, 4)
+	assert.Equal(t, &Error{Code: 0x14}, err)
+
+	assert.NoError(t, client.SetBreakpoint(ctx, BreakpointHardware, 0x7c00, 1))
+
+	stop, err := client.Continue(ctx)
+	assert.NoError(t, err)
+	assert.True(t, stop.IsBreakpoint())
+	assert.Equal(t, 2, stop.Thread)
+
+	threads, err := client.Threads(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, threads)
+
+	output, err := client.Monitor(ctx, "info registers")
+	assert.NoError(t, err)
+	assert.Equal(t, "RIP=0000000000007c00\n", output)
+}
+
+func TestContinueCancel(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+
+	serve(t, server, true)
+
+	client, err := NewClient(context.Background(), conn)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Continue(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The guest was interrupted and its stop was discarded, so the next
+	// request (without a deadline) gets its own reply.
+	memory, err := client.ReadMemory(context.Background(), 0x7c00, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfa, 0x31, 0xc0, 0x8e}, memory)
+}
+
+func TestMonitorCancel(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+
+	serve(t, server, true)
+
+	client, err := NewClient(context.Background(), conn)
+	assert.NoError(t, err)
+
+	// The context has no deadline, so only the cancellation ends the request.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err = client.Monitor(ctx, "info registers")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The rest of the output and the "OK" were discarded, so the next request
+	// gets its own reply.
+	memory, err := client.ReadMemory(context.Background(), 0x7c00, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfa, 0x31, 0xc0, 0x8e}, memory)
+}
+
+func TestMemoryChunks(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+
+	// The stub accepts packets of up to 64 bytes and records the length of
+	// each request and reply.
+	memory := make([]byte, 100)
+	lengths := make(chan int, 100)
+
+	go func() {
+		reader := bufio.NewReader(server)
+
+		for {
+			request, err := readPacket(reader)
+			if err != nil {
+				return
+			}
+
+			_, _ = server.Write([]byte{'+'})
+
+			var address, length int
+
+			reply := "PacketSize=40"
+
+			switch request[0] {
+			case 'm':
+				_, _ = fmt.Sscanf(string(request), "m%x,%x", &address, &length)
+				reply = hex.EncodeToString(memory[address-0x1000 : address-0x1000+length])
+
+			case 'M':
+				_, _ = fmt.Sscanf(string(request), "M%x,%x:", &address, &length)
+				data, _ := hex.DecodeString(string(request[strings.Index(string(request), ":")+1:]))
+				copy(memory[address-0x1000:], data)
+				reply = "OK"
+			}
+
+			lengths <- len(request)
+			lengths <- len(reply)
+
+			_, _ = server.Write(encodePacket([]byte(reply)))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, conn)
+	assert.NoError(t, err)
+
+	data := make([]byte, len(memory))
+	for index := range data {
+		data[index] = byte(index)
+	}
+
+	assert.NoError(t, client.WriteMemory(ctx, 0x1000, data))
+	assert.Equal(t, data, memory)
+
+	read, err := client.ReadMemory(ctx, 0x1000, len(data))
+	assert.NoError(t, err)
+	assert.Equal(t, data, read)
+
+	close(lengths)
+
+	for length := range lengths {
+		assert.LessOrEqual(t, length, 0x40)
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	data, err := decodeData([]byte("0* }]"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0000}", string(data))
+
+	_, err = decodeData([]byte("0*\x1c"))
+	assert.Error(t, err)
+
+	packet := encodePacket([]byte("M0,1:$"))
+	assert.True(t, strings.HasPrefix(string(packet), "$M0,1:}\x04#"))
+}
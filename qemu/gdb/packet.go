@@ -0,0 +1,152 @@
+package gdb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// encodePacket frames the data of a packet as "$data#checksum". The special
+// characters in the data are escaped.
+func encodePacket(data []byte) []byte {
+	escaped := escape(data)
+
+	var sum byte
+	for _, b := range escaped {
+		sum += b
+	}
+
+	packet := make([]byte, 0, len(escaped)+4)
+	packet = append(packet, '$')
+	packet = append(packet, escaped...)
+	packet = append(packet, '#')
+	packet = append(packet, fmt.Sprintf("%02x", sum)...)
+
+	return packet
+}
+
+// escape escapes the characters that have a special meaning in packets.
+func escape(data []byte) []byte {
+	escaped := make([]byte, 0, len(data))
+
+	for _, b := range data {
+		switch b {
+		case '#', '$', '}', '*':
+			escaped = append(escaped, '}', b^0x20)
+
+		default:
+			escaped = append(escaped, b)
+		}
+	}
+
+	return escaped
+}
+
+// decodeData unescapes the data of a packet and expands run-length encoded
+// sequences ("x*n" repeats x n-29 more times).
+func decodeData(data []byte) ([]byte, error) {
+	decoded := make([]byte, 0, len(data))
+
+	for index := 0; index < len(data); index++ {
+		b := data[index]
+
+		switch b {
+		case '}':
+			index++
+			if index == len(data) {
+				return nil, errors.New("gdb: truncated escape sequence")
+			}
+
+			decoded = append(decoded, data[index]^0x20)
+
+		case '*':
+			index++
+			if index == len(data) || len(decoded) == 0 {
+				return nil, errors.New("gdb: invalid run-length encoding")
+			}
+
+			count := int(data[index]) - 29
+			if count < 0 {
+				return nil, fmt.Errorf("gdb: invalid run-length count %q", data[index])
+			}
+
+			last := decoded[len(decoded)-1]
+			decoded = append(decoded, bytes.Repeat([]byte{last}, count)...)
+
+		default:
+			decoded = append(decoded, b)
+		}
+	}
+
+	return decoded, nil
+}
+
+// readPacket reads the next packet and returns its decoded data. Acks and
+// notifications are skipped. If the checksum is invalid, errChecksum is
+// returned, so the packet can be requested again.
+func readPacket(reader *bufio.Reader) ([]byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch b {
+		case '$':
+
+		case '%':
+			// Notifications are not supported, so they are discarded.
+			if _, _, err := readFrame(reader); err != nil {
+				return nil, err
+			}
+
+			continue
+
+		default:
+			// Acks ('+' and '-') and noise are skipped.
+			continue
+		}
+
+		data, sum, err := readFrame(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		var actual byte
+		for _, c := range data {
+			actual += c
+		}
+
+		if actual != sum {
+			return nil, errChecksum
+		}
+
+		return decodeData(data)
+	}
+}
+
+var errChecksum = errors.New("gdb: invalid checksum")
+
+// readFrame reads the data and checksum of a packet after the start
+// character.
+func readFrame(reader *bufio.Reader) ([]byte, byte, error) {
+	data, err := reader.ReadBytes('#')
+	if err != nil {
+		return nil, 0, err
+	}
+
+	checksum := make([]byte, 2)
+	if _, err := io.ReadFull(reader, checksum); err != nil {
+		return nil, 0, err
+	}
+
+	sum, err := strconv.ParseUint(string(checksum), 16, 8)
+	if err != nil {
+		return nil, 0, fmt.Errorf("gdb: invalid checksum %q", checksum)
+	}
+
+	return data[:len(data)-1], byte(sum), nil
+}
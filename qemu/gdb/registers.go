@@ -0,0 +1,53 @@
+package gdb
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// x86_64RegisterNames are the names of the general registers at the start of
+// the register data of the QEMU gdbstub for x86_64, each 8 bytes long.
+var x86_64RegisterNames = []string{
+	"rax", "rbx", "rcx", "rdx", "rsi", "rdi", "rbp", "rsp",
+	"r8", "r9", "r10", "r11", "r12", "r13", "r14", "r15", "rip",
+}
+
+// x86_64SegmentNames are the names of the registers after rip, each 4 bytes
+// long.
+var x86_64SegmentNames = []string{"eflags", "cs", "ss", "ds", "es", "fs", "gs"}
+
+// RegisterRIP is the number of the rip register on x86_64 (see ReadRegister).
+const RegisterRIP = 16
+
+// X86_64Registers decodes the general registers of an x86_64 guest from the
+// data returned by ReadRegisters.
+//
+// Example
+//
+//	data, err := client.ReadRegisters(ctx)
+//	if err != nil {
+//		return err
+//	}
+//
+//	registers, err := gdb.X86_64Registers(data)
+//	fmt.Printf("rip=%#x\n", registers["rip"])
+func X86_64Registers(data []byte) (map[string]uint64, error) {
+	size := len(x86_64RegisterNames)*8 + len(x86_64SegmentNames)*4
+	if len(data) < size {
+		return nil, fmt.Errorf("gdb: register data has %d bytes instead of at least %d", len(data), size)
+	}
+
+	registers := make(map[string]uint64)
+
+	for index, name := range x86_64RegisterNames {
+		registers[name] = binary.LittleEndian.Uint64(data[index*8:])
+	}
+
+	offset := len(x86_64RegisterNames) * 8
+
+	for index, name := range x86_64SegmentNames {
+		registers[name] = uint64(binary.LittleEndian.Uint32(data[offset+index*4:]))
+	}
+
+	return registers, nil
+}
@@ -0,0 +1,115 @@
+package gdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StopKind represents the kind of stop reply.
+type StopKind string
+
+const (
+	// StopSignal means the guest stopped with a signal, such as SIGTRAP at a
+	// breakpoint or after a step, or SIGINT after Interrupt.
+	StopSignal StopKind = "signal"
+
+	// StopExited means the guest (or QEMU) exited.
+	StopExited StopKind = "exited"
+
+	// StopTerminated means the guest was terminated with a signal.
+	StopTerminated StopKind = "terminated"
+)
+
+// Common signals reported by the QEMU gdbstub.
+const (
+	SignalInterrupt = 2
+	SignalTrap      = 5
+)
+
+// Stop is a stop reply, which describes why the guest stopped.
+type Stop struct {
+	// Kind is the kind of stop.
+	Kind StopKind
+
+	// Signal is the signal number for StopSignal and StopTerminated, or the
+	// exit status for StopExited.
+	Signal int
+
+	// Thread is the thread (vCPU) that stopped, if reported.
+	Thread int
+
+	// Watchpoint is the kind of watchpoint that was hit ("watch", "rwatch" or
+	// "awatch"), if any.
+	Watchpoint string
+
+	// Address is the data address that triggered the Watchpoint.
+	Address uint64
+
+	// Info contains all "key:value" pairs of the stop reply.
+	Info map[string]string
+}
+
+// IsBreakpoint returns true if the guest stopped at a breakpoint, watchpoint,
+// or after a step.
+func (s Stop) IsBreakpoint() bool {
+	return s.Kind == StopSignal && s.Signal == SignalTrap
+}
+
+// ParseStop parses a stop reply, such as "T05thread:01;" or "W00".
+func ParseStop(reply string) (Stop, error) {
+	if len(reply) < 3 {
+		return Stop{}, fmt.Errorf("gdb: invalid stop reply %q", reply)
+	}
+
+	signal, err := strconv.ParseUint(reply[1:3], 16, 8)
+	if err != nil {
+		return Stop{}, fmt.Errorf("gdb: invalid stop reply %q", reply)
+	}
+
+	stop := Stop{
+		Signal: int(signal),
+		Info:   make(map[string]string),
+	}
+
+	switch reply[0] {
+	case 'S', 'T':
+		stop.Kind = StopSignal
+
+	case 'W':
+		stop.Kind = StopExited
+
+	case 'X':
+		stop.Kind = StopTerminated
+
+	default:
+		return Stop{}, fmt.Errorf("gdb: invalid stop reply %q", reply)
+	}
+
+	if reply[0] != 'T' {
+		return stop, nil
+	}
+
+	for _, pair := range strings.Split(reply[3:], ";") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key, value := parts[0], parts[1]
+		stop.Info[key] = value
+
+		switch key {
+		case "thread":
+			if thread, err := parseThreadID(value); err == nil {
+				stop.Thread = thread
+			}
+
+		case "watch", "rwatch", "awatch":
+			stop.Watchpoint = key
+			stop.Address, _ = strconv.ParseUint(value, 16, 64)
+		}
+	}
+
+	return stop, nil
+}
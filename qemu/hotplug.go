@@ -0,0 +1,485 @@
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/mikerourke/queso/qemu/object"
+)
+
+// HotpluggableCPU represents a CPU slot reported by query-hotpluggable-cpus.
+type HotpluggableCPU struct {
+	// Type is the CPU device type to use with device_add.
+	Type string `json:"type"`
+
+	// VCPUsCount is the number of vCPUs the slot contains.
+	VCPUsCount int `json:"vcpus-count"`
+
+	// Props are the topology properties (such as "socket-id", "core-id" and
+	// "thread-id") that must be passed to device_add to plug the slot.
+	Props map[string]int `json:"props"`
+
+	// QOMPath is the QOM path of the CPU plugged into the slot, or an empty
+	// string if the slot is free.
+	QOMPath string `json:"qom-path,omitempty"`
+}
+
+// Plugged returns true if a CPU is plugged into the slot.
+func (c HotpluggableCPU) Plugged() bool {
+	return c.QOMPath != ""
+}
+
+// id returns the device ID for a CPU plugged into the slot, which is derived
+// from the topology properties (e.g. "cpu-s0-c1-t0").
+func (c HotpluggableCPU) id() string {
+	parts := []string{"cpu"}
+
+	for _, key := range []string{"node-id", "drawer-id", "book-id", "socket-id", "die-id",
+		"cluster-id", "core-id", "thread-id"} {
+		if value, ok := c.Props[key]; ok {
+			parts = append(parts, key[:1]+strconv.Itoa(value))
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// HotpluggableCPUs returns the CPU slots of the running VM (see
+// query-hotpluggable-cpus). The number of slots is determined by the
+// WithMaxCPUs property of the SMP option.
+func (q *QEMU) HotpluggableCPUs(ctx context.Context) ([]HotpluggableCPU, error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cpus := make([]HotpluggableCPU, 0)
+
+	if err := client.Execute(ctx, "query-hotpluggable-cpus", nil, &cpus); err != nil {
+		return nil, err
+	}
+
+	return cpus, nil
+}
+
+// AddVCPUs plugs CPUs into the free CPU slots until at least count vCPUs are
+// added, and returns the device IDs of the new CPUs. The slots are filled in
+// the order reported by QEMU. An error is returned if there aren't enough
+// free slots (see WithMaxCPUs).
+func (q *QEMU) AddVCPUs(ctx context.Context, count int) ([]string, error) {
+	if count < 1 {
+		return nil, errors.New("the number of vCPUs to add must be at least 1")
+	}
+
+	cpus, err := q.HotpluggableCPUs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make([]HotpluggableCPU, 0)
+	available := 0
+
+	// QEMU lists the slots from the highest to the lowest topology IDs, so the
+	// list is reversed to plug the lowest IDs first.
+	for index := len(cpus) - 1; index >= 0 && available < count; index-- {
+		if !cpus[index].Plugged() {
+			free = append(free, cpus[index])
+			available += cpus[index].VCPUsCount
+		}
+	}
+
+	if available < count {
+		return nil, fmt.Errorf("cannot add %d vCPUs: only %d vCPUs are available for hotplug "+
+			"(see WithMaxCPUs)", count, available)
+	}
+
+	ids := make([]string, 0, len(free))
+
+	for _, cpu := range free {
+		args := map[string]interface{}{
+			"driver": cpu.Type,
+			"id":     cpu.id(),
+		}
+
+		for key, value := range cpu.Props {
+			args[key] = value
+		}
+
+		if err := q.QMP().Execute(ctx, "device_add", args, nil); err != nil {
+			return ids, fmt.Errorf("failed to add CPU %s: %w", cpu.id(), err)
+		}
+
+		ids = append(ids, cpu.id())
+	}
+
+	return ids, nil
+}
+
+// RemoveVCPU unplugs the CPU with the specified device ID (as returned by
+// AddVCPUs) and waits for the guest to release it. Only CPUs added at runtime
+// can be removed.
+func (q *QEMU) RemoveVCPU(ctx context.Context, id string) error {
+	return q.deleteDevice(ctx, id)
+}
+
+// MemoryHotplugOptions represent the options for AddMemory.
+type MemoryHotplugOptions struct {
+	// ID is the device ID of the memory device. The ID of the memory backend
+	// is the ID with a "-backend" suffix. If omitted, an ID is generated.
+	ID string
+
+	// VirtioMem adds a virtio-mem device instead of a pc-dimm. The size of a
+	// virtio-mem device can be changed with ResizeMemory, and it doesn't use
+	// any of the slots specified with WithMemorySlots.
+	VirtioMem bool
+
+	// Node is the NUMA node the memory is assigned to.
+	Node int
+}
+
+// MemoryDevice represents a memory device reported by query-memory-devices.
+type MemoryDevice struct {
+	// Type is the type of the memory device (e.g. "dimm" or "virtio-mem").
+	Type string
+
+	// ID is the device ID of the memory device.
+	ID string
+
+	// Size is the size of the memory device in bytes.
+	Size int64
+
+	// Memdev is the QOM path of the memory backend.
+	Memdev string
+}
+
+// MemoryDevices returns the memory devices of the running VM (see
+// query-memory-devices).
+func (q *QEMU) MemoryDevices(ctx context.Context) ([]MemoryDevice, error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []struct {
+		Type string `json:"type"`
+		Data struct {
+			ID     string `json:"id"`
+			Size   int64  `json:"size"`
+			Memdev string `json:"memdev"`
+		} `json:"data"`
+	}
+
+	if err := client.Execute(ctx, "query-memory-devices", nil, &infos); err != nil {
+		return nil, err
+	}
+
+	devices := make([]MemoryDevice, 0, len(infos))
+
+	for _, info := range infos {
+		devices = append(devices, MemoryDevice{
+			Type:   info.Type,
+			ID:     info.Data.ID,
+			Size:   info.Data.Size,
+			Memdev: info.Data.Memdev,
+		})
+	}
+
+	return devices, nil
+}
+
+// AddMemory adds the specified amount of memory (in bytes) to the running VM
+// and returns the device ID of the new memory device. The memory is backed by
+// an object.MemoryBackendRAM and plugged with a pc-dimm (or virtio-mem) device.
+// An error is returned if the memory would exceed the limits set with the
+// WithMemoryMaximum and WithMemorySlots properties of the Memory option.
+func (q *QEMU) AddMemory(ctx context.Context, size int64, opts MemoryHotplugOptions) (string, error) {
+	devices, err := q.MemoryDevices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := q.checkMemoryLimits(devices, size, !opts.VirtioMem); err != nil {
+		return "", err
+	}
+
+	id := opts.ID
+	if id == "" {
+		id = unusedMemoryID(devices)
+	}
+
+	backend := object.MemoryBackendRAM(id+"-backend",
+		object.WithMemorySize(strconv.FormatInt(size, 10)))
+
+	backendArgs, err := objectArgs(backend)
+	if err != nil {
+		return "", err
+	}
+
+	if err := q.QMP().Execute(ctx, "object-add", backendArgs, nil); err != nil {
+		return "", fmt.Errorf("failed to add memory backend: %w", err)
+	}
+
+	var memoryDevice *queso.Option
+
+	if opts.VirtioMem {
+		memoryDevice = device.Use("virtio-mem-pci",
+			device.WithID(id),
+			device.NewProperty("memdev", id+"-backend"),
+			device.NewProperty("node", opts.Node),
+			device.NewProperty("requested-size", size))
+	} else {
+		memoryDevice = device.Use("pc-dimm",
+			device.WithID(id),
+			device.NewProperty("memdev", id+"-backend"),
+			device.NewProperty("node", opts.Node))
+	}
+
+	if err := q.QMP().Execute(ctx, "device_add", deviceArgs(memoryDevice), nil); err != nil {
+		_ = q.QMP().Execute(ctx, "object-del", map[string]interface{}{"id": id + "-backend"}, nil)
+
+		return "", fmt.Errorf("failed to add memory device: %w", err)
+	}
+
+	return id, nil
+}
+
+// unusedMemoryID returns the first ID in the form "memN" that isn't used by any
+// of the devices.
+func unusedMemoryID(devices []MemoryDevice) string {
+	for index := 0; ; index++ {
+		id := fmt.Sprintf("mem%d", index)
+		used := false
+
+		for _, device := range devices {
+			used = used || device.ID == id
+		}
+
+		if !used {
+			return id
+		}
+	}
+}
+
+// ResizeMemory changes the size (in bytes) of the virtio-mem device with the
+// specified ID, which must not exceed the size of the device it was added with.
+func (q *QEMU) ResizeMemory(ctx context.Context, id string, size int64) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := map[string]interface{}{
+		"path":     "/machine/peripheral/" + id,
+		"property": "requested-size",
+		"value":    size,
+	}
+
+	return client.Execute(ctx, "qom-set", args, nil)
+}
+
+// RemoveMemory unplugs the memory device with the specified ID (as returned by
+// AddMemory), waits for the guest to release it, and removes its backend.
+func (q *QEMU) RemoveMemory(ctx context.Context, id string) error {
+	if err := q.deleteDevice(ctx, id); err != nil {
+		return err
+	}
+
+	return q.QMP().Execute(ctx, "object-del", map[string]interface{}{"id": id + "-backend"}, nil)
+}
+
+// checkMemoryLimits returns an error if adding a memory device of the specified
+// size would exceed the limits set with the Memory option.
+func (q *QEMU) checkMemoryLimits(devices []MemoryDevice, size int64, usesSlot bool) error {
+	option := q.FindOption("m")
+	if option == nil {
+		return errors.New("memory hotplug requires the Memory option with WithMemoryMaximum")
+	}
+
+	table := option.Table()
+
+	if table["maxmem"] == "" {
+		return errors.New("memory hotplug requires the WithMemoryMaximum property")
+	}
+
+	base, err := parseSize(option.Name, 'M')
+	if err != nil {
+		return fmt.Errorf("invalid memory size: %w", err)
+	}
+
+	maximum, err := parseSize(table["maxmem"], 'B')
+	if err != nil {
+		return fmt.Errorf("invalid maximum memory size: %w", err)
+	}
+
+	total := base + size
+	slots := 0
+
+	for _, device := range devices {
+		total += device.Size
+
+		if device.Type == "dimm" || device.Type == "nvdimm" {
+			slots++
+		}
+	}
+
+	if total > maximum {
+		return fmt.Errorf("cannot add %d bytes of memory: the total of %d bytes exceeds the "+
+			"maximum of %d bytes (see WithMemoryMaximum)", size, total, maximum)
+	}
+
+	if usesSlot {
+		maxSlots, _ := strconv.Atoi(table["slots"])
+
+		if slots >= maxSlots {
+			return fmt.Errorf("cannot add memory: all %d memory slots are in use (see WithMemorySlots)",
+				maxSlots)
+		}
+	}
+
+	return nil
+}
+
+// deleteDevice unplugs the device with the specified ID and waits for the
+// DEVICE_DELETED event, which is emitted once the guest released the device.
+func (q *QEMU) deleteDevice(ctx context.Context, id string) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := client.Subscribe("DEVICE_DELETED")
+	defer unsubscribe()
+
+	if err := client.Execute(ctx, "device_del", map[string]interface{}{"id": id}, nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return client.Err()
+			}
+
+			var data struct {
+				Device string `json:"device"`
+			}
+
+			if err := event.DecodeData(&data); err == nil && data.Device == id {
+				return nil
+			}
+
+		case <-ctx.Done():
+			return fmt.Errorf("guest did not release device %s: %w", id, ctx.Err())
+		}
+	}
+}
+
+// objectArgs converts an -object option to the arguments of object-add. QMP
+// requires numbers for the integer properties that are strings on the command
+// line (such as the size of a memory backend), while other properties (such as
+// the ID) must stay strings, even if they look like numbers.
+func objectArgs(option *queso.Option) (map[string]interface{}, error) {
+	args := map[string]interface{}{"qom-type": option.Name}
+
+	for _, property := range option.Properties {
+		value, err := objectValue(property.Key, property.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		args[property.Key] = value
+	}
+
+	return args, nil
+}
+
+// deviceArgs converts a -device option to the arguments of device_add.
+func deviceArgs(option *queso.Option) map[string]interface{} {
+	args := map[string]interface{}{"driver": option.Name}
+
+	for _, property := range option.Properties {
+		args[property.Key] = property.Value
+	}
+
+	return args
+}
+
+// objectSizeProperties are the properties of memory backends that are sizes,
+// which may have a suffix on the command line.
+var objectSizeProperties = map[string]bool{
+	"size":   true,
+	"align":  true,
+	"offset": true,
+}
+
+// objectIntegerProperties are the properties of memory backends that are
+// integers.
+var objectIntegerProperties = map[string]bool{
+	"prealloc-threads": true,
+}
+
+// objectValue returns the QMP value of the object property with the specified
+// key.
+func objectValue(key string, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	switch {
+	case objectSizeProperties[key]:
+		size, err := parseSize(s, 'B')
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+
+		return size, nil
+
+	case objectIntegerProperties[key]:
+		number, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", key, s)
+		}
+
+		return number, nil
+	}
+
+	return value, nil
+}
+
+// parseSize parses a size with an optional suffix (K, M, G, T, P or E, or B
+// for bytes) as QEMU does. The defaultUnit is used if there is no suffix.
+func parseSize(size string, defaultUnit byte) (int64, error) {
+	size = strings.TrimSpace(size)
+	if size == "" {
+		return 0, errors.New("empty size")
+	}
+
+	unit := defaultUnit
+	last := size[len(size)-1]
+
+	if last < '0' || last > '9' {
+		unit = last
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	shifts := map[byte]uint{'B': 0, 'K': 10, 'M': 20, 'G': 30, 'T': 40, 'P': 50, 'E': 60}
+
+	shift, ok := shifts[unit&^0x20]
+	if !ok {
+		return 0, fmt.Errorf("invalid size suffix %q", string(unit))
+	}
+
+	return int64(value * float64(int64(1)<<shift)), nil
+}
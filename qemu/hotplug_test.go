@@ -0,0 +1,56 @@
+package qemu
+
+import (
+	"testing"
+
+	"github.com/mikerourke/queso/qemu/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	size, err := parseSize("2048", 'M')
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2<<30), size)
+
+	size, err = parseSize("1.5G", 'B')
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3<<29), size)
+
+	_, err = parseSize("4X", 'B')
+	assert.Error(t, err)
+}
+
+func TestCheckMemoryLimits(t *testing.T) {
+	q := New("qemu-system-x86_64")
+	q.SetOptions(Memory("2G", WithMemorySlots(2), WithMemoryMaximum("4G")))
+
+	devices := []MemoryDevice{{Type: "dimm", ID: "mem0", Size: 1 << 30}}
+
+	assert.NoError(t, q.checkMemoryLimits(devices, 1<<30, true))
+	assert.ErrorContains(t, q.checkMemoryLimits(devices, 2<<30, false), "WithMemoryMaximum")
+
+	devices = append(devices, MemoryDevice{Type: "dimm", ID: "mem1", Size: 512 << 20})
+	assert.ErrorContains(t, q.checkMemoryLimits(devices, 256<<20, true), "WithMemorySlots")
+	assert.NoError(t, q.checkMemoryLimits(devices, 256<<20, false))
+
+	assert.Equal(t, "mem2", unusedMemoryID(devices))
+}
+
+func TestObjectArgs(t *testing.T) {
+	args, err := objectArgs(object.MemoryBackendFile("1234",
+		object.WithMemorySize("1G"),
+		object.NewMemoryBackendProperty("mem-path", "/dev/hugepages/2048"),
+		object.NewMemoryBackendProperty("prealloc-threads", "4")))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"qom-type":         "memory-backend-file",
+		"id":               "1234",
+		"size":             int64(1 << 30),
+		"mem-path":         "/dev/hugepages/2048",
+		"prealloc-threads": int64(4),
+	}, args)
+
+	_, err = objectArgs(object.MemoryBackendRAM("mem", object.WithMemorySize("1X")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,287 @@
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InputOptions represent the options used to inject keyboard and mouse input
+// with SendKeys, TypeText and the mouse methods.
+type InputOptions struct {
+	// KeyDelay is the delay between two key strokes (or mouse events). The
+	// default is 50ms, which is slow enough for most installers.
+	KeyDelay time.Duration
+
+	// HoldTime is the duration for which the keys are held down. If zero, QEMU
+	// uses its default of 100ms.
+	HoldTime time.Duration
+}
+
+// SetInputOptions sets the options used to inject keyboard and mouse input.
+func (q *QEMU) SetInputOptions(opts InputOptions) {
+	q.inputOpts = opts
+}
+
+// keyAliases maps common key names to QKeyCodes.
+var keyAliases = map[string]string{
+	"control":     "ctrl",
+	"del":         "delete",
+	"enter":       "ret",
+	"return":      "ret",
+	"escape":      "esc",
+	"space":       "spc",
+	"altgr":       "alt_r",
+	"win":         "meta_l",
+	"super":       "meta_l",
+	"pageup":      "pgup",
+	"pagedown":    "pgdn",
+	"printscreen": "print",
+}
+
+// SendKeys presses the specified key combination, such as "ctrl-alt-delete",
+// and releases it again (see send-key). Keys are QKeyCodes (e.g. "ret", "f2"
+// or "a") or common aliases such as "enter", "escape" or "space". Multiple
+// combinations can be separated by spaces (e.g. "down down ret").
+func (q *QEMU) SendKeys(ctx context.Context, combinations string) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, combination := range strings.Fields(combinations) {
+		if index != 0 {
+			if err := q.inputDelay(ctx); err != nil {
+				return err
+			}
+		}
+
+		keys := make([]string, 0)
+
+		for _, name := range strings.Split(combination, "-") {
+			name = strings.ToLower(name)
+			if alias, ok := keyAliases[name]; ok {
+				name = alias
+			}
+
+			keys = append(keys, name)
+		}
+
+		if err := client.Execute(ctx, "send-key", q.sendKeyArgs(keys), nil); err != nil {
+			return fmt.Errorf("failed to send keys %s: %w", combination, err)
+		}
+	}
+
+	return nil
+}
+
+// TypeText types the specified text by converting each character to the key
+// strokes that produce it with the keyboard layout of the guest (set with the
+// KeyboardLayout option, or LanguageEnglishUS if omitted). A newline is typed
+// as the return key. An error is returned before anything is typed if a
+// character can't be typed with the layout.
+//
+// Only the LanguageEnglishUS, LanguageEnglishUK, LanguageGerman,
+// LanguageFrench, LanguageSpanish and LanguageItalian layouts are supported,
+// an error is returned for the others. Characters that require dead keys
+// (such as "^" on a French keyboard) can't be typed; use SendKeys instead.
+func (q *QEMU) TypeText(ctx context.Context, text string) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	language := Language("")
+	if option := q.FindOption("k"); option != nil {
+		language = Language(option.Name)
+	}
+
+	km, err := keymapFor(language)
+	if err != nil {
+		return err
+	}
+
+	strokes := make([]keyStroke, 0, len(text))
+
+	for _, char := range text {
+		stroke, ok := km[char]
+		if !ok {
+			return fmt.Errorf("character %q can't be typed with keyboard layout %s", char, language)
+		}
+
+		strokes = append(strokes, stroke)
+	}
+
+	for index, stroke := range strokes {
+		if index != 0 {
+			if err := q.inputDelay(ctx); err != nil {
+				return err
+			}
+		}
+
+		keys := make([]string, 0, 2)
+
+		if stroke.shift {
+			keys = append(keys, "shift")
+		}
+
+		if stroke.altGr {
+			keys = append(keys, "alt_r")
+		}
+
+		keys = append(keys, stroke.qcode)
+
+		if err := client.Execute(ctx, "send-key", q.sendKeyArgs(keys), nil); err != nil {
+			return fmt.Errorf("failed to type text: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (q *QEMU) sendKeyArgs(keys []string) map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		values = append(values, map[string]interface{}{"type": "qcode", "data": key})
+	}
+
+	args := map[string]interface{}{"keys": values}
+
+	if q.inputOpts.HoldTime != 0 {
+		args["hold-time"] = q.inputOpts.HoldTime.Milliseconds()
+	}
+
+	return args
+}
+
+// inputDelay waits for the configured delay between key strokes.
+func (q *QEMU) inputDelay(ctx context.Context) error {
+	delay := q.inputOpts.KeyDelay
+	if delay == 0 {
+		delay = 50 * time.Millisecond
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// MouseButton represents a button of the mouse used with ClickMouse.
+type MouseButton string
+
+const (
+	MouseButtonLeft      MouseButton = "left"
+	MouseButtonMiddle    MouseButton = "middle"
+	MouseButtonRight     MouseButton = "right"
+	MouseButtonWheelUp   MouseButton = "wheel-up"
+	MouseButtonWheelDown MouseButton = "wheel-down"
+)
+
+// absoluteMax is the maximum value of an absolute pointer axis in QEMU.
+const absoluteMax = 0x7fff
+
+// MoveMouse moves the mouse pointer to the specified pixel coordinates on a
+// screen of the specified size (e.g. the bounds of a Screenshot). Absolute
+// coordinates require a pointer device with absolute coordinates, such as the
+// USBDevice option with USBDeviceTablet.
+func (q *QEMU) MoveMouse(ctx context.Context, x int, y int, width int, height int) error {
+	if !q.hasAbsolutePointer() {
+		return errors.New("moving the mouse requires a tablet device (see USBDeviceTablet)")
+	}
+
+	if width <= 1 || height <= 1 {
+		return fmt.Errorf("invalid screen size %dx%d", width, height)
+	}
+
+	events := []map[string]interface{}{
+		absEvent("x", x*absoluteMax/(width-1)),
+		absEvent("y", y*absoluteMax/(height-1)),
+	}
+
+	return q.sendInputEvents(ctx, events)
+}
+
+// ClickMouse presses and releases the specified mouse button at the current
+// position of the mouse pointer.
+func (q *QEMU) ClickMouse(ctx context.Context, button MouseButton) error {
+	if err := q.sendInputEvents(ctx, []map[string]interface{}{buttonEvent(button, true)}); err != nil {
+		return err
+	}
+
+	if err := q.inputDelay(ctx); err != nil {
+		return err
+	}
+
+	return q.sendInputEvents(ctx, []map[string]interface{}{buttonEvent(button, false)})
+}
+
+// ClickMouseAt moves the mouse pointer to the specified pixel coordinates (see
+// MoveMouse) and clicks the specified mouse button.
+func (q *QEMU) ClickMouseAt(
+	ctx context.Context,
+	x int,
+	y int,
+	width int,
+	height int,
+	button MouseButton,
+) error {
+	if err := q.MoveMouse(ctx, x, y, width, height); err != nil {
+		return err
+	}
+
+	if err := q.inputDelay(ctx); err != nil {
+		return err
+	}
+
+	return q.ClickMouse(ctx, button)
+}
+
+func (q *QEMU) sendInputEvents(ctx context.Context, events []map[string]interface{}) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.Execute(ctx, "input-send-event", map[string]interface{}{"events": events}, nil)
+}
+
+// hasAbsolutePointer returns true if a pointer device with absolute coordinates
+// is configured.
+func (q *QEMU) hasAbsolutePointer() bool {
+	for _, option := range q.Options() {
+		switch option.Flag {
+		case "usbdevice":
+			if option.Name == string(USBDeviceTablet) || option.Name == string(USBDeviceWacomTablet) {
+				return true
+			}
+
+		case "device":
+			if strings.Contains(option.Name, "tablet") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func absEvent(axis string, value int) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "abs",
+		"data": map[string]interface{}{"axis": axis, "value": value},
+	}
+}
+
+func buttonEvent(button MouseButton, down bool) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "btn",
+		"data": map[string]interface{}{"button": string(button), "down": down},
+	}
+}
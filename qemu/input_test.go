@@ -0,0 +1,141 @@
+package qemu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeyDelay = 20 * time.Millisecond
+
+func testInput(t *testing.T, server *qmptest.Server) *QEMU {
+	server.Respond("send-key", map[string]interface{}{})
+	server.Respond("input-send-event", map[string]interface{}{})
+
+	client, err := server.Dial(context.Background())
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	q := New("qemu-system-x86_64")
+	q.qmp = client
+	q.SetInputOptions(InputOptions{KeyDelay: testKeyDelay, HoldTime: 150 * time.Millisecond})
+
+	return q
+}
+
+// sentKeys returns the qcodes of each send-key command received by the server.
+func sentKeys(server *qmptest.Server) [][]string {
+	sent := make([][]string, 0)
+
+	for _, command := range server.Commands() {
+		if command.Name != "send-key" {
+			continue
+		}
+
+		keys := make([]string, 0)
+
+		for _, value := range command.Arguments["keys"].([]interface{}) {
+			keys = append(keys, value.(map[string]interface{})["data"].(string))
+		}
+
+		sent = append(sent, keys)
+	}
+
+	return sent
+}
+
+// sentEvents returns the events of each input-send-event command received by
+// the server.
+func sentEvents(server *qmptest.Server) []interface{} {
+	sent := make([]interface{}, 0)
+
+	for _, command := range server.Commands() {
+		if command.Name == "input-send-event" {
+			sent = append(sent, command.Arguments["events"])
+		}
+	}
+
+	return sent
+}
+
+func TestSendKeys(t *testing.T) {
+	server := qmptest.NewServer()
+	q := testInput(t, server)
+
+	start := time.Now()
+
+	assert.NoError(t, q.SendKeys(context.Background(), "ctrl-alt-Delete enter"))
+	assert.GreaterOrEqual(t, time.Since(start), testKeyDelay)
+
+	assert.Equal(t, [][]string{{"ctrl", "alt", "delete"}, {"ret"}}, sentKeys(server))
+
+	for _, command := range server.Commands() {
+		if command.Name == "send-key" {
+			assert.Equal(t, float64(150), command.Arguments["hold-time"])
+		}
+	}
+}
+
+func TestTypeText(t *testing.T) {
+	server := qmptest.NewServer()
+	q := testInput(t, server)
+	q.SetOptions(KeyboardLayout(LanguageFrench))
+
+	start := time.Now()
+
+	assert.NoError(t, q.TypeText(context.Background(), "Qa1@"))
+	assert.GreaterOrEqual(t, time.Since(start), 3*testKeyDelay)
+
+	assert.Equal(t, [][]string{{"shift", "a"}, {"q"}, {"shift", "1"}, {"alt_r", "0"}}, sentKeys(server))
+
+	// Nothing is typed if a character can't be typed with the layout.
+	assert.Error(t, q.TypeText(context.Background(), "a^"))
+	assert.Len(t, sentKeys(server), 4)
+}
+
+func TestMoveMouse(t *testing.T) {
+	server := qmptest.NewServer()
+	q := testInput(t, server)
+
+	assert.Error(t, q.MoveMouse(context.Background(), 0, 0, 1024, 768))
+
+	q.SetOptions(USBDevice(USBDeviceTablet))
+
+	assert.Error(t, q.MoveMouse(context.Background(), 0, 0, 1, 768))
+	assert.NoError(t, q.MoveMouse(context.Background(), 1023, 384, 1024, 769))
+
+	assert.Equal(t, []interface{}{
+		[]interface{}{
+			map[string]interface{}{"type": "abs", "data": map[string]interface{}{"axis": "x", "value": float64(0x7fff)}},
+			map[string]interface{}{"type": "abs", "data": map[string]interface{}{"axis": "y", "value": float64(0x3fff)}},
+		},
+	}, sentEvents(server))
+}
+
+func TestClickMouseAt(t *testing.T) {
+	server := qmptest.NewServer()
+	q := testInput(t, server)
+	q.SetOptions(USBDevice(USBDeviceTablet))
+
+	start := time.Now()
+
+	assert.NoError(t, q.ClickMouseAt(context.Background(), 0, 0, 800, 600, MouseButtonLeft))
+	assert.GreaterOrEqual(t, time.Since(start), 2*testKeyDelay)
+
+	assert.Equal(t, []interface{}{
+		[]interface{}{
+			map[string]interface{}{"type": "abs", "data": map[string]interface{}{"axis": "x", "value": float64(0)}},
+			map[string]interface{}{"type": "abs", "data": map[string]interface{}{"axis": "y", "value": float64(0)}},
+		},
+		[]interface{}{
+			map[string]interface{}{"type": "btn", "data": map[string]interface{}{"button": "left", "down": true}},
+		},
+		[]interface{}{
+			map[string]interface{}{"type": "btn", "data": map[string]interface{}{"button": "left", "down": false}},
+		},
+	}, sentEvents(server))
+}
@@ -49,6 +49,9 @@ const (
 // not easy to get raw PC keycodes (e.g. on Macs, with some X11 servers or with
 // a VNC or curses display). You don't normally need to use it on PC/Linux or
 // PC/Windows hosts.
+//
+// The layout is also used to type text with QEMU.TypeText, which only supports
+// some layouts.
 func KeyboardLayout(language Language) *queso.Option {
 	return queso.NewOption("k", string(language))
 }
@@ -0,0 +1,367 @@
+package qemu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyStroke represents the keys that produce a character: a QKeyCode and
+// whether shift and/or AltGr must be held.
+type keyStroke struct {
+	qcode string
+	shift bool
+	altGr bool
+}
+
+// keymap maps characters to the key strokes that produce them.
+type keymap map[rune]keyStroke
+
+// keymaps contain the supported layouts for TypeText. Dead keys are omitted,
+// because they don't produce a character on their own. Layouts that need an
+// input method (such as LanguageJapanese) can't be supported.
+var keymaps = map[Language]keymap{
+	LanguageEnglishUS: englishUSKeymap(),
+	LanguageEnglishUK: englishUKKeymap(),
+	LanguageGerman:    germanKeymap(),
+	LanguageFrench:    frenchKeymap(),
+	LanguageSpanish:   spanishKeymap(),
+	LanguageItalian:   italianKeymap(),
+}
+
+// keymapFor returns the keymap for the specified language.
+func keymapFor(language Language) (keymap, error) {
+	if language == "" {
+		language = LanguageEnglishUS
+	}
+
+	km, ok := keymaps[language]
+	if !ok {
+		supported := make([]string, 0, len(keymaps))
+		for lang := range keymaps {
+			supported = append(supported, string(lang))
+		}
+
+		sort.Strings(supported)
+
+		return nil, fmt.Errorf("typing text is not supported for keyboard layout %q (supported: %s)",
+			language, strings.Join(supported, ", "))
+	}
+
+	return km, nil
+}
+
+// with returns a copy of the keymap with the specified characters replaced.
+func (km keymap) with(overrides keymap) keymap {
+	result := make(keymap, len(km)+len(overrides))
+
+	for char, stroke := range km {
+		result[char] = stroke
+	}
+
+	for char, stroke := range overrides {
+		result[char] = stroke
+	}
+
+	return result
+}
+
+func key(qcode string) keyStroke {
+	return keyStroke{qcode: qcode}
+}
+
+func shifted(qcode string) keyStroke {
+	return keyStroke{qcode: qcode, shift: true}
+}
+
+func altGr(qcode string) keyStroke {
+	return keyStroke{qcode: qcode, altGr: true}
+}
+
+func shiftedAltGr(qcode string) keyStroke {
+	return keyStroke{qcode: qcode, shift: true, altGr: true}
+}
+
+func englishUSKeymap() keymap {
+	km := keymap{
+		' ':  key("spc"),
+		'\n': key("ret"),
+		'\t': key("tab"),
+		'\b': key("backspace"),
+		'-':  key("minus"),
+		'_':  shifted("minus"),
+		'=':  key("equal"),
+		'+':  shifted("equal"),
+		'[':  key("bracket_left"),
+		'{':  shifted("bracket_left"),
+		']':  key("bracket_right"),
+		'}':  shifted("bracket_right"),
+		'\\': key("backslash"),
+		'|':  shifted("backslash"),
+		';':  key("semicolon"),
+		':':  shifted("semicolon"),
+		'\'': key("apostrophe"),
+		'"':  shifted("apostrophe"),
+		'`':  key("grave_accent"),
+		'~':  shifted("grave_accent"),
+		',':  key("comma"),
+		'<':  shifted("comma"),
+		'.':  key("dot"),
+		'>':  shifted("dot"),
+		'/':  key("slash"),
+		'?':  shifted("slash"),
+	}
+
+	for char := 'a'; char <= 'z'; char++ {
+		km[char] = key(string(char))
+		km[char-'a'+'A'] = shifted(string(char))
+	}
+
+	for index, char := range "!@#$%^&*()" {
+		digit := string(rune('0' + (index+1)%10))
+
+		km[rune('0'+(index+1)%10)] = key(digit)
+		km[char] = shifted(digit)
+	}
+
+	return km
+}
+
+func englishUKKeymap() keymap {
+	return englishUSKeymap().with(keymap{
+		'"':  shifted("2"),
+		'@':  shifted("apostrophe"),
+		'£':  shifted("3"),
+		'#':  key("backslash"),
+		'~':  shifted("backslash"),
+		'\\': key("less"),
+		'|':  shifted("less"),
+		'¬':  shifted("grave_accent"),
+		'€':  altGr("4"),
+	})
+}
+
+func germanKeymap() keymap {
+	km := englishUSKeymap().with(keymap{
+		'z':  key("y"),
+		'Z':  shifted("y"),
+		'y':  key("z"),
+		'Y':  shifted("z"),
+		'!':  shifted("1"),
+		'"':  shifted("2"),
+		'§':  shifted("3"),
+		'$':  shifted("4"),
+		'%':  shifted("5"),
+		'&':  shifted("6"),
+		'/':  shifted("7"),
+		'(':  shifted("8"),
+		')':  shifted("9"),
+		'=':  shifted("0"),
+		'ß':  key("minus"),
+		'?':  shifted("minus"),
+		'ü':  key("bracket_left"),
+		'Ü':  shifted("bracket_left"),
+		'+':  key("bracket_right"),
+		'*':  shifted("bracket_right"),
+		'~':  altGr("bracket_right"),
+		'ö':  key("semicolon"),
+		'Ö':  shifted("semicolon"),
+		'ä':  key("apostrophe"),
+		'Ä':  shifted("apostrophe"),
+		'#':  key("backslash"),
+		'\'': shifted("backslash"),
+		'<':  key("less"),
+		'>':  shifted("less"),
+		'|':  altGr("less"),
+		',':  key("comma"),
+		';':  shifted("comma"),
+		'.':  key("dot"),
+		':':  shifted("dot"),
+		'-':  key("slash"),
+		'_':  shifted("slash"),
+		'°':  shifted("grave_accent"),
+		'@':  altGr("q"),
+		'€':  altGr("e"),
+		'²':  altGr("2"),
+		'³':  altGr("3"),
+		'{':  altGr("7"),
+		'[':  altGr("8"),
+		']':  altGr("9"),
+		'}':  altGr("0"),
+		'\\': altGr("minus"),
+	})
+
+	// These are dead keys on a German keyboard.
+	delete(km, '^')
+	delete(km, '`')
+
+	return km
+}
+
+func frenchKeymap() keymap {
+	km := englishUSKeymap().with(keymap{
+		'a':  key("q"),
+		'A':  shifted("q"),
+		'q':  key("a"),
+		'Q':  shifted("a"),
+		'z':  key("w"),
+		'Z':  shifted("w"),
+		'w':  key("z"),
+		'W':  shifted("z"),
+		'm':  key("semicolon"),
+		'M':  shifted("semicolon"),
+		'²':  key("grave_accent"),
+		'&':  key("1"),
+		'é':  key("2"),
+		'"':  key("3"),
+		'\'': key("4"),
+		'(':  key("5"),
+		'-':  key("6"),
+		'è':  key("7"),
+		'_':  key("8"),
+		'ç':  key("9"),
+		'à':  key("0"),
+		')':  key("minus"),
+		'°':  shifted("minus"),
+		'=':  key("equal"),
+		'+':  shifted("equal"),
+		'#':  altGr("3"),
+		'{':  altGr("4"),
+		'[':  altGr("5"),
+		'|':  altGr("6"),
+		'\\': altGr("8"),
+		'@':  altGr("0"),
+		']':  altGr("minus"),
+		'}':  altGr("equal"),
+		'€':  altGr("e"),
+		'$':  key("bracket_right"),
+		'£':  shifted("bracket_right"),
+		'¤':  altGr("bracket_right"),
+		'ù':  key("apostrophe"),
+		'%':  shifted("apostrophe"),
+		'*':  key("backslash"),
+		'µ':  shifted("backslash"),
+		'<':  key("less"),
+		'>':  shifted("less"),
+		',':  key("m"),
+		'?':  shifted("m"),
+		';':  key("comma"),
+		'.':  shifted("comma"),
+		':':  key("dot"),
+		'/':  shifted("dot"),
+		'!':  key("slash"),
+		'§':  shifted("slash"),
+	})
+
+	// The digits are on the shifted number keys.
+	for digit := '0'; digit <= '9'; digit++ {
+		km[digit] = shifted(string(digit))
+	}
+
+	// These are dead keys on a French keyboard.
+	delete(km, '^')
+	delete(km, '`')
+	delete(km, '~')
+
+	return km
+}
+
+func spanishKeymap() keymap {
+	km := englishUSKeymap().with(keymap{
+		'º':  key("grave_accent"),
+		'ª':  shifted("grave_accent"),
+		'\\': altGr("grave_accent"),
+		'!':  shifted("1"),
+		'|':  altGr("1"),
+		'"':  shifted("2"),
+		'@':  altGr("2"),
+		'·':  shifted("3"),
+		'#':  altGr("3"),
+		'$':  shifted("4"),
+		'%':  shifted("5"),
+		'&':  shifted("6"),
+		'¬':  altGr("6"),
+		'/':  shifted("7"),
+		'(':  shifted("8"),
+		')':  shifted("9"),
+		'=':  shifted("0"),
+		'\'': key("minus"),
+		'?':  shifted("minus"),
+		'¡':  key("equal"),
+		'¿':  shifted("equal"),
+		'€':  altGr("e"),
+		'[':  altGr("bracket_left"),
+		'+':  key("bracket_right"),
+		'*':  shifted("bracket_right"),
+		']':  altGr("bracket_right"),
+		'ñ':  key("semicolon"),
+		'Ñ':  shifted("semicolon"),
+		'{':  altGr("apostrophe"),
+		'ç':  key("backslash"),
+		'Ç':  shifted("backslash"),
+		'}':  altGr("backslash"),
+		'<':  key("less"),
+		'>':  shifted("less"),
+		';':  shifted("comma"),
+		':':  shifted("dot"),
+		'-':  key("slash"),
+		'_':  shifted("slash"),
+	})
+
+	// These are dead keys on a Spanish keyboard.
+	delete(km, '^')
+	delete(km, '`')
+	delete(km, '~')
+
+	return km
+}
+
+func italianKeymap() keymap {
+	km := englishUSKeymap().with(keymap{
+		'\\': key("grave_accent"),
+		'|':  shifted("grave_accent"),
+		'!':  shifted("1"),
+		'"':  shifted("2"),
+		'£':  shifted("3"),
+		'$':  shifted("4"),
+		'%':  shifted("5"),
+		'&':  shifted("6"),
+		'/':  shifted("7"),
+		'(':  shifted("8"),
+		')':  shifted("9"),
+		'=':  shifted("0"),
+		'\'': key("minus"),
+		'?':  shifted("minus"),
+		'ì':  key("equal"),
+		'^':  shifted("equal"),
+		'€':  altGr("e"),
+		'è':  key("bracket_left"),
+		'é':  shifted("bracket_left"),
+		'[':  altGr("bracket_left"),
+		'{':  shiftedAltGr("bracket_left"),
+		'+':  key("bracket_right"),
+		'*':  shifted("bracket_right"),
+		']':  altGr("bracket_right"),
+		'}':  shiftedAltGr("bracket_right"),
+		'ò':  key("semicolon"),
+		'ç':  shifted("semicolon"),
+		'@':  altGr("semicolon"),
+		'à':  key("apostrophe"),
+		'°':  shifted("apostrophe"),
+		'#':  altGr("apostrophe"),
+		'ù':  key("backslash"),
+		'§':  shifted("backslash"),
+		'<':  key("less"),
+		'>':  shifted("less"),
+		';':  shifted("comma"),
+		':':  shifted("dot"),
+		'-':  key("slash"),
+		'_':  shifted("slash"),
+	})
+
+	// These characters aren't on an Italian keyboard.
+	delete(km, '`')
+	delete(km, '~')
+
+	return km
+}
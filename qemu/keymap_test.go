@@ -0,0 +1,37 @@
+package qemu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeymapFor(t *testing.T) {
+	km, err := keymapFor("")
+	assert.NoError(t, err)
+	assert.Equal(t, key("ret"), km['\n'])
+	assert.Equal(t, shifted("2"), km['@'])
+
+	km, err = keymapFor(LanguageGerman)
+	assert.NoError(t, err)
+	assert.Equal(t, key("y"), km['z'])
+	assert.Equal(t, altGr("q"), km['@'])
+	assert.NotContains(t, km, '^')
+
+	km, err = keymapFor(LanguageFrench)
+	assert.NoError(t, err)
+	assert.Equal(t, key("q"), km['a'])
+	assert.Equal(t, shifted("1"), km['1'])
+	assert.NotContains(t, km, '^')
+
+	km, err = keymapFor(LanguageSpanish)
+	assert.NoError(t, err)
+	assert.Equal(t, key("semicolon"), km['ñ'])
+
+	km, err = keymapFor(LanguageItalian)
+	assert.NoError(t, err)
+	assert.Equal(t, shiftedAltGr("bracket_left"), km['{'])
+
+	_, err = keymapFor(LanguageJapanese)
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+// Package lifecycle is used to receive the QMP events that report guest
+// crashes, watchdog expirations, resets and shutdowns as typed events. What
+// QEMU does when these events occur is configured with debug.WithAction and
+// debug.WatchdogActionOnExpiration.
+package lifecycle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// Kind represents the kind of lifecycle event, which is the name of the
+// corresponding QMP event.
+type Kind string
+
+const (
+	// KindPanicked is emitted when the guest reports a panic through a
+	// pvpanic device (see device.PVPanic) or a paravirtualized interface such
+	// as Hyper-V crash MSRs.
+	KindPanicked Kind = "GUEST_PANICKED"
+
+	// KindCrashLoaded is emitted when the guest has loaded a crash kernel
+	// after a panic.
+	KindCrashLoaded Kind = "GUEST_CRASHLOADED"
+
+	// KindWatchdog is emitted when the watchdog timer expires (see
+	// debug.Watchdog).
+	KindWatchdog Kind = "WATCHDOG"
+
+	// KindReset is emitted when the guest is reset.
+	KindReset Kind = "RESET"
+
+	// KindShutdown is emitted when the guest is shut down (or QEMU is asked to
+	// quit).
+	KindShutdown Kind = "SHUTDOWN"
+)
+
+// Kinds are all the kinds of lifecycle events.
+var Kinds = []Kind{KindPanicked, KindCrashLoaded, KindWatchdog, KindReset, KindShutdown}
+
+// PanicInfo contains the details a guest reports about a panic, if any.
+type PanicInfo struct {
+	// Type is the type of the information, such as "hyper-v" or "s390".
+	Type string `json:"type"`
+
+	// Arg1 to Arg5 are the crash parameters reported through Hyper-V crash
+	// MSRs.
+	Arg1 uint64 `json:"arg1"`
+	Arg2 uint64 `json:"arg2"`
+	Arg3 uint64 `json:"arg3"`
+	Arg4 uint64 `json:"arg4"`
+	Arg5 uint64 `json:"arg5"`
+
+	// Core is the s390 core that panicked.
+	Core uint32 `json:"core"`
+
+	// PSWMask is the program status word mask of the s390 core.
+	PSWMask uint64 `json:"psw-mask"`
+
+	// PSWAddress is the program status word address of the s390 core.
+	PSWAddress uint64 `json:"psw-addr"`
+
+	// Reason is the reason an s390 core panicked.
+	Reason string `json:"reason"`
+}
+
+// Event is a typed lifecycle event.
+type Event struct {
+	// Kind is the kind of event.
+	Kind Kind
+
+	// Time is the time the event was emitted.
+	Time time.Time
+
+	// Action is the action QEMU took for a KindPanicked, KindCrashLoaded or
+	// KindWatchdog event, such as "pause", "poweroff" or "reset".
+	Action string
+
+	// Panic contains the details of a KindPanicked or KindCrashLoaded event,
+	// if the guest reported any.
+	Panic *PanicInfo
+
+	// Guest is true if a KindReset or KindShutdown event was initiated by the
+	// guest.
+	Guest bool
+
+	// Reason is the reason for a KindReset or KindShutdown event, such as
+	// "guest-shutdown", "guest-reset", "guest-panic", "watchdog" or
+	// "host-qmp-quit".
+	Reason string
+}
+
+// IsCrash returns true if the event indicates that the guest crashed or hung,
+// which is a KindPanicked or KindWatchdog event, or a reset or shutdown caused
+// by a panic or watchdog.
+func (e Event) IsCrash() bool {
+	switch e.Kind {
+	case KindPanicked, KindWatchdog:
+		return true
+
+	case KindReset, KindShutdown:
+		return e.Reason == "guest-panic" || e.Reason == "watchdog"
+	}
+
+	return false
+}
+
+// Decode converts a QMP event to a typed Event.
+func Decode(event qmp.Event) (Event, error) {
+	var data struct {
+		Action string     `json:"action"`
+		Info   *PanicInfo `json:"info"`
+		Guest  bool       `json:"guest"`
+		Reason string     `json:"reason"`
+	}
+
+	switch Kind(event.Name) {
+	case KindPanicked, KindCrashLoaded, KindWatchdog, KindReset, KindShutdown:
+
+	default:
+		return Event{}, fmt.Errorf("%s is not a lifecycle event", event.Name)
+	}
+
+	if err := event.DecodeData(&data); err != nil {
+		return Event{}, fmt.Errorf("failed to decode %s event: %w", event.Name, err)
+	}
+
+	return Event{
+		Kind:   Kind(event.Name),
+		Time:   event.Timestamp.Time(),
+		Action: data.Action,
+		Panic:  data.Info,
+		Guest:  data.Guest,
+		Reason: data.Reason,
+	}, nil
+}
+
+// Subscribe returns a channel that receives the lifecycle events of the
+// specified kinds. If no kinds are specified, all lifecycle events are
+// received. Like qmp.Client.Subscribe, events are buffered and the channel is
+// closed when the returned cancel function is called or the connection is
+// closed. Events that can't be decoded are dropped.
+//
+// Example
+//
+//	events, cancel := lifecycle.Subscribe(q.QMP(), lifecycle.KindPanicked)
+//	defer cancel()
+//
+//	event := <-events
+//	fmt.Println("guest panicked, action:", event.Action)
+func Subscribe(client *qmp.Client, kinds ...Kind) (<-chan Event, func()) {
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+
+	names := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		names = append(names, string(kind))
+	}
+
+	events, cancelEvents := client.Subscribe(names...)
+
+	out := make(chan Event)
+	quit := make(chan struct{})
+
+	go func() {
+		defer close(out)
+
+		for event := range events {
+			decoded, err := Decode(event)
+			if err != nil {
+				continue
+			}
+
+			select {
+			case out <- decoded:
+
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	cancel := func() {
+		once.Do(func() {
+			close(quit)
+			cancelEvents()
+		})
+	}
+
+	return out, cancel
+}
@@ -0,0 +1,32 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	event, err := Decode(qmp.Event{
+		Name: "GUEST_PANICKED",
+		Data: json.RawMessage(`{"action": "pause", "info": {"type": "hyper-v", "arg1": 30, "arg2": 1}}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, KindPanicked, event.Kind)
+	assert.Equal(t, "pause", event.Action)
+	assert.Equal(t, uint64(30), event.Panic.Arg1)
+	assert.True(t, event.IsCrash())
+
+	event, err = Decode(qmp.Event{
+		Name: "SHUTDOWN",
+		Data: json.RawMessage(`{"guest": true, "reason": "guest-shutdown"}`),
+	})
+	assert.NoError(t, err)
+	assert.True(t, event.Guest)
+	assert.False(t, event.IsCrash())
+
+	_, err = Decode(qmp.Event{Name: "STOP"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// collector collects the metrics of a VM over QMP. Commands that fail (e.g.
+// because QEMU is too old or the VM has no balloon device) are skipped, so the
+// remaining metrics are still exported.
+type collector struct {
+	ctx      context.Context
+	client   *qmp.Client
+	registry *registry
+	vm       label
+}
+
+func (c *collector) status() {
+	var result struct {
+		Running bool   `json:"running"`
+		Status  string `json:"status"`
+	}
+
+	if err := c.client.Execute(c.ctx, "query-status", nil, &result); err != nil {
+		return
+	}
+
+	running := 0.0
+	if result.Running {
+		running = 1
+	}
+
+	c.registry.add("qemu_vm_running", typeGauge, "Whether the guest CPUs are running.", running, c.vm)
+	c.registry.add("qemu_vm_status", typeGauge, "Run state of the VM, such as running or paused.", 1,
+		c.vm, label{"status", result.Status})
+}
+
+// blockCounters maps the fields of BlockDeviceStats to metric names. Times are
+// converted from nanoseconds to seconds.
+var blockCounters = []struct {
+	field string
+	name  string
+	help  string
+	scale float64
+}{
+	{"rd_bytes", "qemu_block_read_bytes_total", "Bytes read from the drive.", 1},
+	{"wr_bytes", "qemu_block_write_bytes_total", "Bytes written to the drive.", 1},
+	{"rd_operations", "qemu_block_read_operations_total", "Read operations on the drive.", 1},
+	{"wr_operations", "qemu_block_write_operations_total", "Write operations on the drive.", 1},
+	{"flush_operations", "qemu_block_flush_operations_total", "Flush operations on the drive.", 1},
+	{"rd_total_time_ns", "qemu_block_read_seconds_total", "Time spent reading from the drive.", 1e-9},
+	{"wr_total_time_ns", "qemu_block_write_seconds_total", "Time spent writing to the drive.", 1e-9},
+	{"flush_total_time_ns", "qemu_block_flush_seconds_total", "Time spent flushing the drive.", 1e-9},
+	{"failed_rd_operations", "qemu_block_failed_read_operations_total", "Failed read operations on the drive.", 1},
+	{"failed_wr_operations", "qemu_block_failed_write_operations_total", "Failed write operations on the drive.", 1},
+}
+
+func (c *collector) blockStats() {
+	var devices []struct {
+		Device   string             `json:"device"`
+		QDev     string             `json:"qdev"`
+		NodeName string             `json:"node-name"`
+		Stats    map[string]float64 `json:"stats"`
+	}
+
+	if err := c.client.Execute(c.ctx, "query-blockstats", nil, &devices); err != nil {
+		return
+	}
+
+	for _, device := range devices {
+		// Drives created with -drive have a device name, while drives created
+		// with -blockdev are only identified by the ID of the guest device.
+		id := device.Device
+		if id == "" {
+			id = device.QDev
+		}
+
+		if id == "" {
+			id = device.NodeName
+		}
+
+		drive := label{"drive", id}
+
+		for _, counter := range blockCounters {
+			if value, ok := device.Stats[counter.field]; ok {
+				c.registry.add(counter.name, typeCounter, counter.help, value*counter.scale, c.vm, drive)
+			}
+		}
+	}
+}
+
+// stats collects the statistics of the accelerator for the specified target
+// ("vm" or "vcpu") with query-stats. Each statistic is exported as a separate
+// untyped metric, because the type depends on the provider.
+func (c *collector) stats(target string) {
+	var results []struct {
+		Provider string `json:"provider"`
+		QOMPath  string `json:"qom-path"`
+		Stats    []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"stats"`
+	}
+
+	args := map[string]interface{}{"target": target}
+
+	if err := c.client.Execute(c.ctx, "query-stats", args, &results); err != nil {
+		return
+	}
+
+	for _, result := range results {
+		labels := []label{c.vm}
+
+		if target == "vcpu" {
+			labels = append(labels, label{"vcpu", result.QOMPath})
+		}
+
+		for _, stat := range result.Stats {
+			value, ok := statValue(stat.Value)
+			if !ok {
+				continue
+			}
+
+			name := "qemu_stats_" + target + "_" + sanitizeName(result.Provider+"_"+stat.Name)
+
+			c.registry.add(name, typeUntyped, "Statistic "+stat.Name+" of the "+result.Provider+" provider.",
+				value, labels...)
+		}
+	}
+}
+
+// statValue converts the value of a statistic to a float. Histograms (lists of
+// values) are not supported.
+func statValue(raw json.RawMessage) (float64, bool) {
+	switch string(raw) {
+	case "true":
+		return 1, true
+
+	case "false":
+		return 0, true
+	}
+
+	value, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
+
+func (c *collector) balloon() {
+	var result struct {
+		Actual float64 `json:"actual"`
+	}
+
+	if err := c.client.Execute(c.ctx, "query-balloon", nil, &result); err != nil {
+		return
+	}
+
+	c.registry.add("qemu_balloon_actual_bytes", typeGauge,
+		"Memory of the guest in bytes after ballooning.", result.Actual, c.vm)
+}
+
+func (c *collector) migration() {
+	var result struct {
+		Status           string  `json:"status"`
+		TotalTime        float64 `json:"total-time"`
+		ExpectedDowntime float64 `json:"expected-downtime"`
+		RAM              *struct {
+			Transferred    float64 `json:"transferred"`
+			Remaining      float64 `json:"remaining"`
+			Total          float64 `json:"total"`
+			DirtyPagesRate float64 `json:"dirty-pages-rate"`
+			DirtySyncCount float64 `json:"dirty-sync-count"`
+		} `json:"ram"`
+	}
+
+	if err := c.client.Execute(c.ctx, "query-migrate", nil, &result); err != nil || result.Status == "" {
+		return
+	}
+
+	c.registry.add("qemu_migration_status", typeGauge, "Status of the last migration.", 1,
+		c.vm, label{"status", result.Status})
+	c.registry.add("qemu_migration_total_seconds", typeGauge,
+		"Time spent on the migration in seconds.", result.TotalTime/1000, c.vm)
+	c.registry.add("qemu_migration_expected_downtime_seconds", typeGauge,
+		"Expected downtime of the migration in seconds.", result.ExpectedDowntime/1000, c.vm)
+
+	if result.RAM == nil {
+		return
+	}
+
+	c.registry.add("qemu_migration_ram_transferred_bytes", typeGauge,
+		"Bytes of RAM transferred by the migration.", result.RAM.Transferred, c.vm)
+	c.registry.add("qemu_migration_ram_remaining_bytes", typeGauge,
+		"Bytes of RAM remaining to be transferred by the migration.", result.RAM.Remaining, c.vm)
+	c.registry.add("qemu_migration_ram_total_bytes", typeGauge,
+		"Total bytes of RAM of the migration.", result.RAM.Total, c.vm)
+	c.registry.add("qemu_migration_dirty_pages_rate", typeGauge,
+		"Pages dirtied per second during the migration.", result.RAM.DirtyPagesRate, c.vm)
+	c.registry.add("qemu_migration_dirty_sync_count", typeGauge,
+		"Number of times the dirty pages were synchronized.", result.RAM.DirtySyncCount, c.vm)
+}
@@ -0,0 +1,257 @@
+// Package metrics exports the metrics of running QEMU instances in the
+// Prometheus text format. The metrics are collected from QMP and from the
+// QEMU process on every scrape, so no libvirt (or other agent) is required.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso/qemu"
+)
+
+// Options represent the options for NewExporter.
+type Options struct {
+	// Timeout is the maximum time to collect the metrics of a single VM. The
+	// default is 5 seconds.
+	Timeout time.Duration
+
+	// ProcDir is the path to the proc filesystem the process metrics are read
+	// from. The default is "/proc".
+	ProcDir string
+
+	// SysDir is the path to the sys filesystem the metrics of TAP interfaces
+	// are read from. The default is "/sys".
+	SysDir string
+}
+
+// Exporter is an http.Handler that serves the metrics of the VMs added to it in
+// the Prometheus text format. The samples of each VM are labelled with the
+// name of the VM (see qemu.Name), and the samples of drives and NICs are also
+// labelled with their IDs.
+//
+// The following metrics are exported:
+//
+//   - qemu_up: whether QMP is reachable
+//   - qemu_vm_running and qemu_vm_status: the run state (query-status)
+//   - qemu_block_*: the I/O statistics of each drive (query-blockstats)
+//   - qemu_stats_vm_* and qemu_stats_vcpu_*: the statistics of the
+//     accelerator, such as KVM (query-stats, requires QEMU 7.1 or newer)
+//   - qemu_balloon_actual_bytes: the memory of the guest (query-balloon)
+//   - qemu_migration_*: the progress of a migration (query-migrate)
+//   - qemu_net_*: the traffic of each TAP backend with an interface name
+//   - qemu_process_*: the CPU time, resident memory and threads of the QEMU
+//     process (Linux only)
+//
+// Example
+//
+//	exporter := metrics.NewExporter(metrics.Options{})
+//
+//	if err := exporter.Add(q); err != nil {
+//		return err
+//	}
+//
+//	http.Handle("/metrics", exporter)
+type Exporter struct {
+	opts Options
+
+	mu  sync.Mutex
+	vms map[string]*qemu.QEMU
+}
+
+// NewExporter returns a new Exporter with the specified options.
+func NewExporter(opts Options) *Exporter {
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	if opts.ProcDir == "" {
+		opts.ProcDir = "/proc"
+	}
+
+	if opts.SysDir == "" {
+		opts.SysDir = "/sys"
+	}
+
+	return &Exporter{
+		opts: opts,
+		vms:  make(map[string]*qemu.QEMU),
+	}
+}
+
+// Add adds a VM to the Exporter. The VM must have a name (see qemu.Name) that
+// is unique within the Exporter.
+func (e *Exporter) Add(q *qemu.QEMU) error {
+	name := q.GuestName()
+	if name == "" {
+		return errors.New("a VM must have a name to export its metrics")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.vms[name]; ok {
+		return fmt.Errorf("VM %s was already added", name)
+	}
+
+	e.vms[name] = q
+
+	return nil
+}
+
+// Remove removes the VM with the specified name from the Exporter.
+func (e *Exporter) Remove(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.vms, name)
+}
+
+// ServeHTTP collects the metrics of all VMs and writes them in the Prometheus
+// text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	buf := new(bytes.Buffer)
+
+	if err := e.collect(r.Context()).write(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// collect collects the metrics of all VMs concurrently, so a VM that doesn't
+// respond doesn't delay the others by more than the timeout.
+func (e *Exporter) collect(ctx context.Context) *registry {
+	e.mu.Lock()
+
+	names := make([]string, 0, len(e.vms))
+	for name := range e.vms {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	vms := make([]*qemu.QEMU, 0, len(names))
+	for _, name := range names {
+		vms = append(vms, e.vms[name])
+	}
+
+	e.mu.Unlock()
+
+	results := make([]*registry, len(vms))
+
+	var wg sync.WaitGroup
+
+	for index := range vms {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			collectCtx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+			defer cancel()
+
+			results[index] = e.collectVM(collectCtx, names[index], vms[index])
+		}(index)
+	}
+
+	wg.Wait()
+
+	// The registries are merged in order, so the samples of each family are
+	// contiguous and the VMs are always listed in the same order.
+	merged := newRegistry()
+
+	for _, result := range results {
+		for _, name := range result.order {
+			f := result.families[name]
+
+			for _, s := range f.samples {
+				merged.add(f.name, f.kind, f.help, s.value, s.labels...)
+			}
+		}
+	}
+
+	return merged
+}
+
+func (e *Exporter) collectVM(ctx context.Context, name string, q *qemu.QEMU) *registry {
+	r := newRegistry()
+	vm := label{"vm", name}
+
+	if pid := q.PID(); pid != 0 {
+		if stats, err := readProcessStats(e.opts.ProcDir, pid); err == nil {
+			r.add("qemu_process_cpu_seconds_total", typeCounter,
+				"Total user and system CPU time of the QEMU process in seconds.", stats.cpuSeconds, vm)
+			r.add("qemu_process_resident_memory_bytes", typeGauge,
+				"Resident memory of the QEMU process in bytes.", stats.residentMem, vm)
+			r.add("qemu_process_threads", typeGauge,
+				"Number of threads of the QEMU process.", stats.threads, vm)
+		}
+	}
+
+	e.collectNetwork(r, vm, q)
+
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		r.add("qemu_up", typeGauge, "Whether the QMP socket of the VM is reachable.", 0, vm)
+
+		return r
+	}
+
+	r.add("qemu_up", typeGauge, "Whether the QMP socket of the VM is reachable.", 1, vm)
+
+	c := &collector{ctx: ctx, client: client, registry: r, vm: vm}
+	c.status()
+	c.blockStats()
+	c.stats("vm")
+	c.stats("vcpu")
+	c.balloon()
+	c.migration()
+
+	return r
+}
+
+// collectNetwork collects the traffic of the TAP backends with an interface
+// name (see network.WithInterfaceName). The counters are read from the host
+// side of the interface, so received bytes are the bytes sent by the guest.
+func (e *Exporter) collectNetwork(r *registry, vm label, q *qemu.QEMU) {
+	for _, option := range q.Options() {
+		if option.Flag != "netdev" || option.Name != "tap" {
+			continue
+		}
+
+		table := option.Table()
+		if table["ifname"] == "" {
+			continue
+		}
+
+		stats, err := readInterfaceStats(e.opts.SysDir, table["ifname"])
+		if err != nil {
+			continue
+		}
+
+		nic := label{"nic", table["id"]}
+
+		r.add("qemu_net_receive_bytes_total", typeCounter,
+			"Bytes received by the host interface of the NIC (sent by the guest).", stats["rx_bytes"], vm, nic)
+		r.add("qemu_net_transmit_bytes_total", typeCounter,
+			"Bytes transmitted by the host interface of the NIC (received by the guest).", stats["tx_bytes"], vm, nic)
+		r.add("qemu_net_receive_packets_total", typeCounter,
+			"Packets received by the host interface of the NIC.", stats["rx_packets"], vm, nic)
+		r.add("qemu_net_transmit_packets_total", typeCounter,
+			"Packets transmitted by the host interface of the NIC.", stats["tx_packets"], vm, nic)
+		r.add("qemu_net_receive_dropped_total", typeCounter,
+			"Received packets dropped by the host interface of the NIC.", stats["rx_dropped"], vm, nic)
+		r.add("qemu_net_transmit_dropped_total", typeCounter,
+			"Transmitted packets dropped by the host interface of the NIC.", stats["tx_dropped"], vm, nic)
+	}
+}
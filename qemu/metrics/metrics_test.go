@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bytes"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikerourke/queso/qemu"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProcessStat(t *testing.T) {
+	stat := "1234 (qemu-system-x86 (vm)) S 1 1234 1234 0 -1 4194560 51234 0 12 0 " +
+		"250 150 0 0 20 0 7 0 123456 2147483648 25600 18446744073709551615 0 0 0 0 0 0 0 4096 0 0 0 0 17 3 0 0"
+
+	stats, err := parseProcessStat(stat, 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, stats.cpuSeconds)
+	assert.Equal(t, 7.0, stats.threads)
+	assert.Equal(t, 25600.0*4096, stats.residentMem)
+
+	_, err = parseProcessStat("1234 (qemu", 4096)
+	assert.Error(t, err)
+}
+
+func TestRegistryWrite(t *testing.T) {
+	r := newRegistry()
+	vm := label{"vm", `web "1"`}
+
+	r.add("qemu_up", typeGauge, "Whether QMP is reachable.", 1, vm)
+	r.add("qemu_block_read_bytes_total", typeCounter, "Bytes read.", 512, vm, label{"drive", "disk0"})
+	r.add("qemu_up", typeGauge, "Whether QMP is reachable.", 0, label{"vm", "db"})
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, r.write(buf))
+	assert.Equal(t, `# HELP qemu_up Whether QMP is reachable.
+# TYPE qemu_up gauge
+qemu_up{vm="web \"1\""} 1
+qemu_up{vm="db"} 0
+# HELP qemu_block_read_bytes_total Bytes read.
+# TYPE qemu_block_read_bytes_total counter
+qemu_block_read_bytes_total{vm="web \"1\"",drive="disk0"} 512
+`, buf.String())
+}
+
+func TestExporterConcurrentScrapes(t *testing.T) {
+	server := qmptest.NewServer()
+	socket := filepath.Join(t.TempDir(), "qmp.sock")
+
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	var connections int32
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			atomic.AddInt32(&connections, 1)
+
+			go server.ServeConn(conn)
+		}
+	}()
+
+	q := qemu.New("qemu-system-x86_64")
+	q.SetQMPSocket(socket)
+	q.SetOptions(qemu.Name("vm1"))
+
+	t.Cleanup(func() { _ = q.QMP().Close() })
+
+	exporter := NewExporter(Options{ProcDir: t.TempDir(), SysDir: t.TempDir()})
+	assert.NoError(t, exporter.Add(q))
+
+	var wg sync.WaitGroup
+
+	for index := 0; index < 8; index++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			recorder := httptest.NewRecorder()
+			exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+			assert.Contains(t, recorder.Body.String(), `qemu_up{vm="vm1"} 1`)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is the number of clock ticks per second used for the CPU times in
+// /proc/<pid>/stat (USER_HZ), which is 100 on all common Linux architectures.
+const clockTicks = 100
+
+// processStats represent the resource usage of the QEMU process.
+type processStats struct {
+	cpuSeconds  float64
+	residentMem float64
+	threads     float64
+}
+
+// readProcessStats reads the resource usage of the process with the specified
+// PID from /proc, so it is only available on Linux.
+func readProcessStats(procDir string, pid int) (processStats, error) {
+	data, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return processStats{}, err
+	}
+
+	return parseProcessStat(string(data), os.Getpagesize())
+}
+
+// parseProcessStat parses the contents of /proc/<pid>/stat. The command name
+// is enclosed in parentheses and may contain spaces, so the fields are read
+// after the last closing parenthesis.
+func parseProcessStat(stat string, pageSize int) (processStats, error) {
+	end := strings.LastIndex(stat, ")")
+	if end == -1 {
+		return processStats{}, fmt.Errorf("invalid stat %q", stat)
+	}
+
+	// The fields after the command start with the state (field 3).
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return processStats{}, fmt.Errorf("stat has %d fields instead of at least 24", len(fields)+2)
+	}
+
+	field := func(number int) (float64, error) {
+		return strconv.ParseFloat(fields[number-3], 64)
+	}
+
+	utime, err := field(14)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	stime, err := field(15)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	threads, err := field(20)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	rss, err := field(24)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	return processStats{
+		cpuSeconds:  (utime + stime) / clockTicks,
+		residentMem: rss * float64(pageSize),
+		threads:     threads,
+	}, nil
+}
+
+// interfaceStats represent the traffic counters of a host network interface.
+type interfaceStats map[string]float64
+
+// interfaceCounters are the counters read from the statistics directory of a
+// network interface in sysfs.
+var interfaceCounters = []string{"rx_bytes", "tx_bytes", "rx_packets", "tx_packets", "rx_dropped", "tx_dropped"}
+
+// readInterfaceStats reads the counters of the host network interface with the
+// specified name from sysfs.
+func readInterfaceStats(sysDir string, name string) (interfaceStats, error) {
+	stats := make(interfaceStats)
+
+	for _, counter := range interfaceCounters {
+		data, err := os.ReadFile(filepath.Join(sysDir, "class", "net", name, "statistics", counter))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[counter] = value
+	}
+
+	return stats, nil
+}
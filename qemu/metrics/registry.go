@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metricType represents the type of a metric family in the Prometheus text
+// format.
+type metricType string
+
+const (
+	typeCounter metricType = "counter"
+	typeGauge   metricType = "gauge"
+	typeUntyped metricType = "untyped"
+)
+
+// label is a label of a sample. Labels are kept in order, so the output is
+// stable.
+type label struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	labels []label
+	value  float64
+}
+
+// family is a group of samples with the same metric name.
+type family struct {
+	name    string
+	help    string
+	kind    metricType
+	samples []sample
+}
+
+// registry collects the samples of a scrape. Families are written in the
+// order they are first added, because the Prometheus text format requires the
+// samples of a family to be contiguous.
+type registry struct {
+	families map[string]*family
+	order    []string
+}
+
+func newRegistry() *registry {
+	return &registry{families: make(map[string]*family)}
+}
+
+// add adds a sample to the family with the specified name, which is created if
+// it doesn't exist yet.
+func (r *registry) add(name string, kind metricType, help string, value float64, labels ...label) {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind}
+		r.families[name] = f
+		r.order = append(r.order, name)
+	}
+
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// write writes the families in the Prometheus text exposition format.
+func (r *registry) write(w io.Writer) error {
+	for _, name := range r.order {
+		f := r.families[name]
+
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.kind); err != nil {
+			return err
+		}
+
+		for _, s := range f.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(s.labels), formatValue(s.value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatLabels(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(labels))
+
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", l.name, escapeLabel(l.value)))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return helpEscaper.Replace(value)
+}
+
+// sanitizeName converts a name to a valid metric name component by replacing
+// invalid characters with underscores.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
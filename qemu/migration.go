@@ -0,0 +1,784 @@
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// MigrationCapability represents a capability that can be enabled for a
+// migration.
+type MigrationCapability string
+
+const (
+	// MigrationCapabilityXBZRLE enables XOR-based zero run-length encoding of
+	// pages that are sent more than once.
+	MigrationCapabilityXBZRLE MigrationCapability = "xbzrle"
+
+	// MigrationCapabilityAutoConverge throttles the vCPUs if the migration
+	// doesn't converge.
+	MigrationCapabilityAutoConverge MigrationCapability = "auto-converge"
+
+	// MigrationCapabilityPostcopyRAM allows the migration to switch to
+	// postcopy mode, in which the destination starts running and fetches the
+	// remaining pages from the source on demand.
+	MigrationCapabilityPostcopyRAM MigrationCapability = "postcopy-ram"
+
+	// MigrationCapabilityMultifd sends pages over multiple channels in parallel.
+	MigrationCapabilityMultifd MigrationCapability = "multifd"
+
+	// MigrationCapabilityZeroBlocks skips sending zeroed blocks during block
+	// migration.
+	MigrationCapabilityZeroBlocks MigrationCapability = "zero-blocks"
+
+	// MigrationCapabilityEvents emits MIGRATION events when the status changes.
+	MigrationCapabilityEvents MigrationCapability = "events"
+
+	// MigrationCapabilityPauseBeforeSwitchover pauses the migration before the
+	// final switchover to the destination. The progress then has the status
+	// MigrationStatusPreSwitchover, and the migration only finishes once
+	// Migration.Continue is called (or it is canceled).
+	MigrationCapabilityPauseBeforeSwitchover MigrationCapability = "pause-before-switchover"
+)
+
+// MigrationStatus represents the status of a migration as reported by QEMU.
+type MigrationStatus string
+
+const (
+	// MigrationStatusNone indicates that no migration was started.
+	MigrationStatusNone MigrationStatus = "none"
+
+	// MigrationStatusSetup indicates that the migration is being set up.
+	MigrationStatusSetup MigrationStatus = "setup"
+
+	// MigrationStatusActive indicates that the guest RAM is being transferred
+	// while the VM is running (precopy).
+	MigrationStatusActive MigrationStatus = "active"
+
+	// MigrationStatusPostcopyActive indicates that the destination is running
+	// and fetches the remaining pages from the source on demand.
+	MigrationStatusPostcopyActive MigrationStatus = "postcopy-active"
+
+	// MigrationStatusPostcopyPaused indicates that the connection was lost
+	// during postcopy. The migration can be recovered, but the VM can't run
+	// until then.
+	MigrationStatusPostcopyPaused MigrationStatus = "postcopy-paused"
+
+	// MigrationStatusPreSwitchover indicates that the migration is paused
+	// before the switchover (see MigrationCapabilityPauseBeforeSwitchover).
+	MigrationStatusPreSwitchover MigrationStatus = "pre-switchover"
+
+	// MigrationStatusDevice indicates that the VM is stopped and the device
+	// state is being transferred.
+	MigrationStatusDevice MigrationStatus = "device"
+
+	// MigrationStatusCancelling indicates that the migration is being canceled.
+	MigrationStatusCancelling MigrationStatus = "cancelling"
+
+	// MigrationStatusCancelled indicates that the migration was canceled.
+	MigrationStatusCancelled MigrationStatus = "cancelled"
+
+	// MigrationStatusCompleted indicates that the migration completed and the
+	// destination is running the VM.
+	MigrationStatusCompleted MigrationStatus = "completed"
+
+	// MigrationStatusFailed indicates that the migration failed.
+	MigrationStatusFailed MigrationStatus = "failed"
+)
+
+// MigrationOptions represent the options for a migration started with Migrate.
+type MigrationOptions struct {
+	// URI is the URI the destination listens on and the source connects to,
+	// such as "tcp:127.0.0.1:4444" or "unix:/tmp/migrate.sock".
+	URI string
+
+	// Capabilities are the additional capabilities to enable on both sides.
+	Capabilities []MigrationCapability
+
+	// MaxBandwidth is the maximum bandwidth in bytes per second. A value of 0
+	// uses the QEMU default.
+	MaxBandwidth int64
+
+	// DowntimeLimit is the maximum amount of time the VM may be paused for the
+	// final switchover. A value of 0 uses the QEMU default.
+	DowntimeLimit time.Duration
+
+	// Postcopy enables postcopy mode. The migration switches to postcopy once
+	// the first pass over the guest RAM is complete.
+	Postcopy bool
+
+	// MultifdChannels enables multifd with the specified number of channels.
+	MultifdChannels int
+
+	// TLSCredentials is the ID of the TLS credentials object (for example an
+	// object.TLSCredentialsX509 with the "client" endpoint) the source uses for
+	// the migration. The destination uses the object with the same ID, and its
+	// endpoint is changed to "server" automatically.
+	TLSCredentials string
+
+	// TLSHostname is the hostname used to validate the certificate of the
+	// destination. Defaults to the host in the URI.
+	TLSHostname string
+
+	// DestinationOptions are additional options to pass to the destination.
+	// Options of the source that bind host resources (see
+	// QEMU.MigrationDestination) are replaced by the option with the same flag
+	// and ID.
+	DestinationOptions []*queso.Option
+
+	// Destination is the QEMU instance the VM is migrated to. If omitted, the
+	// destination is created with MigrationDestination. A custom destination
+	// must not be started yet, must have a QMP socket, and must use the
+	// debug.UseForIncomingMigration option with "defer".
+	Destination *QEMU
+
+	// PollInterval is the interval at which the migration progress is polled.
+	// Defaults to 500ms.
+	PollInterval time.Duration
+}
+
+// MigrationProgress represents the progress of a migration.
+type MigrationProgress struct {
+	// Status is the status of the migration.
+	Status MigrationStatus
+
+	// Transferred is the amount of RAM (in bytes) that was transferred.
+	Transferred int64
+
+	// Remaining is the amount of RAM (in bytes) that remains to be transferred.
+	Remaining int64
+
+	// Total is the total amount of guest RAM (in bytes).
+	Total int64
+
+	// DirtyRate is the rate (in bytes per second) at which the guest dirties
+	// pages.
+	DirtyRate int64
+
+	// Throughput is the throughput of the migration in megabits per second.
+	Throughput float64
+
+	// Passes is the number of times the dirty bitmap was synchronized, which
+	// corresponds to the number of passes over guest RAM.
+	Passes int
+
+	// ExpectedDowntime is the expected downtime of the switchover.
+	ExpectedDowntime time.Duration
+
+	// Elapsed is the time since the migration started.
+	Elapsed time.Duration
+}
+
+type migrationInfo struct {
+	Status           MigrationStatus `json:"status"`
+	TotalTime        int64           `json:"total-time"`
+	ExpectedDowntime int64           `json:"expected-downtime"`
+	ErrorDesc        string          `json:"error-desc"`
+	RAM              struct {
+		Transferred    int64   `json:"transferred"`
+		Remaining      int64   `json:"remaining"`
+		Total          int64   `json:"total"`
+		DirtyPagesRate int64   `json:"dirty-pages-rate"`
+		PageSize       int64   `json:"page-size"`
+		Mbps           float64 `json:"mbps"`
+		DirtySyncCount int     `json:"dirty-sync-count"`
+	} `json:"ram"`
+}
+
+func (info migrationInfo) progress() MigrationProgress {
+	return MigrationProgress{
+		Status:           info.Status,
+		Transferred:      info.RAM.Transferred,
+		Remaining:        info.RAM.Remaining,
+		Total:            info.RAM.Total,
+		DirtyRate:        info.RAM.DirtyPagesRate * info.RAM.PageSize,
+		Throughput:       info.RAM.Mbps,
+		Passes:           info.RAM.DirtySyncCount,
+		ExpectedDowntime: time.Duration(info.ExpectedDowntime) * time.Millisecond,
+		Elapsed:          time.Duration(info.TotalTime) * time.Millisecond,
+	}
+}
+
+// QueryMigration returns the progress of the current (or last) migration of
+// QEMU.
+func (q *QEMU) QueryMigration(ctx context.Context) (MigrationProgress, error) {
+	info, err := q.queryMigration(ctx)
+	if err != nil {
+		return MigrationProgress{}, err
+	}
+
+	return info.progress(), nil
+}
+
+func (q *QEMU) queryMigration(ctx context.Context) (migrationInfo, error) {
+	info := migrationInfo{}
+
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return info, err
+	}
+
+	err = client.Execute(ctx, "query-migrate", nil, &info)
+
+	return info, err
+}
+
+// MigrationDestination returns a new QEMU instance with the same executable,
+// options and environment variables as q that waits for an incoming migration.
+// If q has a RuntimeDirectory, the destination gets its own RuntimeDirectory
+// with the same options. The destination is started by Migrate.
+//
+// The source keeps running while the destination is started on the same host,
+// so options that bind host resources (such as forwarded ports, VNC displays
+// and listening chardev sockets) can't be copied. An error is returned unless
+// each of them is replaced by an option in DestinationOptions with the same
+// flag and ID.
+func (q *QEMU) MigrationDestination(opts MigrationOptions) (*QEMU, error) {
+	dest := New(q.exePath)
+	dest.env = q.env
+
+	options := make([]*queso.Option, 0, len(q.options)+len(opts.DestinationOptions)+1)
+
+	for _, option := range q.options {
+		switch option.Flag {
+		case "incoming", "loadvm", "pidfile", "daemonize":
+			continue
+		}
+
+		if resource := hostResource(option); resource != "" {
+			if isReplaced(option, opts.DestinationOptions) {
+				continue
+			}
+
+			return nil, fmt.Errorf("option %s binds a %s that is in use by the source, replace it with a destination option",
+				option.ArgsString(), resource)
+		}
+
+		if option.Flag == "object" && opts.TLSCredentials != "" && isTLSCredentials(option, opts.TLSCredentials) {
+			option = withProperty(option, "endpoint", "server")
+		}
+
+		options = append(options, option)
+	}
+
+	options = append(options, opts.DestinationOptions...)
+
+	// The destination defers listening until the capabilities and parameters
+	// are set, because some of them (such as multifd) must be set before the
+	// incoming connection is accepted.
+	options = append(options, debug.UseForIncomingMigration("defer"))
+
+	dest.SetOptions(options...)
+
+	if q.runtimeDir != nil {
+		dirOpts := q.runtimeDir.opts
+		if dirOpts.Name == "" {
+			dirOpts.Name = "queso"
+		}
+
+		dirOpts.Name += "-incoming"
+
+		dir, err := NewRuntimeDirectory(dirOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		dest.SetRuntimeDirectory(dir)
+	} else if q.qmpSocket != "" {
+		return nil, errors.New("the destination requires a RuntimeDirectory for its QMP socket")
+	}
+
+	return dest, nil
+}
+
+// Migration represents a migration started with Migrate.
+type Migration struct {
+	// Source is the QEMU instance the VM is migrated from.
+	Source *QEMU
+
+	// Destination is the QEMU instance the VM is migrated to.
+	Destination *QEMU
+
+	opts     MigrationOptions
+	progress chan MigrationProgress
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// Migrate live migrates the running VM to a new QEMU instance created with
+// MigrationDestination. The destination is started, the capabilities and
+// parameters are set on both sides, and the migration is started over QMP.
+// Use the returned Migration to track its progress, cancel it, or wait for it
+// to complete.
+//
+// If the migration fails or is canceled before the destination took over, the
+// destination is killed and the source is resumed (if it was running). Once
+// postcopy is active, the destination owns the VM state, so it is not rolled
+// back.
+//
+// Example
+//
+//	m, err := q.Migrate(ctx, qemu.MigrationOptions{
+//		URI:          "tcp:127.0.0.1:4444",
+//		MaxBandwidth: 100 << 20,
+//	})
+//
+//	for progress := range m.Progress() {
+//		log.Printf("%s: %d bytes remaining", progress.Status, progress.Remaining)
+//	}
+//
+//	if err := m.Wait(); err != nil {
+//		log.Fatal(err)
+//	}
+func (q *QEMU) Migrate(ctx context.Context, opts MigrationOptions) (*Migration, error) {
+	if opts.URI == "" {
+		return nil, errors.New("a URI is required for the migration")
+	}
+
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 500 * time.Millisecond
+	}
+
+	source, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dest := opts.Destination
+	if dest == nil {
+		dest, err = q.MigrationDestination(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dest.Start(); err != nil {
+		return nil, err
+	}
+
+	rollback := func(err error) error {
+		_ = dest.Kill()
+		_ = dest.Wait()
+
+		return err
+	}
+
+	target, err := dest.ConnectQMP(ctx)
+	if err != nil {
+		return nil, rollback(err)
+	}
+
+	if err := configureMigration(ctx, source, target, opts); err != nil {
+		return nil, rollback(err)
+	}
+
+	if err := target.Execute(ctx, "migrate-incoming", uriArgs(opts.URI), nil); err != nil {
+		return nil, rollback(err)
+	}
+
+	wasRunning, err := q.isRunning(ctx)
+	if err != nil {
+		return nil, rollback(err)
+	}
+
+	if err := source.Execute(ctx, "migrate", uriArgs(opts.URI), nil); err != nil {
+		return nil, rollback(err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	m := &Migration{
+		Source:      q,
+		Destination: dest,
+		opts:        opts,
+		progress:    make(chan MigrationProgress, 1),
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	go m.run(runCtx, wasRunning)
+
+	return m, nil
+}
+
+// Progress returns a channel that receives the progress of the migration. Only
+// the latest progress is retained if the receiver is slower than the poll
+// interval. The channel is closed once the migration is finished.
+func (m *Migration) Progress() <-chan MigrationProgress {
+	return m.progress
+}
+
+// Cancel cancels the migration. Use Wait to wait for the rollback to finish.
+func (m *Migration) Cancel() {
+	m.cancel()
+}
+
+// Continue continues a migration that is paused before the switchover (see
+// MigrationCapabilityPauseBeforeSwitchover). An error is returned if the
+// migration isn't paused.
+//
+// Example
+//
+//	for progress := range m.Progress() {
+//		if progress.Status == qemu.MigrationStatusPreSwitchover {
+//			// Release resources the source needs until the switchover.
+//			if err := m.Continue(ctx); err != nil {
+//				m.Cancel()
+//			}
+//		}
+//	}
+func (m *Migration) Continue(ctx context.Context) error {
+	args := map[string]interface{}{"state": string(MigrationStatusPreSwitchover)}
+
+	if err := m.Source.QMP().Execute(ctx, "migrate-continue", args, nil); err != nil {
+		return fmt.Errorf("failed to continue migration: %w", err)
+	}
+
+	return nil
+}
+
+// Done returns a channel that is closed once the migration is finished.
+func (m *Migration) Done() <-chan struct{} {
+	return m.done
+}
+
+// Wait waits for the migration to finish and returns an error if it failed or
+// was canceled.
+func (m *Migration) Wait() error {
+	<-m.done
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.err
+}
+
+func (m *Migration) finish(err error) {
+	m.mu.Lock()
+	m.err = err
+	m.mu.Unlock()
+
+	close(m.progress)
+	close(m.done)
+}
+
+func (m *Migration) report(progress MigrationProgress) {
+	// Drop the stale progress (if any), so the receiver always gets the latest.
+	select {
+	case <-m.progress:
+	default:
+	}
+
+	select {
+	case m.progress <- progress:
+	default:
+	}
+}
+
+func (m *Migration) run(ctx context.Context, wasRunning bool) {
+	ticker := time.NewTicker(m.opts.PollInterval)
+	defer ticker.Stop()
+
+	postcopyStarted := false
+	done := ctx.Done()
+
+	for {
+		info, err := m.Source.queryMigration(context.Background())
+		if err != nil {
+			m.finish(m.rollback(err, wasRunning, postcopyStarted))
+
+			return
+		}
+
+		m.report(info.progress())
+
+		switch info.Status {
+		case MigrationStatusCompleted:
+			m.finish(nil)
+
+			return
+
+		case MigrationStatusFailed, MigrationStatusCancelled:
+			err := fmt.Errorf("migration %s", info.Status)
+			if info.ErrorDesc != "" {
+				err = fmt.Errorf("migration %s: %s", info.Status, info.ErrorDesc)
+			}
+
+			m.finish(m.rollback(err, wasRunning, postcopyStarted))
+
+			return
+
+		case MigrationStatusPostcopyActive:
+			postcopyStarted = true
+
+		case MigrationStatusActive:
+			// The first pass over guest RAM is complete once the dirty bitmap
+			// was synchronized twice.
+			if m.opts.Postcopy && !postcopyStarted && info.RAM.DirtySyncCount >= 2 {
+				if err := m.Source.QMP().Execute(ctx, "migrate-start-postcopy", nil, nil); err == nil {
+					postcopyStarted = true
+				}
+			}
+		}
+
+		select {
+		case <-done:
+			if postcopyStarted {
+				// Canceling is not possible once the destination is running,
+				// so the migration is polled until it finishes.
+				done = nil
+
+				continue
+			}
+
+			_ = m.Source.QMP().Execute(context.Background(), "migrate_cancel", nil, nil)
+			m.finish(m.rollback(ctx.Err(), wasRunning, false))
+
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// rollback kills the destination and resumes the source after a failed or
+// canceled migration.
+func (m *Migration) rollback(err error, wasRunning bool, postcopyStarted bool) error {
+	if postcopyStarted {
+		return fmt.Errorf("migration failed during postcopy, the VM cannot be recovered: %w", err)
+	}
+
+	_ = m.Destination.Kill()
+	_ = m.Destination.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if wasRunning {
+		if running, statusErr := m.Source.isRunning(ctx); statusErr == nil && !running {
+			_ = m.Source.QMP().Execute(ctx, "cont", nil, nil)
+		}
+	}
+
+	return err
+}
+
+func (q *QEMU) isRunning(ctx context.Context) (bool, error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var status struct {
+		Running bool `json:"running"`
+	}
+
+	if err := client.Execute(ctx, "query-status", nil, &status); err != nil {
+		return false, err
+	}
+
+	return status.Running, nil
+}
+
+func configureMigration(ctx context.Context, source *qmp.Client, target *qmp.Client, opts MigrationOptions) error {
+	capabilities := make([]map[string]interface{}, 0)
+
+	addCapability := func(capability MigrationCapability) {
+		capabilities = append(capabilities, map[string]interface{}{
+			"capability": capability,
+			"state":      true,
+		})
+	}
+
+	for _, capability := range opts.Capabilities {
+		addCapability(capability)
+	}
+
+	if opts.Postcopy {
+		addCapability(MigrationCapabilityPostcopyRAM)
+	}
+
+	if opts.MultifdChannels > 0 {
+		addCapability(MigrationCapabilityMultifd)
+	}
+
+	if len(capabilities) != 0 {
+		args := map[string]interface{}{"capabilities": capabilities}
+
+		for _, client := range []*qmp.Client{source, target} {
+			if err := client.Execute(ctx, "migrate-set-capabilities", args, nil); err != nil {
+				return fmt.Errorf("failed to set migration capabilities: %w", err)
+			}
+		}
+	}
+
+	sourceParams := make(map[string]interface{})
+	targetParams := make(map[string]interface{})
+
+	if opts.MaxBandwidth > 0 {
+		sourceParams["max-bandwidth"] = opts.MaxBandwidth
+	}
+
+	if opts.DowntimeLimit > 0 {
+		sourceParams["downtime-limit"] = opts.DowntimeLimit.Milliseconds()
+	}
+
+	if opts.MultifdChannels > 0 {
+		sourceParams["multifd-channels"] = opts.MultifdChannels
+		targetParams["multifd-channels"] = opts.MultifdChannels
+	}
+
+	if opts.TLSCredentials != "" {
+		sourceParams["tls-creds"] = opts.TLSCredentials
+		targetParams["tls-creds"] = opts.TLSCredentials
+
+		hostname := opts.TLSHostname
+		if hostname == "" {
+			hostname = uriHost(opts.URI)
+		}
+
+		if hostname != "" {
+			sourceParams["tls-hostname"] = hostname
+		}
+	}
+
+	if len(sourceParams) != 0 {
+		if err := source.Execute(ctx, "migrate-set-parameters", sourceParams, nil); err != nil {
+			return fmt.Errorf("failed to set migration parameters on source: %w", err)
+		}
+	}
+
+	if len(targetParams) != 0 {
+		if err := target.Execute(ctx, "migrate-set-parameters", targetParams, nil); err != nil {
+			return fmt.Errorf("failed to set migration parameters on destination: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func uriArgs(uri string) map[string]interface{} {
+	return map[string]interface{}{"uri": uri}
+}
+
+// uriHost returns the host of a "tcp:host:port" migration URI.
+func uriHost(uri string) string {
+	if !strings.HasPrefix(uri, "tcp:") {
+		return ""
+	}
+
+	address := strings.TrimPrefix(uri, "tcp:")
+
+	index := strings.LastIndex(address, ":")
+	if index == -1 {
+		return ""
+	}
+
+	return strings.Trim(address[:index], "[]")
+}
+
+// hostResource returns a description of the host resource the option binds
+// (if any), which can't be bound by two QEMU instances at the same time.
+func hostResource(option *queso.Option) string {
+	table := option.Table()
+
+	for _, property := range option.Properties {
+		if property.Key == "hostfwd" {
+			return "forwarded host port"
+		}
+	}
+
+	switch option.Flag {
+	case "vnc":
+		if option.Name != "none" {
+			return "VNC display"
+		}
+
+	case "spice":
+		if _, ok := table["port"]; ok {
+			return "SPICE port"
+		}
+
+		if _, ok := table["tls-port"]; ok {
+			return "SPICE port"
+		}
+
+	case "gdb":
+		return "GDB server socket"
+
+	case "chardev":
+		if isEnabled(table["server"]) {
+			return "listening chardev socket"
+		}
+
+	case "serial", "parallel", "monitor", "qmp":
+		if isEnabled(table["server"]) {
+			return "listening socket"
+		}
+
+		for _, item := range strings.Split(option.Name, ",") {
+			if item == "server" || strings.HasPrefix(item, "server=") && isEnabled(item[len("server="):]) {
+				return "listening socket"
+			}
+		}
+	}
+
+	return ""
+}
+
+// isEnabled returns true if the value of a boolean property is enabled.
+func isEnabled(value string) bool {
+	switch value {
+	case "on", "true", "yes":
+		return true
+	}
+
+	return false
+}
+
+// isReplaced returns true if one of the replacements has the same flag and ID
+// as the option.
+func isReplaced(option *queso.Option, replacements []*queso.Option) bool {
+	for _, replacement := range replacements {
+		if replacement.Flag == option.Flag && replacement.Table()["id"] == option.Table()["id"] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTLSCredentials(option *queso.Option, id string) bool {
+	if !strings.HasPrefix(option.Name, "tls-creds-") {
+		return false
+	}
+
+	return option.Table()["id"] == id
+}
+
+// withProperty returns a copy of the option with the value of the property
+// with the specified key replaced.
+func withProperty(option *queso.Option, key string, value interface{}) *queso.Option {
+	clone := *option
+	clone.Properties = make([]*queso.Property, len(option.Properties))
+
+	for i, property := range option.Properties {
+		if property.Key == key {
+			property = queso.NewProperty(key, value)
+		}
+
+		clone.Properties[i] = property
+	}
+
+	return &clone
+}
@@ -0,0 +1,292 @@
+package qemu
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/chardev"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/network"
+	"github.com/mikerourke/queso/qemu/object"
+	"github.com/mikerourke/queso/qemu/qmp"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURIHost(t *testing.T) {
+	assert.Equal(t, "127.0.0.1", uriHost("tcp:127.0.0.1:4444"))
+	assert.Equal(t, "dest.example.com", uriHost("tcp:dest.example.com:4444"))
+	assert.Equal(t, "::1", uriHost("tcp:[::1]:4444"))
+	assert.Equal(t, "", uriHost("tcp:4444"))
+	assert.Equal(t, "", uriHost("unix:/tmp/migrate.sock"))
+}
+
+func TestMigrationDestination(t *testing.T) {
+	q := New("qemu-system-x86_64")
+	q.SetEnv("QEMU_AUDIO_DRV=none")
+	q.SetOptions(
+		Memory("1G"),
+		object.TLSCredentialsX509("tls0", "client", "/etc/pki/qemu"),
+		debug.UseForIncomingMigration("tcp:0:4444"),
+		queso.NewOption("pidfile", "/run/vm.pid"))
+
+	dest, err := q.MigrationDestination(MigrationOptions{TLSCredentials: "tls0"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, q.Env(), dest.Env())
+	assert.Equal(t, []string{
+		"-m", "1G",
+		"-object", "tls-creds-x509,id=tls0,endpoint=server,dir=/etc/pki/qemu",
+		"-incoming", "defer",
+	}, dest.Args())
+}
+
+func TestMigrationDestinationHostResources(t *testing.T) {
+	forward := network.WithForwardRule(network.NewHostForwardRule(network.PortTypeTCP, 2222, 22))
+
+	q := New("qemu-system-x86_64")
+	q.SetOptions(
+		network.UserBackend("n", forward),
+		chardev.UnixSocketBackend("console", "/tmp/console.sock", chardev.IsListeningSocket(true)))
+
+	_, err := q.MigrationDestination(MigrationOptions{})
+	assert.EqualError(t, err, "option -netdev user,id=n,hostfwd=tcp::2222-:22 binds a forwarded host port "+
+		"that is in use by the source, replace it with a destination option")
+
+	dest, err := q.MigrationDestination(MigrationOptions{
+		DestinationOptions: []*queso.Option{
+			network.UserBackend("n", network.WithForwardRule(network.NewHostForwardRule(network.PortTypeTCP, 2223, 22))),
+			chardev.UnixSocketBackend("console", "/tmp/console-incoming.sock", chardev.IsListeningSocket(true)),
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"-netdev", "user,id=n,hostfwd=tcp::2223-:22",
+		"-chardev", "socket,id=console,path=/tmp/console-incoming.sock,server=on",
+		"-incoming", "defer",
+	}, dest.Args())
+}
+
+func TestConfigureMigration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	servers := []*qmptest.Server{qmptest.NewServer(), qmptest.NewServer()}
+	clients := make([]*qmp.Client, len(servers))
+
+	for index, server := range servers {
+		server.Respond("migrate-set-capabilities", nil)
+		server.Respond("migrate-set-parameters", nil)
+
+		client, err := server.Dial(ctx)
+		assert.NoError(t, err)
+
+		defer client.Close()
+
+		clients[index] = client
+	}
+
+	err := configureMigration(ctx, clients[0], clients[1], MigrationOptions{
+		URI:             "tcp:dest.example.com:4444",
+		Postcopy:        true,
+		MultifdChannels: 4,
+		MaxBandwidth:    100 << 20,
+		DowntimeLimit:   300 * time.Millisecond,
+		TLSCredentials:  "tls0",
+	})
+	assert.NoError(t, err)
+
+	capabilities := []interface{}{
+		map[string]interface{}{"capability": "postcopy-ram", "state": true},
+		map[string]interface{}{"capability": "multifd", "state": true},
+	}
+
+	source := servers[0].Commands()
+	assert.Len(t, source, 2)
+	assert.Equal(t, capabilities, source[0].Arguments["capabilities"])
+	assert.Equal(t, map[string]interface{}{
+		"max-bandwidth":    float64(100 << 20),
+		"downtime-limit":   float64(300),
+		"multifd-channels": float64(4),
+		"tls-creds":        "tls0",
+		"tls-hostname":     "dest.example.com",
+	}, source[1].Arguments)
+
+	target := servers[1].Commands()
+	assert.Len(t, target, 2)
+	assert.Equal(t, capabilities, target[0].Arguments["capabilities"])
+	assert.Equal(t, map[string]interface{}{
+		"multifd-channels": float64(4),
+		"tls-creds":        "tls0",
+	}, target[1].Arguments)
+}
+
+// testMigration returns a Migration whose source is connected to the server,
+// which reports the specified statuses in order (the last one is repeated).
+func testMigration(t *testing.T, server *qmptest.Server, statuses ...MigrationStatus) *Migration {
+	var mu sync.Mutex
+
+	server.Handle("query-migrate", func(map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+
+		return map[string]interface{}{
+			"status": status,
+			"ram":    map[string]interface{}{"transferred": 1 << 20, "remaining": 1 << 20, "total": 2 << 20},
+		}, nil
+	})
+
+	client, err := server.Dial(context.Background())
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	source := New("qemu-system-x86_64")
+	source.qmp = client
+
+	return &Migration{
+		Source:      source,
+		Destination: New("qemu-system-x86_64"),
+		opts:        MigrationOptions{PollInterval: 10 * time.Millisecond},
+		progress:    make(chan MigrationProgress, 1),
+		done:        make(chan struct{}),
+	}
+}
+
+func TestMigrationRun(t *testing.T) {
+	server := qmptest.NewServer()
+	m := testMigration(t, server, MigrationStatusSetup, MigrationStatusActive, MigrationStatusCompleted)
+
+	go m.run(context.Background(), true)
+
+	var last MigrationProgress
+
+	for progress := range m.Progress() {
+		last = progress
+	}
+
+	assert.NoError(t, m.Wait())
+	assert.Equal(t, MigrationStatusCompleted, last.Status)
+	assert.Equal(t, int64(1<<20), last.Remaining)
+}
+
+func TestMigrationContinue(t *testing.T) {
+	server := qmptest.NewServer()
+	m := testMigration(t, server, MigrationStatusActive)
+
+	var mu sync.Mutex
+
+	continued := false
+
+	server.Handle("migrate-continue", func(map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		continued = true
+
+		return map[string]interface{}{}, nil
+	})
+
+	// The migration stays paused before the switchover until it is continued.
+	server.Handle("query-migrate", func(map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if continued {
+			return map[string]interface{}{"status": MigrationStatusCompleted}, nil
+		}
+
+		return map[string]interface{}{"status": MigrationStatusPreSwitchover}, nil
+	})
+
+	go m.run(context.Background(), true)
+
+	for progress := range m.Progress() {
+		if progress.Status == MigrationStatusPreSwitchover {
+			assert.NoError(t, m.Continue(context.Background()))
+		}
+	}
+
+	assert.NoError(t, m.Wait())
+
+	command, err := server.WaitForCommand(context.Background(), "migrate-continue")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"state": "pre-switchover"}, command.Arguments)
+}
+
+func TestMigrationRunFailed(t *testing.T) {
+	server := qmptest.NewServer()
+	m := testMigration(t, server, MigrationStatusActive, MigrationStatusFailed)
+
+	go m.run(context.Background(), true)
+
+	assert.EqualError(t, m.Wait(), "migration failed")
+}
+
+func TestMigrationRunCancel(t *testing.T) {
+	server := qmptest.NewServer()
+	server.Respond("migrate_cancel", nil)
+
+	m := testMigration(t, server, MigrationStatusActive)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go m.run(ctx, false)
+
+	cancel()
+
+	assert.ErrorIs(t, m.Wait(), context.Canceled)
+
+	_, err := server.WaitForCommand(context.Background(), "migrate_cancel")
+	assert.NoError(t, err)
+}
+
+func TestMigrationRunCancelDuringPostcopy(t *testing.T) {
+	server := qmptest.NewServer()
+
+	m := testMigration(t, server, MigrationStatusPostcopyActive)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go m.run(ctx, false)
+
+	_, err := server.WaitForCommand(context.Background(), "query-migrate")
+	assert.NoError(t, err)
+
+	cancel()
+
+	// The migration can't be canceled, so it keeps polling at the interval.
+	time.Sleep(100 * time.Millisecond)
+
+	queries := 0
+
+	for _, command := range server.Commands() {
+		assert.NotEqual(t, "migrate_cancel", command.Name)
+
+		if command.Name == "query-migrate" {
+			queries++
+		}
+	}
+
+	assert.LessOrEqual(t, queries, 15)
+
+	select {
+	case <-m.Done():
+		t.Fatal("migration finished during postcopy")
+
+	default:
+	}
+
+	server.Respond("query-migrate", map[string]interface{}{"status": MigrationStatusCompleted})
+
+	assert.NoError(t, m.Wait())
+}
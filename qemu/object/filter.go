@@ -131,6 +131,15 @@ func FilterDump(
 	return queso.NewOption("object", "filter-dump", props...)
 }
 
+// FilterReplay records or replays the packets of the network device with ID
+// netdev for deterministic record/replay (see debug.WithReplayMode). Every
+// network backend of a recorded VM requires its own FilterReplay.
+func FilterReplay(id string, netdev string) *queso.Option {
+	return queso.NewOption("object", "filter-replay",
+		queso.NewProperty("id", id),
+		queso.NewProperty("netdev", netdev))
+}
+
 // ColoCompare gets packet from character devices with ID primaryIn and secondaryIn,
 // then compares whether the payload of the primary packet and secondary packet are
 // the same. If same, it will output primary packet to device with ID outdev, else
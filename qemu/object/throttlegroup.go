@@ -0,0 +1,111 @@
+package object
+
+import (
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/blockdev"
+)
+
+// ThrottleGroup creates a throttling quota group with the specified id. All
+// block nodes that reference the group (see blockdev.ThrottleDriver) are
+// accounted for together. The limits can be changed while the VM is running
+// with the throttle package.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		object.ThrottleGroup("limits0",
+//			object.WithThrottleRequestRate(blockdev.IOOperationAll, 200),
+//			object.WithThrottleRequestRateBurst(blockdev.IOOperationAll, 300)),
+//		blockdev.FileDriver("disk.img",
+//			blockdev.WithNodeName("disk_file")),
+//		blockdev.ThrottleDriver("limits0",
+//			blockdev.WithNodeName("disk"),
+//			blockdev.WithFile("disk_file")))
+//
+// Invocation
+//
+//	qemu-system-x86_64 \
+//		-object throttle-group,id=limits0,x-iops-total=200,x-iops-total-max=300 \
+//		-blockdev driver=file,node-name=disk_file,filename=disk.img \
+//		-blockdev driver=throttle,throttle-group=limits0,node-name=disk,file=disk_file
+func ThrottleGroup(id string, properties ...*ThrottleGroupProperty) *queso.Option {
+	props := []*queso.Property{queso.NewProperty("id", id)}
+
+	for _, property := range properties {
+		props = append(props, property.Property)
+	}
+
+	return queso.NewOption("object", "throttle-group", props...)
+}
+
+// ThrottleGroupProperty represents a property that can be used with a
+// ThrottleGroup option.
+type ThrottleGroupProperty struct {
+	*queso.Property
+}
+
+// NewThrottleGroupProperty returns a new instance of ThrottleGroupProperty.
+func NewThrottleGroupProperty(key string, value interface{}) *ThrottleGroupProperty {
+	return &ThrottleGroupProperty{
+		Property: queso.NewProperty(key, value),
+	}
+}
+
+// throttleOperation returns the suffix of the throttle-group properties for
+// the specified operation.
+func throttleOperation(operation blockdev.IOOperation) string {
+	switch operation {
+	case blockdev.IOOperationRead:
+		return "read"
+
+	case blockdev.IOOperationWrite:
+		return "write"
+
+	default:
+		return "total"
+	}
+}
+
+// WithThrottleBandwidth specifies the bandwidth limit in bytes per second for
+// the specified operation of a ThrottleGroup.
+func WithThrottleBandwidth(operation blockdev.IOOperation, bytesPerSecond int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-bps-"+throttleOperation(operation), bytesPerSecond)
+}
+
+// WithThrottleBandwidthBurst specifies the burst in bytes per second for the
+// specified operation of a ThrottleGroup. Bursts allow the I/O to spike above
+// the limit temporarily.
+func WithThrottleBandwidthBurst(operation blockdev.IOOperation, bytesPerSecond int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-bps-"+throttleOperation(operation)+"-max", bytesPerSecond)
+}
+
+// WithThrottleBandwidthBurstLength specifies the maximum number of seconds a
+// bandwidth burst of the specified operation of a ThrottleGroup can last.
+func WithThrottleBandwidthBurstLength(operation blockdev.IOOperation, seconds int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-bps-"+throttleOperation(operation)+"-max-length", seconds)
+}
+
+// WithThrottleRequestRate specifies the request rate limit in requests per
+// second for the specified operation of a ThrottleGroup.
+func WithThrottleRequestRate(operation blockdev.IOOperation, requestsPerSecond int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-iops-"+throttleOperation(operation), requestsPerSecond)
+}
+
+// WithThrottleRequestRateBurst specifies the burst in requests per second for
+// the specified operation of a ThrottleGroup.
+func WithThrottleRequestRateBurst(operation blockdev.IOOperation, requestsPerSecond int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-iops-"+throttleOperation(operation)+"-max", requestsPerSecond)
+}
+
+// WithThrottleRequestRateBurstLength specifies the maximum number of seconds a
+// request rate burst of the specified operation of a ThrottleGroup can last.
+func WithThrottleRequestRateBurstLength(operation blockdev.IOOperation, seconds int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-iops-"+throttleOperation(operation)+"-max-length", seconds)
+}
+
+// WithThrottleRequestSize sets the bytes of a request that count as a new
+// request for the request rate limits of a ThrottleGroup. Use this to prevent
+// guests from circumventing the limits by sending fewer but larger requests.
+func WithThrottleRequestSize(bytes int) *ThrottleGroupProperty {
+	return NewThrottleGroupProperty("x-iops-size", bytes)
+}
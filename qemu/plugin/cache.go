@@ -0,0 +1,316 @@
+package plugin
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mikerourke/queso"
+)
+
+// EvictionPolicy represents the cache eviction policy of a Cache plugin.
+type EvictionPolicy string
+
+const (
+	// EvictionPolicyLRU evicts the least recently used block.
+	EvictionPolicyLRU EvictionPolicy = "lru"
+
+	// EvictionPolicyFIFO evicts the oldest block.
+	EvictionPolicyFIFO EvictionPolicy = "fifo"
+
+	// EvictionPolicyRandom evicts a random block.
+	EvictionPolicyRandom EvictionPolicy = "rand"
+)
+
+// Cache loads the cache plugin from the specified file, which simulates the
+// L1 data and instruction caches (and optionally an L2 cache) of each vCPU and
+// logs the cache statistics and the instructions with the most misses when
+// QEMU exits. Use ParseCacheStats to parse the output.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(append(plugin.Log("cache.log"),
+//		plugin.Cache("contrib/plugins/libcache.so",
+//			plugin.WithDataCacheSize(32768),
+//			plugin.WithEvictionPolicy(plugin.EvictionPolicyLRU),
+//			plugin.WithLimit(10)))...)
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D cache.log -plugin file=contrib/plugins/libcache.so,dcachesize=32768,evict=lru,limit=10
+func Cache(file string, properties ...*Property) *queso.Option {
+	return Load(file, properties...)
+}
+
+// WithDataCacheSize specifies the size of the L1 data cache in bytes.
+func WithDataCacheSize(size int) *Property {
+	return NewProperty("dcachesize", size)
+}
+
+// WithDataCacheAssociativity specifies the associativity of the L1 data cache.
+func WithDataCacheAssociativity(ways int) *Property {
+	return NewProperty("dassoc", ways)
+}
+
+// WithDataCacheBlockSize specifies the block size of the L1 data cache in bytes.
+func WithDataCacheBlockSize(size int) *Property {
+	return NewProperty("dblksize", size)
+}
+
+// WithInstructionCacheSize specifies the size of the L1 instruction cache in
+// bytes.
+func WithInstructionCacheSize(size int) *Property {
+	return NewProperty("icachesize", size)
+}
+
+// WithInstructionCacheAssociativity specifies the associativity of the L1
+// instruction cache.
+func WithInstructionCacheAssociativity(ways int) *Property {
+	return NewProperty("iassoc", ways)
+}
+
+// WithInstructionCacheBlockSize specifies the block size of the L1 instruction
+// cache in bytes.
+func WithInstructionCacheBlockSize(size int) *Property {
+	return NewProperty("iblksize", size)
+}
+
+// IsL2CacheEnabled specifies whether a unified L2 cache is simulated.
+func IsL2CacheEnabled(enabled bool) *Property {
+	return NewProperty("l2", enabled)
+}
+
+// WithL2CacheSize specifies the size of the L2 cache in bytes. It implies
+// IsL2CacheEnabled.
+func WithL2CacheSize(size int) *Property {
+	return NewProperty("l2cachesize", size)
+}
+
+// WithL2CacheAssociativity specifies the associativity of the L2 cache. It
+// implies IsL2CacheEnabled.
+func WithL2CacheAssociativity(ways int) *Property {
+	return NewProperty("l2assoc", ways)
+}
+
+// WithL2CacheBlockSize specifies the block size of the L2 cache in bytes. It
+// implies IsL2CacheEnabled.
+func WithL2CacheBlockSize(size int) *Property {
+	return NewProperty("l2blksize", size)
+}
+
+// WithEvictionPolicy specifies the eviction policy of the simulated caches.
+// The default is EvictionPolicyLRU.
+func WithEvictionPolicy(policy EvictionPolicy) *Property {
+	return NewProperty("evict", policy)
+}
+
+// WithCores specifies the number of simulated cores. By default, the number of
+// vCPUs is used in system emulation.
+func WithCores(count int) *Property {
+	return NewProperty("cores", count)
+}
+
+// WithLimit specifies the number of instructions with the most misses that are
+// logged. The default is 32.
+func WithLimit(count int) *Property {
+	return NewProperty("limit", count)
+}
+
+// CoreCacheStats are the cache statistics of a core logged by the cache plugin.
+type CoreCacheStats struct {
+	// Core is the index of the core, or -1 for the sum of all cores.
+	Core int
+
+	// DataAccesses is the number of L1 data cache accesses.
+	DataAccesses uint64
+
+	// DataMisses is the number of L1 data cache misses.
+	DataMisses uint64
+
+	// DataMissRate is the percentage of data accesses that missed.
+	DataMissRate float64
+
+	// InstructionAccesses is the number of L1 instruction cache accesses.
+	InstructionAccesses uint64
+
+	// InstructionMisses is the number of L1 instruction cache misses.
+	InstructionMisses uint64
+
+	// InstructionMissRate is the percentage of instruction fetches that
+	// missed.
+	InstructionMissRate float64
+
+	// L2Accesses is the number of L2 cache accesses. The L2 statistics are
+	// only logged if the L2 cache is enabled and was accessed.
+	L2Accesses uint64
+
+	// L2Misses is the number of L2 cache misses.
+	L2Misses uint64
+
+	// L2MissRate is the percentage of L2 cache accesses that missed.
+	L2MissRate float64
+}
+
+// CacheMiss is an instruction with cache misses logged by the cache plugin.
+type CacheMiss struct {
+	// Address is the virtual address of the instruction.
+	Address uint64
+
+	// Symbol is the name of the symbol the instruction belongs to, if known.
+	Symbol string
+
+	// Misses is the number of misses caused by the instruction.
+	Misses uint64
+
+	// Disassembly is the disassembled instruction.
+	Disassembly string
+}
+
+// CacheStats are the statistics logged by the cache plugin.
+type CacheStats struct {
+	// Cores are the statistics of each core.
+	Cores []CoreCacheStats
+
+	// Sum is the sum of the statistics of all cores. It is only logged if
+	// there are multiple cores.
+	Sum *CoreCacheStats
+
+	// DataMisses are the instructions with the most L1 data cache misses.
+	DataMisses []CacheMiss
+
+	// FetchMisses are the instructions with the most L1 instruction cache
+	// misses.
+	FetchMisses []CacheMiss
+
+	// L2Misses are the instructions with the most L2 cache misses.
+	L2Misses []CacheMiss
+}
+
+// ParseCacheStats parses the output of the cache plugin, which consists of a
+// table with the statistics of each core followed by lists of the instructions
+// with the most misses:
+//
+//	core #, data accesses, data misses, dmiss rate, insn accesses, insn misses, imiss rate
+//	0       996695         563             0.0565%  2108125        2211            0.1049%
+//
+//	address, data misses, instruction
+//	0xffffffff81001000 (native_write_cr4), 62, movq %rdi, %cr4
+func ParseCacheStats(r io.Reader) (*CacheStats, error) {
+	stats := new(CacheStats)
+
+	var misses *[]CacheMiss
+
+	inTable := false
+
+	err := scanLines(r, func(line string) {
+		switch {
+		case strings.HasPrefix(line, "core #"):
+			inTable, misses = true, nil
+
+			return
+
+		case strings.HasPrefix(line, "address, data misses"):
+			inTable, misses = false, &stats.DataMisses
+
+			return
+
+		case strings.HasPrefix(line, "address, fetch misses"):
+			inTable, misses = false, &stats.FetchMisses
+
+			return
+
+		case strings.HasPrefix(line, "address, L2 misses"):
+			inTable, misses = false, &stats.L2Misses
+
+			return
+		}
+
+		if inTable {
+			if core, ok := parseCoreCacheStats(line); ok {
+				if core.Core == -1 {
+					stats.Sum = &core
+				} else {
+					stats.Cores = append(stats.Cores, core)
+				}
+			}
+		} else if misses != nil {
+			if miss, ok := parseCacheMiss(line); ok {
+				*misses = append(*misses, miss)
+			}
+		}
+	})
+
+	return stats, err
+}
+
+func parseCoreCacheStats(line string) (CoreCacheStats, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 7 && len(fields) != 10 {
+		return CoreCacheStats{}, false
+	}
+
+	stats := CoreCacheStats{Core: -1}
+
+	if fields[0] != "sum" {
+		core, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return CoreCacheStats{}, false
+		}
+
+		stats.Core = core
+	}
+
+	targets := []interface{}{
+		&stats.DataAccesses, &stats.DataMisses, &stats.DataMissRate,
+		&stats.InstructionAccesses, &stats.InstructionMisses, &stats.InstructionMissRate,
+		&stats.L2Accesses, &stats.L2Misses, &stats.L2MissRate,
+	}
+
+	for index, field := range fields[1:] {
+		var err error
+
+		switch target := targets[index].(type) {
+		case *uint64:
+			*target, err = strconv.ParseUint(field, 10, 64)
+
+		case *float64:
+			*target, err = strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		}
+
+		if err != nil {
+			return CoreCacheStats{}, false
+		}
+	}
+
+	return stats, true
+}
+
+func parseCacheMiss(line string) (CacheMiss, bool) {
+	fields := strings.SplitN(line, ",", 3)
+	if len(fields) != 3 || !strings.HasPrefix(fields[0], "0x") {
+		return CacheMiss{}, false
+	}
+
+	var miss CacheMiss
+
+	address := fields[0]
+
+	if open := strings.Index(address, " ("); open != -1 {
+		miss.Symbol = strings.TrimSuffix(address[open+2:], ")")
+		address = address[:open]
+	}
+
+	var err error
+
+	if miss.Address, err = parseUint(address); err != nil {
+		return CacheMiss{}, false
+	}
+
+	if miss.Misses, err = parseUint(fields[1]); err != nil {
+		return CacheMiss{}, false
+	}
+
+	miss.Disassembly = strings.TrimSpace(fields[2])
+
+	return miss, true
+}
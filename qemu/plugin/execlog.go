@@ -0,0 +1,230 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mikerourke/queso"
+)
+
+// ExecLog loads the execlog plugin from the specified file, which logs every
+// executed instruction along with its memory accesses. The log grows quickly,
+// so WithAddressFilter or WithInstructionFilter is recommended for anything
+// but short runs. Use ExecLogReader or ReadCoverage to parse the output.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(append(plugin.Log("exec.log"),
+//		plugin.ExecLog("contrib/plugins/libexeclog.so",
+//			plugin.WithInstructionFilter("cpuid")))...)
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D exec.log -plugin file=contrib/plugins/libexeclog.so,ifilter=cpuid
+func ExecLog(file string, properties ...*Property) *queso.Option {
+	return Load(file, properties...)
+}
+
+// WithInstructionFilter only logs the instructions whose disassembly contains
+// the specified text. It can be specified multiple times for an ExecLog.
+func WithInstructionFilter(match string) *Property {
+	return NewProperty("ifilter", match)
+}
+
+// WithAddressFilter only logs the instruction at the specified virtual address.
+// It can be specified multiple times for an ExecLog.
+func WithAddressFilter(address uint64) *Property {
+	return NewProperty("afilter", fmt.Sprintf("0x%x", address))
+}
+
+// WithRegister logs the changes of the registers matching the specified name,
+// which can be a globbing pattern, after each instruction of an ExecLog. This
+// requires QEMU 8.2 or later.
+func WithRegister(name string) *Property {
+	return NewProperty("reg", name)
+}
+
+// MemoryAccess is a memory access of an Instruction logged by the execlog
+// plugin.
+type MemoryAccess struct {
+	// Store is true for a store and false for a load.
+	Store bool
+
+	// Address is the physical address in system emulation, or the virtual
+	// address in user-mode emulation.
+	Address uint64
+
+	// Device is the name of the memory region (such as "ram" or "io") that
+	// was accessed. It is only available in system emulation.
+	Device string
+}
+
+// Instruction is an executed instruction logged by the execlog plugin.
+type Instruction struct {
+	// CPU is the index of the vCPU that executed the instruction.
+	CPU int
+
+	// Address is the virtual address of the instruction.
+	Address uint64
+
+	// Opcode is the (first 32 bits of the) instruction encoding.
+	Opcode uint64
+
+	// Disassembly is the disassembled instruction, such as "movl %eax, %ebx".
+	Disassembly string
+
+	// Accesses are the memory accesses of the instruction.
+	Accesses []MemoryAccess
+
+	// Registers are the new values of the registers that changed (see
+	// WithRegister).
+	Registers map[string]uint64
+}
+
+// ExecLogReader reads the instructions logged by the execlog plugin.
+type ExecLogReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewExecLogReader returns an ExecLogReader that reads from r.
+func NewExecLogReader(r io.Reader) *ExecLogReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &ExecLogReader{scanner: scanner}
+}
+
+// Next returns the next instruction, skipping lines that aren't execlog
+// records. At the end of the log, it returns io.EOF.
+func (r *ExecLogReader) Next() (Instruction, error) {
+	for r.scanner.Scan() {
+		if insn, ok := parseInstruction(r.scanner.Text()); ok {
+			return insn, nil
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Instruction{}, err
+	}
+
+	return Instruction{}, io.EOF
+}
+
+// parseInstruction parses a line of the form:
+//
+//	0, 0xfff0, 0xea, "ljmpl $0xf000, $0xe05b", load, 0x00001000, ram
+func parseInstruction(line string) (Instruction, bool) {
+	open := strings.Index(line, `"`)
+	closing := strings.LastIndex(line, `"`)
+
+	if open == -1 || closing == open {
+		return Instruction{}, false
+	}
+
+	fields := strings.Split(strings.TrimSuffix(strings.TrimSpace(line[:open]), ","), ",")
+	if len(fields) != 3 {
+		return Instruction{}, false
+	}
+
+	var insn Instruction
+
+	cpu, err := parseUint(fields[0])
+	if err != nil {
+		return Instruction{}, false
+	}
+
+	insn.CPU = int(cpu)
+
+	if insn.Address, err = parseUint(fields[1]); err != nil {
+		return Instruction{}, false
+	}
+
+	if insn.Opcode, err = parseUint(fields[2]); err != nil {
+		return Instruction{}, false
+	}
+
+	insn.Disassembly = line[open+1 : closing]
+
+	var access *MemoryAccess
+
+	for _, field := range strings.Split(line[closing+1:], ",") {
+		field = strings.TrimSpace(field)
+
+		switch {
+		case field == "":
+
+		case field == "load" || field == "store":
+			insn.Accesses = append(insn.Accesses, MemoryAccess{Store: field == "store"})
+			access = &insn.Accesses[len(insn.Accesses)-1]
+
+		case strings.Contains(field, "->"):
+			parts := strings.SplitN(field, "->", 2)
+
+			if value, err := parseUint(parts[1]); err == nil {
+				if insn.Registers == nil {
+					insn.Registers = make(map[string]uint64)
+				}
+
+				insn.Registers[strings.TrimSpace(parts[0])] = value
+			}
+
+		case access != nil && strings.HasPrefix(field, "0x"):
+			access.Address, _ = parseUint(field)
+
+		case access != nil:
+			access.Device = field
+		}
+	}
+
+	return insn, true
+}
+
+// Coverage maps the addresses of executed instructions to the number of times
+// they were executed.
+type Coverage map[uint64]uint64
+
+// ReadCoverage reads the log of the execlog plugin and counts the executions of
+// each instruction, e.g. to measure the code coverage of firmware.
+func ReadCoverage(r io.Reader) (Coverage, error) {
+	coverage := make(Coverage)
+	reader := NewExecLogReader(r)
+
+	for {
+		insn, err := reader.Next()
+		if err == io.EOF {
+			return coverage, nil
+		}
+
+		if err != nil {
+			return coverage, err
+		}
+
+		coverage[insn.Address]++
+	}
+}
+
+// Addresses returns the addresses of the executed instructions in ascending
+// order.
+func (c Coverage) Addresses() []uint64 {
+	addresses := make([]uint64, 0, len(c))
+
+	for address := range c {
+		addresses = append(addresses, address)
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i] < addresses[j]
+	})
+
+	return addresses
+}
+
+// Merge adds the counts of another Coverage, e.g. from a different run.
+func (c Coverage) Merge(other Coverage) {
+	for address, count := range other {
+		c[address] += count
+	}
+}
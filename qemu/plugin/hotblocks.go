@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mikerourke/queso"
+)
+
+// HotBlocks loads the hotblocks plugin from the specified file, which counts
+// the executions of each translation block and logs the 20 most executed
+// blocks when QEMU exits. Use ParseHotBlocks to parse the output.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(append(plugin.Log("hotblocks.log"),
+//		plugin.HotBlocks("contrib/plugins/libhotblocks.so", plugin.IsInline(true)))...)
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D hotblocks.log -plugin file=contrib/plugins/libhotblocks.so,inline=on
+func HotBlocks(file string, properties ...*Property) *queso.Option {
+	return Load(file, properties...)
+}
+
+// HotBlock is a translation block logged by the hotblocks plugin.
+type HotBlock struct {
+	// Address is the virtual address of the first instruction of the block.
+	Address uint64
+
+	// Translations is the number of times the block was translated.
+	Translations uint64
+
+	// Instructions is the number of instructions in the block.
+	Instructions uint64
+
+	// Executions is the number of times the block was executed.
+	Executions uint64
+}
+
+// ParseHotBlocks parses the output of the hotblocks plugin, which is a table of
+// the form:
+//
+//	pc, tcount, icount, ecount
+//	0x00000000000fe05b, 1, 3, 1024
+func ParseHotBlocks(r io.Reader) ([]HotBlock, error) {
+	blocks := make([]HotBlock, 0)
+
+	err := scanLines(r, func(line string) {
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 || !strings.HasPrefix(fields[0], "0x") {
+			return
+		}
+
+		values := make([]uint64, len(fields))
+
+		for index, field := range fields {
+			value, err := strconv.ParseUint(strings.TrimSpace(field), 0, 64)
+			if err != nil {
+				return
+			}
+
+			values[index] = value
+		}
+
+		blocks = append(blocks, HotBlock{
+			Address:      values[0],
+			Translations: values[1],
+			Instructions: values[2],
+			Executions:   values[3],
+		})
+	})
+
+	return blocks, err
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mikerourke/queso"
+)
+
+// Insn loads the insn plugin from the specified file, which counts the executed
+// instructions and logs the count when QEMU exits. Use ParseInstructionCounts
+// to parse the output.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(append(plugin.Log("insn.log"),
+//		plugin.Insn("tests/plugin/libinsn.so", plugin.WithSizes(true)))...)
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D insn.log -plugin file=tests/plugin/libinsn.so,sizes=on
+func Insn(file string, properties ...*Property) *queso.Option {
+	return Load(file, properties...)
+}
+
+// WithSizes specifies whether an Insn plugin counts the instructions by size
+// (in bytes) instead of by vCPU.
+func WithSizes(enabled bool) *Property {
+	return NewProperty("sizes", enabled)
+}
+
+// WithMatch makes an Insn plugin additionally count the executions of the
+// instructions whose disassembly starts with the specified text. It can be
+// specified multiple times.
+func WithMatch(match string) *Property {
+	return NewProperty("match", match)
+}
+
+// InstructionCounts are the instruction counts logged by the insn plugin.
+type InstructionCounts struct {
+	// Total is the total number of executed instructions. It is not logged if
+	// the instructions are counted by size.
+	Total uint64
+
+	// PerCPU maps vCPU indexes to the number of instructions they executed.
+	// It is only logged if the instructions are counted with callbacks.
+	PerCPU map[int]uint64
+
+	// Sizes maps instruction sizes (in bytes) to the number of executed
+	// instructions of that size (see WithSizes).
+	Sizes map[int]uint64
+}
+
+// ParseInstructionCounts parses the output of the insn plugin, which consists
+// of lines such as "cpu 0 insns: 1234", "total insns: 1234" or
+// "len 3 bytes: 1234 insns".
+func ParseInstructionCounts(r io.Reader) (*InstructionCounts, error) {
+	counts := &InstructionCounts{
+		PerCPU: make(map[int]uint64),
+		Sizes:  make(map[int]uint64),
+	}
+
+	err := scanLines(r, func(line string) {
+		var index int
+		var value uint64
+
+		if _, err := fmt.Sscanf(line, "len %d bytes: %d insns", &index, &value); err == nil {
+			counts.Sizes[index] = value
+
+			return
+		}
+
+		key, value, ok := countValue(line)
+		if !ok {
+			return
+		}
+
+		switch {
+		case key == "insns" || key == "total insns":
+			counts.Total = value
+
+		case strings.HasPrefix(key, "cpu ") && strings.HasSuffix(key, " insns"):
+			if _, err := fmt.Sscanf(key, "cpu %d insns", &index); err == nil {
+				counts.PerCPU[index] = value
+			}
+		}
+	})
+
+	return counts, err
+}
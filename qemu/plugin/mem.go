@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mikerourke/queso"
+)
+
+// MemoryTrack represents the memory accesses a Mem plugin counts.
+type MemoryTrack string
+
+const (
+	// MemoryTrackReads counts loads.
+	MemoryTrackReads MemoryTrack = "r"
+
+	// MemoryTrackWrites counts stores.
+	MemoryTrackWrites MemoryTrack = "w"
+
+	// MemoryTrackAll counts loads and stores.
+	MemoryTrackAll MemoryTrack = "rw"
+)
+
+// Mem loads the mem plugin from the specified file, which counts the memory
+// accesses of the guest and logs the count when QEMU exits. Use
+// ParseMemoryCounts to parse the output.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(append(plugin.Log("mem.log"),
+//		plugin.Mem("tests/plugin/libmem.so",
+//			plugin.WithTrack(plugin.MemoryTrackWrites),
+//			plugin.WithHostAddress(true)))...)
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D mem.log -plugin file=tests/plugin/libmem.so,track=w,haddr=on
+func Mem(file string, properties ...*Property) *queso.Option {
+	return Load(file, properties...)
+}
+
+// WithCallback specifies whether a Mem plugin counts the accesses with
+// callbacks.
+func WithCallback(enabled bool) *Property {
+	return NewProperty("callback", enabled)
+}
+
+// WithTrack specifies the memory accesses a Mem plugin counts. The default is
+// MemoryTrackAll.
+func WithTrack(track MemoryTrack) *Property {
+	return NewProperty("track", track)
+}
+
+// WithHostAddress specifies whether a Mem plugin resolves the host address
+// of each access, which is required to count the accesses to I/O memory.
+func WithHostAddress(enabled bool) *Property {
+	return NewProperty("haddr", enabled)
+}
+
+// MemoryCounts are the memory access counts logged by the mem plugin.
+type MemoryCounts struct {
+	// Accesses is the number of memory accesses.
+	Accesses uint64
+
+	// IOAccesses is the number of accesses to I/O memory (see
+	// WithHostAddress).
+	IOAccesses uint64
+}
+
+// ParseMemoryCounts parses the output of the mem plugin, which consists of lines
+// such as "mem accesses: 1234" (prefixed with "inline" or "callback" in newer
+// QEMU versions) and "io accesses: 12".
+func ParseMemoryCounts(r io.Reader) (*MemoryCounts, error) {
+	counts := new(MemoryCounts)
+
+	err := scanLines(r, func(line string) {
+		key, value, ok := countValue(line)
+		if !ok {
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(key, "mem accesses"):
+			counts.Accesses = value
+
+		case key == "io accesses":
+			counts.IOAccesses = value
+		}
+	})
+
+	return counts, err
+}
@@ -0,0 +1,102 @@
+// Package plugin is used to load the TCG plugins bundled with QEMU and to
+// parse the output they write to the QEMU log. Plugins are only supported by
+// the TCG accelerator, and QEMU must be built with --enable-plugins. See
+// https://qemu.readthedocs.io/en/latest/devel/tcg-plugins.html for more
+// details.
+package plugin
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/debug"
+)
+
+// Property represents a property (i.e. an argument) that can be passed to a
+// plugin.
+type Property struct {
+	*queso.Property
+}
+
+// NewProperty returns a new instance of Property.
+func NewProperty(key string, value interface{}) *Property {
+	return &Property{
+		Property: queso.NewProperty(key, value),
+	}
+}
+
+// Load loads the plugin from the specified shared library file with the
+// specified properties. It is equivalent to debug.Plugin, but accepts the
+// properties of this package.
+func Load(file string, properties ...*Property) *queso.Option {
+	props := []*queso.Property{queso.NewProperty("file", file)}
+
+	for _, property := range properties {
+		props = append(props, property.Property)
+	}
+
+	return queso.NewOption("plugin", "", props...)
+}
+
+// Log enables the "plugin" log item and writes the log to the specified file,
+// which is where the output of the plugins ends up. Pass the options to
+// qemu.QEMU.SetOptions along with the plugins.
+//
+// Example
+//
+//	options := append(plugin.Log("plugin.log"),
+//		plugin.HotBlocks("/usr/lib/qemu/plugins/libhotblocks.so"))
+//
+// Invocation
+//
+//	qemu-system-x86_64 -d plugin -D plugin.log -plugin file=/usr/lib/qemu/plugins/libhotblocks.so
+func Log(file string) []*queso.Option {
+	return []*queso.Option{
+		debug.EnableLoggingForItems("plugin"),
+		debug.OutputToLogFile(file),
+	}
+}
+
+// IsInline specifies whether the hotblocks, insn and mem plugins count with
+// inline operations instead of callbacks, which is faster but not thread-safe,
+// so counts may be lost with multiple vCPUs.
+func IsInline(enabled bool) *Property {
+	return NewProperty("inline", enabled)
+}
+
+// scanLines calls fn with each line read from r, without the line ending.
+// Because the QEMU log may contain the output of other log items, the parsers
+// skip the lines they don't recognize.
+func scanLines(r io.Reader, fn func(line string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		fn(strings.TrimRight(scanner.Text(), "\r"))
+	}
+
+	return scanner.Err()
+}
+
+// parseUint parses a decimal or "0x"-prefixed hexadecimal number.
+func parseUint(text string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimSpace(text), 0, 64)
+}
+
+// countValue returns the number after the colon in a line like "insns: 1234".
+func countValue(line string) (string, uint64, bool) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return strings.TrimSpace(parts[0]), value, true
+}
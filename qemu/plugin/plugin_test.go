@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilders(t *testing.T) {
+	option := ExecLog("libexeclog.so",
+		WithInstructionFilter("cpuid"),
+		WithAddressFilter(0xfff0))
+	assert.Equal(t, "-plugin file=libexeclog.so,ifilter=cpuid,afilter=0xfff0", option.ArgsString())
+
+	option = Mem("libmem.so", IsInline(true), WithTrack(MemoryTrackWrites))
+	assert.Equal(t, "-plugin file=libmem.so,inline=on,track=w", option.ArgsString())
+
+	options := Log("plugin.log")
+	assert.Equal(t, "-d plugin", options[0].ArgsString())
+	assert.Equal(t, "-D plugin.log", options[1].ArgsString())
+}
+
+func TestExecLogReader(t *testing.T) {
+	log := strings.Join([]string{
+		`Trace 0: 0x7f00 [00000000/000fe05b/0x00000000/0x00000000]`,
+		`0, 0xfe05b, 0x2e, "cmpl $0, %cs:0x6c08", load, 0x000f6c08, ram`,
+		`1, 0xfe066, 0x89, "movl %eax, (%ebx)", store, 0x00001000, pc.ram, rax -> 0x10`,
+		`0, 0x401000, 0xc3, "ret"`,
+	}, "\n")
+
+	reader := NewExecLogReader(strings.NewReader(log))
+
+	insn, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, Instruction{
+		CPU:         0,
+		Address:     0xfe05b,
+		Opcode:      0x2e,
+		Disassembly: "cmpl $0, %cs:0x6c08",
+		Accesses:    []MemoryAccess{{Address: 0xf6c08, Device: "ram"}},
+	}, insn)
+
+	insn, err = reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, insn.CPU)
+	assert.Equal(t, []MemoryAccess{{Store: true, Address: 0x1000, Device: "pc.ram"}}, insn.Accesses)
+	assert.Equal(t, map[string]uint64{"rax": 0x10}, insn.Registers)
+
+	insn, err = reader.Next()
+	assert.NoError(t, err)
+	assert.Empty(t, insn.Accesses)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+
+	coverage, err := ReadCoverage(strings.NewReader(log + "\n" + log))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0xfe05b, 0xfe066, 0x401000}, coverage.Addresses())
+	assert.Equal(t, uint64(2), coverage[0x401000])
+}
+
+func TestParsers(t *testing.T) {
+	blocks, err := ParseHotBlocks(strings.NewReader(
+		"collected 2 entries in the hash table\npc, tcount, icount, ecount\n" +
+			"0x00000000000fe05b, 1, 3, 1024\n0x0000000000401000, 2, 5, 12\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []HotBlock{
+		{Address: 0xfe05b, Translations: 1, Instructions: 3, Executions: 1024},
+		{Address: 0x401000, Translations: 2, Instructions: 5, Executions: 12},
+	}, blocks)
+
+	insns, err := ParseInstructionCounts(strings.NewReader(
+		"cpu 0 insns: 100\ncpu 1 insns: 50\ntotal insns: 150\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(150), insns.Total)
+	assert.Equal(t, map[int]uint64{0: 100, 1: 50}, insns.PerCPU)
+
+	insns, err = ParseInstructionCounts(strings.NewReader("len 1 bytes: 20 insns\nlen 3 bytes: 7 insns\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]uint64{1: 20, 3: 7}, insns.Sizes)
+
+	mem, err := ParseMemoryCounts(strings.NewReader("inline mem accesses: 4096\nio accesses: 12\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, &MemoryCounts{Accesses: 4096, IOAccesses: 12}, mem)
+}
+
+func TestParseCacheStats(t *testing.T) {
+	output := `core #, data accesses, data misses, dmiss rate, insn accesses, insn misses, imiss rate
+0       996695         563             0.0565%  2108125        2211            0.1049%
+1       1000           10              1.0000%  2000           20              1.0000%
+sum     997695         573             0.0574%  2110125        2231            0.1057%
+
+address, data misses, instruction
+0xffffffff81001000 (native_write_cr4), 62, movq %rdi, %cr4
+0x401000, 3, ret
+
+address, fetch misses, instruction
+0x401004, 9, nop
+`
+
+	stats, err := ParseCacheStats(strings.NewReader(output))
+	assert.NoError(t, err)
+	assert.Len(t, stats.Cores, 2)
+	assert.Equal(t, CoreCacheStats{
+		Core:                0,
+		DataAccesses:        996695,
+		DataMisses:          563,
+		DataMissRate:        0.0565,
+		InstructionAccesses: 2108125,
+		InstructionMisses:   2211,
+		InstructionMissRate: 0.1049,
+	}, stats.Cores[0])
+	assert.Equal(t, -1, stats.Sum.Core)
+	assert.Equal(t, []CacheMiss{
+		{Address: 0xffffffff81001000, Symbol: "native_write_cr4", Misses: 62, Disassembly: "movq %rdi, %cr4"},
+		{Address: 0x401000, Misses: 3, Disassembly: "ret"},
+	}, stats.DataMisses)
+	assert.Equal(t, []CacheMiss{{Address: 0x401004, Misses: 9, Disassembly: "nop"}}, stats.FetchMisses)
+	assert.Empty(t, stats.L2Misses)
+}
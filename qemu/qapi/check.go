@@ -0,0 +1,113 @@
+package qapi
+
+import (
+	"context"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// UsedCommands are the QMP commands called by the packages of queso. Some of
+// them (such as query-stats) are only available in newer QEMU versions.
+var UsedCommands = []string{
+	"balloon",
+	"block-commit",
+	"block-dirty-bitmap-add",
+	"block-dirty-bitmap-clear",
+	"block-dirty-bitmap-disable",
+	"block-dirty-bitmap-enable",
+	"block-dirty-bitmap-merge",
+	"block-dirty-bitmap-remove",
+	"block-job-set-speed",
+	"block-stream",
+	"block_set_io_throttle",
+	"blockdev-add",
+	"blockdev-backup",
+	"blockdev-del",
+	"blockdev-mirror",
+	"blockdev-snapshot-sync",
+	"cont",
+	"device-list-properties",
+	"device_add",
+	"device_del",
+	"dump-guest-memory",
+	"input-send-event",
+	"job-cancel",
+	"job-complete",
+	"job-dismiss",
+	"job-finalize",
+	"job-pause",
+	"job-resume",
+	"migrate",
+	"migrate-continue",
+	"migrate-incoming",
+	"migrate-set-capabilities",
+	"migrate-set-parameters",
+	"migrate-start-postcopy",
+	"migrate_cancel",
+	"object-add",
+	"object-del",
+	"qmp_capabilities",
+	"qom-get",
+	"qom-list",
+	"qom-list-properties",
+	"qom-list-types",
+	"qom-set",
+	"query-balloon",
+	"query-block",
+	"query-blockstats",
+	"query-dump",
+	"query-dump-guest-memory-capability",
+	"query-hotpluggable-cpus",
+	"query-jobs",
+	"query-memory-devices",
+	"query-migrate",
+	"query-named-block-nodes",
+	"query-qmp-schema",
+	"query-stats",
+	"query-status",
+	"quit",
+	"screendump",
+	"send-key",
+	"stop",
+	"trace-event-get-state",
+	"trace-event-set-state",
+	"transaction",
+}
+
+// MissingCommands returns the commands that are missing from the schema. If
+// no commands are specified, UsedCommands are checked.
+func (s *Schema) MissingCommands(commands ...string) []string {
+	if len(commands) == 0 {
+		commands = UsedCommands
+	}
+
+	missing := make([]string, 0)
+
+	for _, command := range commands {
+		if !s.HasCommand(command) {
+			missing = append(missing, command)
+		}
+	}
+
+	return missing
+}
+
+// CheckCommands loads the schema of the connected QEMU instance and returns the
+// specified commands (or UsedCommands) it lacks, so the caller can report them
+// or disable the features that depend on them. Calling a missing command fails
+// with a CommandNotFound error.
+//
+// Example
+//
+//	missing, err := qapi.CheckCommands(ctx, q.QMP())
+//	for _, command := range missing {
+//		fmt.Printf("QEMU doesn't support %s\n", command)
+//	}
+func CheckCommands(ctx context.Context, client *qmp.Client, commands ...string) ([]string, error) {
+	schema, err := Load(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.MissingCommands(commands...), nil
+}
@@ -0,0 +1,395 @@
+package qapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateOptions represent the options for Generate.
+type GenerateOptions struct {
+	// Package is the name of the package of the generated code.
+	Package string
+
+	// Version is the QEMU version the schema was taken from, which is
+	// recorded in the generated code.
+	Version string
+}
+
+// builtinTypes maps the builtin types of the schema to Go types.
+var builtinTypes = map[string]string{
+	"str":    "string",
+	"int":    "int64",
+	"int8":   "int8",
+	"int16":  "int16",
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint8":  "uint8",
+	"uint16": "uint16",
+	"uint32": "uint32",
+	"uint64": "uint64",
+	"size":   "uint64",
+	"number": "float64",
+	"bool":   "bool",
+	"null":   "*struct{}",
+	"any":    "interface{}",
+	"QType":  "string",
+}
+
+// initialisms are the words that are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"cpu": true, "id": true, "io": true, "ip": true, "json": true, "kvm": true,
+	"qemu": true, "qmp": true, "qom": true, "ram": true, "ui": true, "url": true,
+	"uuid": true, "vm": true, "vnc": true,
+}
+
+// generator assigns Go names to the (masked) type names of a schema and
+// writes the Go code.
+type generator struct {
+	schema *Schema
+	opts   GenerateOptions
+	names  map[string]string
+	taken  map[string]bool
+	types  []string
+	buf    bytes.Buffer
+}
+
+// Generate generates Go code from the schema: a type for each enum, object and
+// alternate, a constant for each event, and a function for each command that
+// executes it with a qmp.Client. Because QEMU masks the names of the types in
+// the schema, the types are named after the commands, events and members that
+// use them (e.g. the result of query-status is QueryStatusResult).
+func Generate(schema *Schema, opts GenerateOptions) ([]byte, error) {
+	g := &generator{
+		schema: schema,
+		opts:   opts,
+		names:  make(map[string]string),
+		taken:  make(map[string]bool),
+	}
+
+	entities := schema.Entities()
+
+	// The types are named in the order they are first used by a command or
+	// event, so the names don't depend on the masked names.
+	for _, entity := range entities {
+		switch entity.MetaType {
+		case MetaTypeCommand:
+			g.name(entity.ArgType, goName(entity.Name)+"Arguments")
+			g.name(entity.RetType, goName(entity.Name)+"Result")
+
+		case MetaTypeEvent:
+			g.name(entity.ArgType, goName(strings.ToLower(entity.Name))+"Data")
+		}
+	}
+
+	g.writeHeader()
+
+	for _, name := range g.types {
+		g.writeType(schema.Entity(name))
+	}
+
+	g.writeEvents(entities)
+
+	for _, entity := range entities {
+		if entity.MetaType == MetaTypeCommand {
+			g.writeCommand(entity)
+		}
+	}
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return source, nil
+}
+
+// name assigns the specified Go name to a type (if it doesn't have a name
+// yet), and then names the types it refers to.
+func (g *generator) name(typeName string, preferred string) {
+	entity := g.schema.Entity(typeName)
+	if entity == nil || g.names[typeName] != "" {
+		return
+	}
+
+	switch entity.MetaType {
+	case MetaTypeArray:
+		g.name(entity.ElementType, preferred)
+
+		return
+
+	case MetaTypeBuiltin:
+		return
+
+	case MetaTypeObject:
+		// Empty objects (such as the arguments of commands without arguments)
+		// are omitted.
+		if len(entity.Members) == 0 && len(entity.Variants) == 0 {
+			return
+		}
+	}
+
+	unique := preferred
+	for index := 2; g.taken[unique]; index++ {
+		unique = preferred + strconv.Itoa(index)
+	}
+
+	g.names[typeName] = unique
+	g.taken[unique] = true
+	g.types = append(g.types, typeName)
+
+	for _, member := range entity.Members {
+		g.name(member.Type, unique+goName(member.Name))
+	}
+
+	for _, variant := range entity.Variants {
+		g.name(variant.Type, unique+goName(variant.Case))
+	}
+}
+
+// goType returns the Go type of a schema type. Empty objects have no Go type.
+func (g *generator) goType(typeName string) string {
+	if builtin, ok := builtinTypes[typeName]; ok {
+		return builtin
+	}
+
+	entity := g.schema.Entity(typeName)
+	if entity == nil {
+		return "interface{}"
+	}
+
+	switch entity.MetaType {
+	case MetaTypeArray:
+		if element := g.goType(entity.ElementType); element != "" {
+			return "[]" + element
+		}
+
+		return "[]struct{}"
+
+	case MetaTypeBuiltin:
+		return "interface{}"
+	}
+
+	return g.names[typeName]
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) writeHeader() {
+	g.printf("// Code generated by qapigen from the QMP schema of QEMU %s. DO NOT EDIT.\n\n", g.opts.Version)
+	g.printf("package %s\n\n", g.opts.Package)
+	g.printf("import (\n\"context\"\n")
+
+	if g.usesJSON() {
+		g.printf("\"encoding/json\"\n")
+	}
+
+	g.printf("\n\"github.com/mikerourke/queso/qemu/qmp\"\n)\n\n")
+	g.printf("// QEMUVersion is the version of QEMU the code was generated for.\n")
+	g.printf("const QEMUVersion = %q\n\n", g.opts.Version)
+}
+
+func (g *generator) usesJSON() bool {
+	for _, name := range g.types {
+		if g.schema.Entity(name).MetaType == MetaTypeAlternate {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *generator) writeType(entity *Entity) {
+	name := g.names[entity.Name]
+
+	switch entity.MetaType {
+	case MetaTypeEnum:
+		g.printf("// %s is an enum of the QMP schema.\ntype %s string\n\nconst (\n", name, name)
+
+		for _, value := range entity.Values {
+			g.printf("%s%s %s = %q\n", name, goName(value), name, value)
+		}
+
+		g.printf(")\n\n")
+
+	case MetaTypeAlternate:
+		g.printf("// %s is an alternate of the QMP schema, which is one of several types.\n", name)
+		g.printf("type %s = json.RawMessage\n\n", name)
+
+	case MetaTypeObject:
+		g.printf("// %s is an object of the QMP schema.\n", name)
+		g.writeDeprecated(entity.Features, true)
+		g.printf("type %s struct {\n", name)
+
+		fields := make(map[string]bool)
+
+		for _, member := range entity.Members {
+			g.writeField(member, fields)
+		}
+
+		// The members of the variants are part of the same JSON object, so
+		// they are included as optional fields.
+		for _, variant := range entity.Variants {
+			if variantEntity := g.schema.Entity(variant.Type); variantEntity != nil {
+				for _, member := range variantEntity.Members {
+					member.Optional = true
+					g.writeField(member, fields)
+				}
+			}
+		}
+
+		g.printf("}\n\n")
+	}
+}
+
+func (g *generator) writeField(member Member, fields map[string]bool) {
+	name := goName(member.Name)
+	if fields[name] {
+		return
+	}
+
+	fields[name] = true
+
+	fieldType := g.goType(member.Type)
+	if fieldType == "" {
+		fieldType = "struct{}"
+	}
+
+	tag := member.Name
+
+	if member.Optional {
+		tag += ",omitempty"
+
+		if !strings.HasPrefix(fieldType, "[]") && !strings.HasPrefix(fieldType, "*") &&
+			fieldType != "interface{}" && !g.isMetaType(member.Type, MetaTypeAlternate) {
+			fieldType = "*" + fieldType
+		}
+	}
+
+	g.writeDeprecated(member.Features, false)
+	g.printf("%s %s `json:%q`\n", name, fieldType, tag)
+}
+
+func (g *generator) isMetaType(typeName string, metaType MetaType) bool {
+	entity := g.schema.Entity(typeName)
+
+	return entity != nil && entity.MetaType == metaType
+}
+
+// writeDeprecated writes a deprecation notice if the features include
+// "deprecated". If the notice follows a doc comment, it is separated by an
+// empty comment line.
+func (g *generator) writeDeprecated(features []string, separate bool) {
+	for _, feature := range features {
+		if feature != "deprecated" {
+			continue
+		}
+
+		if separate {
+			g.printf("//\n")
+		}
+
+		g.printf("// Deprecated: deprecated in the QMP schema.\n")
+	}
+}
+
+func (g *generator) writeEvents(entities []*Entity) {
+	events := make([]*Entity, 0)
+
+	for _, entity := range entities {
+		if entity.MetaType == MetaTypeEvent {
+			events = append(events, entity)
+		}
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
+	g.printf("// Names of the events of the QMP schema. The data of an event is decoded\n")
+	g.printf("// into the corresponding Data type with qmp.Event.DecodeData.\nconst (\n")
+
+	for _, event := range events {
+		g.printf("Event%s = %q\n", goName(strings.ToLower(event.Name)), event.Name)
+	}
+
+	g.printf(")\n\n")
+}
+
+func (g *generator) writeCommand(entity *Entity) {
+	name := goName(entity.Name)
+
+	params := "ctx context.Context, client *qmp.Client"
+	args := "nil"
+
+	if argType := g.goType(entity.ArgType); argType != "" {
+		params += ", args " + argType
+		args = "args"
+	}
+
+	g.printf("// %s executes the %s command.\n", name, entity.Name)
+	g.writeDeprecated(entity.Features, true)
+
+	retType := g.goType(entity.RetType)
+
+	if retType == "" || retType == "*struct{}" {
+		g.printf("func %s(%s) error {\n", name, params)
+		g.printf("return client.Execute(ctx, %q, %s, nil)\n}\n\n", entity.Name, args)
+
+		return
+	}
+
+	// Objects are returned as pointers, while other types (such as arrays and
+	// enums) are returned as values.
+	if g.isMetaType(entity.RetType, MetaTypeObject) {
+		g.printf("func %s(%s) (*%s, error) {\n", name, params, retType)
+		g.printf("var result %s\n\n", retType)
+		g.printf("if err := client.Execute(ctx, %q, %s, &result); err != nil {\nreturn nil, err\n}\n\n",
+			entity.Name, args)
+		g.printf("return &result, nil\n}\n\n")
+
+		return
+	}
+
+	g.printf("func %s(%s) (%s, error) {\n", name, params, retType)
+	g.printf("var result %s\n\n", retType)
+	g.printf("err := client.Execute(ctx, %q, %s, &result)\n\n", entity.Name, args)
+	g.printf("return result, err\n}\n\n")
+}
+
+// goName converts a name of the schema (such as "query-status" or "qom-path")
+// to an exported Go name (such as "QueryStatus" or "QOMPath").
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+
+	result := new(strings.Builder)
+
+	for _, word := range words {
+		lower := strings.ToLower(word)
+
+		if initialisms[lower] {
+			result.WriteString(strings.ToUpper(word))
+		} else {
+			result.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+
+	name = result.String()
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+
+	return name
+}
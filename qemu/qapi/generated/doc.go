@@ -0,0 +1,9 @@
+// Package generated contains the Go types and command functions generated by
+// cmd/qapigen from the QMP schema of a pinned QEMU version. The schema in
+// schema.json contains the entities of the output of query-qmp-schema that are
+// reachable from qapi.UsedCommands (along with the run state, version and CPU
+// commands and the lifecycle events). To add commands, append their entities
+// from the full schema and run go generate.
+package generated
+
+//go:generate go run ../../../cmd/qapigen -schema schema.json -package generated -version 8.2.0 -out generated.go
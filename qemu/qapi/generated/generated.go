@@ -0,0 +1,3932 @@
+// Code generated by qapigen from the QMP schema of QEMU 8.2.0. DO NOT EDIT.
+
+package generated
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// QEMUVersion is the version of QEMU the code was generated for.
+const QEMUVersion = "8.2.0"
+
+// QueryStatusResult is an object of the QMP schema.
+type QueryStatusResult struct {
+	Running bool `json:"running"`
+	// Deprecated: deprecated in the QMP schema.
+	Singlestep *bool                   `json:"singlestep,omitempty"`
+	Status     QueryStatusResultStatus `json:"status"`
+}
+
+// QueryStatusResultStatus is an enum of the QMP schema.
+type QueryStatusResultStatus string
+
+const (
+	QueryStatusResultStatusDebug         QueryStatusResultStatus = "debug"
+	QueryStatusResultStatusInmigrate     QueryStatusResultStatus = "inmigrate"
+	QueryStatusResultStatusInternalError QueryStatusResultStatus = "internal-error"
+	QueryStatusResultStatusIOError       QueryStatusResultStatus = "io-error"
+	QueryStatusResultStatusPaused        QueryStatusResultStatus = "paused"
+	QueryStatusResultStatusPostmigrate   QueryStatusResultStatus = "postmigrate"
+	QueryStatusResultStatusPrelaunch     QueryStatusResultStatus = "prelaunch"
+	QueryStatusResultStatusFinishMigrate QueryStatusResultStatus = "finish-migrate"
+	QueryStatusResultStatusRestoreVM     QueryStatusResultStatus = "restore-vm"
+	QueryStatusResultStatusRunning       QueryStatusResultStatus = "running"
+	QueryStatusResultStatusSaveVM        QueryStatusResultStatus = "save-vm"
+	QueryStatusResultStatusShutdown      QueryStatusResultStatus = "shutdown"
+	QueryStatusResultStatusSuspended     QueryStatusResultStatus = "suspended"
+	QueryStatusResultStatusWatchdog      QueryStatusResultStatus = "watchdog"
+	QueryStatusResultStatusGuestPanicked QueryStatusResultStatus = "guest-panicked"
+	QueryStatusResultStatusColo          QueryStatusResultStatus = "colo"
+)
+
+// QueryNameResult is an object of the QMP schema.
+type QueryNameResult struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// QueryVersionResult is an object of the QMP schema.
+type QueryVersionResult struct {
+	QEMU    QueryVersionResultQEMU `json:"qemu"`
+	Package string                 `json:"package"`
+}
+
+// QueryVersionResultQEMU is an object of the QMP schema.
+type QueryVersionResultQEMU struct {
+	Major int64 `json:"major"`
+	Minor int64 `json:"minor"`
+	Micro int64 `json:"micro"`
+}
+
+// QueryKVMResult is an object of the QMP schema.
+type QueryKVMResult struct {
+	Enabled bool `json:"enabled"`
+	Present bool `json:"present"`
+}
+
+// QueryCpusFastResult is an object of the QMP schema.
+type QueryCpusFastResult struct {
+	CPUIndex int64                             `json:"cpu-index"`
+	QOMPath  string                            `json:"qom-path"`
+	ThreadID int64                             `json:"thread-id"`
+	Props    *QueryCpusFastResultProps         `json:"props,omitempty"`
+	Target   QueryCpusFastResultTarget         `json:"target"`
+	CPUState *QueryCpusFastResultS390xCPUState `json:"cpu-state,omitempty"`
+}
+
+// QueryCpusFastResultProps is an object of the QMP schema.
+type QueryCpusFastResultProps struct {
+	NodeID    *int64 `json:"node-id,omitempty"`
+	DrawerID  *int64 `json:"drawer-id,omitempty"`
+	BookID    *int64 `json:"book-id,omitempty"`
+	SocketID  *int64 `json:"socket-id,omitempty"`
+	DieID     *int64 `json:"die-id,omitempty"`
+	ClusterID *int64 `json:"cluster-id,omitempty"`
+	CoreID    *int64 `json:"core-id,omitempty"`
+	ThreadID  *int64 `json:"thread-id,omitempty"`
+}
+
+// QueryCpusFastResultTarget is an enum of the QMP schema.
+type QueryCpusFastResultTarget string
+
+const (
+	QueryCpusFastResultTargetAarch64      QueryCpusFastResultTarget = "aarch64"
+	QueryCpusFastResultTargetAlpha        QueryCpusFastResultTarget = "alpha"
+	QueryCpusFastResultTargetArm          QueryCpusFastResultTarget = "arm"
+	QueryCpusFastResultTargetAvr          QueryCpusFastResultTarget = "avr"
+	QueryCpusFastResultTargetCris         QueryCpusFastResultTarget = "cris"
+	QueryCpusFastResultTargetHppa         QueryCpusFastResultTarget = "hppa"
+	QueryCpusFastResultTargetI386         QueryCpusFastResultTarget = "i386"
+	QueryCpusFastResultTargetLoongarch64  QueryCpusFastResultTarget = "loongarch64"
+	QueryCpusFastResultTargetM68k         QueryCpusFastResultTarget = "m68k"
+	QueryCpusFastResultTargetMicroblaze   QueryCpusFastResultTarget = "microblaze"
+	QueryCpusFastResultTargetMicroblazeel QueryCpusFastResultTarget = "microblazeel"
+	QueryCpusFastResultTargetMips         QueryCpusFastResultTarget = "mips"
+	QueryCpusFastResultTargetMips64       QueryCpusFastResultTarget = "mips64"
+	QueryCpusFastResultTargetMips64el     QueryCpusFastResultTarget = "mips64el"
+	QueryCpusFastResultTargetMipsel       QueryCpusFastResultTarget = "mipsel"
+	QueryCpusFastResultTargetNios2        QueryCpusFastResultTarget = "nios2"
+	QueryCpusFastResultTargetOr1k         QueryCpusFastResultTarget = "or1k"
+	QueryCpusFastResultTargetPpc          QueryCpusFastResultTarget = "ppc"
+	QueryCpusFastResultTargetPpc64        QueryCpusFastResultTarget = "ppc64"
+	QueryCpusFastResultTargetRiscv32      QueryCpusFastResultTarget = "riscv32"
+	QueryCpusFastResultTargetRiscv64      QueryCpusFastResultTarget = "riscv64"
+	QueryCpusFastResultTargetRx           QueryCpusFastResultTarget = "rx"
+	QueryCpusFastResultTargetS390x        QueryCpusFastResultTarget = "s390x"
+	QueryCpusFastResultTargetSh4          QueryCpusFastResultTarget = "sh4"
+	QueryCpusFastResultTargetSh4eb        QueryCpusFastResultTarget = "sh4eb"
+	QueryCpusFastResultTargetSparc        QueryCpusFastResultTarget = "sparc"
+	QueryCpusFastResultTargetSparc64      QueryCpusFastResultTarget = "sparc64"
+	QueryCpusFastResultTargetTricore      QueryCpusFastResultTarget = "tricore"
+	QueryCpusFastResultTargetX8664        QueryCpusFastResultTarget = "x86_64"
+	QueryCpusFastResultTargetXtensa       QueryCpusFastResultTarget = "xtensa"
+	QueryCpusFastResultTargetXtensaeb     QueryCpusFastResultTarget = "xtensaeb"
+)
+
+// QueryCpusFastResultS390x is an object of the QMP schema.
+type QueryCpusFastResultS390x struct {
+	CPUState QueryCpusFastResultS390xCPUState `json:"cpu-state"`
+}
+
+// QueryCpusFastResultS390xCPUState is an enum of the QMP schema.
+type QueryCpusFastResultS390xCPUState string
+
+const (
+	QueryCpusFastResultS390xCPUStateUninitialized QueryCpusFastResultS390xCPUState = "uninitialized"
+	QueryCpusFastResultS390xCPUStateStopped       QueryCpusFastResultS390xCPUState = "stopped"
+	QueryCpusFastResultS390xCPUStateCheckStop     QueryCpusFastResultS390xCPUState = "check-stop"
+	QueryCpusFastResultS390xCPUStateOperating     QueryCpusFastResultS390xCPUState = "operating"
+	QueryCpusFastResultS390xCPUStateLoad          QueryCpusFastResultS390xCPUState = "load"
+)
+
+// BalloonArguments is an object of the QMP schema.
+type BalloonArguments struct {
+	Value int64 `json:"value"`
+}
+
+// QueryBalloonResult is an object of the QMP schema.
+type QueryBalloonResult struct {
+	Actual int64 `json:"actual"`
+}
+
+// ScreendumpArguments is an object of the QMP schema.
+type ScreendumpArguments struct {
+	Filename string                     `json:"filename"`
+	Device   *string                    `json:"device,omitempty"`
+	Head     *int64                     `json:"head,omitempty"`
+	Format   *ScreendumpArgumentsFormat `json:"format,omitempty"`
+}
+
+// ScreendumpArgumentsFormat is an enum of the QMP schema.
+type ScreendumpArgumentsFormat string
+
+const (
+	ScreendumpArgumentsFormatPpm ScreendumpArgumentsFormat = "ppm"
+	ScreendumpArgumentsFormatPng ScreendumpArgumentsFormat = "png"
+)
+
+// QMPCapabilitiesArguments is an object of the QMP schema.
+type QMPCapabilitiesArguments struct {
+	Enable []QMPCapabilitiesArgumentsEnable `json:"enable,omitempty"`
+}
+
+// QMPCapabilitiesArgumentsEnable is an enum of the QMP schema.
+type QMPCapabilitiesArgumentsEnable string
+
+const (
+	QMPCapabilitiesArgumentsEnableOob QMPCapabilitiesArgumentsEnable = "oob"
+)
+
+// QueryQMPSchemaResult is an object of the QMP schema.
+type QueryQMPSchemaResult struct {
+	Name     string                               `json:"name"`
+	MetaType QueryQMPSchemaResultMetaType         `json:"meta-type"`
+	Features []string                             `json:"features,omitempty"`
+	JSONType *QueryQMPSchemaResultBuiltinJSONType `json:"json-type,omitempty"`
+	Members  []QueryQMPSchemaResultEnumMembers    `json:"members,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Values      []string                             `json:"values,omitempty"`
+	ElementType *string                              `json:"element-type,omitempty"`
+	Tag         *string                              `json:"tag,omitempty"`
+	Variants    []QueryQMPSchemaResultObjectVariants `json:"variants,omitempty"`
+	ArgType     *string                              `json:"arg-type,omitempty"`
+	RetType     *string                              `json:"ret-type,omitempty"`
+	AllowOob    *bool                                `json:"allow-oob,omitempty"`
+}
+
+// QueryQMPSchemaResultMetaType is an enum of the QMP schema.
+type QueryQMPSchemaResultMetaType string
+
+const (
+	QueryQMPSchemaResultMetaTypeBuiltin   QueryQMPSchemaResultMetaType = "builtin"
+	QueryQMPSchemaResultMetaTypeEnum      QueryQMPSchemaResultMetaType = "enum"
+	QueryQMPSchemaResultMetaTypeArray     QueryQMPSchemaResultMetaType = "array"
+	QueryQMPSchemaResultMetaTypeObject    QueryQMPSchemaResultMetaType = "object"
+	QueryQMPSchemaResultMetaTypeAlternate QueryQMPSchemaResultMetaType = "alternate"
+	QueryQMPSchemaResultMetaTypeCommand   QueryQMPSchemaResultMetaType = "command"
+	QueryQMPSchemaResultMetaTypeEvent     QueryQMPSchemaResultMetaType = "event"
+)
+
+// QueryQMPSchemaResultBuiltin is an object of the QMP schema.
+type QueryQMPSchemaResultBuiltin struct {
+	JSONType QueryQMPSchemaResultBuiltinJSONType `json:"json-type"`
+}
+
+// QueryQMPSchemaResultBuiltinJSONType is an enum of the QMP schema.
+type QueryQMPSchemaResultBuiltinJSONType string
+
+const (
+	QueryQMPSchemaResultBuiltinJSONTypeString  QueryQMPSchemaResultBuiltinJSONType = "string"
+	QueryQMPSchemaResultBuiltinJSONTypeNumber  QueryQMPSchemaResultBuiltinJSONType = "number"
+	QueryQMPSchemaResultBuiltinJSONTypeInt     QueryQMPSchemaResultBuiltinJSONType = "int"
+	QueryQMPSchemaResultBuiltinJSONTypeBoolean QueryQMPSchemaResultBuiltinJSONType = "boolean"
+	QueryQMPSchemaResultBuiltinJSONTypeNull    QueryQMPSchemaResultBuiltinJSONType = "null"
+	QueryQMPSchemaResultBuiltinJSONTypeObject  QueryQMPSchemaResultBuiltinJSONType = "object"
+	QueryQMPSchemaResultBuiltinJSONTypeArray   QueryQMPSchemaResultBuiltinJSONType = "array"
+	QueryQMPSchemaResultBuiltinJSONTypeValue   QueryQMPSchemaResultBuiltinJSONType = "value"
+)
+
+// QueryQMPSchemaResultEnum is an object of the QMP schema.
+type QueryQMPSchemaResultEnum struct {
+	Members []QueryQMPSchemaResultEnumMembers `json:"members"`
+	// Deprecated: deprecated in the QMP schema.
+	Values []string `json:"values"`
+}
+
+// QueryQMPSchemaResultEnumMembers is an object of the QMP schema.
+type QueryQMPSchemaResultEnumMembers struct {
+	Name     string   `json:"name"`
+	Features []string `json:"features,omitempty"`
+}
+
+// QueryQMPSchemaResultArray is an object of the QMP schema.
+type QueryQMPSchemaResultArray struct {
+	ElementType string `json:"element-type"`
+}
+
+// QueryQMPSchemaResultObject is an object of the QMP schema.
+type QueryQMPSchemaResultObject struct {
+	Members  []QueryQMPSchemaResultObjectMembers  `json:"members"`
+	Tag      *string                              `json:"tag,omitempty"`
+	Variants []QueryQMPSchemaResultObjectVariants `json:"variants,omitempty"`
+}
+
+// QueryQMPSchemaResultObjectMembers is an object of the QMP schema.
+type QueryQMPSchemaResultObjectMembers struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Default  interface{} `json:"default,omitempty"`
+	Features []string    `json:"features,omitempty"`
+}
+
+// QueryQMPSchemaResultObjectVariants is an object of the QMP schema.
+type QueryQMPSchemaResultObjectVariants struct {
+	Case string `json:"case"`
+	Type string `json:"type"`
+}
+
+// QueryQMPSchemaResultAlternate is an object of the QMP schema.
+type QueryQMPSchemaResultAlternate struct {
+	Members []QueryQMPSchemaResultAlternateMembers `json:"members"`
+}
+
+// QueryQMPSchemaResultAlternateMembers is an object of the QMP schema.
+type QueryQMPSchemaResultAlternateMembers struct {
+	Type string `json:"type"`
+}
+
+// QueryQMPSchemaResultCommand is an object of the QMP schema.
+type QueryQMPSchemaResultCommand struct {
+	ArgType  string `json:"arg-type"`
+	RetType  string `json:"ret-type"`
+	AllowOob *bool  `json:"allow-oob,omitempty"`
+}
+
+// QueryQMPSchemaResultEvent is an object of the QMP schema.
+type QueryQMPSchemaResultEvent struct {
+	ArgType string `json:"arg-type"`
+}
+
+// QueryHotpluggableCpusResult is an object of the QMP schema.
+type QueryHotpluggableCpusResult struct {
+	Type       string                   `json:"type"`
+	VcpusCount int64                    `json:"vcpus-count"`
+	Props      QueryCpusFastResultProps `json:"props"`
+	QOMPath    *string                  `json:"qom-path,omitempty"`
+}
+
+// QueryMemoryDevicesResult is an object of the QMP schema.
+type QueryMemoryDevicesResult struct {
+	Type QueryMemoryDevicesResultType      `json:"type"`
+	Data *QueryMemoryDevicesResultDimmData `json:"data,omitempty"`
+}
+
+// QueryMemoryDevicesResultType is an enum of the QMP schema.
+type QueryMemoryDevicesResultType string
+
+const (
+	QueryMemoryDevicesResultTypeDimm       QueryMemoryDevicesResultType = "dimm"
+	QueryMemoryDevicesResultTypeNvdimm     QueryMemoryDevicesResultType = "nvdimm"
+	QueryMemoryDevicesResultTypeVirtioPmem QueryMemoryDevicesResultType = "virtio-pmem"
+	QueryMemoryDevicesResultTypeVirtioMem  QueryMemoryDevicesResultType = "virtio-mem"
+	QueryMemoryDevicesResultTypeSgxEpc     QueryMemoryDevicesResultType = "sgx-epc"
+	QueryMemoryDevicesResultTypeHvBalloon  QueryMemoryDevicesResultType = "hv-balloon"
+)
+
+// QueryMemoryDevicesResultDimm is an object of the QMP schema.
+type QueryMemoryDevicesResultDimm struct {
+	Data QueryMemoryDevicesResultDimmData `json:"data"`
+}
+
+// QueryMemoryDevicesResultDimmData is an object of the QMP schema.
+type QueryMemoryDevicesResultDimmData struct {
+	ID           *string `json:"id,omitempty"`
+	Addr         int64   `json:"addr"`
+	Size         int64   `json:"size"`
+	Slot         int64   `json:"slot"`
+	Node         int64   `json:"node"`
+	Memdev       string  `json:"memdev"`
+	Hotplugged   bool    `json:"hotplugged"`
+	Hotpluggable bool    `json:"hotpluggable"`
+}
+
+// QueryMemoryDevicesResultVirtioPmem is an object of the QMP schema.
+type QueryMemoryDevicesResultVirtioPmem struct {
+	Data QueryMemoryDevicesResultVirtioPmemData `json:"data"`
+}
+
+// QueryMemoryDevicesResultVirtioPmemData is an object of the QMP schema.
+type QueryMemoryDevicesResultVirtioPmemData struct {
+	ID      *string `json:"id,omitempty"`
+	Memaddr uint64  `json:"memaddr"`
+	Size    uint64  `json:"size"`
+	Memdev  string  `json:"memdev"`
+}
+
+// QueryMemoryDevicesResultVirtioMem is an object of the QMP schema.
+type QueryMemoryDevicesResultVirtioMem struct {
+	Data QueryMemoryDevicesResultVirtioMemData `json:"data"`
+}
+
+// QueryMemoryDevicesResultVirtioMemData is an object of the QMP schema.
+type QueryMemoryDevicesResultVirtioMemData struct {
+	ID            *string `json:"id,omitempty"`
+	Memaddr       uint64  `json:"memaddr"`
+	RequestedSize uint64  `json:"requested-size"`
+	Size          uint64  `json:"size"`
+	MaxSize       uint64  `json:"max-size"`
+	BlockSize     uint64  `json:"block-size"`
+	Node          int64   `json:"node"`
+	Memdev        string  `json:"memdev"`
+}
+
+// QueryMemoryDevicesResultSgxEpc is an object of the QMP schema.
+type QueryMemoryDevicesResultSgxEpc struct {
+	Data QueryMemoryDevicesResultSgxEpcData `json:"data"`
+}
+
+// QueryMemoryDevicesResultSgxEpcData is an object of the QMP schema.
+type QueryMemoryDevicesResultSgxEpcData struct {
+	ID      *string `json:"id,omitempty"`
+	Memaddr uint64  `json:"memaddr"`
+	Size    uint64  `json:"size"`
+	Node    int64   `json:"node"`
+	Memdev  string  `json:"memdev"`
+}
+
+// QueryMemoryDevicesResultHvBalloon is an object of the QMP schema.
+type QueryMemoryDevicesResultHvBalloon struct {
+	Data QueryMemoryDevicesResultHvBalloonData `json:"data"`
+}
+
+// QueryMemoryDevicesResultHvBalloonData is an object of the QMP schema.
+type QueryMemoryDevicesResultHvBalloonData struct {
+	ID      *string `json:"id,omitempty"`
+	Memaddr *uint64 `json:"memaddr,omitempty"`
+	MaxSize uint64  `json:"max-size"`
+	Memdev  *string `json:"memdev,omitempty"`
+}
+
+// DeviceAddArguments is an object of the QMP schema.
+type DeviceAddArguments struct {
+	Driver string  `json:"driver"`
+	Bus    *string `json:"bus,omitempty"`
+	ID     *string `json:"id,omitempty"`
+}
+
+// DeviceDelArguments is an object of the QMP schema.
+type DeviceDelArguments struct {
+	ID string `json:"id"`
+}
+
+// DeviceListPropertiesArguments is an object of the QMP schema.
+type DeviceListPropertiesArguments struct {
+	Typename string `json:"typename"`
+}
+
+// DeviceListPropertiesResult is an object of the QMP schema.
+type DeviceListPropertiesResult struct {
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	Description  *string     `json:"description,omitempty"`
+	DefaultValue interface{} `json:"default-value,omitempty"`
+}
+
+// QOMListArguments is an object of the QMP schema.
+type QOMListArguments struct {
+	Path string `json:"path"`
+}
+
+// QOMGetArguments is an object of the QMP schema.
+type QOMGetArguments struct {
+	Path     string `json:"path"`
+	Property string `json:"property"`
+}
+
+// QOMSetArguments is an object of the QMP schema.
+type QOMSetArguments struct {
+	Path     string      `json:"path"`
+	Property string      `json:"property"`
+	Value    interface{} `json:"value"`
+}
+
+// QOMListTypesArguments is an object of the QMP schema.
+type QOMListTypesArguments struct {
+	Implements *string `json:"implements,omitempty"`
+	Abstract   *bool   `json:"abstract,omitempty"`
+}
+
+// QOMListTypesResult is an object of the QMP schema.
+type QOMListTypesResult struct {
+	Name     string  `json:"name"`
+	Abstract *bool   `json:"abstract,omitempty"`
+	Parent   *string `json:"parent,omitempty"`
+}
+
+// QOMListPropertiesArguments is an object of the QMP schema.
+type QOMListPropertiesArguments struct {
+	Typename string `json:"typename"`
+}
+
+// ObjectAddArguments is an object of the QMP schema.
+type ObjectAddArguments struct {
+	QOMType                        ObjectAddArgumentsQOMType               `json:"qom-type"`
+	ID                             string                                  `json:"id"`
+	Policy                         *ObjectAddArgumentsAuthzListPolicy      `json:"policy,omitempty"`
+	Rules                          []ObjectAddArgumentsAuthzListRules      `json:"rules,omitempty"`
+	Filename                       *string                                 `json:"filename,omitempty"`
+	Refresh                        *bool                                   `json:"refresh,omitempty"`
+	Service                        *string                                 `json:"service,omitempty"`
+	Identity                       *string                                 `json:"identity,omitempty"`
+	If                             *string                                 `json:"if,omitempty"`
+	Canbus                         *string                                 `json:"canbus,omitempty"`
+	PrimaryIn                      *string                                 `json:"primary_in,omitempty"`
+	SecondaryIn                    *string                                 `json:"secondary_in,omitempty"`
+	Outdev                         *string                                 `json:"outdev,omitempty"`
+	Iothread                       *string                                 `json:"iothread,omitempty"`
+	NotifyDev                      *string                                 `json:"notify_dev,omitempty"`
+	CompareTimeout                 *uint64                                 `json:"compare_timeout,omitempty"`
+	ExpiredScanCycle               *uint32                                 `json:"expired_scan_cycle,omitempty"`
+	MaxQueueSize                   *uint32                                 `json:"max_queue_size,omitempty"`
+	VnetHdrSupport                 *bool                                   `json:"vnet_hdr_support,omitempty"`
+	Queues                         *uint32                                 `json:"queues,omitempty"`
+	ThrottleBps                    *uint64                                 `json:"throttle-bps,omitempty"`
+	ThrottleOps                    *uint64                                 `json:"throttle-ops,omitempty"`
+	Chardev                        *string                                 `json:"chardev,omitempty"`
+	Addr                           *string                                 `json:"addr,omitempty"`
+	IDList                         *string                                 `json:"id-list,omitempty"`
+	Netdev                         *string                                 `json:"netdev,omitempty"`
+	Queue                          *ObjectAddArgumentsFilterBufferQueue    `json:"queue,omitempty"`
+	Status                         *string                                 `json:"status,omitempty"`
+	Position                       *string                                 `json:"position,omitempty"`
+	Insert                         *ObjectAddArgumentsFilterBufferInsert   `json:"insert,omitempty"`
+	Interval                       *uint32                                 `json:"interval,omitempty"`
+	File                           *string                                 `json:"file,omitempty"`
+	Maxlen                         *uint32                                 `json:"maxlen,omitempty"`
+	Indev                          *string                                 `json:"indev,omitempty"`
+	Name                           *string                                 `json:"name,omitempty"`
+	Server                         *string                                 `json:"server,omitempty"`
+	Port                           *string                                 `json:"port,omitempty"`
+	XOrigin                        *string                                 `json:"x-origin,omitempty"`
+	YOrigin                        *string                                 `json:"y-origin,omitempty"`
+	Width                          *string                                 `json:"width,omitempty"`
+	Height                         *string                                 `json:"height,omitempty"`
+	Evdev                          *string                                 `json:"evdev,omitempty"`
+	GrabAll                        *bool                                   `json:"grab_all,omitempty"`
+	Repeat                         *bool                                   `json:"repeat,omitempty"`
+	GrabToggle                     *ObjectAddArgumentsInputLinuxGrabToggle `json:"grab-toggle,omitempty"`
+	AioMaxBatch                    *int64                                  `json:"aio-max-batch,omitempty"`
+	ThreadPoolMin                  *int64                                  `json:"thread-pool-min,omitempty"`
+	ThreadPoolMax                  *int64                                  `json:"thread-pool-max,omitempty"`
+	PollMaxNs                      *int64                                  `json:"poll-max-ns,omitempty"`
+	PollGrow                       *int64                                  `json:"poll-grow,omitempty"`
+	PollShrink                     *int64                                  `json:"poll-shrink,omitempty"`
+	Dump                           *bool                                   `json:"dump,omitempty"`
+	HostNodes                      []uint16                                `json:"host-nodes,omitempty"`
+	Merge                          *bool                                   `json:"merge,omitempty"`
+	Prealloc                       *bool                                   `json:"prealloc,omitempty"`
+	PreallocThreads                *uint32                                 `json:"prealloc-threads,omitempty"`
+	PreallocContext                *string                                 `json:"prealloc-context,omitempty"`
+	Share                          *bool                                   `json:"share,omitempty"`
+	Reserve                        *bool                                   `json:"reserve,omitempty"`
+	Size                           *uint64                                 `json:"size,omitempty"`
+	XUseCanonicalPathForRamblockID *bool                                   `json:"x-use-canonical-path-for-ramblock-id,omitempty"`
+	Align                          *uint64                                 `json:"align,omitempty"`
+	Offset                         *uint64                                 `json:"offset,omitempty"`
+	DiscardData                    *bool                                   `json:"discard-data,omitempty"`
+	MemPath                        *string                                 `json:"mem-path,omitempty"`
+	Readonly                       *bool                                   `json:"readonly,omitempty"`
+	Rom                            *ObjectAddArgumentsMemoryBackendFileRom `json:"rom,omitempty"`
+	Hugetlb                        *bool                                   `json:"hugetlb,omitempty"`
+	Hugetlbsize                    *uint64                                 `json:"hugetlbsize,omitempty"`
+	Seal                           *bool                                   `json:"seal,omitempty"`
+	Path                           *string                                 `json:"path,omitempty"`
+	Log                            *string                                 `json:"log,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Opened *bool `json:"opened,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Loaded              *bool                                    `json:"loaded,omitempty"`
+	Format              *ObjectAddArgumentsSecretFormat          `json:"format,omitempty"`
+	Keyid               *string                                  `json:"keyid,omitempty"`
+	Iv                  *string                                  `json:"iv,omitempty"`
+	Data                *string                                  `json:"data,omitempty"`
+	Serial              *int32                                   `json:"serial,omitempty"`
+	SevDevice           *string                                  `json:"sev-device,omitempty"`
+	DhCertFile          *string                                  `json:"dh-cert-file,omitempty"`
+	SessionFile         *string                                  `json:"session-file,omitempty"`
+	Handle              *uint32                                  `json:"handle,omitempty"`
+	Cbitpos             *uint32                                  `json:"cbitpos,omitempty"`
+	ReducedPhysBits     *uint32                                  `json:"reduced-phys-bits,omitempty"`
+	KernelHashes        *bool                                    `json:"kernel-hashes,omitempty"`
+	CPUAffinity         []uint16                                 `json:"cpu-affinity,omitempty"`
+	NodeAffinity        []uint16                                 `json:"node-affinity,omitempty"`
+	Limits              *ObjectAddArgumentsThrottleGroupLimits   `json:"limits,omitempty"`
+	XIopsTotal          *int64                                   `json:"x-iops-total,omitempty"`
+	XIopsTotalMax       *int64                                   `json:"x-iops-total-max,omitempty"`
+	XIopsTotalMaxLength *int64                                   `json:"x-iops-total-max-length,omitempty"`
+	XIopsRead           *int64                                   `json:"x-iops-read,omitempty"`
+	XIopsReadMax        *int64                                   `json:"x-iops-read-max,omitempty"`
+	XIopsReadMaxLength  *int64                                   `json:"x-iops-read-max-length,omitempty"`
+	XIopsWrite          *int64                                   `json:"x-iops-write,omitempty"`
+	XIopsWriteMax       *int64                                   `json:"x-iops-write-max,omitempty"`
+	XIopsWriteMaxLength *int64                                   `json:"x-iops-write-max-length,omitempty"`
+	XBpsTotal           *int64                                   `json:"x-bps-total,omitempty"`
+	XBpsTotalMax        *int64                                   `json:"x-bps-total-max,omitempty"`
+	XBpsTotalMaxLength  *int64                                   `json:"x-bps-total-max-length,omitempty"`
+	XBpsRead            *int64                                   `json:"x-bps-read,omitempty"`
+	XBpsReadMax         *int64                                   `json:"x-bps-read-max,omitempty"`
+	XBpsReadMaxLength   *int64                                   `json:"x-bps-read-max-length,omitempty"`
+	XBpsWrite           *int64                                   `json:"x-bps-write,omitempty"`
+	XBpsWriteMax        *int64                                   `json:"x-bps-write-max,omitempty"`
+	XBpsWriteMaxLength  *int64                                   `json:"x-bps-write-max-length,omitempty"`
+	XIopsSize           *int64                                   `json:"x-iops-size,omitempty"`
+	VerifyPeer          *bool                                    `json:"verify-peer,omitempty"`
+	Dir                 *string                                  `json:"dir,omitempty"`
+	Endpoint            *ObjectAddArgumentsTlsCredsAnonEndpoint  `json:"endpoint,omitempty"`
+	Priority            *string                                  `json:"priority,omitempty"`
+	Username            *string                                  `json:"username,omitempty"`
+	SanityCheck         *bool                                    `json:"sanity-check,omitempty"`
+	Passwordid          *string                                  `json:"passwordid,omitempty"`
+	Fd                  *string                                  `json:"fd,omitempty"`
+	Devid               *string                                  `json:"devid,omitempty"`
+	Socket              *ObjectAddArgumentsXVfioUserServerSocket `json:"socket,omitempty"`
+	Device              *string                                  `json:"device,omitempty"`
+}
+
+// ObjectAddArgumentsQOMType is an enum of the QMP schema.
+type ObjectAddArgumentsQOMType string
+
+const (
+	ObjectAddArgumentsQOMTypeAuthzList               ObjectAddArgumentsQOMType = "authz-list"
+	ObjectAddArgumentsQOMTypeAuthzListfile           ObjectAddArgumentsQOMType = "authz-listfile"
+	ObjectAddArgumentsQOMTypeAuthzPam                ObjectAddArgumentsQOMType = "authz-pam"
+	ObjectAddArgumentsQOMTypeAuthzSimple             ObjectAddArgumentsQOMType = "authz-simple"
+	ObjectAddArgumentsQOMTypeCanBus                  ObjectAddArgumentsQOMType = "can-bus"
+	ObjectAddArgumentsQOMTypeCanHostSocketcan        ObjectAddArgumentsQOMType = "can-host-socketcan"
+	ObjectAddArgumentsQOMTypeColoCompare             ObjectAddArgumentsQOMType = "colo-compare"
+	ObjectAddArgumentsQOMTypeCryptodevBackend        ObjectAddArgumentsQOMType = "cryptodev-backend"
+	ObjectAddArgumentsQOMTypeCryptodevBackendBuiltin ObjectAddArgumentsQOMType = "cryptodev-backend-builtin"
+	ObjectAddArgumentsQOMTypeCryptodevBackendLkcf    ObjectAddArgumentsQOMType = "cryptodev-backend-lkcf"
+	ObjectAddArgumentsQOMTypeCryptodevVhostUser      ObjectAddArgumentsQOMType = "cryptodev-vhost-user"
+	ObjectAddArgumentsQOMTypeDbusVmstate             ObjectAddArgumentsQOMType = "dbus-vmstate"
+	ObjectAddArgumentsQOMTypeFilterBuffer            ObjectAddArgumentsQOMType = "filter-buffer"
+	ObjectAddArgumentsQOMTypeFilterDump              ObjectAddArgumentsQOMType = "filter-dump"
+	ObjectAddArgumentsQOMTypeFilterMirror            ObjectAddArgumentsQOMType = "filter-mirror"
+	ObjectAddArgumentsQOMTypeFilterRedirector        ObjectAddArgumentsQOMType = "filter-redirector"
+	ObjectAddArgumentsQOMTypeFilterReplay            ObjectAddArgumentsQOMType = "filter-replay"
+	ObjectAddArgumentsQOMTypeFilterRewriter          ObjectAddArgumentsQOMType = "filter-rewriter"
+	ObjectAddArgumentsQOMTypeInputBarrier            ObjectAddArgumentsQOMType = "input-barrier"
+	ObjectAddArgumentsQOMTypeInputLinux              ObjectAddArgumentsQOMType = "input-linux"
+	ObjectAddArgumentsQOMTypeIothread                ObjectAddArgumentsQOMType = "iothread"
+	ObjectAddArgumentsQOMTypeMainLoop                ObjectAddArgumentsQOMType = "main-loop"
+	ObjectAddArgumentsQOMTypeMemoryBackendEpc        ObjectAddArgumentsQOMType = "memory-backend-epc"
+	ObjectAddArgumentsQOMTypeMemoryBackendFile       ObjectAddArgumentsQOMType = "memory-backend-file"
+	ObjectAddArgumentsQOMTypeMemoryBackendMemfd      ObjectAddArgumentsQOMType = "memory-backend-memfd"
+	ObjectAddArgumentsQOMTypeMemoryBackendRAM        ObjectAddArgumentsQOMType = "memory-backend-ram"
+	ObjectAddArgumentsQOMTypePefGuest                ObjectAddArgumentsQOMType = "pef-guest"
+	ObjectAddArgumentsQOMTypePrManagerHelper         ObjectAddArgumentsQOMType = "pr-manager-helper"
+	ObjectAddArgumentsQOMTypeQtest                   ObjectAddArgumentsQOMType = "qtest"
+	ObjectAddArgumentsQOMTypeRngBuiltin              ObjectAddArgumentsQOMType = "rng-builtin"
+	ObjectAddArgumentsQOMTypeRngEgd                  ObjectAddArgumentsQOMType = "rng-egd"
+	ObjectAddArgumentsQOMTypeRngRandom               ObjectAddArgumentsQOMType = "rng-random"
+	ObjectAddArgumentsQOMTypeSecret                  ObjectAddArgumentsQOMType = "secret"
+	ObjectAddArgumentsQOMTypeSecretKeyring           ObjectAddArgumentsQOMType = "secret_keyring"
+	ObjectAddArgumentsQOMTypeSevGuest                ObjectAddArgumentsQOMType = "sev-guest"
+	ObjectAddArgumentsQOMTypeThreadContext           ObjectAddArgumentsQOMType = "thread-context"
+	ObjectAddArgumentsQOMTypeS390PvGuest             ObjectAddArgumentsQOMType = "s390-pv-guest"
+	ObjectAddArgumentsQOMTypeThrottleGroup           ObjectAddArgumentsQOMType = "throttle-group"
+	ObjectAddArgumentsQOMTypeTlsCredsAnon            ObjectAddArgumentsQOMType = "tls-creds-anon"
+	ObjectAddArgumentsQOMTypeTlsCredsPsk             ObjectAddArgumentsQOMType = "tls-creds-psk"
+	ObjectAddArgumentsQOMTypeTlsCredsX509            ObjectAddArgumentsQOMType = "tls-creds-x509"
+	ObjectAddArgumentsQOMTypeTlsCipherSuites         ObjectAddArgumentsQOMType = "tls-cipher-suites"
+	ObjectAddArgumentsQOMTypeXRemoteObject           ObjectAddArgumentsQOMType = "x-remote-object"
+	ObjectAddArgumentsQOMTypeXVfioUserServer         ObjectAddArgumentsQOMType = "x-vfio-user-server"
+)
+
+// ObjectAddArgumentsAuthzList is an object of the QMP schema.
+type ObjectAddArgumentsAuthzList struct {
+	Policy *ObjectAddArgumentsAuthzListPolicy `json:"policy,omitempty"`
+	Rules  []ObjectAddArgumentsAuthzListRules `json:"rules,omitempty"`
+}
+
+// ObjectAddArgumentsAuthzListPolicy is an enum of the QMP schema.
+type ObjectAddArgumentsAuthzListPolicy string
+
+const (
+	ObjectAddArgumentsAuthzListPolicyDeny  ObjectAddArgumentsAuthzListPolicy = "deny"
+	ObjectAddArgumentsAuthzListPolicyAllow ObjectAddArgumentsAuthzListPolicy = "allow"
+)
+
+// ObjectAddArgumentsAuthzListRules is an object of the QMP schema.
+type ObjectAddArgumentsAuthzListRules struct {
+	Match  string                                  `json:"match"`
+	Policy ObjectAddArgumentsAuthzListPolicy       `json:"policy"`
+	Format *ObjectAddArgumentsAuthzListRulesFormat `json:"format,omitempty"`
+}
+
+// ObjectAddArgumentsAuthzListRulesFormat is an enum of the QMP schema.
+type ObjectAddArgumentsAuthzListRulesFormat string
+
+const (
+	ObjectAddArgumentsAuthzListRulesFormatExact ObjectAddArgumentsAuthzListRulesFormat = "exact"
+	ObjectAddArgumentsAuthzListRulesFormatGlob  ObjectAddArgumentsAuthzListRulesFormat = "glob"
+)
+
+// ObjectAddArgumentsAuthzListfile is an object of the QMP schema.
+type ObjectAddArgumentsAuthzListfile struct {
+	Filename string `json:"filename"`
+	Refresh  *bool  `json:"refresh,omitempty"`
+}
+
+// ObjectAddArgumentsAuthzPam is an object of the QMP schema.
+type ObjectAddArgumentsAuthzPam struct {
+	Service string `json:"service"`
+}
+
+// ObjectAddArgumentsAuthzSimple is an object of the QMP schema.
+type ObjectAddArgumentsAuthzSimple struct {
+	Identity string `json:"identity"`
+}
+
+// ObjectAddArgumentsCanHostSocketcan is an object of the QMP schema.
+type ObjectAddArgumentsCanHostSocketcan struct {
+	If     string `json:"if"`
+	Canbus string `json:"canbus"`
+}
+
+// ObjectAddArgumentsColoCompare is an object of the QMP schema.
+type ObjectAddArgumentsColoCompare struct {
+	PrimaryIn        string  `json:"primary_in"`
+	SecondaryIn      string  `json:"secondary_in"`
+	Outdev           string  `json:"outdev"`
+	Iothread         string  `json:"iothread"`
+	NotifyDev        *string `json:"notify_dev,omitempty"`
+	CompareTimeout   *uint64 `json:"compare_timeout,omitempty"`
+	ExpiredScanCycle *uint32 `json:"expired_scan_cycle,omitempty"`
+	MaxQueueSize     *uint32 `json:"max_queue_size,omitempty"`
+	VnetHdrSupport   *bool   `json:"vnet_hdr_support,omitempty"`
+}
+
+// ObjectAddArgumentsCryptodevBackend is an object of the QMP schema.
+type ObjectAddArgumentsCryptodevBackend struct {
+	Queues      *uint32 `json:"queues,omitempty"`
+	ThrottleBps *uint64 `json:"throttle-bps,omitempty"`
+	ThrottleOps *uint64 `json:"throttle-ops,omitempty"`
+}
+
+// ObjectAddArgumentsCryptodevVhostUser is an object of the QMP schema.
+type ObjectAddArgumentsCryptodevVhostUser struct {
+	Queues      *uint32 `json:"queues,omitempty"`
+	ThrottleBps *uint64 `json:"throttle-bps,omitempty"`
+	ThrottleOps *uint64 `json:"throttle-ops,omitempty"`
+	Chardev     string  `json:"chardev"`
+}
+
+// ObjectAddArgumentsDbusVmstate is an object of the QMP schema.
+type ObjectAddArgumentsDbusVmstate struct {
+	Addr   string  `json:"addr"`
+	IDList *string `json:"id-list,omitempty"`
+}
+
+// ObjectAddArgumentsFilterBuffer is an object of the QMP schema.
+type ObjectAddArgumentsFilterBuffer struct {
+	Netdev   string                                `json:"netdev"`
+	Queue    *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status   *string                               `json:"status,omitempty"`
+	Position *string                               `json:"position,omitempty"`
+	Insert   *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+	Interval uint32                                `json:"interval"`
+}
+
+// ObjectAddArgumentsFilterBufferQueue is an enum of the QMP schema.
+type ObjectAddArgumentsFilterBufferQueue string
+
+const (
+	ObjectAddArgumentsFilterBufferQueueAll ObjectAddArgumentsFilterBufferQueue = "all"
+	ObjectAddArgumentsFilterBufferQueueRx  ObjectAddArgumentsFilterBufferQueue = "rx"
+	ObjectAddArgumentsFilterBufferQueueTx  ObjectAddArgumentsFilterBufferQueue = "tx"
+)
+
+// ObjectAddArgumentsFilterBufferInsert is an enum of the QMP schema.
+type ObjectAddArgumentsFilterBufferInsert string
+
+const (
+	ObjectAddArgumentsFilterBufferInsertBefore ObjectAddArgumentsFilterBufferInsert = "before"
+	ObjectAddArgumentsFilterBufferInsertBehind ObjectAddArgumentsFilterBufferInsert = "behind"
+)
+
+// ObjectAddArgumentsFilterDump is an object of the QMP schema.
+type ObjectAddArgumentsFilterDump struct {
+	Netdev   string                                `json:"netdev"`
+	Queue    *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status   *string                               `json:"status,omitempty"`
+	Position *string                               `json:"position,omitempty"`
+	Insert   *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+	File     string                                `json:"file"`
+	Maxlen   *uint32                               `json:"maxlen,omitempty"`
+}
+
+// ObjectAddArgumentsFilterMirror is an object of the QMP schema.
+type ObjectAddArgumentsFilterMirror struct {
+	Netdev         string                                `json:"netdev"`
+	Queue          *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status         *string                               `json:"status,omitempty"`
+	Position       *string                               `json:"position,omitempty"`
+	Insert         *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+	Outdev         string                                `json:"outdev"`
+	VnetHdrSupport *bool                                 `json:"vnet_hdr_support,omitempty"`
+}
+
+// ObjectAddArgumentsFilterRedirector is an object of the QMP schema.
+type ObjectAddArgumentsFilterRedirector struct {
+	Netdev         string                                `json:"netdev"`
+	Queue          *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status         *string                               `json:"status,omitempty"`
+	Position       *string                               `json:"position,omitempty"`
+	Insert         *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+	Indev          *string                               `json:"indev,omitempty"`
+	Outdev         *string                               `json:"outdev,omitempty"`
+	VnetHdrSupport *bool                                 `json:"vnet_hdr_support,omitempty"`
+}
+
+// ObjectAddArgumentsFilterReplay is an object of the QMP schema.
+type ObjectAddArgumentsFilterReplay struct {
+	Netdev   string                                `json:"netdev"`
+	Queue    *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status   *string                               `json:"status,omitempty"`
+	Position *string                               `json:"position,omitempty"`
+	Insert   *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+}
+
+// ObjectAddArgumentsFilterRewriter is an object of the QMP schema.
+type ObjectAddArgumentsFilterRewriter struct {
+	Netdev         string                                `json:"netdev"`
+	Queue          *ObjectAddArgumentsFilterBufferQueue  `json:"queue,omitempty"`
+	Status         *string                               `json:"status,omitempty"`
+	Position       *string                               `json:"position,omitempty"`
+	Insert         *ObjectAddArgumentsFilterBufferInsert `json:"insert,omitempty"`
+	VnetHdrSupport *bool                                 `json:"vnet_hdr_support,omitempty"`
+}
+
+// ObjectAddArgumentsInputBarrier is an object of the QMP schema.
+type ObjectAddArgumentsInputBarrier struct {
+	Name    string  `json:"name"`
+	Server  *string `json:"server,omitempty"`
+	Port    *string `json:"port,omitempty"`
+	XOrigin *string `json:"x-origin,omitempty"`
+	YOrigin *string `json:"y-origin,omitempty"`
+	Width   *string `json:"width,omitempty"`
+	Height  *string `json:"height,omitempty"`
+}
+
+// ObjectAddArgumentsInputLinux is an object of the QMP schema.
+type ObjectAddArgumentsInputLinux struct {
+	Evdev      string                                  `json:"evdev"`
+	GrabAll    *bool                                   `json:"grab_all,omitempty"`
+	Repeat     *bool                                   `json:"repeat,omitempty"`
+	GrabToggle *ObjectAddArgumentsInputLinuxGrabToggle `json:"grab-toggle,omitempty"`
+}
+
+// ObjectAddArgumentsInputLinuxGrabToggle is an enum of the QMP schema.
+type ObjectAddArgumentsInputLinuxGrabToggle string
+
+const (
+	ObjectAddArgumentsInputLinuxGrabToggleCtrlCtrl       ObjectAddArgumentsInputLinuxGrabToggle = "ctrl-ctrl"
+	ObjectAddArgumentsInputLinuxGrabToggleAltAlt         ObjectAddArgumentsInputLinuxGrabToggle = "alt-alt"
+	ObjectAddArgumentsInputLinuxGrabToggleShiftShift     ObjectAddArgumentsInputLinuxGrabToggle = "shift-shift"
+	ObjectAddArgumentsInputLinuxGrabToggleMetaMeta       ObjectAddArgumentsInputLinuxGrabToggle = "meta-meta"
+	ObjectAddArgumentsInputLinuxGrabToggleScrolllock     ObjectAddArgumentsInputLinuxGrabToggle = "scrolllock"
+	ObjectAddArgumentsInputLinuxGrabToggleCtrlScrolllock ObjectAddArgumentsInputLinuxGrabToggle = "ctrl-scrolllock"
+)
+
+// ObjectAddArgumentsIothread is an object of the QMP schema.
+type ObjectAddArgumentsIothread struct {
+	AioMaxBatch   *int64 `json:"aio-max-batch,omitempty"`
+	ThreadPoolMin *int64 `json:"thread-pool-min,omitempty"`
+	ThreadPoolMax *int64 `json:"thread-pool-max,omitempty"`
+	PollMaxNs     *int64 `json:"poll-max-ns,omitempty"`
+	PollGrow      *int64 `json:"poll-grow,omitempty"`
+	PollShrink    *int64 `json:"poll-shrink,omitempty"`
+}
+
+// ObjectAddArgumentsMainLoop is an object of the QMP schema.
+type ObjectAddArgumentsMainLoop struct {
+	AioMaxBatch   *int64 `json:"aio-max-batch,omitempty"`
+	ThreadPoolMin *int64 `json:"thread-pool-min,omitempty"`
+	ThreadPoolMax *int64 `json:"thread-pool-max,omitempty"`
+}
+
+// ObjectAddArgumentsMemoryBackendEpc is an object of the QMP schema.
+type ObjectAddArgumentsMemoryBackendEpc struct {
+	Dump                           *bool                                     `json:"dump,omitempty"`
+	HostNodes                      []uint16                                  `json:"host-nodes,omitempty"`
+	Merge                          *bool                                     `json:"merge,omitempty"`
+	Policy                         *ObjectAddArgumentsMemoryBackendEpcPolicy `json:"policy,omitempty"`
+	Prealloc                       *bool                                     `json:"prealloc,omitempty"`
+	PreallocThreads                *uint32                                   `json:"prealloc-threads,omitempty"`
+	PreallocContext                *string                                   `json:"prealloc-context,omitempty"`
+	Share                          *bool                                     `json:"share,omitempty"`
+	Reserve                        *bool                                     `json:"reserve,omitempty"`
+	Size                           uint64                                    `json:"size"`
+	XUseCanonicalPathForRamblockID *bool                                     `json:"x-use-canonical-path-for-ramblock-id,omitempty"`
+}
+
+// ObjectAddArgumentsMemoryBackendEpcPolicy is an enum of the QMP schema.
+type ObjectAddArgumentsMemoryBackendEpcPolicy string
+
+const (
+	ObjectAddArgumentsMemoryBackendEpcPolicyDefault    ObjectAddArgumentsMemoryBackendEpcPolicy = "default"
+	ObjectAddArgumentsMemoryBackendEpcPolicyPreferred  ObjectAddArgumentsMemoryBackendEpcPolicy = "preferred"
+	ObjectAddArgumentsMemoryBackendEpcPolicyBind       ObjectAddArgumentsMemoryBackendEpcPolicy = "bind"
+	ObjectAddArgumentsMemoryBackendEpcPolicyInterleave ObjectAddArgumentsMemoryBackendEpcPolicy = "interleave"
+)
+
+// ObjectAddArgumentsMemoryBackendFile is an object of the QMP schema.
+type ObjectAddArgumentsMemoryBackendFile struct {
+	Dump                           *bool                                     `json:"dump,omitempty"`
+	HostNodes                      []uint16                                  `json:"host-nodes,omitempty"`
+	Merge                          *bool                                     `json:"merge,omitempty"`
+	Policy                         *ObjectAddArgumentsMemoryBackendEpcPolicy `json:"policy,omitempty"`
+	Prealloc                       *bool                                     `json:"prealloc,omitempty"`
+	PreallocThreads                *uint32                                   `json:"prealloc-threads,omitempty"`
+	PreallocContext                *string                                   `json:"prealloc-context,omitempty"`
+	Share                          *bool                                     `json:"share,omitempty"`
+	Reserve                        *bool                                     `json:"reserve,omitempty"`
+	Size                           uint64                                    `json:"size"`
+	XUseCanonicalPathForRamblockID *bool                                     `json:"x-use-canonical-path-for-ramblock-id,omitempty"`
+	Align                          *uint64                                   `json:"align,omitempty"`
+	Offset                         *uint64                                   `json:"offset,omitempty"`
+	DiscardData                    *bool                                     `json:"discard-data,omitempty"`
+	MemPath                        string                                    `json:"mem-path"`
+	Readonly                       *bool                                     `json:"readonly,omitempty"`
+	Rom                            *ObjectAddArgumentsMemoryBackendFileRom   `json:"rom,omitempty"`
+}
+
+// ObjectAddArgumentsMemoryBackendFileRom is an enum of the QMP schema.
+type ObjectAddArgumentsMemoryBackendFileRom string
+
+const (
+	ObjectAddArgumentsMemoryBackendFileRomAuto ObjectAddArgumentsMemoryBackendFileRom = "auto"
+	ObjectAddArgumentsMemoryBackendFileRomOn   ObjectAddArgumentsMemoryBackendFileRom = "on"
+	ObjectAddArgumentsMemoryBackendFileRomOff  ObjectAddArgumentsMemoryBackendFileRom = "off"
+)
+
+// ObjectAddArgumentsMemoryBackendMemfd is an object of the QMP schema.
+type ObjectAddArgumentsMemoryBackendMemfd struct {
+	Dump                           *bool                                     `json:"dump,omitempty"`
+	HostNodes                      []uint16                                  `json:"host-nodes,omitempty"`
+	Merge                          *bool                                     `json:"merge,omitempty"`
+	Policy                         *ObjectAddArgumentsMemoryBackendEpcPolicy `json:"policy,omitempty"`
+	Prealloc                       *bool                                     `json:"prealloc,omitempty"`
+	PreallocThreads                *uint32                                   `json:"prealloc-threads,omitempty"`
+	PreallocContext                *string                                   `json:"prealloc-context,omitempty"`
+	Share                          *bool                                     `json:"share,omitempty"`
+	Reserve                        *bool                                     `json:"reserve,omitempty"`
+	Size                           uint64                                    `json:"size"`
+	XUseCanonicalPathForRamblockID *bool                                     `json:"x-use-canonical-path-for-ramblock-id,omitempty"`
+	Hugetlb                        *bool                                     `json:"hugetlb,omitempty"`
+	Hugetlbsize                    *uint64                                   `json:"hugetlbsize,omitempty"`
+	Seal                           *bool                                     `json:"seal,omitempty"`
+}
+
+// ObjectAddArgumentsMemoryBackendRAM is an object of the QMP schema.
+type ObjectAddArgumentsMemoryBackendRAM struct {
+	Dump                           *bool                                     `json:"dump,omitempty"`
+	HostNodes                      []uint16                                  `json:"host-nodes,omitempty"`
+	Merge                          *bool                                     `json:"merge,omitempty"`
+	Policy                         *ObjectAddArgumentsMemoryBackendEpcPolicy `json:"policy,omitempty"`
+	Prealloc                       *bool                                     `json:"prealloc,omitempty"`
+	PreallocThreads                *uint32                                   `json:"prealloc-threads,omitempty"`
+	PreallocContext                *string                                   `json:"prealloc-context,omitempty"`
+	Share                          *bool                                     `json:"share,omitempty"`
+	Reserve                        *bool                                     `json:"reserve,omitempty"`
+	Size                           uint64                                    `json:"size"`
+	XUseCanonicalPathForRamblockID *bool                                     `json:"x-use-canonical-path-for-ramblock-id,omitempty"`
+}
+
+// ObjectAddArgumentsPrManagerHelper is an object of the QMP schema.
+type ObjectAddArgumentsPrManagerHelper struct {
+	Path string `json:"path"`
+}
+
+// ObjectAddArgumentsQtest is an object of the QMP schema.
+type ObjectAddArgumentsQtest struct {
+	Chardev string  `json:"chardev"`
+	Log     *string `json:"log,omitempty"`
+}
+
+// ObjectAddArgumentsRngBuiltin is an object of the QMP schema.
+type ObjectAddArgumentsRngBuiltin struct {
+	// Deprecated: deprecated in the QMP schema.
+	Opened *bool `json:"opened,omitempty"`
+}
+
+// ObjectAddArgumentsRngEgd is an object of the QMP schema.
+type ObjectAddArgumentsRngEgd struct {
+	// Deprecated: deprecated in the QMP schema.
+	Opened  *bool  `json:"opened,omitempty"`
+	Chardev string `json:"chardev"`
+}
+
+// ObjectAddArgumentsRngRandom is an object of the QMP schema.
+type ObjectAddArgumentsRngRandom struct {
+	// Deprecated: deprecated in the QMP schema.
+	Opened   *bool   `json:"opened,omitempty"`
+	Filename *string `json:"filename,omitempty"`
+}
+
+// ObjectAddArgumentsSecret is an object of the QMP schema.
+type ObjectAddArgumentsSecret struct {
+	// Deprecated: deprecated in the QMP schema.
+	Loaded *bool                           `json:"loaded,omitempty"`
+	Format *ObjectAddArgumentsSecretFormat `json:"format,omitempty"`
+	Keyid  *string                         `json:"keyid,omitempty"`
+	Iv     *string                         `json:"iv,omitempty"`
+	Data   *string                         `json:"data,omitempty"`
+	File   *string                         `json:"file,omitempty"`
+}
+
+// ObjectAddArgumentsSecretFormat is an enum of the QMP schema.
+type ObjectAddArgumentsSecretFormat string
+
+const (
+	ObjectAddArgumentsSecretFormatRaw    ObjectAddArgumentsSecretFormat = "raw"
+	ObjectAddArgumentsSecretFormatBase64 ObjectAddArgumentsSecretFormat = "base64"
+)
+
+// ObjectAddArgumentsSecretKeyring is an object of the QMP schema.
+type ObjectAddArgumentsSecretKeyring struct {
+	// Deprecated: deprecated in the QMP schema.
+	Loaded *bool                           `json:"loaded,omitempty"`
+	Format *ObjectAddArgumentsSecretFormat `json:"format,omitempty"`
+	Keyid  *string                         `json:"keyid,omitempty"`
+	Iv     *string                         `json:"iv,omitempty"`
+	Serial int32                           `json:"serial"`
+}
+
+// ObjectAddArgumentsSevGuest is an object of the QMP schema.
+type ObjectAddArgumentsSevGuest struct {
+	SevDevice       *string `json:"sev-device,omitempty"`
+	DhCertFile      *string `json:"dh-cert-file,omitempty"`
+	SessionFile     *string `json:"session-file,omitempty"`
+	Policy          *uint32 `json:"policy,omitempty"`
+	Handle          *uint32 `json:"handle,omitempty"`
+	Cbitpos         *uint32 `json:"cbitpos,omitempty"`
+	ReducedPhysBits uint32  `json:"reduced-phys-bits"`
+	KernelHashes    *bool   `json:"kernel-hashes,omitempty"`
+}
+
+// ObjectAddArgumentsThreadContext is an object of the QMP schema.
+type ObjectAddArgumentsThreadContext struct {
+	CPUAffinity  []uint16 `json:"cpu-affinity,omitempty"`
+	NodeAffinity []uint16 `json:"node-affinity,omitempty"`
+}
+
+// ObjectAddArgumentsThrottleGroup is an object of the QMP schema.
+type ObjectAddArgumentsThrottleGroup struct {
+	Limits              *ObjectAddArgumentsThrottleGroupLimits `json:"limits,omitempty"`
+	XIopsTotal          *int64                                 `json:"x-iops-total,omitempty"`
+	XIopsTotalMax       *int64                                 `json:"x-iops-total-max,omitempty"`
+	XIopsTotalMaxLength *int64                                 `json:"x-iops-total-max-length,omitempty"`
+	XIopsRead           *int64                                 `json:"x-iops-read,omitempty"`
+	XIopsReadMax        *int64                                 `json:"x-iops-read-max,omitempty"`
+	XIopsReadMaxLength  *int64                                 `json:"x-iops-read-max-length,omitempty"`
+	XIopsWrite          *int64                                 `json:"x-iops-write,omitempty"`
+	XIopsWriteMax       *int64                                 `json:"x-iops-write-max,omitempty"`
+	XIopsWriteMaxLength *int64                                 `json:"x-iops-write-max-length,omitempty"`
+	XBpsTotal           *int64                                 `json:"x-bps-total,omitempty"`
+	XBpsTotalMax        *int64                                 `json:"x-bps-total-max,omitempty"`
+	XBpsTotalMaxLength  *int64                                 `json:"x-bps-total-max-length,omitempty"`
+	XBpsRead            *int64                                 `json:"x-bps-read,omitempty"`
+	XBpsReadMax         *int64                                 `json:"x-bps-read-max,omitempty"`
+	XBpsReadMaxLength   *int64                                 `json:"x-bps-read-max-length,omitempty"`
+	XBpsWrite           *int64                                 `json:"x-bps-write,omitempty"`
+	XBpsWriteMax        *int64                                 `json:"x-bps-write-max,omitempty"`
+	XBpsWriteMaxLength  *int64                                 `json:"x-bps-write-max-length,omitempty"`
+	XIopsSize           *int64                                 `json:"x-iops-size,omitempty"`
+}
+
+// ObjectAddArgumentsThrottleGroupLimits is an object of the QMP schema.
+type ObjectAddArgumentsThrottleGroupLimits struct {
+	IopsTotal          *int64 `json:"iops-total,omitempty"`
+	IopsTotalMax       *int64 `json:"iops-total-max,omitempty"`
+	IopsTotalMaxLength *int64 `json:"iops-total-max-length,omitempty"`
+	IopsRead           *int64 `json:"iops-read,omitempty"`
+	IopsReadMax        *int64 `json:"iops-read-max,omitempty"`
+	IopsReadMaxLength  *int64 `json:"iops-read-max-length,omitempty"`
+	IopsWrite          *int64 `json:"iops-write,omitempty"`
+	IopsWriteMax       *int64 `json:"iops-write-max,omitempty"`
+	IopsWriteMaxLength *int64 `json:"iops-write-max-length,omitempty"`
+	BpsTotal           *int64 `json:"bps-total,omitempty"`
+	BpsTotalMax        *int64 `json:"bps-total-max,omitempty"`
+	BpsTotalMaxLength  *int64 `json:"bps-total-max-length,omitempty"`
+	BpsRead            *int64 `json:"bps-read,omitempty"`
+	BpsReadMax         *int64 `json:"bps-read-max,omitempty"`
+	BpsReadMaxLength   *int64 `json:"bps-read-max-length,omitempty"`
+	BpsWrite           *int64 `json:"bps-write,omitempty"`
+	BpsWriteMax        *int64 `json:"bps-write-max,omitempty"`
+	BpsWriteMaxLength  *int64 `json:"bps-write-max-length,omitempty"`
+	IopsSize           *int64 `json:"iops-size,omitempty"`
+}
+
+// ObjectAddArgumentsTlsCredsAnon is an object of the QMP schema.
+type ObjectAddArgumentsTlsCredsAnon struct {
+	VerifyPeer *bool                                   `json:"verify-peer,omitempty"`
+	Dir        *string                                 `json:"dir,omitempty"`
+	Endpoint   *ObjectAddArgumentsTlsCredsAnonEndpoint `json:"endpoint,omitempty"`
+	Priority   *string                                 `json:"priority,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Loaded *bool `json:"loaded,omitempty"`
+}
+
+// ObjectAddArgumentsTlsCredsAnonEndpoint is an enum of the QMP schema.
+type ObjectAddArgumentsTlsCredsAnonEndpoint string
+
+const (
+	ObjectAddArgumentsTlsCredsAnonEndpointClient ObjectAddArgumentsTlsCredsAnonEndpoint = "client"
+	ObjectAddArgumentsTlsCredsAnonEndpointServer ObjectAddArgumentsTlsCredsAnonEndpoint = "server"
+)
+
+// ObjectAddArgumentsTlsCredsPsk is an object of the QMP schema.
+type ObjectAddArgumentsTlsCredsPsk struct {
+	VerifyPeer *bool                                   `json:"verify-peer,omitempty"`
+	Dir        *string                                 `json:"dir,omitempty"`
+	Endpoint   *ObjectAddArgumentsTlsCredsAnonEndpoint `json:"endpoint,omitempty"`
+	Priority   *string                                 `json:"priority,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Loaded   *bool   `json:"loaded,omitempty"`
+	Username *string `json:"username,omitempty"`
+}
+
+// ObjectAddArgumentsTlsCredsX509 is an object of the QMP schema.
+type ObjectAddArgumentsTlsCredsX509 struct {
+	VerifyPeer *bool                                   `json:"verify-peer,omitempty"`
+	Dir        *string                                 `json:"dir,omitempty"`
+	Endpoint   *ObjectAddArgumentsTlsCredsAnonEndpoint `json:"endpoint,omitempty"`
+	Priority   *string                                 `json:"priority,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Loaded      *bool   `json:"loaded,omitempty"`
+	SanityCheck *bool   `json:"sanity-check,omitempty"`
+	Passwordid  *string `json:"passwordid,omitempty"`
+}
+
+// ObjectAddArgumentsTlsCipherSuites is an object of the QMP schema.
+type ObjectAddArgumentsTlsCipherSuites struct {
+	VerifyPeer *bool                                   `json:"verify-peer,omitempty"`
+	Dir        *string                                 `json:"dir,omitempty"`
+	Endpoint   *ObjectAddArgumentsTlsCredsAnonEndpoint `json:"endpoint,omitempty"`
+	Priority   *string                                 `json:"priority,omitempty"`
+}
+
+// ObjectAddArgumentsXRemoteObject is an object of the QMP schema.
+type ObjectAddArgumentsXRemoteObject struct {
+	Fd    string `json:"fd"`
+	Devid string `json:"devid"`
+}
+
+// ObjectAddArgumentsXVfioUserServer is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServer struct {
+	Socket ObjectAddArgumentsXVfioUserServerSocket `json:"socket"`
+	Device string                                  `json:"device"`
+}
+
+// ObjectAddArgumentsXVfioUserServerSocket is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocket struct {
+	Type      ObjectAddArgumentsXVfioUserServerSocketType `json:"type"`
+	Host      *string                                     `json:"host,omitempty"`
+	Port      *string                                     `json:"port,omitempty"`
+	Numeric   *bool                                       `json:"numeric,omitempty"`
+	To        *uint16                                     `json:"to,omitempty"`
+	Ipv4      *bool                                       `json:"ipv4,omitempty"`
+	Ipv6      *bool                                       `json:"ipv6,omitempty"`
+	KeepAlive *bool                                       `json:"keep-alive,omitempty"`
+	Mptcp     *bool                                       `json:"mptcp,omitempty"`
+	Path      *string                                     `json:"path,omitempty"`
+	Abstract  *bool                                       `json:"abstract,omitempty"`
+	Tight     *bool                                       `json:"tight,omitempty"`
+	Cid       *string                                     `json:"cid,omitempty"`
+	Str       *string                                     `json:"str,omitempty"`
+}
+
+// ObjectAddArgumentsXVfioUserServerSocketType is an enum of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocketType string
+
+const (
+	ObjectAddArgumentsXVfioUserServerSocketTypeInet  ObjectAddArgumentsXVfioUserServerSocketType = "inet"
+	ObjectAddArgumentsXVfioUserServerSocketTypeUnix  ObjectAddArgumentsXVfioUserServerSocketType = "unix"
+	ObjectAddArgumentsXVfioUserServerSocketTypeVsock ObjectAddArgumentsXVfioUserServerSocketType = "vsock"
+	ObjectAddArgumentsXVfioUserServerSocketTypeFd    ObjectAddArgumentsXVfioUserServerSocketType = "fd"
+)
+
+// ObjectAddArgumentsXVfioUserServerSocketInet is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocketInet struct {
+	Host      string  `json:"host"`
+	Port      string  `json:"port"`
+	Numeric   *bool   `json:"numeric,omitempty"`
+	To        *uint16 `json:"to,omitempty"`
+	Ipv4      *bool   `json:"ipv4,omitempty"`
+	Ipv6      *bool   `json:"ipv6,omitempty"`
+	KeepAlive *bool   `json:"keep-alive,omitempty"`
+	Mptcp     *bool   `json:"mptcp,omitempty"`
+}
+
+// ObjectAddArgumentsXVfioUserServerSocketUnix is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocketUnix struct {
+	Path     string `json:"path"`
+	Abstract *bool  `json:"abstract,omitempty"`
+	Tight    *bool  `json:"tight,omitempty"`
+}
+
+// ObjectAddArgumentsXVfioUserServerSocketVsock is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocketVsock struct {
+	Cid  string `json:"cid"`
+	Port string `json:"port"`
+}
+
+// ObjectAddArgumentsXVfioUserServerSocketFd is an object of the QMP schema.
+type ObjectAddArgumentsXVfioUserServerSocketFd struct {
+	Str string `json:"str"`
+}
+
+// ObjectDelArguments is an object of the QMP schema.
+type ObjectDelArguments struct {
+	ID string `json:"id"`
+}
+
+// QueryBlockResult is an object of the QMP schema.
+type QueryBlockResult struct {
+	Device    string                    `json:"device"`
+	Qdev      *string                   `json:"qdev,omitempty"`
+	Type      string                    `json:"type"`
+	Removable bool                      `json:"removable"`
+	Locked    bool                      `json:"locked"`
+	Inserted  *QueryBlockResultInserted `json:"inserted,omitempty"`
+	TrayOpen  *bool                     `json:"tray_open,omitempty"`
+	IOStatus  *QueryBlockResultIOStatus `json:"io-status,omitempty"`
+}
+
+// QueryBlockResultInserted is an object of the QMP schema.
+type QueryBlockResultInserted struct {
+	File             string                                 `json:"file"`
+	NodeName         *string                                `json:"node-name,omitempty"`
+	Ro               bool                                   `json:"ro"`
+	Drv              string                                 `json:"drv"`
+	BackingFile      *string                                `json:"backing_file,omitempty"`
+	BackingFileDepth int64                                  `json:"backing_file_depth"`
+	Encrypted        bool                                   `json:"encrypted"`
+	DetectZeroes     QueryBlockResultInsertedDetectZeroes   `json:"detect_zeroes"`
+	Bps              int64                                  `json:"bps"`
+	BpsRd            int64                                  `json:"bps_rd"`
+	BpsWr            int64                                  `json:"bps_wr"`
+	Iops             int64                                  `json:"iops"`
+	IopsRd           int64                                  `json:"iops_rd"`
+	IopsWr           int64                                  `json:"iops_wr"`
+	Image            QueryBlockResultInsertedImage          `json:"image"`
+	BpsMax           *int64                                 `json:"bps_max,omitempty"`
+	BpsRdMax         *int64                                 `json:"bps_rd_max,omitempty"`
+	BpsWrMax         *int64                                 `json:"bps_wr_max,omitempty"`
+	IopsMax          *int64                                 `json:"iops_max,omitempty"`
+	IopsRdMax        *int64                                 `json:"iops_rd_max,omitempty"`
+	IopsWrMax        *int64                                 `json:"iops_wr_max,omitempty"`
+	BpsMaxLength     *int64                                 `json:"bps_max_length,omitempty"`
+	BpsRdMaxLength   *int64                                 `json:"bps_rd_max_length,omitempty"`
+	BpsWrMaxLength   *int64                                 `json:"bps_wr_max_length,omitempty"`
+	IopsMaxLength    *int64                                 `json:"iops_max_length,omitempty"`
+	IopsRdMaxLength  *int64                                 `json:"iops_rd_max_length,omitempty"`
+	IopsWrMaxLength  *int64                                 `json:"iops_wr_max_length,omitempty"`
+	IopsSize         *int64                                 `json:"iops_size,omitempty"`
+	Group            *string                                `json:"group,omitempty"`
+	Cache            QueryBlockResultInsertedCache          `json:"cache"`
+	WriteThreshold   int64                                  `json:"write_threshold"`
+	DirtyBitmaps     []QueryBlockResultInsertedDirtyBitmaps `json:"dirty-bitmaps,omitempty"`
+}
+
+// QueryBlockResultInsertedDetectZeroes is an enum of the QMP schema.
+type QueryBlockResultInsertedDetectZeroes string
+
+const (
+	QueryBlockResultInsertedDetectZeroesOff   QueryBlockResultInsertedDetectZeroes = "off"
+	QueryBlockResultInsertedDetectZeroesOn    QueryBlockResultInsertedDetectZeroes = "on"
+	QueryBlockResultInsertedDetectZeroesUnmap QueryBlockResultInsertedDetectZeroes = "unmap"
+)
+
+// QueryBlockResultInsertedImage is an object of the QMP schema.
+type QueryBlockResultInsertedImage struct {
+	Filename              string                                       `json:"filename"`
+	Format                string                                       `json:"format"`
+	DirtyFlag             *bool                                        `json:"dirty-flag,omitempty"`
+	ActualSize            *int64                                       `json:"actual-size,omitempty"`
+	VirtualSize           int64                                        `json:"virtual-size"`
+	ClusterSize           *int64                                       `json:"cluster-size,omitempty"`
+	Encrypted             *bool                                        `json:"encrypted,omitempty"`
+	Compressed            *bool                                        `json:"compressed,omitempty"`
+	BackingFilename       *string                                      `json:"backing-filename,omitempty"`
+	FullBackingFilename   *string                                      `json:"full-backing-filename,omitempty"`
+	BackingFilenameFormat *string                                      `json:"backing-filename-format,omitempty"`
+	Snapshots             []QueryBlockResultInsertedImageSnapshots     `json:"snapshots,omitempty"`
+	FormatSpecific        *QueryBlockResultInsertedImageFormatSpecific `json:"format-specific,omitempty"`
+	BackingImage          *QueryBlockResultInsertedImage               `json:"backing-image,omitempty"`
+}
+
+// QueryBlockResultInsertedImageSnapshots is an object of the QMP schema.
+type QueryBlockResultInsertedImageSnapshots struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	VMStateSize int64  `json:"vm-state-size"`
+	DateSec     int64  `json:"date-sec"`
+	DateNsec    int64  `json:"date-nsec"`
+	VMClockSec  int64  `json:"vm-clock-sec"`
+	VMClockNsec int64  `json:"vm-clock-nsec"`
+	Icount      *int64 `json:"icount,omitempty"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecific is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecific struct {
+	Type QueryBlockResultInsertedImageFormatSpecificType       `json:"type"`
+	Data *QueryBlockResultInsertedImageFormatSpecificQcow2Data `json:"data,omitempty"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificType is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificType string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificTypeQcow2 QueryBlockResultInsertedImageFormatSpecificType = "qcow2"
+	QueryBlockResultInsertedImageFormatSpecificTypeVmdk  QueryBlockResultInsertedImageFormatSpecificType = "vmdk"
+	QueryBlockResultInsertedImageFormatSpecificTypeLuks  QueryBlockResultInsertedImageFormatSpecificType = "luks"
+	QueryBlockResultInsertedImageFormatSpecificTypeRbd   QueryBlockResultInsertedImageFormatSpecificType = "rbd"
+	QueryBlockResultInsertedImageFormatSpecificTypeFile  QueryBlockResultInsertedImageFormatSpecificType = "file"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2 is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2 struct {
+	Data QueryBlockResultInsertedImageFormatSpecificQcow2Data `json:"data"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2Data is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2Data struct {
+	Compat          string                                                              `json:"compat"`
+	DataFile        *string                                                             `json:"data-file,omitempty"`
+	DataFileRaw     *bool                                                               `json:"data-file-raw,omitempty"`
+	ExtendedL2      *bool                                                               `json:"extended-l2,omitempty"`
+	RefcountBits    int64                                                               `json:"refcount-bits"`
+	LazyRefcounts   *bool                                                               `json:"lazy-refcounts,omitempty"`
+	Corrupt         *bool                                                               `json:"corrupt,omitempty"`
+	Bitmaps         []QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmaps       `json:"bitmaps,omitempty"`
+	Encrypt         *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncrypt        `json:"encrypt,omitempty"`
+	CompressionType QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionType `json:"compression-type"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmaps is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmaps struct {
+	Name        string                                                             `json:"name"`
+	Granularity uint32                                                             `json:"granularity"`
+	Flags       []QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlags `json:"flags"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlags is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlags string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlagsInUse QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlags = "in-use"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlagsAuto  QueryBlockResultInsertedImageFormatSpecificQcow2DataBitmapsFlags = "auto"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncrypt is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncrypt struct {
+	Format         QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat            `json:"format"`
+	CipherAlg      *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg    `json:"cipher-alg,omitempty"`
+	CipherMode     *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode   `json:"cipher-mode,omitempty"`
+	IvgenAlg       *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg     `json:"ivgen-alg,omitempty"`
+	IvgenHashAlg   *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg `json:"ivgen-hash-alg,omitempty"`
+	HashAlg        *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg `json:"hash-alg,omitempty"`
+	PayloadOffset  *int64                                                                       `json:"payload-offset,omitempty"`
+	MasterKeyIters *int64                                                                       `json:"master-key-iters,omitempty"`
+	UUID           *string                                                                      `json:"uuid,omitempty"`
+	Slots          []QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksSlots       `json:"slots,omitempty"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormatAes  QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat = "aes"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormatLuks QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat = "luks"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuks is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuks struct {
+	CipherAlg      QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg     `json:"cipher-alg"`
+	CipherMode     QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode    `json:"cipher-mode"`
+	IvgenAlg       QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg      `json:"ivgen-alg"`
+	IvgenHashAlg   *QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg `json:"ivgen-hash-alg,omitempty"`
+	HashAlg        QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg  `json:"hash-alg"`
+	PayloadOffset  int64                                                                        `json:"payload-offset"`
+	MasterKeyIters int64                                                                        `json:"master-key-iters"`
+	UUID           string                                                                       `json:"uuid"`
+	Slots          []QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksSlots       `json:"slots"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgAes128     QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "aes-128"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgAes192     QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "aes-192"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgAes256     QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "aes-256"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgDes        QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "des"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgX3des      QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "3des"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgCast5128   QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "cast5-128"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgSerpent128 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "serpent-128"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgSerpent192 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "serpent-192"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgSerpent256 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "serpent-256"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgTwofish128 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "twofish-128"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgTwofish192 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "twofish-192"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlgTwofish256 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherAlg = "twofish-256"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherModeEcb QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode = "ecb"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherModeCbc QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode = "cbc"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherModeXts QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode = "xts"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherModeCtr QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksCipherMode = "ctr"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlgPlain   QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg = "plain"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlgPlain64 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg = "plain64"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlgEssiv   QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenAlg = "essiv"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgMd5       QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "md5"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgSha1      QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "sha1"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgSha224    QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "sha224"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgSha256    QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "sha256"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgSha384    QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "sha384"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgSha512    QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "sha512"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlgRipemd160 QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksIvgenHashAlg = "ripemd160"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksSlots is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuksSlots struct {
+	Active    bool   `json:"active"`
+	Iters     *int64 `json:"iters,omitempty"`
+	Stripes   *int64 `json:"stripes,omitempty"`
+	KeyOffset int64  `json:"key-offset"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionType is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionType string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionTypeZlib QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionType = "zlib"
+	QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionTypeZstd QueryBlockResultInsertedImageFormatSpecificQcow2DataCompressionType = "zstd"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificVmdk is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificVmdk struct {
+	Data QueryBlockResultInsertedImageFormatSpecificVmdkData `json:"data"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificVmdkData is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificVmdkData struct {
+	CreateType string                                                       `json:"create-type"`
+	Cid        int64                                                        `json:"cid"`
+	ParentCid  int64                                                        `json:"parent-cid"`
+	Extents    []QueryBlockResultInsertedImageFormatSpecificVmdkDataExtents `json:"extents"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificVmdkDataExtents is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificVmdkDataExtents struct {
+	Filename    string `json:"filename"`
+	Format      string `json:"format"`
+	VirtualSize int64  `json:"virtual-size"`
+	ClusterSize *int64 `json:"cluster-size,omitempty"`
+	Compressed  *bool  `json:"compressed,omitempty"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificLuks is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificLuks struct {
+	Data QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptLuks `json:"data"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificRbd is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificRbd struct {
+	Data QueryBlockResultInsertedImageFormatSpecificRbdData `json:"data"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificRbdData is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificRbdData struct {
+	EncryptionFormat *QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat `json:"encryption-format,omitempty"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat is an enum of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat string
+
+const (
+	QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormatLuks    QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat = "luks"
+	QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormatLuks2   QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat = "luks2"
+	QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormatLuksAny QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat = "luks-any"
+)
+
+// QueryBlockResultInsertedImageFormatSpecificFile is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificFile struct {
+	Data QueryBlockResultInsertedImageFormatSpecificFileData `json:"data"`
+}
+
+// QueryBlockResultInsertedImageFormatSpecificFileData is an object of the QMP schema.
+type QueryBlockResultInsertedImageFormatSpecificFileData struct {
+	ExtentSizeHint *uint64 `json:"extent-size-hint,omitempty"`
+}
+
+// QueryBlockResultInsertedCache is an object of the QMP schema.
+type QueryBlockResultInsertedCache struct {
+	Writeback bool `json:"writeback"`
+	Direct    bool `json:"direct"`
+	NoFlush   bool `json:"no-flush"`
+}
+
+// QueryBlockResultInsertedDirtyBitmaps is an object of the QMP schema.
+type QueryBlockResultInsertedDirtyBitmaps struct {
+	Name         *string `json:"name,omitempty"`
+	Count        int64   `json:"count"`
+	Granularity  uint32  `json:"granularity"`
+	Recording    bool    `json:"recording"`
+	Busy         bool    `json:"busy"`
+	Persistent   bool    `json:"persistent"`
+	Inconsistent *bool   `json:"inconsistent,omitempty"`
+}
+
+// QueryBlockResultIOStatus is an enum of the QMP schema.
+type QueryBlockResultIOStatus string
+
+const (
+	QueryBlockResultIOStatusOk      QueryBlockResultIOStatus = "ok"
+	QueryBlockResultIOStatusFailed  QueryBlockResultIOStatus = "failed"
+	QueryBlockResultIOStatusNospace QueryBlockResultIOStatus = "nospace"
+)
+
+// QueryBlockstatsArguments is an object of the QMP schema.
+type QueryBlockstatsArguments struct {
+	QueryNodes *bool `json:"query-nodes,omitempty"`
+}
+
+// QueryBlockstatsResult is an object of the QMP schema.
+type QueryBlockstatsResult struct {
+	Device         *string                              `json:"device,omitempty"`
+	Qdev           *string                              `json:"qdev,omitempty"`
+	NodeName       *string                              `json:"node-name,omitempty"`
+	Stats          QueryBlockstatsResultStats           `json:"stats"`
+	DriverSpecific *QueryBlockstatsResultDriverSpecific `json:"driver-specific,omitempty"`
+	Parent         *QueryBlockstatsResult               `json:"parent,omitempty"`
+	Backing        *QueryBlockstatsResult               `json:"backing,omitempty"`
+}
+
+// QueryBlockstatsResultStats is an object of the QMP schema.
+type QueryBlockstatsResultStats struct {
+	RdBytes                     int64                                         `json:"rd_bytes"`
+	WrBytes                     int64                                         `json:"wr_bytes"`
+	ZoneAppendBytes             int64                                         `json:"zone_append_bytes"`
+	UnmapBytes                  int64                                         `json:"unmap_bytes"`
+	RdOperations                int64                                         `json:"rd_operations"`
+	WrOperations                int64                                         `json:"wr_operations"`
+	ZoneAppendOperations        int64                                         `json:"zone_append_operations"`
+	FlushOperations             int64                                         `json:"flush_operations"`
+	UnmapOperations             int64                                         `json:"unmap_operations"`
+	RdTotalTimeNs               int64                                         `json:"rd_total_time_ns"`
+	WrTotalTimeNs               int64                                         `json:"wr_total_time_ns"`
+	ZoneAppendTotalTimeNs       int64                                         `json:"zone_append_total_time_ns"`
+	FlushTotalTimeNs            int64                                         `json:"flush_total_time_ns"`
+	UnmapTotalTimeNs            int64                                         `json:"unmap_total_time_ns"`
+	WrHighestOffset             int64                                         `json:"wr_highest_offset"`
+	RdMerged                    int64                                         `json:"rd_merged"`
+	WrMerged                    int64                                         `json:"wr_merged"`
+	ZoneAppendMerged            int64                                         `json:"zone_append_merged"`
+	UnmapMerged                 int64                                         `json:"unmap_merged"`
+	IdleTimeNs                  *int64                                        `json:"idle_time_ns,omitempty"`
+	FailedRdOperations          int64                                         `json:"failed_rd_operations"`
+	FailedWrOperations          int64                                         `json:"failed_wr_operations"`
+	FailedZoneAppendOperations  int64                                         `json:"failed_zone_append_operations"`
+	FailedFlushOperations       int64                                         `json:"failed_flush_operations"`
+	FailedUnmapOperations       int64                                         `json:"failed_unmap_operations"`
+	InvalidRdOperations         int64                                         `json:"invalid_rd_operations"`
+	InvalidWrOperations         int64                                         `json:"invalid_wr_operations"`
+	InvalidZoneAppendOperations int64                                         `json:"invalid_zone_append_operations"`
+	InvalidFlushOperations      int64                                         `json:"invalid_flush_operations"`
+	InvalidUnmapOperations      int64                                         `json:"invalid_unmap_operations"`
+	AccountInvalid              bool                                          `json:"account_invalid"`
+	AccountFailed               bool                                          `json:"account_failed"`
+	TimedStats                  []QueryBlockstatsResultStatsTimedStats        `json:"timed_stats"`
+	RdLatencyHistogram          *QueryBlockstatsResultStatsRdLatencyHistogram `json:"rd_latency_histogram,omitempty"`
+	WrLatencyHistogram          *QueryBlockstatsResultStatsRdLatencyHistogram `json:"wr_latency_histogram,omitempty"`
+	ZoneAppendLatencyHistogram  *QueryBlockstatsResultStatsRdLatencyHistogram `json:"zone_append_latency_histogram,omitempty"`
+	FlushLatencyHistogram       *QueryBlockstatsResultStatsRdLatencyHistogram `json:"flush_latency_histogram,omitempty"`
+}
+
+// QueryBlockstatsResultStatsTimedStats is an object of the QMP schema.
+type QueryBlockstatsResultStatsTimedStats struct {
+	IntervalLength          int64   `json:"interval_length"`
+	MinRdLatencyNs          int64   `json:"min_rd_latency_ns"`
+	MaxRdLatencyNs          int64   `json:"max_rd_latency_ns"`
+	AvgRdLatencyNs          int64   `json:"avg_rd_latency_ns"`
+	MinWrLatencyNs          int64   `json:"min_wr_latency_ns"`
+	MaxWrLatencyNs          int64   `json:"max_wr_latency_ns"`
+	AvgWrLatencyNs          int64   `json:"avg_wr_latency_ns"`
+	MinZoneAppendLatencyNs  int64   `json:"min_zone_append_latency_ns"`
+	MaxZoneAppendLatencyNs  int64   `json:"max_zone_append_latency_ns"`
+	AvgZoneAppendLatencyNs  int64   `json:"avg_zone_append_latency_ns"`
+	MinFlushLatencyNs       int64   `json:"min_flush_latency_ns"`
+	MaxFlushLatencyNs       int64   `json:"max_flush_latency_ns"`
+	AvgFlushLatencyNs       int64   `json:"avg_flush_latency_ns"`
+	AvgRdQueueDepth         float64 `json:"avg_rd_queue_depth"`
+	AvgWrQueueDepth         float64 `json:"avg_wr_queue_depth"`
+	AvgZoneAppendQueueDepth float64 `json:"avg_zone_append_queue_depth"`
+}
+
+// QueryBlockstatsResultStatsRdLatencyHistogram is an object of the QMP schema.
+type QueryBlockstatsResultStatsRdLatencyHistogram struct {
+	Boundaries []uint64 `json:"boundaries"`
+	Bins       []uint64 `json:"bins"`
+}
+
+// QueryBlockstatsResultDriverSpecific is an object of the QMP schema.
+type QueryBlockstatsResultDriverSpecific struct {
+	Driver            QueryBlockstatsResultDriverSpecificDriver `json:"driver"`
+	DiscardNbOk       *uint64                                   `json:"discard-nb-ok,omitempty"`
+	DiscardNbFailed   *uint64                                   `json:"discard-nb-failed,omitempty"`
+	DiscardBytesOk    *uint64                                   `json:"discard-bytes-ok,omitempty"`
+	CompletionErrors  *uint64                                   `json:"completion-errors,omitempty"`
+	AlignedAccesses   *uint64                                   `json:"aligned-accesses,omitempty"`
+	UnalignedAccesses *uint64                                   `json:"unaligned-accesses,omitempty"`
+}
+
+// QueryBlockstatsResultDriverSpecificDriver is an enum of the QMP schema.
+type QueryBlockstatsResultDriverSpecificDriver string
+
+const (
+	QueryBlockstatsResultDriverSpecificDriverBlkdebug        QueryBlockstatsResultDriverSpecificDriver = "blkdebug"
+	QueryBlockstatsResultDriverSpecificDriverBlklogwrites    QueryBlockstatsResultDriverSpecificDriver = "blklogwrites"
+	QueryBlockstatsResultDriverSpecificDriverBlkreplay       QueryBlockstatsResultDriverSpecificDriver = "blkreplay"
+	QueryBlockstatsResultDriverSpecificDriverBlkverify       QueryBlockstatsResultDriverSpecificDriver = "blkverify"
+	QueryBlockstatsResultDriverSpecificDriverBochs           QueryBlockstatsResultDriverSpecificDriver = "bochs"
+	QueryBlockstatsResultDriverSpecificDriverCloop           QueryBlockstatsResultDriverSpecificDriver = "cloop"
+	QueryBlockstatsResultDriverSpecificDriverCompress        QueryBlockstatsResultDriverSpecificDriver = "compress"
+	QueryBlockstatsResultDriverSpecificDriverCopyBeforeWrite QueryBlockstatsResultDriverSpecificDriver = "copy-before-write"
+	QueryBlockstatsResultDriverSpecificDriverCopyOnRead      QueryBlockstatsResultDriverSpecificDriver = "copy-on-read"
+	QueryBlockstatsResultDriverSpecificDriverDmg             QueryBlockstatsResultDriverSpecificDriver = "dmg"
+	QueryBlockstatsResultDriverSpecificDriverFile            QueryBlockstatsResultDriverSpecificDriver = "file"
+	QueryBlockstatsResultDriverSpecificDriverSnapshotAccess  QueryBlockstatsResultDriverSpecificDriver = "snapshot-access"
+	QueryBlockstatsResultDriverSpecificDriverFtp             QueryBlockstatsResultDriverSpecificDriver = "ftp"
+	QueryBlockstatsResultDriverSpecificDriverFtps            QueryBlockstatsResultDriverSpecificDriver = "ftps"
+	QueryBlockstatsResultDriverSpecificDriverGluster         QueryBlockstatsResultDriverSpecificDriver = "gluster"
+	QueryBlockstatsResultDriverSpecificDriverHostCdrom       QueryBlockstatsResultDriverSpecificDriver = "host_cdrom"
+	QueryBlockstatsResultDriverSpecificDriverHostDevice      QueryBlockstatsResultDriverSpecificDriver = "host_device"
+	QueryBlockstatsResultDriverSpecificDriverHttp            QueryBlockstatsResultDriverSpecificDriver = "http"
+	QueryBlockstatsResultDriverSpecificDriverHttps           QueryBlockstatsResultDriverSpecificDriver = "https"
+	QueryBlockstatsResultDriverSpecificDriverIscsi           QueryBlockstatsResultDriverSpecificDriver = "iscsi"
+	QueryBlockstatsResultDriverSpecificDriverLuks            QueryBlockstatsResultDriverSpecificDriver = "luks"
+	QueryBlockstatsResultDriverSpecificDriverNbd             QueryBlockstatsResultDriverSpecificDriver = "nbd"
+	QueryBlockstatsResultDriverSpecificDriverNfs             QueryBlockstatsResultDriverSpecificDriver = "nfs"
+	QueryBlockstatsResultDriverSpecificDriverNullAio         QueryBlockstatsResultDriverSpecificDriver = "null-aio"
+	QueryBlockstatsResultDriverSpecificDriverNullCo          QueryBlockstatsResultDriverSpecificDriver = "null-co"
+	QueryBlockstatsResultDriverSpecificDriverNvme            QueryBlockstatsResultDriverSpecificDriver = "nvme"
+	QueryBlockstatsResultDriverSpecificDriverParallels       QueryBlockstatsResultDriverSpecificDriver = "parallels"
+	QueryBlockstatsResultDriverSpecificDriverPreallocate     QueryBlockstatsResultDriverSpecificDriver = "preallocate"
+	QueryBlockstatsResultDriverSpecificDriverQcow            QueryBlockstatsResultDriverSpecificDriver = "qcow"
+	QueryBlockstatsResultDriverSpecificDriverQcow2           QueryBlockstatsResultDriverSpecificDriver = "qcow2"
+	QueryBlockstatsResultDriverSpecificDriverQed             QueryBlockstatsResultDriverSpecificDriver = "qed"
+	QueryBlockstatsResultDriverSpecificDriverQuorum          QueryBlockstatsResultDriverSpecificDriver = "quorum"
+	QueryBlockstatsResultDriverSpecificDriverRaw             QueryBlockstatsResultDriverSpecificDriver = "raw"
+	QueryBlockstatsResultDriverSpecificDriverRbd             QueryBlockstatsResultDriverSpecificDriver = "rbd"
+	QueryBlockstatsResultDriverSpecificDriverReplication     QueryBlockstatsResultDriverSpecificDriver = "replication"
+	QueryBlockstatsResultDriverSpecificDriverSsh             QueryBlockstatsResultDriverSpecificDriver = "ssh"
+	QueryBlockstatsResultDriverSpecificDriverThrottle        QueryBlockstatsResultDriverSpecificDriver = "throttle"
+	QueryBlockstatsResultDriverSpecificDriverVdi             QueryBlockstatsResultDriverSpecificDriver = "vdi"
+	QueryBlockstatsResultDriverSpecificDriverVhdx            QueryBlockstatsResultDriverSpecificDriver = "vhdx"
+	QueryBlockstatsResultDriverSpecificDriverVmdk            QueryBlockstatsResultDriverSpecificDriver = "vmdk"
+	QueryBlockstatsResultDriverSpecificDriverVpc             QueryBlockstatsResultDriverSpecificDriver = "vpc"
+	QueryBlockstatsResultDriverSpecificDriverVvfat           QueryBlockstatsResultDriverSpecificDriver = "vvfat"
+)
+
+// QueryBlockstatsResultDriverSpecificFile is an object of the QMP schema.
+type QueryBlockstatsResultDriverSpecificFile struct {
+	DiscardNbOk     uint64 `json:"discard-nb-ok"`
+	DiscardNbFailed uint64 `json:"discard-nb-failed"`
+	DiscardBytesOk  uint64 `json:"discard-bytes-ok"`
+}
+
+// QueryBlockstatsResultDriverSpecificNvme is an object of the QMP schema.
+type QueryBlockstatsResultDriverSpecificNvme struct {
+	CompletionErrors  uint64 `json:"completion-errors"`
+	AlignedAccesses   uint64 `json:"aligned-accesses"`
+	UnalignedAccesses uint64 `json:"unaligned-accesses"`
+}
+
+// QueryNamedBlockNodesArguments is an object of the QMP schema.
+type QueryNamedBlockNodesArguments struct {
+	Flat *bool `json:"flat,omitempty"`
+}
+
+// BlockdevAddArguments is an object of the QMP schema.
+type BlockdevAddArguments struct {
+	Driver                 QueryBlockstatsResultDriverSpecificDriver      `json:"driver"`
+	NodeName               *string                                        `json:"node-name,omitempty"`
+	Discard                *BlockdevAddArgumentsDiscard                   `json:"discard,omitempty"`
+	Cache                  *BlockdevAddArgumentsCache                     `json:"cache,omitempty"`
+	ReadOnly               *bool                                          `json:"read-only,omitempty"`
+	AutoReadOnly           *bool                                          `json:"auto-read-only,omitempty"`
+	ForceShare             *bool                                          `json:"force-share,omitempty"`
+	DetectZeroes           *QueryBlockResultInsertedDetectZeroes          `json:"detect-zeroes,omitempty"`
+	Image                  BlockdevAddArgumentsBlkdebugImage              `json:"image,omitempty"`
+	Config                 *string                                        `json:"config,omitempty"`
+	Align                  *int64                                         `json:"align,omitempty"`
+	MaxTransfer            *int32                                         `json:"max-transfer,omitempty"`
+	OptWriteZero           *int32                                         `json:"opt-write-zero,omitempty"`
+	MaxWriteZero           *int32                                         `json:"max-write-zero,omitempty"`
+	OptDiscard             *int32                                         `json:"opt-discard,omitempty"`
+	MaxDiscard             *int32                                         `json:"max-discard,omitempty"`
+	InjectError            []BlockdevAddArgumentsBlkdebugInjectError      `json:"inject-error,omitempty"`
+	SetState               []BlockdevAddArgumentsBlkdebugSetState         `json:"set-state,omitempty"`
+	TakeChildPerms         []BlockdevAddArgumentsBlkdebugTakeChildPerms   `json:"take-child-perms,omitempty"`
+	UnshareChildPerms      []BlockdevAddArgumentsBlkdebugTakeChildPerms   `json:"unshare-child-perms,omitempty"`
+	File                   BlockdevAddArgumentsBlkdebugImage              `json:"file,omitempty"`
+	Log                    BlockdevAddArgumentsBlkdebugImage              `json:"log,omitempty"`
+	LogSectorSize          *uint32                                        `json:"log-sector-size,omitempty"`
+	LogAppend              *bool                                          `json:"log-append,omitempty"`
+	LogSuperUpdateInterval *uint64                                        `json:"log-super-update-interval,omitempty"`
+	Test                   BlockdevAddArgumentsBlkdebugImage              `json:"test,omitempty"`
+	Raw                    BlockdevAddArgumentsBlkdebugImage              `json:"raw,omitempty"`
+	Target                 BlockdevAddArgumentsBlkdebugImage              `json:"target,omitempty"`
+	Bitmap                 *BlockdevAddArgumentsCopyBeforeWriteBitmap     `json:"bitmap,omitempty"`
+	OnCbwError             *BlockdevAddArgumentsCopyBeforeWriteOnCbwError `json:"on-cbw-error,omitempty"`
+	CbwTimeout             *uint32                                        `json:"cbw-timeout,omitempty"`
+	Bottom                 *string                                        `json:"bottom,omitempty"`
+	Filename               *string                                        `json:"filename,omitempty"`
+	PrManager              *string                                        `json:"pr-manager,omitempty"`
+	Locking                *ObjectAddArgumentsMemoryBackendFileRom        `json:"locking,omitempty"`
+	Aio                    *BlockdevAddArgumentsFileAio                   `json:"aio,omitempty"`
+	AioMaxBatch            *int64                                         `json:"aio-max-batch,omitempty"`
+	DropCache              *bool                                          `json:"drop-cache,omitempty"`
+	XCheckCacheDropped     *bool                                          `json:"x-check-cache-dropped,omitempty"`
+	URL                    *string                                        `json:"url,omitempty"`
+	Readahead              *int64                                         `json:"readahead,omitempty"`
+	Timeout                *int64                                         `json:"timeout,omitempty"`
+	Username               *string                                        `json:"username,omitempty"`
+	PasswordSecret         *string                                        `json:"password-secret,omitempty"`
+	ProxyUsername          *string                                        `json:"proxy-username,omitempty"`
+	ProxyPasswordSecret    *string                                        `json:"proxy-password-secret,omitempty"`
+	Sslverify              *bool                                          `json:"sslverify,omitempty"`
+	Volume                 *string                                        `json:"volume,omitempty"`
+	Path                   *string                                        `json:"path,omitempty"`
+	Server                 []ObjectAddArgumentsXVfioUserServerSocket      `json:"server,omitempty"`
+	Debug                  *int64                                         `json:"debug,omitempty"`
+	Logfile                *string                                        `json:"logfile,omitempty"`
+	Cookie                 *string                                        `json:"cookie,omitempty"`
+	CookieSecret           *string                                        `json:"cookie-secret,omitempty"`
+	Transport              *BlockdevAddArgumentsIscsiTransport            `json:"transport,omitempty"`
+	Portal                 *string                                        `json:"portal,omitempty"`
+	Lun                    *int64                                         `json:"lun,omitempty"`
+	User                   *string                                        `json:"user,omitempty"`
+	InitiatorName          *string                                        `json:"initiator-name,omitempty"`
+	HeaderDigest           *BlockdevAddArgumentsIscsiHeaderDigest         `json:"header-digest,omitempty"`
+	KeySecret              *string                                        `json:"key-secret,omitempty"`
+	Export                 *string                                        `json:"export,omitempty"`
+	TlsCreds               *string                                        `json:"tls-creds,omitempty"`
+	TlsHostname            *string                                        `json:"tls-hostname,omitempty"`
+	XDirtyBitmap           *string                                        `json:"x-dirty-bitmap,omitempty"`
+	ReconnectDelay         *uint32                                        `json:"reconnect-delay,omitempty"`
+	OpenTimeout            *uint32                                        `json:"open-timeout,omitempty"`
+	Group                  *int64                                         `json:"group,omitempty"`
+	TcpSynCount            *int64                                         `json:"tcp-syn-count,omitempty"`
+	ReadaheadSize          *int64                                         `json:"readahead-size,omitempty"`
+	PageCacheSize          *int64                                         `json:"page-cache-size,omitempty"`
+	Size                   *int64                                         `json:"size,omitempty"`
+	LatencyNs              *uint64                                        `json:"latency-ns,omitempty"`
+	ReadZeroes             *bool                                          `json:"read-zeroes,omitempty"`
+	Device                 *string                                        `json:"device,omitempty"`
+	Namespace              *int64                                         `json:"namespace,omitempty"`
+	PreallocAlign          *int64                                         `json:"prealloc-align,omitempty"`
+	PreallocSize           *int64                                         `json:"prealloc-size,omitempty"`
+	Backing                BlockdevAddArgumentsQcowBacking                `json:"backing,omitempty"`
+	Encrypt                *BlockdevAddArgumentsQcowEncrypt               `json:"encrypt,omitempty"`
+	LazyRefcounts          *bool                                          `json:"lazy-refcounts,omitempty"`
+	PassDiscardRequest     *bool                                          `json:"pass-discard-request,omitempty"`
+	PassDiscardSnapshot    *bool                                          `json:"pass-discard-snapshot,omitempty"`
+	PassDiscardOther       *bool                                          `json:"pass-discard-other,omitempty"`
+	DiscardNoUnref         *bool                                          `json:"discard-no-unref,omitempty"`
+	OverlapCheck           BlockdevAddArgumentsQcow2OverlapCheck          `json:"overlap-check,omitempty"`
+	CacheSize              *int64                                         `json:"cache-size,omitempty"`
+	L2CacheSize            *int64                                         `json:"l2-cache-size,omitempty"`
+	L2CacheEntrySize       *int64                                         `json:"l2-cache-entry-size,omitempty"`
+	RefcountCacheSize      *int64                                         `json:"refcount-cache-size,omitempty"`
+	CacheCleanInterval     *int64                                         `json:"cache-clean-interval,omitempty"`
+	DataFile               BlockdevAddArgumentsBlkdebugImage              `json:"data-file,omitempty"`
+	Blkverify              *bool                                          `json:"blkverify,omitempty"`
+	Children               []BlockdevAddArgumentsBlkdebugImage            `json:"children,omitempty"`
+	VoteThreshold          *int64                                         `json:"vote-threshold,omitempty"`
+	RewriteCorrupted       *bool                                          `json:"rewrite-corrupted,omitempty"`
+	ReadPattern            *BlockdevAddArgumentsQuorumReadPattern         `json:"read-pattern,omitempty"`
+	Offset                 *int64                                         `json:"offset,omitempty"`
+	Pool                   *string                                        `json:"pool,omitempty"`
+	Conf                   *string                                        `json:"conf,omitempty"`
+	Snapshot               *string                                        `json:"snapshot,omitempty"`
+	AuthClientRequired     []BlockdevAddArgumentsRbdAuthClientRequired    `json:"auth-client-required,omitempty"`
+	Mode                   *BlockdevAddArgumentsReplicationMode           `json:"mode,omitempty"`
+	TopID                  *string                                        `json:"top-id,omitempty"`
+	HostKeyCheck           *BlockdevAddArgumentsSshHostKeyCheck           `json:"host-key-check,omitempty"`
+	ThrottleGroup          *string                                        `json:"throttle-group,omitempty"`
+	Dir                    *string                                        `json:"dir,omitempty"`
+	FatType                *int64                                         `json:"fat-type,omitempty"`
+	Floppy                 *bool                                          `json:"floppy,omitempty"`
+	Label                  *string                                        `json:"label,omitempty"`
+	Rw                     *bool                                          `json:"rw,omitempty"`
+}
+
+// BlockdevAddArgumentsDiscard is an enum of the QMP schema.
+type BlockdevAddArgumentsDiscard string
+
+const (
+	BlockdevAddArgumentsDiscardIgnore BlockdevAddArgumentsDiscard = "ignore"
+	BlockdevAddArgumentsDiscardUnmap  BlockdevAddArgumentsDiscard = "unmap"
+)
+
+// BlockdevAddArgumentsCache is an object of the QMP schema.
+type BlockdevAddArgumentsCache struct {
+	Direct  *bool `json:"direct,omitempty"`
+	NoFlush *bool `json:"no-flush,omitempty"`
+}
+
+// BlockdevAddArgumentsBlkdebug is an object of the QMP schema.
+type BlockdevAddArgumentsBlkdebug struct {
+	Image             BlockdevAddArgumentsBlkdebugImage            `json:"image"`
+	Config            *string                                      `json:"config,omitempty"`
+	Align             *int64                                       `json:"align,omitempty"`
+	MaxTransfer       *int32                                       `json:"max-transfer,omitempty"`
+	OptWriteZero      *int32                                       `json:"opt-write-zero,omitempty"`
+	MaxWriteZero      *int32                                       `json:"max-write-zero,omitempty"`
+	OptDiscard        *int32                                       `json:"opt-discard,omitempty"`
+	MaxDiscard        *int32                                       `json:"max-discard,omitempty"`
+	InjectError       []BlockdevAddArgumentsBlkdebugInjectError    `json:"inject-error,omitempty"`
+	SetState          []BlockdevAddArgumentsBlkdebugSetState       `json:"set-state,omitempty"`
+	TakeChildPerms    []BlockdevAddArgumentsBlkdebugTakeChildPerms `json:"take-child-perms,omitempty"`
+	UnshareChildPerms []BlockdevAddArgumentsBlkdebugTakeChildPerms `json:"unshare-child-perms,omitempty"`
+}
+
+// BlockdevAddArgumentsBlkdebugImage is an alternate of the QMP schema, which is one of several types.
+type BlockdevAddArgumentsBlkdebugImage = json.RawMessage
+
+// BlockdevAddArgumentsBlkdebugInjectError is an object of the QMP schema.
+type BlockdevAddArgumentsBlkdebugInjectError struct {
+	Event       BlockdevAddArgumentsBlkdebugInjectErrorEvent   `json:"event"`
+	State       *int64                                         `json:"state,omitempty"`
+	Iotype      *BlockdevAddArgumentsBlkdebugInjectErrorIotype `json:"iotype,omitempty"`
+	Errno       *int64                                         `json:"errno,omitempty"`
+	Sector      *int64                                         `json:"sector,omitempty"`
+	Once        *bool                                          `json:"once,omitempty"`
+	Immediately *bool                                          `json:"immediately,omitempty"`
+}
+
+// BlockdevAddArgumentsBlkdebugInjectErrorEvent is an enum of the QMP schema.
+type BlockdevAddArgumentsBlkdebugInjectErrorEvent string
+
+const (
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1Update                 BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_update"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1GrowAllocTable         BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_grow.alloc_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1GrowWriteTable         BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_grow.write_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1GrowActivateTable      BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_grow.activate_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL2Load                   BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l2_load"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL2Update                 BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l2_update"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL2UpdateCompressed       BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l2_update_compressed"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL2AllocCowRead           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l2_alloc.cow_read"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL2AllocWrite             BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l2_alloc.write"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReadAio                  BlockdevAddArgumentsBlkdebugInjectErrorEvent = "read_aio"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReadBackingAio           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "read_backing_aio"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReadCompressed           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "read_compressed"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventWriteAio                 BlockdevAddArgumentsBlkdebugInjectErrorEvent = "write_aio"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventWriteCompressed          BlockdevAddArgumentsBlkdebugInjectErrorEvent = "write_compressed"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventVmstateLoad              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "vmstate_load"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventVmstateSave              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "vmstate_save"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventCowRead                  BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cow_read"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventCowWrite                 BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cow_write"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReftableLoad             BlockdevAddArgumentsBlkdebugInjectErrorEvent = "reftable_load"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReftableGrow             BlockdevAddArgumentsBlkdebugInjectErrorEvent = "reftable_grow"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventReftableUpdate           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "reftable_update"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockLoad             BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_load"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockUpdate           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_update"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockUpdatePart       BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_update_part"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAlloc            BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAllocHookup      BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc.hookup"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAllocWrite       BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc.write"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAllocWriteBlocks BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc.write_blocks"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAllocWriteTable  BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc.write_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventRefblockAllocSwitchTable BlockdevAddArgumentsBlkdebugInjectErrorEvent = "refblock_alloc.switch_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventClusterAlloc             BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cluster_alloc"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventClusterAllocBytes        BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cluster_alloc_bytes"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventClusterFree              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cluster_free"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventFlushToOs                BlockdevAddArgumentsBlkdebugInjectErrorEvent = "flush_to_os"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventFlushToDisk              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "flush_to_disk"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevRmwHead           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_rmw_head"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevRmwAfterHead      BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_rmw_after_head"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevRmwTail           BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_rmw_tail"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevRmwAfterTail      BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_rmw_after_tail"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritev                  BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevZero              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_zero"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventPwritevDone              BlockdevAddArgumentsBlkdebugInjectErrorEvent = "pwritev_done"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventEmptyImagePrepare        BlockdevAddArgumentsBlkdebugInjectErrorEvent = "empty_image_prepare"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1ShrinkWriteTable       BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_shrink_write_table"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventL1ShrinkFreeL2Clusters   BlockdevAddArgumentsBlkdebugInjectErrorEvent = "l1_shrink_free_l2_clusters"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventCorWrite                 BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cor_write"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventClusterAllocSpace        BlockdevAddArgumentsBlkdebugInjectErrorEvent = "cluster_alloc_space"
+	BlockdevAddArgumentsBlkdebugInjectErrorEventNone                     BlockdevAddArgumentsBlkdebugInjectErrorEvent = "none"
+)
+
+// BlockdevAddArgumentsBlkdebugInjectErrorIotype is an enum of the QMP schema.
+type BlockdevAddArgumentsBlkdebugInjectErrorIotype string
+
+const (
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeRead        BlockdevAddArgumentsBlkdebugInjectErrorIotype = "read"
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeWrite       BlockdevAddArgumentsBlkdebugInjectErrorIotype = "write"
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeWriteZeroes BlockdevAddArgumentsBlkdebugInjectErrorIotype = "write-zeroes"
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeDiscard     BlockdevAddArgumentsBlkdebugInjectErrorIotype = "discard"
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeFlush       BlockdevAddArgumentsBlkdebugInjectErrorIotype = "flush"
+	BlockdevAddArgumentsBlkdebugInjectErrorIotypeBlockStatus BlockdevAddArgumentsBlkdebugInjectErrorIotype = "block-status"
+)
+
+// BlockdevAddArgumentsBlkdebugSetState is an object of the QMP schema.
+type BlockdevAddArgumentsBlkdebugSetState struct {
+	Event    BlockdevAddArgumentsBlkdebugInjectErrorEvent `json:"event"`
+	State    *int64                                       `json:"state,omitempty"`
+	NewState int64                                        `json:"new_state"`
+}
+
+// BlockdevAddArgumentsBlkdebugTakeChildPerms is an enum of the QMP schema.
+type BlockdevAddArgumentsBlkdebugTakeChildPerms string
+
+const (
+	BlockdevAddArgumentsBlkdebugTakeChildPermsConsistentRead BlockdevAddArgumentsBlkdebugTakeChildPerms = "consistent-read"
+	BlockdevAddArgumentsBlkdebugTakeChildPermsWrite          BlockdevAddArgumentsBlkdebugTakeChildPerms = "write"
+	BlockdevAddArgumentsBlkdebugTakeChildPermsWriteUnchanged BlockdevAddArgumentsBlkdebugTakeChildPerms = "write-unchanged"
+	BlockdevAddArgumentsBlkdebugTakeChildPermsResize         BlockdevAddArgumentsBlkdebugTakeChildPerms = "resize"
+)
+
+// BlockdevAddArgumentsBlklogwrites is an object of the QMP schema.
+type BlockdevAddArgumentsBlklogwrites struct {
+	File                   BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	Log                    BlockdevAddArgumentsBlkdebugImage `json:"log"`
+	LogSectorSize          *uint32                           `json:"log-sector-size,omitempty"`
+	LogAppend              *bool                             `json:"log-append,omitempty"`
+	LogSuperUpdateInterval *uint64                           `json:"log-super-update-interval,omitempty"`
+}
+
+// BlockdevAddArgumentsBlkreplay is an object of the QMP schema.
+type BlockdevAddArgumentsBlkreplay struct {
+	Image BlockdevAddArgumentsBlkdebugImage `json:"image"`
+}
+
+// BlockdevAddArgumentsBlkverify is an object of the QMP schema.
+type BlockdevAddArgumentsBlkverify struct {
+	Test BlockdevAddArgumentsBlkdebugImage `json:"test"`
+	Raw  BlockdevAddArgumentsBlkdebugImage `json:"raw"`
+}
+
+// BlockdevAddArgumentsBochs is an object of the QMP schema.
+type BlockdevAddArgumentsBochs struct {
+	File BlockdevAddArgumentsBlkdebugImage `json:"file"`
+}
+
+// BlockdevAddArgumentsCopyBeforeWrite is an object of the QMP schema.
+type BlockdevAddArgumentsCopyBeforeWrite struct {
+	File       BlockdevAddArgumentsBlkdebugImage              `json:"file"`
+	Target     BlockdevAddArgumentsBlkdebugImage              `json:"target"`
+	Bitmap     *BlockdevAddArgumentsCopyBeforeWriteBitmap     `json:"bitmap,omitempty"`
+	OnCbwError *BlockdevAddArgumentsCopyBeforeWriteOnCbwError `json:"on-cbw-error,omitempty"`
+	CbwTimeout *uint32                                        `json:"cbw-timeout,omitempty"`
+}
+
+// BlockdevAddArgumentsCopyBeforeWriteBitmap is an object of the QMP schema.
+type BlockdevAddArgumentsCopyBeforeWriteBitmap struct {
+	Node string `json:"node"`
+	Name string `json:"name"`
+}
+
+// BlockdevAddArgumentsCopyBeforeWriteOnCbwError is an enum of the QMP schema.
+type BlockdevAddArgumentsCopyBeforeWriteOnCbwError string
+
+const (
+	BlockdevAddArgumentsCopyBeforeWriteOnCbwErrorBreakGuestWrite BlockdevAddArgumentsCopyBeforeWriteOnCbwError = "break-guest-write"
+	BlockdevAddArgumentsCopyBeforeWriteOnCbwErrorBreakSnapshot   BlockdevAddArgumentsCopyBeforeWriteOnCbwError = "break-snapshot"
+)
+
+// BlockdevAddArgumentsCopyOnRead is an object of the QMP schema.
+type BlockdevAddArgumentsCopyOnRead struct {
+	File   BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	Bottom *string                           `json:"bottom,omitempty"`
+}
+
+// BlockdevAddArgumentsFile is an object of the QMP schema.
+type BlockdevAddArgumentsFile struct {
+	Filename           string                                  `json:"filename"`
+	PrManager          *string                                 `json:"pr-manager,omitempty"`
+	Locking            *ObjectAddArgumentsMemoryBackendFileRom `json:"locking,omitempty"`
+	Aio                *BlockdevAddArgumentsFileAio            `json:"aio,omitempty"`
+	AioMaxBatch        *int64                                  `json:"aio-max-batch,omitempty"`
+	DropCache          *bool                                   `json:"drop-cache,omitempty"`
+	XCheckCacheDropped *bool                                   `json:"x-check-cache-dropped,omitempty"`
+}
+
+// BlockdevAddArgumentsFileAio is an enum of the QMP schema.
+type BlockdevAddArgumentsFileAio string
+
+const (
+	BlockdevAddArgumentsFileAioThreads BlockdevAddArgumentsFileAio = "threads"
+	BlockdevAddArgumentsFileAioNative  BlockdevAddArgumentsFileAio = "native"
+	BlockdevAddArgumentsFileAioIOUring BlockdevAddArgumentsFileAio = "io_uring"
+)
+
+// BlockdevAddArgumentsFtp is an object of the QMP schema.
+type BlockdevAddArgumentsFtp struct {
+	URL                 string  `json:"url"`
+	Readahead           *int64  `json:"readahead,omitempty"`
+	Timeout             *int64  `json:"timeout,omitempty"`
+	Username            *string `json:"username,omitempty"`
+	PasswordSecret      *string `json:"password-secret,omitempty"`
+	ProxyUsername       *string `json:"proxy-username,omitempty"`
+	ProxyPasswordSecret *string `json:"proxy-password-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsFtps is an object of the QMP schema.
+type BlockdevAddArgumentsFtps struct {
+	URL                 string  `json:"url"`
+	Readahead           *int64  `json:"readahead,omitempty"`
+	Timeout             *int64  `json:"timeout,omitempty"`
+	Username            *string `json:"username,omitempty"`
+	PasswordSecret      *string `json:"password-secret,omitempty"`
+	ProxyUsername       *string `json:"proxy-username,omitempty"`
+	ProxyPasswordSecret *string `json:"proxy-password-secret,omitempty"`
+	Sslverify           *bool   `json:"sslverify,omitempty"`
+}
+
+// BlockdevAddArgumentsGluster is an object of the QMP schema.
+type BlockdevAddArgumentsGluster struct {
+	Volume  string                                    `json:"volume"`
+	Path    string                                    `json:"path"`
+	Server  []ObjectAddArgumentsXVfioUserServerSocket `json:"server"`
+	Debug   *int64                                    `json:"debug,omitempty"`
+	Logfile *string                                   `json:"logfile,omitempty"`
+}
+
+// BlockdevAddArgumentsHttp is an object of the QMP schema.
+type BlockdevAddArgumentsHttp struct {
+	URL                 string  `json:"url"`
+	Readahead           *int64  `json:"readahead,omitempty"`
+	Timeout             *int64  `json:"timeout,omitempty"`
+	Username            *string `json:"username,omitempty"`
+	PasswordSecret      *string `json:"password-secret,omitempty"`
+	ProxyUsername       *string `json:"proxy-username,omitempty"`
+	ProxyPasswordSecret *string `json:"proxy-password-secret,omitempty"`
+	Cookie              *string `json:"cookie,omitempty"`
+	CookieSecret        *string `json:"cookie-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsHttps is an object of the QMP schema.
+type BlockdevAddArgumentsHttps struct {
+	URL                 string  `json:"url"`
+	Readahead           *int64  `json:"readahead,omitempty"`
+	Timeout             *int64  `json:"timeout,omitempty"`
+	Username            *string `json:"username,omitempty"`
+	PasswordSecret      *string `json:"password-secret,omitempty"`
+	ProxyUsername       *string `json:"proxy-username,omitempty"`
+	ProxyPasswordSecret *string `json:"proxy-password-secret,omitempty"`
+	Cookie              *string `json:"cookie,omitempty"`
+	Sslverify           *bool   `json:"sslverify,omitempty"`
+	CookieSecret        *string `json:"cookie-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsIscsi is an object of the QMP schema.
+type BlockdevAddArgumentsIscsi struct {
+	Transport      BlockdevAddArgumentsIscsiTransport     `json:"transport"`
+	Portal         string                                 `json:"portal"`
+	Target         string                                 `json:"target"`
+	Lun            *int64                                 `json:"lun,omitempty"`
+	User           *string                                `json:"user,omitempty"`
+	PasswordSecret *string                                `json:"password-secret,omitempty"`
+	InitiatorName  *string                                `json:"initiator-name,omitempty"`
+	HeaderDigest   *BlockdevAddArgumentsIscsiHeaderDigest `json:"header-digest,omitempty"`
+	Timeout        *int64                                 `json:"timeout,omitempty"`
+}
+
+// BlockdevAddArgumentsIscsiTransport is an enum of the QMP schema.
+type BlockdevAddArgumentsIscsiTransport string
+
+const (
+	BlockdevAddArgumentsIscsiTransportTcp  BlockdevAddArgumentsIscsiTransport = "tcp"
+	BlockdevAddArgumentsIscsiTransportIser BlockdevAddArgumentsIscsiTransport = "iser"
+)
+
+// BlockdevAddArgumentsIscsiHeaderDigest is an enum of the QMP schema.
+type BlockdevAddArgumentsIscsiHeaderDigest string
+
+const (
+	BlockdevAddArgumentsIscsiHeaderDigestCrc32c     BlockdevAddArgumentsIscsiHeaderDigest = "crc32c"
+	BlockdevAddArgumentsIscsiHeaderDigestNone       BlockdevAddArgumentsIscsiHeaderDigest = "none"
+	BlockdevAddArgumentsIscsiHeaderDigestCrc32cNone BlockdevAddArgumentsIscsiHeaderDigest = "crc32c-none"
+	BlockdevAddArgumentsIscsiHeaderDigestNoneCrc32c BlockdevAddArgumentsIscsiHeaderDigest = "none-crc32c"
+)
+
+// BlockdevAddArgumentsLuks is an object of the QMP schema.
+type BlockdevAddArgumentsLuks struct {
+	File      BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	KeySecret *string                           `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsNbd is an object of the QMP schema.
+type BlockdevAddArgumentsNbd struct {
+	Server         ObjectAddArgumentsXVfioUserServerSocket `json:"server"`
+	Export         *string                                 `json:"export,omitempty"`
+	TlsCreds       *string                                 `json:"tls-creds,omitempty"`
+	TlsHostname    *string                                 `json:"tls-hostname,omitempty"`
+	XDirtyBitmap   *string                                 `json:"x-dirty-bitmap,omitempty"`
+	ReconnectDelay *uint32                                 `json:"reconnect-delay,omitempty"`
+	OpenTimeout    *uint32                                 `json:"open-timeout,omitempty"`
+}
+
+// BlockdevAddArgumentsNfs is an object of the QMP schema.
+type BlockdevAddArgumentsNfs struct {
+	Server        BlockdevAddArgumentsNfsServer `json:"server"`
+	Path          string                        `json:"path"`
+	User          *int64                        `json:"user,omitempty"`
+	Group         *int64                        `json:"group,omitempty"`
+	TcpSynCount   *int64                        `json:"tcp-syn-count,omitempty"`
+	ReadaheadSize *int64                        `json:"readahead-size,omitempty"`
+	PageCacheSize *int64                        `json:"page-cache-size,omitempty"`
+	Debug         *int64                        `json:"debug,omitempty"`
+}
+
+// BlockdevAddArgumentsNfsServer is an object of the QMP schema.
+type BlockdevAddArgumentsNfsServer struct {
+	Type BlockdevAddArgumentsNfsServerType `json:"type"`
+	Host string                            `json:"host"`
+}
+
+// BlockdevAddArgumentsNfsServerType is an enum of the QMP schema.
+type BlockdevAddArgumentsNfsServerType string
+
+const (
+	BlockdevAddArgumentsNfsServerTypeInet BlockdevAddArgumentsNfsServerType = "inet"
+)
+
+// BlockdevAddArgumentsNullAio is an object of the QMP schema.
+type BlockdevAddArgumentsNullAio struct {
+	Size       *int64  `json:"size,omitempty"`
+	LatencyNs  *uint64 `json:"latency-ns,omitempty"`
+	ReadZeroes *bool   `json:"read-zeroes,omitempty"`
+}
+
+// BlockdevAddArgumentsNvme is an object of the QMP schema.
+type BlockdevAddArgumentsNvme struct {
+	Device    string `json:"device"`
+	Namespace int64  `json:"namespace"`
+}
+
+// BlockdevAddArgumentsPreallocate is an object of the QMP schema.
+type BlockdevAddArgumentsPreallocate struct {
+	File          BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	PreallocAlign *int64                            `json:"prealloc-align,omitempty"`
+	PreallocSize  *int64                            `json:"prealloc-size,omitempty"`
+}
+
+// BlockdevAddArgumentsQcow is an object of the QMP schema.
+type BlockdevAddArgumentsQcow struct {
+	File    BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	Backing BlockdevAddArgumentsQcowBacking   `json:"backing,omitempty"`
+	Encrypt *BlockdevAddArgumentsQcowEncrypt  `json:"encrypt,omitempty"`
+}
+
+// BlockdevAddArgumentsQcowBacking is an alternate of the QMP schema, which is one of several types.
+type BlockdevAddArgumentsQcowBacking = json.RawMessage
+
+// BlockdevAddArgumentsQcowEncrypt is an object of the QMP schema.
+type BlockdevAddArgumentsQcowEncrypt struct {
+	Format    BlockdevAddArgumentsQcowEncryptFormat `json:"format"`
+	KeySecret *string                               `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsQcowEncryptFormat is an enum of the QMP schema.
+type BlockdevAddArgumentsQcowEncryptFormat string
+
+const (
+	BlockdevAddArgumentsQcowEncryptFormatAes BlockdevAddArgumentsQcowEncryptFormat = "aes"
+)
+
+// BlockdevAddArgumentsQcowEncryptAes is an object of the QMP schema.
+type BlockdevAddArgumentsQcowEncryptAes struct {
+	KeySecret *string `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsQcow2 is an object of the QMP schema.
+type BlockdevAddArgumentsQcow2 struct {
+	File                BlockdevAddArgumentsBlkdebugImage     `json:"file"`
+	Backing             BlockdevAddArgumentsQcowBacking       `json:"backing,omitempty"`
+	LazyRefcounts       *bool                                 `json:"lazy-refcounts,omitempty"`
+	PassDiscardRequest  *bool                                 `json:"pass-discard-request,omitempty"`
+	PassDiscardSnapshot *bool                                 `json:"pass-discard-snapshot,omitempty"`
+	PassDiscardOther    *bool                                 `json:"pass-discard-other,omitempty"`
+	DiscardNoUnref      *bool                                 `json:"discard-no-unref,omitempty"`
+	OverlapCheck        BlockdevAddArgumentsQcow2OverlapCheck `json:"overlap-check,omitempty"`
+	CacheSize           *int64                                `json:"cache-size,omitempty"`
+	L2CacheSize         *int64                                `json:"l2-cache-size,omitempty"`
+	L2CacheEntrySize    *int64                                `json:"l2-cache-entry-size,omitempty"`
+	RefcountCacheSize   *int64                                `json:"refcount-cache-size,omitempty"`
+	CacheCleanInterval  *int64                                `json:"cache-clean-interval,omitempty"`
+	Encrypt             *BlockdevAddArgumentsQcow2Encrypt     `json:"encrypt,omitempty"`
+	DataFile            BlockdevAddArgumentsBlkdebugImage     `json:"data-file,omitempty"`
+}
+
+// BlockdevAddArgumentsQcow2OverlapCheck is an alternate of the QMP schema, which is one of several types.
+type BlockdevAddArgumentsQcow2OverlapCheck = json.RawMessage
+
+// BlockdevAddArgumentsQcow2Encrypt is an object of the QMP schema.
+type BlockdevAddArgumentsQcow2Encrypt struct {
+	Format    QueryBlockResultInsertedImageFormatSpecificQcow2DataEncryptFormat `json:"format"`
+	KeySecret *string                                                           `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsQcow2EncryptLuks is an object of the QMP schema.
+type BlockdevAddArgumentsQcow2EncryptLuks struct {
+	KeySecret *string `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsQed is an object of the QMP schema.
+type BlockdevAddArgumentsQed struct {
+	File    BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	Backing BlockdevAddArgumentsQcowBacking   `json:"backing,omitempty"`
+}
+
+// BlockdevAddArgumentsQuorum is an object of the QMP schema.
+type BlockdevAddArgumentsQuorum struct {
+	Blkverify        *bool                                  `json:"blkverify,omitempty"`
+	Children         []BlockdevAddArgumentsBlkdebugImage    `json:"children"`
+	VoteThreshold    int64                                  `json:"vote-threshold"`
+	RewriteCorrupted *bool                                  `json:"rewrite-corrupted,omitempty"`
+	ReadPattern      *BlockdevAddArgumentsQuorumReadPattern `json:"read-pattern,omitempty"`
+}
+
+// BlockdevAddArgumentsQuorumReadPattern is an enum of the QMP schema.
+type BlockdevAddArgumentsQuorumReadPattern string
+
+const (
+	BlockdevAddArgumentsQuorumReadPatternQuorum BlockdevAddArgumentsQuorumReadPattern = "quorum"
+	BlockdevAddArgumentsQuorumReadPatternFifo   BlockdevAddArgumentsQuorumReadPattern = "fifo"
+)
+
+// BlockdevAddArgumentsRaw is an object of the QMP schema.
+type BlockdevAddArgumentsRaw struct {
+	File   BlockdevAddArgumentsBlkdebugImage `json:"file"`
+	Offset *int64                            `json:"offset,omitempty"`
+	Size   *int64                            `json:"size,omitempty"`
+}
+
+// BlockdevAddArgumentsRbd is an object of the QMP schema.
+type BlockdevAddArgumentsRbd struct {
+	Pool               string                                      `json:"pool"`
+	Namespace          *string                                     `json:"namespace,omitempty"`
+	Image              string                                      `json:"image"`
+	Conf               *string                                     `json:"conf,omitempty"`
+	Snapshot           *string                                     `json:"snapshot,omitempty"`
+	Encrypt            *BlockdevAddArgumentsRbdEncrypt             `json:"encrypt,omitempty"`
+	User               *string                                     `json:"user,omitempty"`
+	AuthClientRequired []BlockdevAddArgumentsRbdAuthClientRequired `json:"auth-client-required,omitempty"`
+	KeySecret          *string                                     `json:"key-secret,omitempty"`
+	Server             []BlockdevAddArgumentsRbdServer             `json:"server,omitempty"`
+}
+
+// BlockdevAddArgumentsRbdEncrypt is an object of the QMP schema.
+type BlockdevAddArgumentsRbdEncrypt struct {
+	Format    QueryBlockResultInsertedImageFormatSpecificRbdDataEncryptionFormat `json:"format"`
+	Parent    *BlockdevAddArgumentsRbdEncrypt                                    `json:"parent,omitempty"`
+	KeySecret *string                                                            `json:"key-secret,omitempty"`
+}
+
+// BlockdevAddArgumentsRbdEncryptLuks is an object of the QMP schema.
+type BlockdevAddArgumentsRbdEncryptLuks struct {
+	KeySecret string `json:"key-secret"`
+}
+
+// BlockdevAddArgumentsRbdEncryptLuks2 is an object of the QMP schema.
+type BlockdevAddArgumentsRbdEncryptLuks2 struct {
+	KeySecret string `json:"key-secret"`
+}
+
+// BlockdevAddArgumentsRbdEncryptLuksAny is an object of the QMP schema.
+type BlockdevAddArgumentsRbdEncryptLuksAny struct {
+	KeySecret string `json:"key-secret"`
+}
+
+// BlockdevAddArgumentsRbdAuthClientRequired is an enum of the QMP schema.
+type BlockdevAddArgumentsRbdAuthClientRequired string
+
+const (
+	BlockdevAddArgumentsRbdAuthClientRequiredCephx BlockdevAddArgumentsRbdAuthClientRequired = "cephx"
+	BlockdevAddArgumentsRbdAuthClientRequiredNone  BlockdevAddArgumentsRbdAuthClientRequired = "none"
+)
+
+// BlockdevAddArgumentsRbdServer is an object of the QMP schema.
+type BlockdevAddArgumentsRbdServer struct {
+	Host string `json:"host"`
+	Port string `json:"port"`
+}
+
+// BlockdevAddArgumentsReplication is an object of the QMP schema.
+type BlockdevAddArgumentsReplication struct {
+	File  BlockdevAddArgumentsBlkdebugImage   `json:"file"`
+	Mode  BlockdevAddArgumentsReplicationMode `json:"mode"`
+	TopID *string                             `json:"top-id,omitempty"`
+}
+
+// BlockdevAddArgumentsReplicationMode is an enum of the QMP schema.
+type BlockdevAddArgumentsReplicationMode string
+
+const (
+	BlockdevAddArgumentsReplicationModePrimary   BlockdevAddArgumentsReplicationMode = "primary"
+	BlockdevAddArgumentsReplicationModeSecondary BlockdevAddArgumentsReplicationMode = "secondary"
+)
+
+// BlockdevAddArgumentsSsh is an object of the QMP schema.
+type BlockdevAddArgumentsSsh struct {
+	Server       ObjectAddArgumentsXVfioUserServerSocketInet `json:"server"`
+	Path         string                                      `json:"path"`
+	User         *string                                     `json:"user,omitempty"`
+	HostKeyCheck *BlockdevAddArgumentsSshHostKeyCheck        `json:"host-key-check,omitempty"`
+}
+
+// BlockdevAddArgumentsSshHostKeyCheck is an object of the QMP schema.
+type BlockdevAddArgumentsSshHostKeyCheck struct {
+	Mode BlockdevAddArgumentsSshHostKeyCheckMode      `json:"mode"`
+	Type *BlockdevAddArgumentsSshHostKeyCheckHashType `json:"type,omitempty"`
+	Hash *string                                      `json:"hash,omitempty"`
+}
+
+// BlockdevAddArgumentsSshHostKeyCheckMode is an enum of the QMP schema.
+type BlockdevAddArgumentsSshHostKeyCheckMode string
+
+const (
+	BlockdevAddArgumentsSshHostKeyCheckModeNone       BlockdevAddArgumentsSshHostKeyCheckMode = "none"
+	BlockdevAddArgumentsSshHostKeyCheckModeHash       BlockdevAddArgumentsSshHostKeyCheckMode = "hash"
+	BlockdevAddArgumentsSshHostKeyCheckModeKnownHosts BlockdevAddArgumentsSshHostKeyCheckMode = "known_hosts"
+)
+
+// BlockdevAddArgumentsSshHostKeyCheckHash is an object of the QMP schema.
+type BlockdevAddArgumentsSshHostKeyCheckHash struct {
+	Type BlockdevAddArgumentsSshHostKeyCheckHashType `json:"type"`
+	Hash string                                      `json:"hash"`
+}
+
+// BlockdevAddArgumentsSshHostKeyCheckHashType is an enum of the QMP schema.
+type BlockdevAddArgumentsSshHostKeyCheckHashType string
+
+const (
+	BlockdevAddArgumentsSshHostKeyCheckHashTypeMd5    BlockdevAddArgumentsSshHostKeyCheckHashType = "md5"
+	BlockdevAddArgumentsSshHostKeyCheckHashTypeSha1   BlockdevAddArgumentsSshHostKeyCheckHashType = "sha1"
+	BlockdevAddArgumentsSshHostKeyCheckHashTypeSha256 BlockdevAddArgumentsSshHostKeyCheckHashType = "sha256"
+)
+
+// BlockdevAddArgumentsThrottle is an object of the QMP schema.
+type BlockdevAddArgumentsThrottle struct {
+	ThrottleGroup string                            `json:"throttle-group"`
+	File          BlockdevAddArgumentsBlkdebugImage `json:"file"`
+}
+
+// BlockdevAddArgumentsVvfat is an object of the QMP schema.
+type BlockdevAddArgumentsVvfat struct {
+	Dir     string  `json:"dir"`
+	FatType *int64  `json:"fat-type,omitempty"`
+	Floppy  *bool   `json:"floppy,omitempty"`
+	Label   *string `json:"label,omitempty"`
+	Rw      *bool   `json:"rw,omitempty"`
+}
+
+// BlockdevDelArguments is an object of the QMP schema.
+type BlockdevDelArguments struct {
+	NodeName string `json:"node-name"`
+}
+
+// BlockdevSnapshotSyncArguments is an object of the QMP schema.
+type BlockdevSnapshotSyncArguments struct {
+	Device           *string                            `json:"device,omitempty"`
+	NodeName         *string                            `json:"node-name,omitempty"`
+	SnapshotFile     string                             `json:"snapshot-file"`
+	SnapshotNodeName *string                            `json:"snapshot-node-name,omitempty"`
+	Format           *string                            `json:"format,omitempty"`
+	Mode             *BlockdevSnapshotSyncArgumentsMode `json:"mode,omitempty"`
+}
+
+// BlockdevSnapshotSyncArgumentsMode is an enum of the QMP schema.
+type BlockdevSnapshotSyncArgumentsMode string
+
+const (
+	BlockdevSnapshotSyncArgumentsModeExisting      BlockdevSnapshotSyncArgumentsMode = "existing"
+	BlockdevSnapshotSyncArgumentsModeAbsolutePaths BlockdevSnapshotSyncArgumentsMode = "absolute-paths"
+)
+
+// BlockCommitArguments is an object of the QMP schema.
+type BlockCommitArguments struct {
+	JobID    *string `json:"job-id,omitempty"`
+	Device   string  `json:"device"`
+	BaseNode *string `json:"base-node,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Base    *string `json:"base,omitempty"`
+	TopNode *string `json:"top-node,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Top                 *string                      `json:"top,omitempty"`
+	BackingFile         *string                      `json:"backing-file,omitempty"`
+	BackingMaskProtocol *bool                        `json:"backing-mask-protocol,omitempty"`
+	Speed               *int64                       `json:"speed,omitempty"`
+	OnError             *BlockCommitArgumentsOnError `json:"on-error,omitempty"`
+	FilterNodeName      *string                      `json:"filter-node-name,omitempty"`
+	AutoFinalize        *bool                        `json:"auto-finalize,omitempty"`
+	AutoDismiss         *bool                        `json:"auto-dismiss,omitempty"`
+}
+
+// BlockCommitArgumentsOnError is an enum of the QMP schema.
+type BlockCommitArgumentsOnError string
+
+const (
+	BlockCommitArgumentsOnErrorReport BlockCommitArgumentsOnError = "report"
+	BlockCommitArgumentsOnErrorIgnore BlockCommitArgumentsOnError = "ignore"
+	BlockCommitArgumentsOnErrorEnospc BlockCommitArgumentsOnError = "enospc"
+	BlockCommitArgumentsOnErrorStop   BlockCommitArgumentsOnError = "stop"
+	BlockCommitArgumentsOnErrorAuto   BlockCommitArgumentsOnError = "auto"
+)
+
+// BlockStreamArguments is an object of the QMP schema.
+type BlockStreamArguments struct {
+	JobID               *string                      `json:"job-id,omitempty"`
+	Device              string                       `json:"device"`
+	Base                *string                      `json:"base,omitempty"`
+	BaseNode            *string                      `json:"base-node,omitempty"`
+	BackingFile         *string                      `json:"backing-file,omitempty"`
+	BackingMaskProtocol *bool                        `json:"backing-mask-protocol,omitempty"`
+	Bottom              *string                      `json:"bottom,omitempty"`
+	Speed               *int64                       `json:"speed,omitempty"`
+	OnError             *BlockCommitArgumentsOnError `json:"on-error,omitempty"`
+	FilterNodeName      *string                      `json:"filter-node-name,omitempty"`
+	AutoFinalize        *bool                        `json:"auto-finalize,omitempty"`
+	AutoDismiss         *bool                        `json:"auto-dismiss,omitempty"`
+}
+
+// BlockdevBackupArguments is an object of the QMP schema.
+type BlockdevBackupArguments struct {
+	JobID          *string                            `json:"job-id,omitempty"`
+	Device         string                             `json:"device"`
+	Sync           BlockdevBackupArgumentsSync        `json:"sync"`
+	Speed          *int64                             `json:"speed,omitempty"`
+	Bitmap         *string                            `json:"bitmap,omitempty"`
+	BitmapMode     *BlockdevBackupArgumentsBitmapMode `json:"bitmap-mode,omitempty"`
+	Compress       *bool                              `json:"compress,omitempty"`
+	OnSourceError  *BlockCommitArgumentsOnError       `json:"on-source-error,omitempty"`
+	OnTargetError  *BlockCommitArgumentsOnError       `json:"on-target-error,omitempty"`
+	AutoFinalize   *bool                              `json:"auto-finalize,omitempty"`
+	AutoDismiss    *bool                              `json:"auto-dismiss,omitempty"`
+	FilterNodeName *string                            `json:"filter-node-name,omitempty"`
+	XPerf          *BlockdevBackupArgumentsXPerf      `json:"x-perf,omitempty"`
+	Target         string                             `json:"target"`
+}
+
+// BlockdevBackupArgumentsSync is an enum of the QMP schema.
+type BlockdevBackupArgumentsSync string
+
+const (
+	BlockdevBackupArgumentsSyncTop         BlockdevBackupArgumentsSync = "top"
+	BlockdevBackupArgumentsSyncFull        BlockdevBackupArgumentsSync = "full"
+	BlockdevBackupArgumentsSyncNone        BlockdevBackupArgumentsSync = "none"
+	BlockdevBackupArgumentsSyncIncremental BlockdevBackupArgumentsSync = "incremental"
+	BlockdevBackupArgumentsSyncBitmap      BlockdevBackupArgumentsSync = "bitmap"
+)
+
+// BlockdevBackupArgumentsBitmapMode is an enum of the QMP schema.
+type BlockdevBackupArgumentsBitmapMode string
+
+const (
+	BlockdevBackupArgumentsBitmapModeOnSuccess BlockdevBackupArgumentsBitmapMode = "on-success"
+	BlockdevBackupArgumentsBitmapModeNever     BlockdevBackupArgumentsBitmapMode = "never"
+	BlockdevBackupArgumentsBitmapModeAlways    BlockdevBackupArgumentsBitmapMode = "always"
+)
+
+// BlockdevBackupArgumentsXPerf is an object of the QMP schema.
+type BlockdevBackupArgumentsXPerf struct {
+	UseCopyRange *bool  `json:"use-copy-range,omitempty"`
+	MaxWorkers   *int64 `json:"max-workers,omitempty"`
+	MaxChunk     *int64 `json:"max-chunk,omitempty"`
+}
+
+// BlockdevMirrorArguments is an object of the QMP schema.
+type BlockdevMirrorArguments struct {
+	JobID          *string                          `json:"job-id,omitempty"`
+	Device         string                           `json:"device"`
+	Target         string                           `json:"target"`
+	Replaces       *string                          `json:"replaces,omitempty"`
+	Sync           BlockdevBackupArgumentsSync      `json:"sync"`
+	Speed          *int64                           `json:"speed,omitempty"`
+	Granularity    *uint32                          `json:"granularity,omitempty"`
+	BufSize        *int64                           `json:"buf-size,omitempty"`
+	OnSourceError  *BlockCommitArgumentsOnError     `json:"on-source-error,omitempty"`
+	OnTargetError  *BlockCommitArgumentsOnError     `json:"on-target-error,omitempty"`
+	FilterNodeName *string                          `json:"filter-node-name,omitempty"`
+	CopyMode       *BlockdevMirrorArgumentsCopyMode `json:"copy-mode,omitempty"`
+	AutoFinalize   *bool                            `json:"auto-finalize,omitempty"`
+	AutoDismiss    *bool                            `json:"auto-dismiss,omitempty"`
+}
+
+// BlockdevMirrorArgumentsCopyMode is an enum of the QMP schema.
+type BlockdevMirrorArgumentsCopyMode string
+
+const (
+	BlockdevMirrorArgumentsCopyModeBackground    BlockdevMirrorArgumentsCopyMode = "background"
+	BlockdevMirrorArgumentsCopyModeWriteBlocking BlockdevMirrorArgumentsCopyMode = "write-blocking"
+)
+
+// BlockJobSetSpeedArguments is an object of the QMP schema.
+type BlockJobSetSpeedArguments struct {
+	Device string `json:"device"`
+	Speed  int64  `json:"speed"`
+}
+
+// BlockSetIOThrottleArguments is an object of the QMP schema.
+type BlockSetIOThrottleArguments struct {
+	// Deprecated: deprecated in the QMP schema.
+	Device          *string `json:"device,omitempty"`
+	ID              *string `json:"id,omitempty"`
+	Bps             int64   `json:"bps"`
+	BpsRd           int64   `json:"bps_rd"`
+	BpsWr           int64   `json:"bps_wr"`
+	Iops            int64   `json:"iops"`
+	IopsRd          int64   `json:"iops_rd"`
+	IopsWr          int64   `json:"iops_wr"`
+	BpsMax          *int64  `json:"bps_max,omitempty"`
+	BpsRdMax        *int64  `json:"bps_rd_max,omitempty"`
+	BpsWrMax        *int64  `json:"bps_wr_max,omitempty"`
+	IopsMax         *int64  `json:"iops_max,omitempty"`
+	IopsRdMax       *int64  `json:"iops_rd_max,omitempty"`
+	IopsWrMax       *int64  `json:"iops_wr_max,omitempty"`
+	BpsMaxLength    *int64  `json:"bps_max_length,omitempty"`
+	BpsRdMaxLength  *int64  `json:"bps_rd_max_length,omitempty"`
+	BpsWrMaxLength  *int64  `json:"bps_wr_max_length,omitempty"`
+	IopsMaxLength   *int64  `json:"iops_max_length,omitempty"`
+	IopsRdMaxLength *int64  `json:"iops_rd_max_length,omitempty"`
+	IopsWrMaxLength *int64  `json:"iops_wr_max_length,omitempty"`
+	IopsSize        *int64  `json:"iops_size,omitempty"`
+	Group           *string `json:"group,omitempty"`
+}
+
+// BlockDirtyBitmapAddArguments is an object of the QMP schema.
+type BlockDirtyBitmapAddArguments struct {
+	Node        string  `json:"node"`
+	Name        string  `json:"name"`
+	Granularity *uint32 `json:"granularity,omitempty"`
+	Persistent  *bool   `json:"persistent,omitempty"`
+	Disabled    *bool   `json:"disabled,omitempty"`
+}
+
+// BlockDirtyBitmapMergeArguments is an object of the QMP schema.
+type BlockDirtyBitmapMergeArguments struct {
+	Node    string                                  `json:"node"`
+	Target  string                                  `json:"target"`
+	Bitmaps []BlockDirtyBitmapMergeArgumentsBitmaps `json:"bitmaps"`
+}
+
+// BlockDirtyBitmapMergeArgumentsBitmaps is an alternate of the QMP schema, which is one of several types.
+type BlockDirtyBitmapMergeArgumentsBitmaps = json.RawMessage
+
+// TransactionArguments is an object of the QMP schema.
+type TransactionArguments struct {
+	Actions    []TransactionArgumentsActions   `json:"actions"`
+	Properties *TransactionArgumentsProperties `json:"properties,omitempty"`
+}
+
+// TransactionArgumentsActions is an object of the QMP schema.
+type TransactionArgumentsActions struct {
+	Type TransactionArgumentsActionsType `json:"type"`
+	Data *struct{}                       `json:"data,omitempty"`
+}
+
+// TransactionArgumentsActionsType is an enum of the QMP schema.
+type TransactionArgumentsActionsType string
+
+const (
+	TransactionArgumentsActionsTypeAbort                        TransactionArgumentsActionsType = "abort"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapAdd          TransactionArgumentsActionsType = "block-dirty-bitmap-add"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapRemove       TransactionArgumentsActionsType = "block-dirty-bitmap-remove"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapClear        TransactionArgumentsActionsType = "block-dirty-bitmap-clear"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapEnable       TransactionArgumentsActionsType = "block-dirty-bitmap-enable"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapDisable      TransactionArgumentsActionsType = "block-dirty-bitmap-disable"
+	TransactionArgumentsActionsTypeBlockDirtyBitmapMerge        TransactionArgumentsActionsType = "block-dirty-bitmap-merge"
+	TransactionArgumentsActionsTypeBlockdevBackup               TransactionArgumentsActionsType = "blockdev-backup"
+	TransactionArgumentsActionsTypeBlockdevSnapshot             TransactionArgumentsActionsType = "blockdev-snapshot"
+	TransactionArgumentsActionsTypeBlockdevSnapshotInternalSync TransactionArgumentsActionsType = "blockdev-snapshot-internal-sync"
+	TransactionArgumentsActionsTypeBlockdevSnapshotSync         TransactionArgumentsActionsType = "blockdev-snapshot-sync"
+	TransactionArgumentsActionsTypeDriveBackup                  TransactionArgumentsActionsType = "drive-backup"
+)
+
+// TransactionArgumentsActionsAbort is an object of the QMP schema.
+type TransactionArgumentsActionsAbort struct {
+	Data struct{} `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockDirtyBitmapAdd is an object of the QMP schema.
+type TransactionArgumentsActionsBlockDirtyBitmapAdd struct {
+	Data BlockDirtyBitmapAddArguments `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockDirtyBitmapRemove is an object of the QMP schema.
+type TransactionArgumentsActionsBlockDirtyBitmapRemove struct {
+	Data BlockdevAddArgumentsCopyBeforeWriteBitmap `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockDirtyBitmapMerge is an object of the QMP schema.
+type TransactionArgumentsActionsBlockDirtyBitmapMerge struct {
+	Data BlockDirtyBitmapMergeArguments `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockdevBackup is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevBackup struct {
+	Data BlockdevBackupArguments `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockdevSnapshot is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevSnapshot struct {
+	Data TransactionArgumentsActionsBlockdevSnapshotData `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockdevSnapshotData is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevSnapshotData struct {
+	Node    string `json:"node"`
+	Overlay string `json:"overlay"`
+}
+
+// TransactionArgumentsActionsBlockdevSnapshotInternalSync is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevSnapshotInternalSync struct {
+	Data TransactionArgumentsActionsBlockdevSnapshotInternalSyncData `json:"data"`
+}
+
+// TransactionArgumentsActionsBlockdevSnapshotInternalSyncData is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevSnapshotInternalSyncData struct {
+	Device string `json:"device"`
+	Name   string `json:"name"`
+}
+
+// TransactionArgumentsActionsBlockdevSnapshotSync is an object of the QMP schema.
+type TransactionArgumentsActionsBlockdevSnapshotSync struct {
+	Data BlockdevSnapshotSyncArguments `json:"data"`
+}
+
+// TransactionArgumentsActionsDriveBackup is an object of the QMP schema.
+type TransactionArgumentsActionsDriveBackup struct {
+	Data TransactionArgumentsActionsDriveBackupData `json:"data"`
+}
+
+// TransactionArgumentsActionsDriveBackupData is an object of the QMP schema.
+type TransactionArgumentsActionsDriveBackupData struct {
+	JobID          *string                            `json:"job-id,omitempty"`
+	Device         string                             `json:"device"`
+	Sync           BlockdevBackupArgumentsSync        `json:"sync"`
+	Speed          *int64                             `json:"speed,omitempty"`
+	Bitmap         *string                            `json:"bitmap,omitempty"`
+	BitmapMode     *BlockdevBackupArgumentsBitmapMode `json:"bitmap-mode,omitempty"`
+	Compress       *bool                              `json:"compress,omitempty"`
+	OnSourceError  *BlockCommitArgumentsOnError       `json:"on-source-error,omitempty"`
+	OnTargetError  *BlockCommitArgumentsOnError       `json:"on-target-error,omitempty"`
+	AutoFinalize   *bool                              `json:"auto-finalize,omitempty"`
+	AutoDismiss    *bool                              `json:"auto-dismiss,omitempty"`
+	FilterNodeName *string                            `json:"filter-node-name,omitempty"`
+	XPerf          *BlockdevBackupArgumentsXPerf      `json:"x-perf,omitempty"`
+	Target         string                             `json:"target"`
+	Format         *string                            `json:"format,omitempty"`
+	Mode           *BlockdevSnapshotSyncArgumentsMode `json:"mode,omitempty"`
+}
+
+// TransactionArgumentsProperties is an object of the QMP schema.
+type TransactionArgumentsProperties struct {
+	CompletionMode *TransactionArgumentsPropertiesCompletionMode `json:"completion-mode,omitempty"`
+}
+
+// TransactionArgumentsPropertiesCompletionMode is an enum of the QMP schema.
+type TransactionArgumentsPropertiesCompletionMode string
+
+const (
+	TransactionArgumentsPropertiesCompletionModeIndividual TransactionArgumentsPropertiesCompletionMode = "individual"
+	TransactionArgumentsPropertiesCompletionModeGrouped    TransactionArgumentsPropertiesCompletionMode = "grouped"
+)
+
+// QueryJobsResult is an object of the QMP schema.
+type QueryJobsResult struct {
+	ID              string                `json:"id"`
+	Type            QueryJobsResultType   `json:"type"`
+	Status          QueryJobsResultStatus `json:"status"`
+	CurrentProgress int64                 `json:"current-progress"`
+	TotalProgress   int64                 `json:"total-progress"`
+	Error           *string               `json:"error,omitempty"`
+}
+
+// QueryJobsResultType is an enum of the QMP schema.
+type QueryJobsResultType string
+
+const (
+	QueryJobsResultTypeCommit         QueryJobsResultType = "commit"
+	QueryJobsResultTypeStream         QueryJobsResultType = "stream"
+	QueryJobsResultTypeMirror         QueryJobsResultType = "mirror"
+	QueryJobsResultTypeBackup         QueryJobsResultType = "backup"
+	QueryJobsResultTypeCreate         QueryJobsResultType = "create"
+	QueryJobsResultTypeAmend          QueryJobsResultType = "amend"
+	QueryJobsResultTypeSnapshotLoad   QueryJobsResultType = "snapshot-load"
+	QueryJobsResultTypeSnapshotSave   QueryJobsResultType = "snapshot-save"
+	QueryJobsResultTypeSnapshotDelete QueryJobsResultType = "snapshot-delete"
+)
+
+// QueryJobsResultStatus is an enum of the QMP schema.
+type QueryJobsResultStatus string
+
+const (
+	QueryJobsResultStatusUndefined QueryJobsResultStatus = "undefined"
+	QueryJobsResultStatusCreated   QueryJobsResultStatus = "created"
+	QueryJobsResultStatusRunning   QueryJobsResultStatus = "running"
+	QueryJobsResultStatusPaused    QueryJobsResultStatus = "paused"
+	QueryJobsResultStatusReady     QueryJobsResultStatus = "ready"
+	QueryJobsResultStatusStandby   QueryJobsResultStatus = "standby"
+	QueryJobsResultStatusWaiting   QueryJobsResultStatus = "waiting"
+	QueryJobsResultStatusPending   QueryJobsResultStatus = "pending"
+	QueryJobsResultStatusAborting  QueryJobsResultStatus = "aborting"
+	QueryJobsResultStatusConcluded QueryJobsResultStatus = "concluded"
+	QueryJobsResultStatusNull      QueryJobsResultStatus = "null"
+)
+
+// JobPauseArguments is an object of the QMP schema.
+type JobPauseArguments struct {
+	ID string `json:"id"`
+}
+
+// JobResumeArguments is an object of the QMP schema.
+type JobResumeArguments struct {
+	ID string `json:"id"`
+}
+
+// JobCancelArguments is an object of the QMP schema.
+type JobCancelArguments struct {
+	ID string `json:"id"`
+}
+
+// JobCompleteArguments is an object of the QMP schema.
+type JobCompleteArguments struct {
+	ID string `json:"id"`
+}
+
+// JobDismissArguments is an object of the QMP schema.
+type JobDismissArguments struct {
+	ID string `json:"id"`
+}
+
+// JobFinalizeArguments is an object of the QMP schema.
+type JobFinalizeArguments struct {
+	ID string `json:"id"`
+}
+
+// QueryMigrateResult is an object of the QMP schema.
+type QueryMigrateResult struct {
+	Status *QueryMigrateResultStatus `json:"status,omitempty"`
+	RAM    *QueryMigrateResultRAM    `json:"ram,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Disk                           *QueryMigrateResultRAM                    `json:"disk,omitempty"`
+	Vfio                           *QueryMigrateResultVfio                   `json:"vfio,omitempty"`
+	XbzrleCache                    *QueryMigrateResultXbzrleCache            `json:"xbzrle-cache,omitempty"`
+	TotalTime                      *int64                                    `json:"total-time,omitempty"`
+	ExpectedDowntime               *int64                                    `json:"expected-downtime,omitempty"`
+	Downtime                       *int64                                    `json:"downtime,omitempty"`
+	SetupTime                      *int64                                    `json:"setup-time,omitempty"`
+	CPUThrottlePercentage          *int64                                    `json:"cpu-throttle-percentage,omitempty"`
+	ErrorDesc                      *string                                   `json:"error-desc,omitempty"`
+	BlockedReasons                 []string                                  `json:"blocked-reasons,omitempty"`
+	PostcopyBlocktime              *uint32                                   `json:"postcopy-blocktime,omitempty"`
+	PostcopyVcpuBlocktime          []uint32                                  `json:"postcopy-vcpu-blocktime,omitempty"`
+	Compression                    *QueryMigrateResultCompression            `json:"compression,omitempty"`
+	SocketAddress                  []ObjectAddArgumentsXVfioUserServerSocket `json:"socket-address,omitempty"`
+	DirtyLimitThrottleTimePerRound *uint64                                   `json:"dirty-limit-throttle-time-per-round,omitempty"`
+	DirtyLimitRingFullTime         *uint64                                   `json:"dirty-limit-ring-full-time,omitempty"`
+}
+
+// QueryMigrateResultStatus is an enum of the QMP schema.
+type QueryMigrateResultStatus string
+
+const (
+	QueryMigrateResultStatusNone            QueryMigrateResultStatus = "none"
+	QueryMigrateResultStatusSetup           QueryMigrateResultStatus = "setup"
+	QueryMigrateResultStatusCancelling      QueryMigrateResultStatus = "cancelling"
+	QueryMigrateResultStatusCancelled       QueryMigrateResultStatus = "cancelled"
+	QueryMigrateResultStatusActive          QueryMigrateResultStatus = "active"
+	QueryMigrateResultStatusPostcopyActive  QueryMigrateResultStatus = "postcopy-active"
+	QueryMigrateResultStatusPostcopyPaused  QueryMigrateResultStatus = "postcopy-paused"
+	QueryMigrateResultStatusPostcopyRecover QueryMigrateResultStatus = "postcopy-recover"
+	QueryMigrateResultStatusCompleted       QueryMigrateResultStatus = "completed"
+	QueryMigrateResultStatusFailed          QueryMigrateResultStatus = "failed"
+	QueryMigrateResultStatusColo            QueryMigrateResultStatus = "colo"
+	QueryMigrateResultStatusPreSwitchover   QueryMigrateResultStatus = "pre-switchover"
+	QueryMigrateResultStatusDevice          QueryMigrateResultStatus = "device"
+	QueryMigrateResultStatusWaitUnplug      QueryMigrateResultStatus = "wait-unplug"
+)
+
+// QueryMigrateResultRAM is an object of the QMP schema.
+type QueryMigrateResultRAM struct {
+	Transferred int64 `json:"transferred"`
+	Remaining   int64 `json:"remaining"`
+	Total       int64 `json:"total"`
+	Duplicate   int64 `json:"duplicate"`
+	// Deprecated: deprecated in the QMP schema.
+	Skipped                 int64   `json:"skipped"`
+	Normal                  int64   `json:"normal"`
+	NormalBytes             int64   `json:"normal-bytes"`
+	DirtyPagesRate          int64   `json:"dirty-pages-rate"`
+	Mbps                    float64 `json:"mbps"`
+	DirtySyncCount          int64   `json:"dirty-sync-count"`
+	PostcopyRequests        int64   `json:"postcopy-requests"`
+	PageSize                int64   `json:"page-size"`
+	MultifdBytes            uint64  `json:"multifd-bytes"`
+	PagesPerSecond          uint64  `json:"pages-per-second"`
+	PrecopyBytes            uint64  `json:"precopy-bytes"`
+	DowntimeBytes           uint64  `json:"downtime-bytes"`
+	PostcopyBytes           uint64  `json:"postcopy-bytes"`
+	DirtySyncMissedZeroCopy uint64  `json:"dirty-sync-missed-zero-copy"`
+}
+
+// QueryMigrateResultVfio is an object of the QMP schema.
+type QueryMigrateResultVfio struct {
+	Transferred int64 `json:"transferred"`
+}
+
+// QueryMigrateResultXbzrleCache is an object of the QMP schema.
+type QueryMigrateResultXbzrleCache struct {
+	CacheSize     uint64  `json:"cache-size"`
+	Bytes         int64   `json:"bytes"`
+	Pages         int64   `json:"pages"`
+	CacheMiss     int64   `json:"cache-miss"`
+	CacheMissRate float64 `json:"cache-miss-rate"`
+	EncodingRate  float64 `json:"encoding-rate"`
+	Overflow      int64   `json:"overflow"`
+}
+
+// QueryMigrateResultCompression is an object of the QMP schema.
+type QueryMigrateResultCompression struct {
+	Pages           int64   `json:"pages"`
+	Busy            int64   `json:"busy"`
+	BusyRate        float64 `json:"busy-rate"`
+	CompressedSize  int64   `json:"compressed-size"`
+	CompressionRate float64 `json:"compression-rate"`
+}
+
+// MigrateSetCapabilitiesArguments is an object of the QMP schema.
+type MigrateSetCapabilitiesArguments struct {
+	Capabilities []MigrateSetCapabilitiesArgumentsCapabilities `json:"capabilities"`
+}
+
+// MigrateSetCapabilitiesArgumentsCapabilities is an object of the QMP schema.
+type MigrateSetCapabilitiesArgumentsCapabilities struct {
+	Capability MigrateSetCapabilitiesArgumentsCapabilitiesCapability `json:"capability"`
+	State      bool                                                  `json:"state"`
+}
+
+// MigrateSetCapabilitiesArgumentsCapabilitiesCapability is an enum of the QMP schema.
+type MigrateSetCapabilitiesArgumentsCapabilitiesCapability string
+
+const (
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityXbzrle                MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "xbzrle"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityRdmaPinAll            MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "rdma-pin-all"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityAutoConverge          MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "auto-converge"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityZeroBlocks            MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "zero-blocks"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityCompress              MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "compress"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityEvents                MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "events"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityPostcopyRAM           MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "postcopy-ram"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityXColo                 MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "x-colo"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityReleaseRAM            MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "release-ram"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityBlock                 MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "block"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityReturnPath            MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "return-path"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityPauseBeforeSwitchover MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "pause-before-switchover"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityMultifd               MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "multifd"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityDirtyBitmaps          MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "dirty-bitmaps"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityPostcopyBlocktime     MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "postcopy-blocktime"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityLateBlockActivate     MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "late-block-activate"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityXIgnoreShared         MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "x-ignore-shared"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityValidateUUID          MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "validate-uuid"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityBackgroundSnapshot    MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "background-snapshot"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityZeroCopySend          MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "zero-copy-send"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityPostcopyPreempt       MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "postcopy-preempt"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilitySwitchoverAck         MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "switchover-ack"
+	MigrateSetCapabilitiesArgumentsCapabilitiesCapabilityDirtyLimit            MigrateSetCapabilitiesArgumentsCapabilitiesCapability = "dirty-limit"
+)
+
+// MigrateSetParametersArguments is an object of the QMP schema.
+type MigrateSetParametersArguments struct {
+	AnnounceInitial          *uint64                               `json:"announce-initial,omitempty"`
+	AnnounceMax              *uint64                               `json:"announce-max,omitempty"`
+	AnnounceRounds           *uint64                               `json:"announce-rounds,omitempty"`
+	AnnounceStep             *uint64                               `json:"announce-step,omitempty"`
+	CompressLevel            *uint8                                `json:"compress-level,omitempty"`
+	CompressThreads          *uint8                                `json:"compress-threads,omitempty"`
+	CompressWaitThread       *bool                                 `json:"compress-wait-thread,omitempty"`
+	DecompressThreads        *uint8                                `json:"decompress-threads,omitempty"`
+	ThrottleTriggerThreshold *uint8                                `json:"throttle-trigger-threshold,omitempty"`
+	CPUThrottleInitial       *uint8                                `json:"cpu-throttle-initial,omitempty"`
+	CPUThrottleIncrement     *uint8                                `json:"cpu-throttle-increment,omitempty"`
+	CPUThrottleTailslow      *bool                                 `json:"cpu-throttle-tailslow,omitempty"`
+	TlsCreds                 MigrateSetParametersArgumentsTlsCreds `json:"tls-creds,omitempty"`
+	TlsHostname              MigrateSetParametersArgumentsTlsCreds `json:"tls-hostname,omitempty"`
+	TlsAuthz                 MigrateSetParametersArgumentsTlsCreds `json:"tls-authz,omitempty"`
+	MaxBandwidth             *uint64                               `json:"max-bandwidth,omitempty"`
+	AvailSwitchoverBandwidth *uint64                               `json:"avail-switchover-bandwidth,omitempty"`
+	DowntimeLimit            *uint64                               `json:"downtime-limit,omitempty"`
+	XCheckpointDelay         *uint32                               `json:"x-checkpoint-delay,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	BlockIncremental      *bool                                             `json:"block-incremental,omitempty"`
+	MultifdChannels       *uint8                                            `json:"multifd-channels,omitempty"`
+	XbzrleCacheSize       *uint64                                           `json:"xbzrle-cache-size,omitempty"`
+	MaxPostcopyBandwidth  *uint64                                           `json:"max-postcopy-bandwidth,omitempty"`
+	MaxCPUThrottle        *uint8                                            `json:"max-cpu-throttle,omitempty"`
+	MultifdCompression    *MigrateSetParametersArgumentsMultifdCompression  `json:"multifd-compression,omitempty"`
+	MultifdZlibLevel      *uint8                                            `json:"multifd-zlib-level,omitempty"`
+	MultifdZstdLevel      *uint8                                            `json:"multifd-zstd-level,omitempty"`
+	BlockBitmapMapping    []MigrateSetParametersArgumentsBlockBitmapMapping `json:"block-bitmap-mapping,omitempty"`
+	XVcpuDirtyLimitPeriod *uint64                                           `json:"x-vcpu-dirty-limit-period,omitempty"`
+	VcpuDirtyLimit        *uint64                                           `json:"vcpu-dirty-limit,omitempty"`
+	Mode                  *MigrateSetParametersArgumentsMode                `json:"mode,omitempty"`
+}
+
+// MigrateSetParametersArgumentsTlsCreds is an alternate of the QMP schema, which is one of several types.
+type MigrateSetParametersArgumentsTlsCreds = json.RawMessage
+
+// MigrateSetParametersArgumentsMultifdCompression is an enum of the QMP schema.
+type MigrateSetParametersArgumentsMultifdCompression string
+
+const (
+	MigrateSetParametersArgumentsMultifdCompressionNone MigrateSetParametersArgumentsMultifdCompression = "none"
+	MigrateSetParametersArgumentsMultifdCompressionZlib MigrateSetParametersArgumentsMultifdCompression = "zlib"
+	MigrateSetParametersArgumentsMultifdCompressionZstd MigrateSetParametersArgumentsMultifdCompression = "zstd"
+)
+
+// MigrateSetParametersArgumentsBlockBitmapMapping is an object of the QMP schema.
+type MigrateSetParametersArgumentsBlockBitmapMapping struct {
+	NodeName string                                                   `json:"node-name"`
+	Alias    string                                                   `json:"alias"`
+	Bitmaps  []MigrateSetParametersArgumentsBlockBitmapMappingBitmaps `json:"bitmaps"`
+}
+
+// MigrateSetParametersArgumentsBlockBitmapMappingBitmaps is an object of the QMP schema.
+type MigrateSetParametersArgumentsBlockBitmapMappingBitmaps struct {
+	Name      string                                                           `json:"name"`
+	Alias     string                                                           `json:"alias"`
+	Transform *MigrateSetParametersArgumentsBlockBitmapMappingBitmapsTransform `json:"transform,omitempty"`
+}
+
+// MigrateSetParametersArgumentsBlockBitmapMappingBitmapsTransform is an object of the QMP schema.
+type MigrateSetParametersArgumentsBlockBitmapMappingBitmapsTransform struct {
+	Persistent *bool `json:"persistent,omitempty"`
+}
+
+// MigrateSetParametersArgumentsMode is an enum of the QMP schema.
+type MigrateSetParametersArgumentsMode string
+
+const (
+	MigrateSetParametersArgumentsModeNormal    MigrateSetParametersArgumentsMode = "normal"
+	MigrateSetParametersArgumentsModeCprReboot MigrateSetParametersArgumentsMode = "cpr-reboot"
+)
+
+// MigrateArguments is an object of the QMP schema.
+type MigrateArguments struct {
+	Uri      *string                    `json:"uri,omitempty"`
+	Channels []MigrateArgumentsChannels `json:"channels,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Blk *bool `json:"blk,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Inc    *bool `json:"inc,omitempty"`
+	Detach *bool `json:"detach,omitempty"`
+	Resume *bool `json:"resume,omitempty"`
+}
+
+// MigrateArgumentsChannels is an object of the QMP schema.
+type MigrateArgumentsChannels struct {
+	ChannelType MigrateArgumentsChannelsChannelType `json:"channel-type"`
+	Addr        MigrateArgumentsChannelsAddr        `json:"addr"`
+}
+
+// MigrateArgumentsChannelsChannelType is an enum of the QMP schema.
+type MigrateArgumentsChannelsChannelType string
+
+const (
+	MigrateArgumentsChannelsChannelTypeMain MigrateArgumentsChannelsChannelType = "main"
+)
+
+// MigrateArgumentsChannelsAddr is an object of the QMP schema.
+type MigrateArgumentsChannelsAddr struct {
+	Transport MigrateArgumentsChannelsAddrTransport        `json:"transport"`
+	Type      *ObjectAddArgumentsXVfioUserServerSocketType `json:"type,omitempty"`
+	Args      []string                                     `json:"args,omitempty"`
+	Host      *string                                      `json:"host,omitempty"`
+	Port      *string                                      `json:"port,omitempty"`
+	Numeric   *bool                                        `json:"numeric,omitempty"`
+	To        *uint16                                      `json:"to,omitempty"`
+	Ipv4      *bool                                        `json:"ipv4,omitempty"`
+	Ipv6      *bool                                        `json:"ipv6,omitempty"`
+	KeepAlive *bool                                        `json:"keep-alive,omitempty"`
+	Mptcp     *bool                                        `json:"mptcp,omitempty"`
+	Filename  *string                                      `json:"filename,omitempty"`
+	Offset    *uint64                                      `json:"offset,omitempty"`
+}
+
+// MigrateArgumentsChannelsAddrTransport is an enum of the QMP schema.
+type MigrateArgumentsChannelsAddrTransport string
+
+const (
+	MigrateArgumentsChannelsAddrTransportSocket MigrateArgumentsChannelsAddrTransport = "socket"
+	MigrateArgumentsChannelsAddrTransportExec   MigrateArgumentsChannelsAddrTransport = "exec"
+	MigrateArgumentsChannelsAddrTransportRdma   MigrateArgumentsChannelsAddrTransport = "rdma"
+	MigrateArgumentsChannelsAddrTransportFile   MigrateArgumentsChannelsAddrTransport = "file"
+)
+
+// MigrateArgumentsChannelsAddrExec is an object of the QMP schema.
+type MigrateArgumentsChannelsAddrExec struct {
+	Args []string `json:"args"`
+}
+
+// MigrateArgumentsChannelsAddrFile is an object of the QMP schema.
+type MigrateArgumentsChannelsAddrFile struct {
+	Filename string `json:"filename"`
+	Offset   uint64 `json:"offset"`
+}
+
+// MigrateIncomingArguments is an object of the QMP schema.
+type MigrateIncomingArguments struct {
+	Uri      *string                    `json:"uri,omitempty"`
+	Channels []MigrateArgumentsChannels `json:"channels,omitempty"`
+}
+
+// MigrateContinueArguments is an object of the QMP schema.
+type MigrateContinueArguments struct {
+	State QueryMigrateResultStatus `json:"state"`
+}
+
+// DumpGuestMemoryArguments is an object of the QMP schema.
+type DumpGuestMemoryArguments struct {
+	Paging   bool                            `json:"paging"`
+	Protocol string                          `json:"protocol"`
+	Detach   *bool                           `json:"detach,omitempty"`
+	Begin    *int64                          `json:"begin,omitempty"`
+	Length   *int64                          `json:"length,omitempty"`
+	Format   *DumpGuestMemoryArgumentsFormat `json:"format,omitempty"`
+}
+
+// DumpGuestMemoryArgumentsFormat is an enum of the QMP schema.
+type DumpGuestMemoryArgumentsFormat string
+
+const (
+	DumpGuestMemoryArgumentsFormatElf            DumpGuestMemoryArgumentsFormat = "elf"
+	DumpGuestMemoryArgumentsFormatKdumpZlib      DumpGuestMemoryArgumentsFormat = "kdump-zlib"
+	DumpGuestMemoryArgumentsFormatKdumpLzo       DumpGuestMemoryArgumentsFormat = "kdump-lzo"
+	DumpGuestMemoryArgumentsFormatKdumpSnappy    DumpGuestMemoryArgumentsFormat = "kdump-snappy"
+	DumpGuestMemoryArgumentsFormatKdumpRawZlib   DumpGuestMemoryArgumentsFormat = "kdump-raw-zlib"
+	DumpGuestMemoryArgumentsFormatKdumpRawLzo    DumpGuestMemoryArgumentsFormat = "kdump-raw-lzo"
+	DumpGuestMemoryArgumentsFormatKdumpRawSnappy DumpGuestMemoryArgumentsFormat = "kdump-raw-snappy"
+	DumpGuestMemoryArgumentsFormatWinDmp         DumpGuestMemoryArgumentsFormat = "win-dmp"
+)
+
+// QueryDumpResult is an object of the QMP schema.
+type QueryDumpResult struct {
+	Status    QueryDumpResultStatus `json:"status"`
+	Completed int64                 `json:"completed"`
+	Total     int64                 `json:"total"`
+}
+
+// QueryDumpResultStatus is an enum of the QMP schema.
+type QueryDumpResultStatus string
+
+const (
+	QueryDumpResultStatusNone      QueryDumpResultStatus = "none"
+	QueryDumpResultStatusActive    QueryDumpResultStatus = "active"
+	QueryDumpResultStatusCompleted QueryDumpResultStatus = "completed"
+	QueryDumpResultStatusFailed    QueryDumpResultStatus = "failed"
+)
+
+// QueryDumpGuestMemoryCapabilityResult is an object of the QMP schema.
+type QueryDumpGuestMemoryCapabilityResult struct {
+	Formats []DumpGuestMemoryArgumentsFormat `json:"formats"`
+}
+
+// SendKeyArguments is an object of the QMP schema.
+type SendKeyArguments struct {
+	Keys     []SendKeyArgumentsKeys `json:"keys"`
+	HoldTime *int64                 `json:"hold-time,omitempty"`
+}
+
+// SendKeyArgumentsKeys is an object of the QMP schema.
+type SendKeyArgumentsKeys struct {
+	Type SendKeyArgumentsKeysType `json:"type"`
+	Data *int64                   `json:"data,omitempty"`
+}
+
+// SendKeyArgumentsKeysType is an enum of the QMP schema.
+type SendKeyArgumentsKeysType string
+
+const (
+	SendKeyArgumentsKeysTypeNumber SendKeyArgumentsKeysType = "number"
+	SendKeyArgumentsKeysTypeQcode  SendKeyArgumentsKeysType = "qcode"
+)
+
+// SendKeyArgumentsKeysNumber is an object of the QMP schema.
+type SendKeyArgumentsKeysNumber struct {
+	Data int64 `json:"data"`
+}
+
+// SendKeyArgumentsKeysQcode is an object of the QMP schema.
+type SendKeyArgumentsKeysQcode struct {
+	Data SendKeyArgumentsKeysQcodeData `json:"data"`
+}
+
+// SendKeyArgumentsKeysQcodeData is an enum of the QMP schema.
+type SendKeyArgumentsKeysQcodeData string
+
+const (
+	SendKeyArgumentsKeysQcodeDataUnmapped         SendKeyArgumentsKeysQcodeData = "unmapped"
+	SendKeyArgumentsKeysQcodeDataShift            SendKeyArgumentsKeysQcodeData = "shift"
+	SendKeyArgumentsKeysQcodeDataShiftR           SendKeyArgumentsKeysQcodeData = "shift_r"
+	SendKeyArgumentsKeysQcodeDataAlt              SendKeyArgumentsKeysQcodeData = "alt"
+	SendKeyArgumentsKeysQcodeDataAltR             SendKeyArgumentsKeysQcodeData = "alt_r"
+	SendKeyArgumentsKeysQcodeDataCtrl             SendKeyArgumentsKeysQcodeData = "ctrl"
+	SendKeyArgumentsKeysQcodeDataCtrlR            SendKeyArgumentsKeysQcodeData = "ctrl_r"
+	SendKeyArgumentsKeysQcodeDataMenu             SendKeyArgumentsKeysQcodeData = "menu"
+	SendKeyArgumentsKeysQcodeDataEsc              SendKeyArgumentsKeysQcodeData = "esc"
+	SendKeyArgumentsKeysQcodeDataX1               SendKeyArgumentsKeysQcodeData = "1"
+	SendKeyArgumentsKeysQcodeDataX2               SendKeyArgumentsKeysQcodeData = "2"
+	SendKeyArgumentsKeysQcodeDataX3               SendKeyArgumentsKeysQcodeData = "3"
+	SendKeyArgumentsKeysQcodeDataX4               SendKeyArgumentsKeysQcodeData = "4"
+	SendKeyArgumentsKeysQcodeDataX5               SendKeyArgumentsKeysQcodeData = "5"
+	SendKeyArgumentsKeysQcodeDataX6               SendKeyArgumentsKeysQcodeData = "6"
+	SendKeyArgumentsKeysQcodeDataX7               SendKeyArgumentsKeysQcodeData = "7"
+	SendKeyArgumentsKeysQcodeDataX8               SendKeyArgumentsKeysQcodeData = "8"
+	SendKeyArgumentsKeysQcodeDataX9               SendKeyArgumentsKeysQcodeData = "9"
+	SendKeyArgumentsKeysQcodeDataX0               SendKeyArgumentsKeysQcodeData = "0"
+	SendKeyArgumentsKeysQcodeDataMinus            SendKeyArgumentsKeysQcodeData = "minus"
+	SendKeyArgumentsKeysQcodeDataEqual            SendKeyArgumentsKeysQcodeData = "equal"
+	SendKeyArgumentsKeysQcodeDataBackspace        SendKeyArgumentsKeysQcodeData = "backspace"
+	SendKeyArgumentsKeysQcodeDataTab              SendKeyArgumentsKeysQcodeData = "tab"
+	SendKeyArgumentsKeysQcodeDataQ                SendKeyArgumentsKeysQcodeData = "q"
+	SendKeyArgumentsKeysQcodeDataW                SendKeyArgumentsKeysQcodeData = "w"
+	SendKeyArgumentsKeysQcodeDataE                SendKeyArgumentsKeysQcodeData = "e"
+	SendKeyArgumentsKeysQcodeDataR                SendKeyArgumentsKeysQcodeData = "r"
+	SendKeyArgumentsKeysQcodeDataT                SendKeyArgumentsKeysQcodeData = "t"
+	SendKeyArgumentsKeysQcodeDataY                SendKeyArgumentsKeysQcodeData = "y"
+	SendKeyArgumentsKeysQcodeDataU                SendKeyArgumentsKeysQcodeData = "u"
+	SendKeyArgumentsKeysQcodeDataI                SendKeyArgumentsKeysQcodeData = "i"
+	SendKeyArgumentsKeysQcodeDataO                SendKeyArgumentsKeysQcodeData = "o"
+	SendKeyArgumentsKeysQcodeDataP                SendKeyArgumentsKeysQcodeData = "p"
+	SendKeyArgumentsKeysQcodeDataBracketLeft      SendKeyArgumentsKeysQcodeData = "bracket_left"
+	SendKeyArgumentsKeysQcodeDataBracketRight     SendKeyArgumentsKeysQcodeData = "bracket_right"
+	SendKeyArgumentsKeysQcodeDataRet              SendKeyArgumentsKeysQcodeData = "ret"
+	SendKeyArgumentsKeysQcodeDataA                SendKeyArgumentsKeysQcodeData = "a"
+	SendKeyArgumentsKeysQcodeDataS                SendKeyArgumentsKeysQcodeData = "s"
+	SendKeyArgumentsKeysQcodeDataD                SendKeyArgumentsKeysQcodeData = "d"
+	SendKeyArgumentsKeysQcodeDataF                SendKeyArgumentsKeysQcodeData = "f"
+	SendKeyArgumentsKeysQcodeDataG                SendKeyArgumentsKeysQcodeData = "g"
+	SendKeyArgumentsKeysQcodeDataH                SendKeyArgumentsKeysQcodeData = "h"
+	SendKeyArgumentsKeysQcodeDataJ                SendKeyArgumentsKeysQcodeData = "j"
+	SendKeyArgumentsKeysQcodeDataK                SendKeyArgumentsKeysQcodeData = "k"
+	SendKeyArgumentsKeysQcodeDataL                SendKeyArgumentsKeysQcodeData = "l"
+	SendKeyArgumentsKeysQcodeDataSemicolon        SendKeyArgumentsKeysQcodeData = "semicolon"
+	SendKeyArgumentsKeysQcodeDataApostrophe       SendKeyArgumentsKeysQcodeData = "apostrophe"
+	SendKeyArgumentsKeysQcodeDataGraveAccent      SendKeyArgumentsKeysQcodeData = "grave_accent"
+	SendKeyArgumentsKeysQcodeDataBackslash        SendKeyArgumentsKeysQcodeData = "backslash"
+	SendKeyArgumentsKeysQcodeDataZ                SendKeyArgumentsKeysQcodeData = "z"
+	SendKeyArgumentsKeysQcodeDataX                SendKeyArgumentsKeysQcodeData = "x"
+	SendKeyArgumentsKeysQcodeDataC                SendKeyArgumentsKeysQcodeData = "c"
+	SendKeyArgumentsKeysQcodeDataV                SendKeyArgumentsKeysQcodeData = "v"
+	SendKeyArgumentsKeysQcodeDataB                SendKeyArgumentsKeysQcodeData = "b"
+	SendKeyArgumentsKeysQcodeDataN                SendKeyArgumentsKeysQcodeData = "n"
+	SendKeyArgumentsKeysQcodeDataM                SendKeyArgumentsKeysQcodeData = "m"
+	SendKeyArgumentsKeysQcodeDataComma            SendKeyArgumentsKeysQcodeData = "comma"
+	SendKeyArgumentsKeysQcodeDataDot              SendKeyArgumentsKeysQcodeData = "dot"
+	SendKeyArgumentsKeysQcodeDataSlash            SendKeyArgumentsKeysQcodeData = "slash"
+	SendKeyArgumentsKeysQcodeDataAsterisk         SendKeyArgumentsKeysQcodeData = "asterisk"
+	SendKeyArgumentsKeysQcodeDataSpc              SendKeyArgumentsKeysQcodeData = "spc"
+	SendKeyArgumentsKeysQcodeDataCapsLock         SendKeyArgumentsKeysQcodeData = "caps_lock"
+	SendKeyArgumentsKeysQcodeDataF1               SendKeyArgumentsKeysQcodeData = "f1"
+	SendKeyArgumentsKeysQcodeDataF2               SendKeyArgumentsKeysQcodeData = "f2"
+	SendKeyArgumentsKeysQcodeDataF3               SendKeyArgumentsKeysQcodeData = "f3"
+	SendKeyArgumentsKeysQcodeDataF4               SendKeyArgumentsKeysQcodeData = "f4"
+	SendKeyArgumentsKeysQcodeDataF5               SendKeyArgumentsKeysQcodeData = "f5"
+	SendKeyArgumentsKeysQcodeDataF6               SendKeyArgumentsKeysQcodeData = "f6"
+	SendKeyArgumentsKeysQcodeDataF7               SendKeyArgumentsKeysQcodeData = "f7"
+	SendKeyArgumentsKeysQcodeDataF8               SendKeyArgumentsKeysQcodeData = "f8"
+	SendKeyArgumentsKeysQcodeDataF9               SendKeyArgumentsKeysQcodeData = "f9"
+	SendKeyArgumentsKeysQcodeDataF10              SendKeyArgumentsKeysQcodeData = "f10"
+	SendKeyArgumentsKeysQcodeDataNumLock          SendKeyArgumentsKeysQcodeData = "num_lock"
+	SendKeyArgumentsKeysQcodeDataScrollLock       SendKeyArgumentsKeysQcodeData = "scroll_lock"
+	SendKeyArgumentsKeysQcodeDataKpDivide         SendKeyArgumentsKeysQcodeData = "kp_divide"
+	SendKeyArgumentsKeysQcodeDataKpMultiply       SendKeyArgumentsKeysQcodeData = "kp_multiply"
+	SendKeyArgumentsKeysQcodeDataKpSubtract       SendKeyArgumentsKeysQcodeData = "kp_subtract"
+	SendKeyArgumentsKeysQcodeDataKpAdd            SendKeyArgumentsKeysQcodeData = "kp_add"
+	SendKeyArgumentsKeysQcodeDataKpEnter          SendKeyArgumentsKeysQcodeData = "kp_enter"
+	SendKeyArgumentsKeysQcodeDataKpDecimal        SendKeyArgumentsKeysQcodeData = "kp_decimal"
+	SendKeyArgumentsKeysQcodeDataSysrq            SendKeyArgumentsKeysQcodeData = "sysrq"
+	SendKeyArgumentsKeysQcodeDataKp0              SendKeyArgumentsKeysQcodeData = "kp_0"
+	SendKeyArgumentsKeysQcodeDataKp1              SendKeyArgumentsKeysQcodeData = "kp_1"
+	SendKeyArgumentsKeysQcodeDataKp2              SendKeyArgumentsKeysQcodeData = "kp_2"
+	SendKeyArgumentsKeysQcodeDataKp3              SendKeyArgumentsKeysQcodeData = "kp_3"
+	SendKeyArgumentsKeysQcodeDataKp4              SendKeyArgumentsKeysQcodeData = "kp_4"
+	SendKeyArgumentsKeysQcodeDataKp5              SendKeyArgumentsKeysQcodeData = "kp_5"
+	SendKeyArgumentsKeysQcodeDataKp6              SendKeyArgumentsKeysQcodeData = "kp_6"
+	SendKeyArgumentsKeysQcodeDataKp7              SendKeyArgumentsKeysQcodeData = "kp_7"
+	SendKeyArgumentsKeysQcodeDataKp8              SendKeyArgumentsKeysQcodeData = "kp_8"
+	SendKeyArgumentsKeysQcodeDataKp9              SendKeyArgumentsKeysQcodeData = "kp_9"
+	SendKeyArgumentsKeysQcodeDataLess             SendKeyArgumentsKeysQcodeData = "less"
+	SendKeyArgumentsKeysQcodeDataF11              SendKeyArgumentsKeysQcodeData = "f11"
+	SendKeyArgumentsKeysQcodeDataF12              SendKeyArgumentsKeysQcodeData = "f12"
+	SendKeyArgumentsKeysQcodeDataPrint            SendKeyArgumentsKeysQcodeData = "print"
+	SendKeyArgumentsKeysQcodeDataHome             SendKeyArgumentsKeysQcodeData = "home"
+	SendKeyArgumentsKeysQcodeDataPgup             SendKeyArgumentsKeysQcodeData = "pgup"
+	SendKeyArgumentsKeysQcodeDataPgdn             SendKeyArgumentsKeysQcodeData = "pgdn"
+	SendKeyArgumentsKeysQcodeDataEnd              SendKeyArgumentsKeysQcodeData = "end"
+	SendKeyArgumentsKeysQcodeDataLeft             SendKeyArgumentsKeysQcodeData = "left"
+	SendKeyArgumentsKeysQcodeDataUp               SendKeyArgumentsKeysQcodeData = "up"
+	SendKeyArgumentsKeysQcodeDataDown             SendKeyArgumentsKeysQcodeData = "down"
+	SendKeyArgumentsKeysQcodeDataRight            SendKeyArgumentsKeysQcodeData = "right"
+	SendKeyArgumentsKeysQcodeDataInsert           SendKeyArgumentsKeysQcodeData = "insert"
+	SendKeyArgumentsKeysQcodeDataDelete           SendKeyArgumentsKeysQcodeData = "delete"
+	SendKeyArgumentsKeysQcodeDataStop             SendKeyArgumentsKeysQcodeData = "stop"
+	SendKeyArgumentsKeysQcodeDataAgain            SendKeyArgumentsKeysQcodeData = "again"
+	SendKeyArgumentsKeysQcodeDataProps            SendKeyArgumentsKeysQcodeData = "props"
+	SendKeyArgumentsKeysQcodeDataUndo             SendKeyArgumentsKeysQcodeData = "undo"
+	SendKeyArgumentsKeysQcodeDataFront            SendKeyArgumentsKeysQcodeData = "front"
+	SendKeyArgumentsKeysQcodeDataCopy             SendKeyArgumentsKeysQcodeData = "copy"
+	SendKeyArgumentsKeysQcodeDataOpen             SendKeyArgumentsKeysQcodeData = "open"
+	SendKeyArgumentsKeysQcodeDataPaste            SendKeyArgumentsKeysQcodeData = "paste"
+	SendKeyArgumentsKeysQcodeDataFind             SendKeyArgumentsKeysQcodeData = "find"
+	SendKeyArgumentsKeysQcodeDataCut              SendKeyArgumentsKeysQcodeData = "cut"
+	SendKeyArgumentsKeysQcodeDataLf               SendKeyArgumentsKeysQcodeData = "lf"
+	SendKeyArgumentsKeysQcodeDataHelp             SendKeyArgumentsKeysQcodeData = "help"
+	SendKeyArgumentsKeysQcodeDataMetaL            SendKeyArgumentsKeysQcodeData = "meta_l"
+	SendKeyArgumentsKeysQcodeDataMetaR            SendKeyArgumentsKeysQcodeData = "meta_r"
+	SendKeyArgumentsKeysQcodeDataCompose          SendKeyArgumentsKeysQcodeData = "compose"
+	SendKeyArgumentsKeysQcodeDataPause            SendKeyArgumentsKeysQcodeData = "pause"
+	SendKeyArgumentsKeysQcodeDataRo               SendKeyArgumentsKeysQcodeData = "ro"
+	SendKeyArgumentsKeysQcodeDataHiragana         SendKeyArgumentsKeysQcodeData = "hiragana"
+	SendKeyArgumentsKeysQcodeDataHenkan           SendKeyArgumentsKeysQcodeData = "henkan"
+	SendKeyArgumentsKeysQcodeDataYen              SendKeyArgumentsKeysQcodeData = "yen"
+	SendKeyArgumentsKeysQcodeDataMuhenkan         SendKeyArgumentsKeysQcodeData = "muhenkan"
+	SendKeyArgumentsKeysQcodeDataKatakanahiragana SendKeyArgumentsKeysQcodeData = "katakanahiragana"
+	SendKeyArgumentsKeysQcodeDataKpComma          SendKeyArgumentsKeysQcodeData = "kp_comma"
+	SendKeyArgumentsKeysQcodeDataKpEquals         SendKeyArgumentsKeysQcodeData = "kp_equals"
+	SendKeyArgumentsKeysQcodeDataPower            SendKeyArgumentsKeysQcodeData = "power"
+	SendKeyArgumentsKeysQcodeDataSleep            SendKeyArgumentsKeysQcodeData = "sleep"
+	SendKeyArgumentsKeysQcodeDataWake             SendKeyArgumentsKeysQcodeData = "wake"
+	SendKeyArgumentsKeysQcodeDataAudionext        SendKeyArgumentsKeysQcodeData = "audionext"
+	SendKeyArgumentsKeysQcodeDataAudioprev        SendKeyArgumentsKeysQcodeData = "audioprev"
+	SendKeyArgumentsKeysQcodeDataAudiostop        SendKeyArgumentsKeysQcodeData = "audiostop"
+	SendKeyArgumentsKeysQcodeDataAudioplay        SendKeyArgumentsKeysQcodeData = "audioplay"
+	SendKeyArgumentsKeysQcodeDataAudiomute        SendKeyArgumentsKeysQcodeData = "audiomute"
+	SendKeyArgumentsKeysQcodeDataVolumeup         SendKeyArgumentsKeysQcodeData = "volumeup"
+	SendKeyArgumentsKeysQcodeDataVolumedown       SendKeyArgumentsKeysQcodeData = "volumedown"
+	SendKeyArgumentsKeysQcodeDataMediaselect      SendKeyArgumentsKeysQcodeData = "mediaselect"
+	SendKeyArgumentsKeysQcodeDataMail             SendKeyArgumentsKeysQcodeData = "mail"
+	SendKeyArgumentsKeysQcodeDataCalculator       SendKeyArgumentsKeysQcodeData = "calculator"
+	SendKeyArgumentsKeysQcodeDataComputer         SendKeyArgumentsKeysQcodeData = "computer"
+	SendKeyArgumentsKeysQcodeDataAcHome           SendKeyArgumentsKeysQcodeData = "ac_home"
+	SendKeyArgumentsKeysQcodeDataAcBack           SendKeyArgumentsKeysQcodeData = "ac_back"
+	SendKeyArgumentsKeysQcodeDataAcForward        SendKeyArgumentsKeysQcodeData = "ac_forward"
+	SendKeyArgumentsKeysQcodeDataAcRefresh        SendKeyArgumentsKeysQcodeData = "ac_refresh"
+	SendKeyArgumentsKeysQcodeDataAcBookmarks      SendKeyArgumentsKeysQcodeData = "ac_bookmarks"
+	SendKeyArgumentsKeysQcodeDataLang1            SendKeyArgumentsKeysQcodeData = "lang1"
+	SendKeyArgumentsKeysQcodeDataLang2            SendKeyArgumentsKeysQcodeData = "lang2"
+	SendKeyArgumentsKeysQcodeDataF13              SendKeyArgumentsKeysQcodeData = "f13"
+	SendKeyArgumentsKeysQcodeDataF14              SendKeyArgumentsKeysQcodeData = "f14"
+	SendKeyArgumentsKeysQcodeDataF15              SendKeyArgumentsKeysQcodeData = "f15"
+	SendKeyArgumentsKeysQcodeDataF16              SendKeyArgumentsKeysQcodeData = "f16"
+	SendKeyArgumentsKeysQcodeDataF17              SendKeyArgumentsKeysQcodeData = "f17"
+	SendKeyArgumentsKeysQcodeDataF18              SendKeyArgumentsKeysQcodeData = "f18"
+	SendKeyArgumentsKeysQcodeDataF19              SendKeyArgumentsKeysQcodeData = "f19"
+	SendKeyArgumentsKeysQcodeDataF20              SendKeyArgumentsKeysQcodeData = "f20"
+	SendKeyArgumentsKeysQcodeDataF21              SendKeyArgumentsKeysQcodeData = "f21"
+	SendKeyArgumentsKeysQcodeDataF22              SendKeyArgumentsKeysQcodeData = "f22"
+	SendKeyArgumentsKeysQcodeDataF23              SendKeyArgumentsKeysQcodeData = "f23"
+	SendKeyArgumentsKeysQcodeDataF24              SendKeyArgumentsKeysQcodeData = "f24"
+)
+
+// InputSendEventArguments is an object of the QMP schema.
+type InputSendEventArguments struct {
+	Device *string                         `json:"device,omitempty"`
+	Head   *int64                          `json:"head,omitempty"`
+	Events []InputSendEventArgumentsEvents `json:"events"`
+}
+
+// InputSendEventArgumentsEvents is an object of the QMP schema.
+type InputSendEventArgumentsEvents struct {
+	Type InputSendEventArgumentsEventsType     `json:"type"`
+	Data *InputSendEventArgumentsEventsKeyData `json:"data,omitempty"`
+}
+
+// InputSendEventArgumentsEventsType is an enum of the QMP schema.
+type InputSendEventArgumentsEventsType string
+
+const (
+	InputSendEventArgumentsEventsTypeKey InputSendEventArgumentsEventsType = "key"
+	InputSendEventArgumentsEventsTypeBtn InputSendEventArgumentsEventsType = "btn"
+	InputSendEventArgumentsEventsTypeRel InputSendEventArgumentsEventsType = "rel"
+	InputSendEventArgumentsEventsTypeAbs InputSendEventArgumentsEventsType = "abs"
+	InputSendEventArgumentsEventsTypeMtt InputSendEventArgumentsEventsType = "mtt"
+)
+
+// InputSendEventArgumentsEventsKey is an object of the QMP schema.
+type InputSendEventArgumentsEventsKey struct {
+	Data InputSendEventArgumentsEventsKeyData `json:"data"`
+}
+
+// InputSendEventArgumentsEventsKeyData is an object of the QMP schema.
+type InputSendEventArgumentsEventsKeyData struct {
+	Key  SendKeyArgumentsKeys `json:"key"`
+	Down bool                 `json:"down"`
+}
+
+// InputSendEventArgumentsEventsBtn is an object of the QMP schema.
+type InputSendEventArgumentsEventsBtn struct {
+	Data InputSendEventArgumentsEventsBtnData `json:"data"`
+}
+
+// InputSendEventArgumentsEventsBtnData is an object of the QMP schema.
+type InputSendEventArgumentsEventsBtnData struct {
+	Button InputSendEventArgumentsEventsBtnDataButton `json:"button"`
+	Down   bool                                       `json:"down"`
+}
+
+// InputSendEventArgumentsEventsBtnDataButton is an enum of the QMP schema.
+type InputSendEventArgumentsEventsBtnDataButton string
+
+const (
+	InputSendEventArgumentsEventsBtnDataButtonLeft       InputSendEventArgumentsEventsBtnDataButton = "left"
+	InputSendEventArgumentsEventsBtnDataButtonMiddle     InputSendEventArgumentsEventsBtnDataButton = "middle"
+	InputSendEventArgumentsEventsBtnDataButtonRight      InputSendEventArgumentsEventsBtnDataButton = "right"
+	InputSendEventArgumentsEventsBtnDataButtonWheelUp    InputSendEventArgumentsEventsBtnDataButton = "wheel-up"
+	InputSendEventArgumentsEventsBtnDataButtonWheelDown  InputSendEventArgumentsEventsBtnDataButton = "wheel-down"
+	InputSendEventArgumentsEventsBtnDataButtonSide       InputSendEventArgumentsEventsBtnDataButton = "side"
+	InputSendEventArgumentsEventsBtnDataButtonExtra      InputSendEventArgumentsEventsBtnDataButton = "extra"
+	InputSendEventArgumentsEventsBtnDataButtonWheelLeft  InputSendEventArgumentsEventsBtnDataButton = "wheel-left"
+	InputSendEventArgumentsEventsBtnDataButtonWheelRight InputSendEventArgumentsEventsBtnDataButton = "wheel-right"
+	InputSendEventArgumentsEventsBtnDataButtonTouch      InputSendEventArgumentsEventsBtnDataButton = "touch"
+)
+
+// InputSendEventArgumentsEventsRel is an object of the QMP schema.
+type InputSendEventArgumentsEventsRel struct {
+	Data InputSendEventArgumentsEventsRelData `json:"data"`
+}
+
+// InputSendEventArgumentsEventsRelData is an object of the QMP schema.
+type InputSendEventArgumentsEventsRelData struct {
+	Axis  InputSendEventArgumentsEventsRelDataAxis `json:"axis"`
+	Value int64                                    `json:"value"`
+}
+
+// InputSendEventArgumentsEventsRelDataAxis is an enum of the QMP schema.
+type InputSendEventArgumentsEventsRelDataAxis string
+
+const (
+	InputSendEventArgumentsEventsRelDataAxisX InputSendEventArgumentsEventsRelDataAxis = "x"
+	InputSendEventArgumentsEventsRelDataAxisY InputSendEventArgumentsEventsRelDataAxis = "y"
+)
+
+// InputSendEventArgumentsEventsMtt is an object of the QMP schema.
+type InputSendEventArgumentsEventsMtt struct {
+	Data InputSendEventArgumentsEventsMttData `json:"data"`
+}
+
+// InputSendEventArgumentsEventsMttData is an object of the QMP schema.
+type InputSendEventArgumentsEventsMttData struct {
+	Type       InputSendEventArgumentsEventsMttDataType `json:"type"`
+	Slot       int64                                    `json:"slot"`
+	TrackingID int64                                    `json:"tracking-id"`
+	Axis       InputSendEventArgumentsEventsRelDataAxis `json:"axis"`
+	Value      int64                                    `json:"value"`
+}
+
+// InputSendEventArgumentsEventsMttDataType is an enum of the QMP schema.
+type InputSendEventArgumentsEventsMttDataType string
+
+const (
+	InputSendEventArgumentsEventsMttDataTypeBegin  InputSendEventArgumentsEventsMttDataType = "begin"
+	InputSendEventArgumentsEventsMttDataTypeUpdate InputSendEventArgumentsEventsMttDataType = "update"
+	InputSendEventArgumentsEventsMttDataTypeEnd    InputSendEventArgumentsEventsMttDataType = "end"
+	InputSendEventArgumentsEventsMttDataTypeCancel InputSendEventArgumentsEventsMttDataType = "cancel"
+	InputSendEventArgumentsEventsMttDataTypeData   InputSendEventArgumentsEventsMttDataType = "data"
+)
+
+// TraceEventGetStateArguments is an object of the QMP schema.
+type TraceEventGetStateArguments struct {
+	Name string `json:"name"`
+	// Deprecated: deprecated in the QMP schema.
+	Vcpu *int64 `json:"vcpu,omitempty"`
+}
+
+// TraceEventGetStateResult is an object of the QMP schema.
+type TraceEventGetStateResult struct {
+	Name  string                        `json:"name"`
+	State TraceEventGetStateResultState `json:"state"`
+	// Deprecated: deprecated in the QMP schema.
+	Vcpu bool `json:"vcpu"`
+}
+
+// TraceEventGetStateResultState is an enum of the QMP schema.
+type TraceEventGetStateResultState string
+
+const (
+	TraceEventGetStateResultStateUnavailable TraceEventGetStateResultState = "unavailable"
+	TraceEventGetStateResultStateDisabled    TraceEventGetStateResultState = "disabled"
+	TraceEventGetStateResultStateEnabled     TraceEventGetStateResultState = "enabled"
+)
+
+// TraceEventSetStateArguments is an object of the QMP schema.
+type TraceEventSetStateArguments struct {
+	Name              string `json:"name"`
+	Enable            bool   `json:"enable"`
+	IgnoreUnavailable *bool  `json:"ignore-unavailable,omitempty"`
+	// Deprecated: deprecated in the QMP schema.
+	Vcpu *int64 `json:"vcpu,omitempty"`
+}
+
+// QueryStatsArguments is an object of the QMP schema.
+type QueryStatsArguments struct {
+	Target    QueryStatsArgumentsTarget      `json:"target"`
+	Providers []QueryStatsArgumentsProviders `json:"providers,omitempty"`
+	Vcpus     []string                       `json:"vcpus,omitempty"`
+}
+
+// QueryStatsArgumentsTarget is an enum of the QMP schema.
+type QueryStatsArgumentsTarget string
+
+const (
+	QueryStatsArgumentsTargetVM        QueryStatsArgumentsTarget = "vm"
+	QueryStatsArgumentsTargetVcpu      QueryStatsArgumentsTarget = "vcpu"
+	QueryStatsArgumentsTargetCryptodev QueryStatsArgumentsTarget = "cryptodev"
+)
+
+// QueryStatsArgumentsProviders is an object of the QMP schema.
+type QueryStatsArgumentsProviders struct {
+	Provider QueryStatsArgumentsProvidersProvider `json:"provider"`
+	Names    []string                             `json:"names,omitempty"`
+}
+
+// QueryStatsArgumentsProvidersProvider is an enum of the QMP schema.
+type QueryStatsArgumentsProvidersProvider string
+
+const (
+	QueryStatsArgumentsProvidersProviderKVM       QueryStatsArgumentsProvidersProvider = "kvm"
+	QueryStatsArgumentsProvidersProviderCryptodev QueryStatsArgumentsProvidersProvider = "cryptodev"
+)
+
+// QueryStatsArgumentsVcpu is an object of the QMP schema.
+type QueryStatsArgumentsVcpu struct {
+	Vcpus []string `json:"vcpus,omitempty"`
+}
+
+// QueryStatsResult is an object of the QMP schema.
+type QueryStatsResult struct {
+	Provider QueryStatsArgumentsProvidersProvider `json:"provider"`
+	QOMPath  *string                              `json:"qom-path,omitempty"`
+	Stats    []QueryStatsResultStats              `json:"stats"`
+}
+
+// QueryStatsResultStats is an object of the QMP schema.
+type QueryStatsResultStats struct {
+	Name  string                     `json:"name"`
+	Value QueryStatsResultStatsValue `json:"value"`
+}
+
+// QueryStatsResultStatsValue is an alternate of the QMP schema, which is one of several types.
+type QueryStatsResultStatsValue = json.RawMessage
+
+// ShutdownData is an object of the QMP schema.
+type ShutdownData struct {
+	Guest  bool               `json:"guest"`
+	Reason ShutdownDataReason `json:"reason"`
+}
+
+// ShutdownDataReason is an enum of the QMP schema.
+type ShutdownDataReason string
+
+const (
+	ShutdownDataReasonNone               ShutdownDataReason = "none"
+	ShutdownDataReasonHostError          ShutdownDataReason = "host-error"
+	ShutdownDataReasonHostQMPQuit        ShutdownDataReason = "host-qmp-quit"
+	ShutdownDataReasonHostQMPSystemReset ShutdownDataReason = "host-qmp-system-reset"
+	ShutdownDataReasonHostSignal         ShutdownDataReason = "host-signal"
+	ShutdownDataReasonHostUI             ShutdownDataReason = "host-ui"
+	ShutdownDataReasonGuestShutdown      ShutdownDataReason = "guest-shutdown"
+	ShutdownDataReasonGuestReset         ShutdownDataReason = "guest-reset"
+	ShutdownDataReasonGuestPanic         ShutdownDataReason = "guest-panic"
+	ShutdownDataReasonSubsystemReset     ShutdownDataReason = "subsystem-reset"
+	ShutdownDataReasonSnapshotLoad       ShutdownDataReason = "snapshot-load"
+)
+
+// ResetData is an object of the QMP schema.
+type ResetData struct {
+	Guest  bool               `json:"guest"`
+	Reason ShutdownDataReason `json:"reason"`
+}
+
+// BalloonChangeData is an object of the QMP schema.
+type BalloonChangeData struct {
+	Actual int64 `json:"actual"`
+}
+
+// Names of the events of the QMP schema. The data of an event is decoded
+// into the corresponding Data type with qmp.Event.DecodeData.
+const (
+	EventBalloonChange = "BALLOON_CHANGE"
+	EventReset         = "RESET"
+	EventResume        = "RESUME"
+	EventShutdown      = "SHUTDOWN"
+	EventStop          = "STOP"
+)
+
+// QueryStatus executes the query-status command.
+func QueryStatus(ctx context.Context, client *qmp.Client) (*QueryStatusResult, error) {
+	var result QueryStatusResult
+
+	if err := client.Execute(ctx, "query-status", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryName executes the query-name command.
+func QueryName(ctx context.Context, client *qmp.Client) (*QueryNameResult, error) {
+	var result QueryNameResult
+
+	if err := client.Execute(ctx, "query-name", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryVersion executes the query-version command.
+func QueryVersion(ctx context.Context, client *qmp.Client) (*QueryVersionResult, error) {
+	var result QueryVersionResult
+
+	if err := client.Execute(ctx, "query-version", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryKVM executes the query-kvm command.
+func QueryKVM(ctx context.Context, client *qmp.Client) (*QueryKVMResult, error) {
+	var result QueryKVMResult
+
+	if err := client.Execute(ctx, "query-kvm", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryCpusFast executes the query-cpus-fast command.
+func QueryCpusFast(ctx context.Context, client *qmp.Client) ([]QueryCpusFastResult, error) {
+	var result []QueryCpusFastResult
+
+	err := client.Execute(ctx, "query-cpus-fast", nil, &result)
+
+	return result, err
+}
+
+// Stop executes the stop command.
+func Stop(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "stop", nil, nil)
+}
+
+// Cont executes the cont command.
+func Cont(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "cont", nil, nil)
+}
+
+// SystemReset executes the system_reset command.
+func SystemReset(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "system_reset", nil, nil)
+}
+
+// SystemPowerdown executes the system_powerdown command.
+func SystemPowerdown(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "system_powerdown", nil, nil)
+}
+
+// Quit executes the quit command.
+func Quit(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "quit", nil, nil)
+}
+
+// Balloon executes the balloon command.
+func Balloon(ctx context.Context, client *qmp.Client, args BalloonArguments) error {
+	return client.Execute(ctx, "balloon", args, nil)
+}
+
+// QueryBalloon executes the query-balloon command.
+func QueryBalloon(ctx context.Context, client *qmp.Client) (*QueryBalloonResult, error) {
+	var result QueryBalloonResult
+
+	if err := client.Execute(ctx, "query-balloon", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Screendump executes the screendump command.
+func Screendump(ctx context.Context, client *qmp.Client, args ScreendumpArguments) error {
+	return client.Execute(ctx, "screendump", args, nil)
+}
+
+// QMPCapabilities executes the qmp_capabilities command.
+func QMPCapabilities(ctx context.Context, client *qmp.Client, args QMPCapabilitiesArguments) error {
+	return client.Execute(ctx, "qmp_capabilities", args, nil)
+}
+
+// QueryQMPSchema executes the query-qmp-schema command.
+func QueryQMPSchema(ctx context.Context, client *qmp.Client) ([]QueryQMPSchemaResult, error) {
+	var result []QueryQMPSchemaResult
+
+	err := client.Execute(ctx, "query-qmp-schema", nil, &result)
+
+	return result, err
+}
+
+// QueryHotpluggableCpus executes the query-hotpluggable-cpus command.
+func QueryHotpluggableCpus(ctx context.Context, client *qmp.Client) ([]QueryHotpluggableCpusResult, error) {
+	var result []QueryHotpluggableCpusResult
+
+	err := client.Execute(ctx, "query-hotpluggable-cpus", nil, &result)
+
+	return result, err
+}
+
+// QueryMemoryDevices executes the query-memory-devices command.
+func QueryMemoryDevices(ctx context.Context, client *qmp.Client) ([]QueryMemoryDevicesResult, error) {
+	var result []QueryMemoryDevicesResult
+
+	err := client.Execute(ctx, "query-memory-devices", nil, &result)
+
+	return result, err
+}
+
+// DeviceAdd executes the device_add command.
+func DeviceAdd(ctx context.Context, client *qmp.Client, args DeviceAddArguments) error {
+	return client.Execute(ctx, "device_add", args, nil)
+}
+
+// DeviceDel executes the device_del command.
+func DeviceDel(ctx context.Context, client *qmp.Client, args DeviceDelArguments) error {
+	return client.Execute(ctx, "device_del", args, nil)
+}
+
+// DeviceListProperties executes the device-list-properties command.
+func DeviceListProperties(ctx context.Context, client *qmp.Client, args DeviceListPropertiesArguments) ([]DeviceListPropertiesResult, error) {
+	var result []DeviceListPropertiesResult
+
+	err := client.Execute(ctx, "device-list-properties", args, &result)
+
+	return result, err
+}
+
+// QOMList executes the qom-list command.
+func QOMList(ctx context.Context, client *qmp.Client, args QOMListArguments) ([]DeviceListPropertiesResult, error) {
+	var result []DeviceListPropertiesResult
+
+	err := client.Execute(ctx, "qom-list", args, &result)
+
+	return result, err
+}
+
+// QOMGet executes the qom-get command.
+func QOMGet(ctx context.Context, client *qmp.Client, args QOMGetArguments) (interface{}, error) {
+	var result interface{}
+
+	err := client.Execute(ctx, "qom-get", args, &result)
+
+	return result, err
+}
+
+// QOMSet executes the qom-set command.
+func QOMSet(ctx context.Context, client *qmp.Client, args QOMSetArguments) error {
+	return client.Execute(ctx, "qom-set", args, nil)
+}
+
+// QOMListTypes executes the qom-list-types command.
+func QOMListTypes(ctx context.Context, client *qmp.Client, args QOMListTypesArguments) ([]QOMListTypesResult, error) {
+	var result []QOMListTypesResult
+
+	err := client.Execute(ctx, "qom-list-types", args, &result)
+
+	return result, err
+}
+
+// QOMListProperties executes the qom-list-properties command.
+func QOMListProperties(ctx context.Context, client *qmp.Client, args QOMListPropertiesArguments) ([]DeviceListPropertiesResult, error) {
+	var result []DeviceListPropertiesResult
+
+	err := client.Execute(ctx, "qom-list-properties", args, &result)
+
+	return result, err
+}
+
+// ObjectAdd executes the object-add command.
+func ObjectAdd(ctx context.Context, client *qmp.Client, args ObjectAddArguments) error {
+	return client.Execute(ctx, "object-add", args, nil)
+}
+
+// ObjectDel executes the object-del command.
+func ObjectDel(ctx context.Context, client *qmp.Client, args ObjectDelArguments) error {
+	return client.Execute(ctx, "object-del", args, nil)
+}
+
+// QueryBlock executes the query-block command.
+func QueryBlock(ctx context.Context, client *qmp.Client) ([]QueryBlockResult, error) {
+	var result []QueryBlockResult
+
+	err := client.Execute(ctx, "query-block", nil, &result)
+
+	return result, err
+}
+
+// QueryBlockstats executes the query-blockstats command.
+func QueryBlockstats(ctx context.Context, client *qmp.Client, args QueryBlockstatsArguments) ([]QueryBlockstatsResult, error) {
+	var result []QueryBlockstatsResult
+
+	err := client.Execute(ctx, "query-blockstats", args, &result)
+
+	return result, err
+}
+
+// QueryNamedBlockNodes executes the query-named-block-nodes command.
+func QueryNamedBlockNodes(ctx context.Context, client *qmp.Client, args QueryNamedBlockNodesArguments) ([]QueryBlockResultInserted, error) {
+	var result []QueryBlockResultInserted
+
+	err := client.Execute(ctx, "query-named-block-nodes", args, &result)
+
+	return result, err
+}
+
+// BlockdevAdd executes the blockdev-add command.
+func BlockdevAdd(ctx context.Context, client *qmp.Client, args BlockdevAddArguments) error {
+	return client.Execute(ctx, "blockdev-add", args, nil)
+}
+
+// BlockdevDel executes the blockdev-del command.
+func BlockdevDel(ctx context.Context, client *qmp.Client, args BlockdevDelArguments) error {
+	return client.Execute(ctx, "blockdev-del", args, nil)
+}
+
+// BlockdevSnapshotSync executes the blockdev-snapshot-sync command.
+func BlockdevSnapshotSync(ctx context.Context, client *qmp.Client, args BlockdevSnapshotSyncArguments) error {
+	return client.Execute(ctx, "blockdev-snapshot-sync", args, nil)
+}
+
+// BlockCommit executes the block-commit command.
+func BlockCommit(ctx context.Context, client *qmp.Client, args BlockCommitArguments) error {
+	return client.Execute(ctx, "block-commit", args, nil)
+}
+
+// BlockStream executes the block-stream command.
+func BlockStream(ctx context.Context, client *qmp.Client, args BlockStreamArguments) error {
+	return client.Execute(ctx, "block-stream", args, nil)
+}
+
+// BlockdevBackup executes the blockdev-backup command.
+func BlockdevBackup(ctx context.Context, client *qmp.Client, args BlockdevBackupArguments) error {
+	return client.Execute(ctx, "blockdev-backup", args, nil)
+}
+
+// BlockdevMirror executes the blockdev-mirror command.
+func BlockdevMirror(ctx context.Context, client *qmp.Client, args BlockdevMirrorArguments) error {
+	return client.Execute(ctx, "blockdev-mirror", args, nil)
+}
+
+// BlockJobSetSpeed executes the block-job-set-speed command.
+func BlockJobSetSpeed(ctx context.Context, client *qmp.Client, args BlockJobSetSpeedArguments) error {
+	return client.Execute(ctx, "block-job-set-speed", args, nil)
+}
+
+// BlockSetIOThrottle executes the block_set_io_throttle command.
+func BlockSetIOThrottle(ctx context.Context, client *qmp.Client, args BlockSetIOThrottleArguments) error {
+	return client.Execute(ctx, "block_set_io_throttle", args, nil)
+}
+
+// BlockDirtyBitmapAdd executes the block-dirty-bitmap-add command.
+func BlockDirtyBitmapAdd(ctx context.Context, client *qmp.Client, args BlockDirtyBitmapAddArguments) error {
+	return client.Execute(ctx, "block-dirty-bitmap-add", args, nil)
+}
+
+// BlockDirtyBitmapRemove executes the block-dirty-bitmap-remove command.
+func BlockDirtyBitmapRemove(ctx context.Context, client *qmp.Client, args BlockdevAddArgumentsCopyBeforeWriteBitmap) error {
+	return client.Execute(ctx, "block-dirty-bitmap-remove", args, nil)
+}
+
+// BlockDirtyBitmapClear executes the block-dirty-bitmap-clear command.
+func BlockDirtyBitmapClear(ctx context.Context, client *qmp.Client, args BlockdevAddArgumentsCopyBeforeWriteBitmap) error {
+	return client.Execute(ctx, "block-dirty-bitmap-clear", args, nil)
+}
+
+// BlockDirtyBitmapEnable executes the block-dirty-bitmap-enable command.
+func BlockDirtyBitmapEnable(ctx context.Context, client *qmp.Client, args BlockdevAddArgumentsCopyBeforeWriteBitmap) error {
+	return client.Execute(ctx, "block-dirty-bitmap-enable", args, nil)
+}
+
+// BlockDirtyBitmapDisable executes the block-dirty-bitmap-disable command.
+func BlockDirtyBitmapDisable(ctx context.Context, client *qmp.Client, args BlockdevAddArgumentsCopyBeforeWriteBitmap) error {
+	return client.Execute(ctx, "block-dirty-bitmap-disable", args, nil)
+}
+
+// BlockDirtyBitmapMerge executes the block-dirty-bitmap-merge command.
+func BlockDirtyBitmapMerge(ctx context.Context, client *qmp.Client, args BlockDirtyBitmapMergeArguments) error {
+	return client.Execute(ctx, "block-dirty-bitmap-merge", args, nil)
+}
+
+// Transaction executes the transaction command.
+func Transaction(ctx context.Context, client *qmp.Client, args TransactionArguments) error {
+	return client.Execute(ctx, "transaction", args, nil)
+}
+
+// QueryJobs executes the query-jobs command.
+func QueryJobs(ctx context.Context, client *qmp.Client) ([]QueryJobsResult, error) {
+	var result []QueryJobsResult
+
+	err := client.Execute(ctx, "query-jobs", nil, &result)
+
+	return result, err
+}
+
+// JobPause executes the job-pause command.
+func JobPause(ctx context.Context, client *qmp.Client, args JobPauseArguments) error {
+	return client.Execute(ctx, "job-pause", args, nil)
+}
+
+// JobResume executes the job-resume command.
+func JobResume(ctx context.Context, client *qmp.Client, args JobResumeArguments) error {
+	return client.Execute(ctx, "job-resume", args, nil)
+}
+
+// JobCancel executes the job-cancel command.
+func JobCancel(ctx context.Context, client *qmp.Client, args JobCancelArguments) error {
+	return client.Execute(ctx, "job-cancel", args, nil)
+}
+
+// JobComplete executes the job-complete command.
+func JobComplete(ctx context.Context, client *qmp.Client, args JobCompleteArguments) error {
+	return client.Execute(ctx, "job-complete", args, nil)
+}
+
+// JobDismiss executes the job-dismiss command.
+func JobDismiss(ctx context.Context, client *qmp.Client, args JobDismissArguments) error {
+	return client.Execute(ctx, "job-dismiss", args, nil)
+}
+
+// JobFinalize executes the job-finalize command.
+func JobFinalize(ctx context.Context, client *qmp.Client, args JobFinalizeArguments) error {
+	return client.Execute(ctx, "job-finalize", args, nil)
+}
+
+// QueryMigrate executes the query-migrate command.
+func QueryMigrate(ctx context.Context, client *qmp.Client) (*QueryMigrateResult, error) {
+	var result QueryMigrateResult
+
+	if err := client.Execute(ctx, "query-migrate", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// MigrateSetCapabilities executes the migrate-set-capabilities command.
+func MigrateSetCapabilities(ctx context.Context, client *qmp.Client, args MigrateSetCapabilitiesArguments) error {
+	return client.Execute(ctx, "migrate-set-capabilities", args, nil)
+}
+
+// MigrateSetParameters executes the migrate-set-parameters command.
+func MigrateSetParameters(ctx context.Context, client *qmp.Client, args MigrateSetParametersArguments) error {
+	return client.Execute(ctx, "migrate-set-parameters", args, nil)
+}
+
+// Migrate executes the migrate command.
+func Migrate(ctx context.Context, client *qmp.Client, args MigrateArguments) error {
+	return client.Execute(ctx, "migrate", args, nil)
+}
+
+// MigrateIncoming executes the migrate-incoming command.
+func MigrateIncoming(ctx context.Context, client *qmp.Client, args MigrateIncomingArguments) error {
+	return client.Execute(ctx, "migrate-incoming", args, nil)
+}
+
+// MigrateCancel executes the migrate_cancel command.
+func MigrateCancel(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "migrate_cancel", nil, nil)
+}
+
+// MigrateContinue executes the migrate-continue command.
+func MigrateContinue(ctx context.Context, client *qmp.Client, args MigrateContinueArguments) error {
+	return client.Execute(ctx, "migrate-continue", args, nil)
+}
+
+// MigrateStartPostcopy executes the migrate-start-postcopy command.
+func MigrateStartPostcopy(ctx context.Context, client *qmp.Client) error {
+	return client.Execute(ctx, "migrate-start-postcopy", nil, nil)
+}
+
+// DumpGuestMemory executes the dump-guest-memory command.
+func DumpGuestMemory(ctx context.Context, client *qmp.Client, args DumpGuestMemoryArguments) error {
+	return client.Execute(ctx, "dump-guest-memory", args, nil)
+}
+
+// QueryDump executes the query-dump command.
+func QueryDump(ctx context.Context, client *qmp.Client) (*QueryDumpResult, error) {
+	var result QueryDumpResult
+
+	if err := client.Execute(ctx, "query-dump", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryDumpGuestMemoryCapability executes the query-dump-guest-memory-capability command.
+func QueryDumpGuestMemoryCapability(ctx context.Context, client *qmp.Client) (*QueryDumpGuestMemoryCapabilityResult, error) {
+	var result QueryDumpGuestMemoryCapabilityResult
+
+	if err := client.Execute(ctx, "query-dump-guest-memory-capability", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SendKey executes the send-key command.
+func SendKey(ctx context.Context, client *qmp.Client, args SendKeyArguments) error {
+	return client.Execute(ctx, "send-key", args, nil)
+}
+
+// InputSendEvent executes the input-send-event command.
+func InputSendEvent(ctx context.Context, client *qmp.Client, args InputSendEventArguments) error {
+	return client.Execute(ctx, "input-send-event", args, nil)
+}
+
+// TraceEventGetState executes the trace-event-get-state command.
+func TraceEventGetState(ctx context.Context, client *qmp.Client, args TraceEventGetStateArguments) ([]TraceEventGetStateResult, error) {
+	var result []TraceEventGetStateResult
+
+	err := client.Execute(ctx, "trace-event-get-state", args, &result)
+
+	return result, err
+}
+
+// TraceEventSetState executes the trace-event-set-state command.
+func TraceEventSetState(ctx context.Context, client *qmp.Client, args TraceEventSetStateArguments) error {
+	return client.Execute(ctx, "trace-event-set-state", args, nil)
+}
+
+// QueryStats executes the query-stats command.
+func QueryStats(ctx context.Context, client *qmp.Client, args QueryStatsArguments) ([]QueryStatsResult, error) {
+	var result []QueryStatsResult
+
+	err := client.Execute(ctx, "query-stats", args, &result)
+
+	return result, err
+}
@@ -0,0 +1,162 @@
+package qapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `{"return": [
+	{"name": "query-thing", "meta-type": "command", "arg-type": "1", "ret-type": "[2]"},
+	{"name": "THING_CHANGED", "meta-type": "event", "arg-type": "2"},
+	{"name": "1", "meta-type": "object", "members": [{"name": "id", "type": "str", "default": null}]},
+	{"name": "[2]", "meta-type": "array", "element-type": "2"},
+	{"name": "2", "meta-type": "object", "members": [
+		{"name": "kind", "type": "3"},
+		{"name": "size", "type": "4"}
+	], "tag": "kind", "variants": [{"case": "disk", "type": "5"}]},
+	{"name": "3", "meta-type": "enum", "values": ["disk", "nic"]},
+	{"name": "4", "meta-type": "alternate", "members": [{"type": "int"}, {"type": "str"}]},
+	{"name": "5", "meta-type": "object", "members": [{"name": "read-only", "type": "bool"}]},
+	{"name": "int", "meta-type": "builtin", "json-type": "int"},
+	{"name": "str", "meta-type": "builtin", "json-type": "string"},
+	{"name": "bool", "meta-type": "builtin", "json-type": "boolean"}
+]}`
+
+func TestParse(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	assert.NoError(t, err)
+	assert.True(t, schema.HasCommand("query-thing"))
+	assert.True(t, schema.HasEvent("THING_CHANGED"))
+	assert.Equal(t, []string{"disk", "nic"}, schema.Entity("3").Values)
+	assert.Equal(t, []string{"int", "str"}, schema.Entity("4").Alternatives)
+	assert.True(t, schema.Entity("1").Members[0].Optional)
+	assert.Equal(t, []string{"quit"}, schema.MissingCommands("query-thing", "quit"))
+}
+
+func TestCheckCommands(t *testing.T) {
+	var response struct {
+		Return json.RawMessage `json:"return"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(testSchema), &response))
+
+	server := qmptest.NewServer()
+	server.Respond("query-qmp-schema", response.Return)
+
+	client, err := server.Dial(context.Background())
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	missing, err := CheckCommands(context.Background(), client, "query-thing", "quit")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"quit"}, missing)
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	assert.NoError(t, err)
+
+	source, err := Generate(schema, GenerateOptions{Package: "test", Version: "8.2.0"})
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "ID *string `json:\"id,omitempty\"`")
+	assert.Contains(t, code, "type QueryThingResultSize = json.RawMessage")
+	assert.Regexp(t, "ReadOnly +\\*bool +`json:\"read-only,omitempty\"`", code)
+	assert.Contains(t, code, `QueryThingResultKindDisk QueryThingResultKind = "disk"`)
+	assert.Contains(t, code, `EventThingChanged = "THING_CHANGED"`)
+	assert.Contains(t, code,
+		"func QueryThing(ctx context.Context, client *qmp.Client, args QueryThingArguments) ([]QueryThingResult, error)")
+}
+
+// TestGeneratedUpToDate ensures the checked-in generated code matches the
+// pinned schema.
+func TestGeneratedUpToDate(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("generated", "schema.json"))
+	assert.NoError(t, err)
+
+	schema, err := Parse(data)
+	assert.NoError(t, err)
+
+	source, err := Generate(schema, GenerateOptions{Package: "generated", Version: "8.2.0"})
+	assert.NoError(t, err)
+
+	existing, err := os.ReadFile(filepath.Join("generated", "generated.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(existing), string(source), "run go generate ./qemu/qapi/generated")
+}
+
+// TestPinnedSchema ensures the pinned schema contains UsedCommands and every
+// type they refer to.
+func TestPinnedSchema(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("generated", "schema.json"))
+	assert.NoError(t, err)
+
+	schema, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Empty(t, schema.MissingCommands())
+
+	for _, entity := range schema.Entities() {
+		refs := append([]string{entity.ElementType, entity.ArgType, entity.RetType}, entity.Alternatives...)
+
+		for _, member := range entity.Members {
+			refs = append(refs, member.Type)
+		}
+
+		for _, variant := range entity.Variants {
+			refs = append(refs, variant.Type)
+		}
+
+		for _, ref := range refs {
+			if ref != "" {
+				assert.NotNil(t, schema.Entity(ref), "%s refers to the missing type %s", entity.Name, ref)
+			}
+		}
+	}
+}
+
+// TestUsedCommands ensures that every command executed with a literal name in
+// the qemu packages is listed in UsedCommands.
+func TestUsedCommands(t *testing.T) {
+	pattern := regexp.MustCompile(`Execute\([^,]+, "([a-z_-]+)"`)
+	used := make(map[string]bool)
+
+	for _, command := range UsedCommands {
+		used[command] = true
+	}
+
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == "generated" {
+			return filepath.SkipDir
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range pattern.FindAllStringSubmatch(string(data), -1) {
+			assert.True(t, used[match[1]], "%s calls %s, which is missing from UsedCommands", path, match[1])
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,280 @@
+// Package qapi is used to introspect the QMP schema of a running QEMU instance
+// (see query-qmp-schema), to check that it supports the commands queso calls,
+// and to generate Go types from a schema (see cmd/qapigen and the generated
+// package).
+package qapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// MetaType represents the kind of schema entity.
+type MetaType string
+
+const (
+	MetaTypeBuiltin   MetaType = "builtin"
+	MetaTypeEnum      MetaType = "enum"
+	MetaTypeArray     MetaType = "array"
+	MetaTypeObject    MetaType = "object"
+	MetaTypeAlternate MetaType = "alternate"
+	MetaTypeCommand   MetaType = "command"
+	MetaTypeEvent     MetaType = "event"
+)
+
+// Member is a member of an object type.
+type Member struct {
+	// Name is the name of the member.
+	Name string
+
+	// Type is the name of the type of the member.
+	Type string
+
+	// Optional is true if the member can be omitted.
+	Optional bool
+
+	// Features are the features of the member, such as "deprecated".
+	Features []string
+}
+
+// Variant is a variant of a union. If the tag member of the union has the
+// value Case, the members of Type are also present.
+type Variant struct {
+	Case string `json:"case"`
+	Type string `json:"type"`
+}
+
+// Entity is an entity of the schema. Most fields only apply to some meta types.
+// The names of all types except the builtin types are masked by QEMU, so they
+// are only meaningful within the schema.
+type Entity struct {
+	// Name is the name of the entity.
+	Name string
+
+	// MetaType is the kind of entity.
+	MetaType MetaType
+
+	// Features are the features of the entity, such as "deprecated".
+	Features []string
+
+	// JSONType is the JSON type of a builtin type.
+	JSONType string
+
+	// Values are the values of an enum.
+	Values []string
+
+	// ElementType is the type of the elements of an array.
+	ElementType string
+
+	// Members are the members of an object.
+	Members []Member
+
+	// Tag is the name of the member that selects the Variants of a union.
+	Tag string
+
+	// Variants are the variants of a union.
+	Variants []Variant
+
+	// Alternatives are the types of an alternate.
+	Alternatives []string
+
+	// ArgType is the type of the arguments of a command or the data of an
+	// event.
+	ArgType string
+
+	// RetType is the type of the result of a command.
+	RetType string
+}
+
+// rawEntity is the JSON representation of a SchemaInfo.
+type rawEntity struct {
+	Name        string                       `json:"name"`
+	MetaType    MetaType                     `json:"meta-type"`
+	Features    []string                     `json:"features"`
+	JSONType    string                       `json:"json-type"`
+	Values      []string                     `json:"values"`
+	ElementType string                       `json:"element-type"`
+	Members     []map[string]json.RawMessage `json:"members"`
+	Tag         string                       `json:"tag"`
+	Variants    []Variant                    `json:"variants"`
+	ArgType     string                       `json:"arg-type"`
+	RetType     string                       `json:"ret-type"`
+}
+
+// Schema is the QMP schema of a QEMU version.
+type Schema struct {
+	entities map[string]*Entity
+	order    []string
+}
+
+// Load returns the schema of the connected QEMU instance (see
+// query-qmp-schema).
+func Load(ctx context.Context, client *qmp.Client) (*Schema, error) {
+	var raw json.RawMessage
+
+	if err := client.Execute(ctx, "query-qmp-schema", nil, &raw); err != nil {
+		return nil, err
+	}
+
+	return Parse(raw)
+}
+
+// Parse parses the result of query-qmp-schema. The result can also be wrapped
+// in a QMP response (i.e. {"return": [...]}), as written by tools such as
+// qmp-shell.
+func Parse(data []byte) (*Schema, error) {
+	var response struct {
+		Return []rawEntity `json:"return"`
+	}
+
+	var raws []rawEntity
+
+	if err := json.Unmarshal(data, &raws); err != nil {
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse schema: %w", err)
+		}
+
+		raws = response.Return
+	}
+
+	schema := &Schema{entities: make(map[string]*Entity)}
+
+	for _, raw := range raws {
+		entity, err := newEntity(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		schema.entities[entity.Name] = entity
+		schema.order = append(schema.order, entity.Name)
+	}
+
+	return schema, nil
+}
+
+func newEntity(raw rawEntity) (*Entity, error) {
+	entity := &Entity{
+		Name:        raw.Name,
+		MetaType:    raw.MetaType,
+		Features:    raw.Features,
+		JSONType:    raw.JSONType,
+		Values:      raw.Values,
+		ElementType: raw.ElementType,
+		Tag:         raw.Tag,
+		Variants:    raw.Variants,
+		ArgType:     raw.ArgType,
+		RetType:     raw.RetType,
+	}
+
+	values := make([]string, 0)
+
+	for _, fields := range raw.Members {
+		var name, typeName string
+		var features []string
+
+		if value, ok := fields["name"]; ok {
+			if err := json.Unmarshal(value, &name); err != nil {
+				return nil, fmt.Errorf("invalid member of %s: %w", raw.Name, err)
+			}
+		}
+
+		if value, ok := fields["type"]; ok {
+			if err := json.Unmarshal(value, &typeName); err != nil {
+				return nil, fmt.Errorf("invalid member of %s: %w", raw.Name, err)
+			}
+		}
+
+		if value, ok := fields["features"]; ok {
+			_ = json.Unmarshal(value, &features)
+		}
+
+		switch raw.MetaType {
+		case MetaTypeEnum:
+			values = append(values, name)
+
+		case MetaTypeAlternate:
+			entity.Alternatives = append(entity.Alternatives, typeName)
+
+		default:
+			// A member is optional if it has a default value, even if the
+			// default is null.
+			_, optional := fields["default"]
+
+			entity.Members = append(entity.Members, Member{
+				Name:     name,
+				Type:     typeName,
+				Optional: optional,
+				Features: features,
+			})
+		}
+	}
+
+	// Enums list their values as members since QEMU 6.2, while the values
+	// field is deprecated.
+	if len(values) != 0 {
+		entity.Values = values
+	}
+
+	return entity, nil
+}
+
+// Entity returns the entity with the specified name, or nil if it doesn't
+// exist.
+func (s *Schema) Entity(name string) *Entity {
+	return s.entities[name]
+}
+
+// Entities returns all entities in the order of the schema.
+func (s *Schema) Entities() []*Entity {
+	entities := make([]*Entity, 0, len(s.order))
+
+	for _, name := range s.order {
+		entities = append(entities, s.entities[name])
+	}
+
+	return entities
+}
+
+// HasCommand returns true if the schema contains the command with the
+// specified name.
+func (s *Schema) HasCommand(name string) bool {
+	entity, ok := s.entities[name]
+
+	return ok && entity.MetaType == MetaTypeCommand
+}
+
+// HasEvent returns true if the schema contains the event with the specified
+// name.
+func (s *Schema) HasEvent(name string) bool {
+	entity, ok := s.entities[name]
+
+	return ok && entity.MetaType == MetaTypeEvent
+}
+
+// Commands returns the sorted names of all commands.
+func (s *Schema) Commands() []string {
+	return s.names(MetaTypeCommand)
+}
+
+// Events returns the sorted names of all events.
+func (s *Schema) Events() []string {
+	return s.names(MetaTypeEvent)
+}
+
+func (s *Schema) names(metaType MetaType) []string {
+	names := make([]string, 0)
+
+	for name, entity := range s.entities {
+		if entity.MetaType == metaType {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -1,17 +1,43 @@
 package qemu
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/qmp"
 )
 
 // QEMU represents an instance of the QEMU process.
 type QEMU struct {
-	exePath string
-	args    []string
-	cmd     *exec.Cmd
+	exePath    string
+	options    []*queso.Option
+	args       []string
+	env        []string
+	cmd        *exec.Cmd
+	process    *os.Process
+	startTime  time.Time
+	runtimeDir *RuntimeDirectory
+	qmpSocket  string
+	qmp        *qmp.Client
+	qmpMu      sync.Mutex
+	stderr     tailBuffer
+	inputOpts  InputOptions
+	recording  *ReplayOptions
+
+	// diskImages maps the disk images in the options to the overlays the VM
+	// writes to since it was last cloned.
+	diskImages map[string]string
 }
 
 // New returns a new instance of QEMU. The path parameter represents the path
@@ -25,32 +51,356 @@ func New(path string) *QEMU {
 
 // SetOptions sets the options to use for invoking QEMU.
 func (q *QEMU) SetOptions(options ...*queso.Option) {
-	args := make([]string, 0)
+	q.options = options
+}
 
-	for _, option := range options {
-		args = append(args, option.Args()...)
+// Options returns the options that will be passed to QEMU, including the
+// options required by the RuntimeDirectory (if any).
+func (q *QEMU) Options() []*queso.Option {
+	options := make([]*queso.Option, 0, len(q.options))
+	options = append(options, q.options...)
+
+	if q.runtimeDir != nil {
+		options = append(options, q.runtimeDir.Options()...)
+	}
+
+	return options
+}
+
+// SetRuntimeDirectory sets the RuntimeDirectory in which QEMU places its
+// runtime artifacts. Once QEMU exits, the directory is removed, unless QEMU
+// failed and the directory was created with the KeepOnFailure option.
+func (q *QEMU) SetRuntimeDirectory(dir *RuntimeDirectory) {
+	q.runtimeDir = dir
+}
+
+// RuntimeDirectory returns the RuntimeDirectory associated with QEMU, or nil if
+// no directory was set.
+func (q *QEMU) RuntimeDirectory() *RuntimeDirectory {
+	return q.runtimeDir
+}
+
+// SetQMPSocket sets the path to the Unix socket QEMU listens on for QMP
+// connections. This is only required if the QMP socket is not managed by a
+// RuntimeDirectory.
+func (q *QEMU) SetQMPSocket(path string) {
+	q.qmpSocket = path
+}
+
+// QMPSocket returns the path to the Unix socket QEMU listens on for QMP
+// connections, or an empty string if the socket is unknown.
+func (q *QEMU) QMPSocket() string {
+	if q.qmpSocket != "" {
+		return q.qmpSocket
 	}
 
-	q.args = args
+	if q.runtimeDir != nil {
+		return q.runtimeDir.QMPSocket()
+	}
+
+	return ""
+}
+
+// SetEnv sets additional environment variables for the QEMU process. Each
+// variable is in "key=value" form. The variables are added to the environment
+// inherited from the current process when QEMU is started, and the environment
+// of the current process is left unchanged.
+func (q *QEMU) SetEnv(env ...string) {
+	q.env = env
+}
+
+// Env returns the additional environment variables set with SetEnv.
+func (q *QEMU) Env() []string {
+	return q.env
 }
 
 // Args returns a slice of the args that will be passed to QEMU. This is
 // useful for debugging purposes.
 func (q *QEMU) Args() []string {
-	return q.args
+	if q.args != nil {
+		return q.args
+	}
+
+	args := make([]string, 0)
+
+	for _, option := range q.Options() {
+		args = append(args, option.Args()...)
+	}
+
+	return args
 }
 
 // Cmd returns the exec.Cmd instance for QEMU.
 func (q *QEMU) Cmd() *exec.Cmd {
-	q.cmd = exec.Command(q.exePath, q.args...)
+	q.cmd = exec.Command(q.exePath, q.Args()...)
+
+	if len(q.env) != 0 {
+		q.cmd.Env = append(os.Environ(), q.env...)
+	}
 
 	return q.cmd
 }
 
-// Run starts the QEMU executable.
+// Start starts the QEMU executable but does not wait for it to exit. If the
+// exec.Cmd wasn't created with Cmd, it is created automatically.
+//
+// If the Daemonize option is used, Start waits until QEMU has detached (which
+// it only does once it is ready to receive connections) and reads the PID of
+// the daemon from the file specified with the UsePIDFile option.
+//
+// If QEMU has a RuntimeDirectory, a Record is saved to the directory once
+// QEMU is started, so it can be reattached with Attach later.
+func (q *QEMU) Start() error {
+	if q.cmd == nil {
+		q.Cmd()
+	}
+
+	if q.cmd.Stdout == nil {
+		q.cmd.Stdout = os.Stdout
+	}
+
+	// Stderr is always captured, so error messages can be parsed if QEMU fails
+	// (see Error).
+	if q.cmd.Stderr == nil {
+		q.cmd.Stderr = io.MultiWriter(os.Stderr, &q.stderr)
+	} else {
+		q.cmd.Stderr = io.MultiWriter(q.cmd.Stderr, &q.stderr)
+	}
+
+	if err := q.cmd.Start(); err != nil {
+		return err
+	}
+
+	q.startTime = time.Now()
+	q.process = q.cmd.Process
+
+	if q.HasOption("daemonize") {
+		if err := q.cmd.Wait(); err != nil {
+			return q.wrapError(err)
+		}
+
+		process, err := q.daemonProcess()
+		if err != nil {
+			return err
+		}
+
+		q.process = process
+	}
+
+	if q.runtimeDir != nil {
+		record, err := q.Record()
+		if err != nil {
+			return err
+		}
+
+		if err := record.Save(q.runtimeDir.RecordFile()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Wait waits for the QEMU process started with Start (or reattached with
+// Attach) to exit, closes the QMP connection and cleans up the RuntimeDirectory
+// (if any). If QEMU fails and printed error messages to stderr, the returned
+// error is an *Error that contains the parsed messages. The exit status of a
+// daemonized or reattached process is unknown, so Wait only returns an error
+// for those if waiting fails.
+func (q *QEMU) Wait() error {
+	if q.process == nil {
+		return errors.New("qemu has not been started")
+	}
+
+	var err error
+
+	if q.cmd != nil && q.process == q.cmd.Process {
+		err = q.wrapError(q.cmd.Wait())
+	} else {
+		err = waitForProcess(q.process)
+	}
+
+	q.qmpMu.Lock()
+	if q.qmp != nil {
+		_ = q.qmp.Close()
+	}
+	q.qmpMu.Unlock()
+
+	if q.runtimeDir != nil {
+		if cleanupErr := q.runtimeDir.cleanup(err); cleanupErr != nil && err == nil {
+			err = cleanupErr
+		}
+	}
+
+	return err
+}
+
+// Run starts the QEMU executable and waits for it to exit.
 func (q *QEMU) Run() error {
-	q.cmd.Stdout = os.Stdout
-	q.cmd.Stderr = os.Stderr
+	if err := q.Start(); err != nil {
+		return err
+	}
+
+	return q.Wait()
+}
+
+// PID returns the process ID of QEMU, or 0 if QEMU hasn't been started.
+func (q *QEMU) PID() int {
+	if q.process == nil {
+		return 0
+	}
+
+	return q.process.Pid
+}
+
+// StartTime returns the time at which QEMU was started.
+func (q *QEMU) StartTime() time.Time {
+	return q.startTime
+}
+
+// Kill causes the QEMU process to exit immediately.
+func (q *QEMU) Kill() error {
+	if q.process == nil {
+		return errors.New("qemu has not been started")
+	}
+
+	return q.process.Kill()
+}
+
+// ConnectQMP connects to the QMP socket of the running QEMU instance. Because
+// QEMU may not be listening yet right after it is started, connecting is retried
+// until it succeeds, the context is done or the QEMU process exits. It is safe
+// to call ConnectQMP concurrently, all callers share the same client.
+func (q *QEMU) ConnectQMP(ctx context.Context) (*qmp.Client, error) {
+	q.qmpMu.Lock()
+	defer q.qmpMu.Unlock()
+
+	if q.qmp != nil && q.qmp.Err() == nil {
+		return q.qmp, nil
+	}
+
+	socket := q.QMPSocket()
+	if socket == "" {
+		return nil, errors.New("no QMP socket is configured")
+	}
+
+	for {
+		client, err := qmp.Dial(ctx, "unix", socket)
+		if err == nil {
+			q.qmp = client
+
+			return client, nil
+		}
+
+		if q.process != nil && processExited(q.process) {
+			return nil, fmt.Errorf("qemu exited before connecting to QMP socket %s: %w", socket, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to QMP socket %s: %w", socket, err)
+
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// QMP returns the QMP client connected with ConnectQMP, or nil if QMP isn't
+// connected.
+func (q *QEMU) QMP() *qmp.Client {
+	q.qmpMu.Lock()
+	defer q.qmpMu.Unlock()
+
+	return q.qmp
+}
+
+// Quit asks QEMU to exit over QMP and waits for it to exit.
+func (q *QEMU) Quit(ctx context.Context) error {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Execute(ctx, "quit", nil, nil); err != nil && !errors.Is(err, qmp.ErrClosed) {
+		return err
+	}
+
+	return q.Wait()
+}
+
+// HasOption returns true if an option with the specified flag will be passed
+// to QEMU.
+func (q *QEMU) HasOption(flag string) bool {
+	return q.FindOption(flag) != nil
+}
+
+// FindOption returns the first option with the specified flag that will be
+// passed to QEMU, or nil if no option with the flag exists.
+func (q *QEMU) FindOption(flag string) *queso.Option {
+	for _, option := range q.Options() {
+		if option.Flag == flag {
+			return option
+		}
+	}
+
+	return nil
+}
+
+// daemonProcess returns the process of a daemonized QEMU instance based on the
+// contents of the PID file.
+func (q *QEMU) daemonProcess() (*os.Process, error) {
+	option := q.FindOption("pidfile")
+	if option == nil {
+		return nil, errors.New("the UsePIDFile option is required to track a daemonized QEMU")
+	}
+
+	contents, err := os.ReadFile(option.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PID file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PID file %s: %w", option.Name, err)
+	}
+
+	return os.FindProcess(pid)
+}
+
+// waitForProcess waits for a process that isn't a child of this process to
+// exit by periodically checking whether it still exists.
+func waitForProcess(process *os.Process) error {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !processExists(process) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func processExists(process *os.Process) bool {
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// processExited returns true if the process has exited. A child process that
+// exited but wasn't waited for is a zombie, which still exists, so its state is
+// read from /proc (if available).
+func processExited(process *os.Process) bool {
+	if !processExists(process) {
+		return true
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", process.Pid))
+	if err != nil {
+		return false
+	}
+
+	// The state follows the name of the command, which is in parentheses.
+	end := bytes.LastIndexByte(stat, ')')
 
-	return q.cmd.Run()
+	return end != -1 && end+2 < len(stat) && stat[end+2] == 'Z'
 }
@@ -0,0 +1,50 @@
+package qemu
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	qmptest.FakeQEMUMain()
+	os.Exit(m.Run())
+}
+
+func TestFakeQEMU(t *testing.T) {
+	fake := qmptest.NewFakeQEMU(t)
+	assert.NoError(t, fake.Respond("query-balloon", map[string]interface{}{"actual": 1 << 30}))
+
+	dir, err := NewRuntimeDirectory(RuntimeDirectoryOptions{Parent: t.TempDir(), Name: "fake"})
+	assert.NoError(t, err)
+
+	q := New(fake.Path)
+	q.SetRuntimeDirectory(dir)
+	q.SetEnv(fake.Env...)
+	q.SetOptions(Memory("1G"))
+
+	assert.NoError(t, q.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := q.ConnectQMP(ctx)
+	assert.NoError(t, err)
+
+	var balloon struct {
+		Actual int64 `json:"actual"`
+	}
+
+	assert.NoError(t, client.Execute(ctx, "query-balloon", nil, &balloon))
+	assert.Equal(t, int64(1<<30), balloon.Actual)
+
+	assert.NoError(t, q.Quit(ctx))
+
+	args, err := fake.Args()
+	assert.NoError(t, err)
+	assert.Equal(t, q.Args(), args)
+}
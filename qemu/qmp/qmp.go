@@ -0,0 +1,470 @@
+// Package qmp is a client for the QEMU Machine Protocol (QMP), which is used
+// to control a running QEMU instance. See
+// https://qemu.readthedocs.io/en/latest/interop/qmp-spec.html for more details.
+package qmp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso/internal/queue"
+)
+
+// ErrClosed is returned when a command is executed on a Client whose connection
+// is closed.
+var ErrClosed = errors.New("qmp: connection closed")
+
+// Version represents the QEMU version reported in the QMP greeting.
+type Version struct {
+	QEMU struct {
+		Major int `json:"major"`
+		Minor int `json:"minor"`
+		Micro int `json:"micro"`
+	} `json:"qemu"`
+	Package string `json:"package"`
+}
+
+// String returns the version in "major.minor.micro" format.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.QEMU.Major, v.QEMU.Minor, v.QEMU.Micro)
+}
+
+// AtLeast returns true if the version is greater than or equal to the specified
+// version.
+func (v Version) AtLeast(major int, minor int, micro int) bool {
+	if v.QEMU.Major != major {
+		return v.QEMU.Major > major
+	}
+
+	if v.QEMU.Minor != minor {
+		return v.QEMU.Minor > minor
+	}
+
+	return v.QEMU.Micro >= micro
+}
+
+// Greeting is the message QEMU sends when a client connects.
+type Greeting struct {
+	QMP struct {
+		Version      Version  `json:"version"`
+		Capabilities []string `json:"capabilities"`
+	} `json:"QMP"`
+}
+
+// Error is returned when QEMU responds to a command with an error.
+type Error struct {
+	// Class is the error class, such as "GenericError" or "CommandNotFound".
+	Class string `json:"class"`
+
+	// Description is the human-readable description of the error.
+	Description string `json:"desc"`
+}
+
+// Error returns the error class and description.
+func (e *Error) Error() string {
+	return fmt.Sprintf("qmp: %s: %s", e.Class, e.Description)
+}
+
+// Timestamp represents the time at which an Event was emitted.
+type Timestamp struct {
+	Seconds      int64 `json:"seconds"`
+	Microseconds int64 `json:"microseconds"`
+}
+
+// Time returns the Timestamp as a time.Time.
+func (t Timestamp) Time() time.Time {
+	return time.Unix(t.Seconds, t.Microseconds*int64(time.Microsecond))
+}
+
+// Event represents an asynchronous event emitted by QEMU.
+type Event struct {
+	// Name is the name of the event, such as "SHUTDOWN".
+	Name string `json:"event"`
+
+	// Data contains the event-specific data. Use DecodeData to decode it.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// Timestamp is the time the event was emitted.
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// DecodeData decodes the event data into the specified value.
+func (e Event) DecodeData(v interface{}) error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(e.Data, v)
+}
+
+type request struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+	ID        string      `json:"id"`
+}
+
+type message struct {
+	Event  string          `json:"event"`
+	Return json.RawMessage `json:"return"`
+	Error  *Error          `json:"error"`
+	ID     string          `json:"id"`
+
+	Data      json.RawMessage `json:"data"`
+	Timestamp Timestamp       `json:"timestamp"`
+}
+
+// Client is a QMP client connected to a QEMU instance. A Client is safe for
+// concurrent use.
+type Client struct {
+	conn     net.Conn
+	greeting Greeting
+
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	nextID        uint64
+	pending       map[string]chan message
+	subscriptions map[*subscription]struct{}
+	err           error
+
+	done chan struct{}
+}
+
+// Dial connects to the QMP server at the specified address and performs the
+// capabilities negotiation. The network is typically "unix" or "tcp".
+func Dial(ctx context.Context, network string, address string) (*Client, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(ctx, conn)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// NewClient returns a new Client that communicates over the specified connection.
+// The QMP greeting is read and the capabilities negotiation is performed before
+// the Client is returned.
+func NewClient(ctx context.Context, conn net.Conn) (*Client, error) {
+	reader := bufio.NewReader(conn)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetReadDeadline(deadline)
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("qmp: failed to read greeting: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Time{})
+
+	c := &Client{
+		conn:          conn,
+		pending:       make(map[string]chan message),
+		subscriptions: make(map[*subscription]struct{}),
+		done:          make(chan struct{}),
+	}
+
+	if err := json.Unmarshal(line, &c.greeting); err != nil {
+		return nil, fmt.Errorf("qmp: invalid greeting: %w", err)
+	}
+
+	go c.read(reader)
+
+	if err := c.Execute(ctx, "qmp_capabilities", nil, nil); err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Greeting returns the greeting QEMU sent when the Client connected.
+func (c *Client) Greeting() Greeting {
+	return c.greeting
+}
+
+// Execute executes the specified command with the specified arguments. The
+// arguments can be nil or any value that encodes to a JSON object. If result is
+// not nil, the value QEMU returns is decoded into it.
+func (c *Client) Execute(
+	ctx context.Context,
+	command string,
+	arguments interface{},
+	result interface{},
+) error {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+
+		return c.err
+	}
+
+	c.nextID++
+	id := strconv.FormatUint(c.nextID, 10)
+	responses := make(chan message, 1)
+	c.pending[id] = responses
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	payload, err := json.Marshal(request{
+		Execute:   command,
+		Arguments: arguments,
+		ID:        id,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	_, err = c.conn.Write(append(payload, '\n'))
+	c.writeMu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("qmp: failed to send %s: %w", command, err)
+	}
+
+	select {
+	case response := <-responses:
+		return decodeResponse(command, response, result)
+
+	case <-c.done:
+		// The response may have arrived right before the connection was
+		// closed (e.g. for the "quit" command).
+		select {
+		case response := <-responses:
+			return decodeResponse(command, response, result)
+
+		default:
+			return c.Err()
+		}
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func decodeResponse(command string, response message, result interface{}) error {
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if result != nil && len(response.Return) != 0 {
+		if err := json.Unmarshal(response.Return, result); err != nil {
+			return fmt.Errorf("qmp: failed to decode %s result: %w", command, err)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel that receives the events with the specified names.
+// If no names are specified, all events are received. Events are buffered, so
+// a slow receiver doesn't block the Client. The channel is closed when the
+// returned cancel function is called or the connection is closed.
+func (c *Client) Subscribe(names ...string) (<-chan Event, func()) {
+	sub := newSubscription(names)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		sub.finish()
+
+		return sub.out, func() {}
+	}
+
+	c.subscriptions[sub] = struct{}{}
+	c.mu.Unlock()
+
+	var once sync.Once
+
+	cancel := func() {
+		once.Do(func() {
+			c.mu.Lock()
+			delete(c.subscriptions, sub)
+			c.mu.Unlock()
+
+			sub.cancel()
+		})
+	}
+
+	return sub.out, cancel
+}
+
+// WaitForEvent waits for the first event with one of the specified names and
+// returns it.
+func (c *Client) WaitForEvent(ctx context.Context, names ...string) (Event, error) {
+	events, cancel := c.Subscribe(names...)
+	defer cancel()
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			return Event{}, c.Err()
+		}
+
+		return event, nil
+
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection was closed, or nil if it is still open.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Close closes the connection to QEMU.
+func (c *Client) Close() error {
+	err := c.conn.Close()
+
+	c.shutdown(ErrClosed)
+
+	return err
+}
+
+func (c *Client) read(reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			c.shutdown(fmt.Errorf("%w: %v", ErrClosed, err))
+
+			return
+		}
+
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+
+		if msg.Event != "" {
+			c.dispatch(Event{
+				Name:      msg.Event,
+				Data:      msg.Data,
+				Timestamp: msg.Timestamp,
+			})
+
+			continue
+		}
+
+		c.mu.Lock()
+		responses, ok := c.pending[msg.ID]
+		c.mu.Unlock()
+
+		if ok {
+			responses <- msg
+		}
+	}
+}
+
+func (c *Client) dispatch(event Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for sub := range c.subscriptions {
+		sub.send(event)
+	}
+}
+
+func (c *Client) shutdown(reason error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = reason
+	close(c.done)
+
+	for sub := range c.subscriptions {
+		sub.finish()
+		delete(c.subscriptions, sub)
+	}
+}
+
+// subscription queues events for a subscriber without limit, so the reader
+// goroutine never blocks on a slow subscriber.
+type subscription struct {
+	names map[string]bool
+	out   chan Event
+	queue *queue.Queue
+}
+
+func newSubscription(names []string) *subscription {
+	sub := &subscription{
+		names: make(map[string]bool),
+		out:   make(chan Event),
+		queue: queue.New(),
+	}
+
+	for _, name := range names {
+		sub.names[name] = true
+	}
+
+	go func() {
+		defer close(sub.out)
+
+		sub.queue.Run(func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case sub.out <- value.(Event):
+				return true
+
+			case <-quit:
+				return false
+			}
+		})
+	}()
+
+	return sub
+}
+
+func (s *subscription) send(event Event) {
+	if len(s.names) != 0 && !s.names[event.Name] {
+		return
+	}
+
+	s.queue.Push(event)
+}
+
+// finish stops queueing new events. Events that are already queued are still
+// delivered before the channel is closed.
+func (s *subscription) finish() {
+	s.queue.Finish()
+}
+
+// cancel stops delivering events and closes the channel.
+func (s *subscription) cancel() {
+	s.queue.Cancel()
+}
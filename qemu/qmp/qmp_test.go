@@ -0,0 +1,55 @@
+package qmp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	server := qmptest.NewServer()
+	server.SetVersion(7, 2, 0)
+
+	server.Handle("query-status", func(map[string]interface{}) (interface{}, error) {
+		_ = server.Emit("RESUME", nil)
+
+		return map[string]interface{}{"status": "running", "running": true}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "7.2.0", client.Greeting().QMP.Version.String())
+
+	events, unsubscribe := client.Subscribe("RESUME")
+	defer unsubscribe()
+
+	var status struct {
+		Status  string `json:"status"`
+		Running bool   `json:"running"`
+	}
+
+	err = client.Execute(ctx, "query-status", nil, &status)
+	assert.NoError(t, err)
+	assert.Equal(t, "running", status.Status)
+	assert.True(t, status.Running)
+
+	event := <-events
+	assert.Equal(t, "RESUME", event.Name)
+	assert.NotZero(t, event.Timestamp.Seconds)
+
+	err = client.Execute(ctx, "bogus", nil, nil)
+	assert.Equal(t, "CommandNotFound", err.(*qmp.Error).Class)
+
+	assert.NoError(t, client.Close())
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.ErrorIs(t, client.Execute(ctx, "query-status", nil, nil), qmp.ErrClosed)
+}
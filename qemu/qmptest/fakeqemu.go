@@ -0,0 +1,244 @@
+package qmptest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// fakeQEMUEnv is set to the directory of a FakeQEMU, so FakeQEMUMain knows
+	// the test binary was started as a fake QEMU.
+	fakeQEMUEnv = "QMPTEST_FAKE_QEMU_DIR"
+
+	fakeArgsFile      = "args.json"
+	fakeResponsesFile = "responses.json"
+)
+
+// FakeQEMU is a fake qemu-system-* executable that records its arguments and
+// serves QMP with a Server on the socket specified with the "-qmp" option
+// (e.g. the socket of a qemu.RuntimeDirectory). It exits when it receives the
+// quit command. If the "-pidfile" option is specified, its PID is written to
+// the file.
+//
+// The system_powerdown and system_reset commands emulate a guest that shuts
+// down or is reset: system_powerdown emits SHUTDOWN and exits, unless the
+// "-no-shutdown" option is specified, and system_reset emits RESET, or emits
+// SHUTDOWN and exits if the "-no-reboot" option is specified.
+//
+// The fake executable is the test binary itself, so the TestMain function of
+// the package must call FakeQEMUMain first.
+//
+// Example
+//
+//	func TestMain(m *testing.M) {
+//		qmptest.FakeQEMUMain()
+//		os.Exit(m.Run())
+//	}
+//
+//	func TestStart(t *testing.T) {
+//		fake := qmptest.NewFakeQEMU(t)
+//		fake.Respond("query-balloon", map[string]interface{}{"actual": 1 << 30})
+//
+//		q := qemu.New(fake.Path)
+//		q.SetEnv(fake.Env...)
+//	}
+type FakeQEMU struct {
+	// Path is the path to the fake executable.
+	Path string
+
+	// Env contains the environment variables that must be set for the fake
+	// executable (see qemu.QEMU.SetEnv).
+	Env []string
+
+	dir       string
+	responses map[string]interface{}
+}
+
+// NewFakeQEMU returns a new FakeQEMU whose files are stored in a temporary
+// directory of the test.
+func NewFakeQEMU(t testing.TB) *FakeQEMU {
+	t.Helper()
+
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find test executable: %v", err)
+	}
+
+	dir := t.TempDir()
+
+	return &FakeQEMU{
+		Path:      path,
+		Env:       []string{fakeQEMUEnv + "=" + dir},
+		dir:       dir,
+		responses: make(map[string]interface{}),
+	}
+}
+
+// Respond registers a canned result for the specified command. It must be
+// called before the fake executable is started.
+func (f *FakeQEMU) Respond(command string, result interface{}) error {
+	f.responses[command] = result
+
+	data, err := json.Marshal(f.responses)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(f.dir, fakeResponsesFile), data, 0o600)
+}
+
+// Args returns the arguments the fake executable was started with.
+func (f *FakeQEMU) Args() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, fakeArgsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// FakeQEMUMain runs the fake QEMU executable and exits if the test binary was
+// started by a FakeQEMU. Otherwise, it returns immediately.
+func FakeQEMUMain() {
+	dir := os.Getenv(fakeQEMUEnv)
+	if dir == "" {
+		return
+	}
+
+	if err := runFakeQEMU(dir, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "qemu-system-fake: %v\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func runFakeQEMU(dir string, args []string) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fakeArgsFile), data, 0o600); err != nil {
+		return err
+	}
+
+	socket, pidFile := parseFakeArgs(args)
+
+	if pidFile != "" {
+		if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600); err != nil {
+			return err
+		}
+	}
+
+	if socket == "" {
+		return errors.New("no QMP socket specified with -qmp unix:<path>")
+	}
+
+	server := NewServer()
+
+	quit := make(chan struct{})
+
+	var once sync.Once
+
+	exit := func() {
+		once.Do(func() {
+			close(quit)
+		})
+	}
+
+	server.OnQuit = exit
+
+	// The fake exits after the response to the command is sent.
+	exitLater := func() {
+		time.AfterFunc(50*time.Millisecond, exit)
+	}
+
+	server.Handle("system_powerdown", func(map[string]interface{}) (interface{}, error) {
+		_ = server.Emit("POWERDOWN", nil)
+		_ = server.Emit("SHUTDOWN", map[string]interface{}{"guest": true, "reason": "guest-shutdown"})
+
+		if !hasFlag(args, "-no-shutdown") {
+			exitLater()
+		}
+
+		return nil, nil
+	})
+
+	server.Handle("system_reset", func(map[string]interface{}) (interface{}, error) {
+		data := map[string]interface{}{"guest": false, "reason": "host-qmp-system-reset"}
+
+		if hasFlag(args, "-no-reboot") {
+			_ = server.Emit("SHUTDOWN", data)
+			exitLater()
+		} else {
+			_ = server.Emit("RESET", data)
+		}
+
+		return nil, nil
+	})
+
+	// Canned responses replace the emulated commands.
+	if data, err := os.ReadFile(filepath.Join(dir, fakeResponsesFile)); err == nil {
+		responses := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return err
+		}
+
+		for command, result := range responses {
+			server.Respond(command, result)
+		}
+	}
+
+	if err := server.Listen(socket); err != nil {
+		return err
+	}
+
+	<-quit
+
+	return server.Close()
+}
+
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFakeArgs returns the path to the QMP socket and the PID file from the
+// QEMU arguments.
+func parseFakeArgs(args []string) (string, string) {
+	socket, pidFile := "", ""
+
+	for index := 0; index+1 < len(args); index++ {
+		switch args[index] {
+		case "-qmp":
+			value := strings.Split(args[index+1], ",")[0]
+			if strings.HasPrefix(value, "unix:") {
+				socket = strings.TrimPrefix(value, "unix:")
+			}
+
+		case "-pidfile":
+			pidFile = args[index+1]
+		}
+	}
+
+	return socket, pidFile
+}
@@ -0,0 +1,446 @@
+// Package qmptest provides an in-process QMP server and a fake QEMU executable
+// for testing code that controls QEMU without starting a real QEMU instance.
+package qmptest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// HandlerFunc handles a command with the specified arguments and returns its
+// result. If it returns a *qmp.Error, its class and description are sent to
+// the client. Any other error is sent as a GenericError.
+type HandlerFunc func(args map[string]interface{}) (interface{}, error)
+
+// Command is a command the Server received.
+type Command struct {
+	// Name is the name of the command.
+	Name string
+
+	// Arguments are the arguments of the command.
+	Arguments map[string]interface{}
+}
+
+// Server is a QMP server that serves canned responses. It performs the
+// greeting and capabilities negotiation like QEMU, records the commands it
+// receives, and emits events to the connected clients.
+//
+// The following commands are handled by default, unless a handler is
+// registered for them:
+//
+//   - qmp_capabilities: completes the negotiation
+//   - query-status: returns the run state, which is changed by stop and cont
+//   - stop and cont: change the run state and emit STOP and RESUME
+//   - quit: emits SHUTDOWN, closes the connection and calls the OnQuit
+//     function (if any)
+//
+// Other commands fail with a CommandNotFound error.
+//
+// Example
+//
+//	server := qmptest.NewServer()
+//	server.Respond("query-balloon", map[string]interface{}{"actual": 1 << 30})
+//
+//	client, err := server.Dial(ctx)
+type Server struct {
+	// OnQuit is called when a client executes the quit command.
+	OnQuit func()
+
+	mu       sync.Mutex
+	greeting qmp.Greeting
+	handlers map[string]HandlerFunc
+	commands []Command
+	conns    map[*conn]struct{}
+	running  bool
+	received chan struct{}
+	listener net.Listener
+}
+
+// conn is a connection to a client.
+type conn struct {
+	net.Conn
+
+	writeMu    sync.Mutex
+	negotiated bool
+}
+
+func (c *conn) send(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.Write(append(data, '\n'))
+
+	return err
+}
+
+func (c *conn) setNegotiated() {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.negotiated = true
+}
+
+func (c *conn) isNegotiated() bool {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.negotiated
+}
+
+// NewServer returns a new Server that reports QEMU 8.2.0 in its greeting.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]HandlerFunc),
+		conns:    make(map[*conn]struct{}),
+		running:  true,
+		received: make(chan struct{}),
+	}
+
+	s.SetVersion(8, 2, 0)
+
+	return s
+}
+
+// SetVersion sets the QEMU version reported in the greeting.
+func (s *Server) SetVersion(major int, minor int, micro int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.greeting.QMP.Version.QEMU.Major = major
+	s.greeting.QMP.Version.QEMU.Minor = minor
+	s.greeting.QMP.Version.QEMU.Micro = micro
+	s.greeting.QMP.Capabilities = []string{}
+}
+
+// Handle registers a handler for the specified command, which replaces any
+// previous handler (or default behavior) of the command.
+func (s *Server) Handle(command string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[command] = handler
+}
+
+// Respond registers a canned result for the specified command.
+func (s *Server) Respond(command string, result interface{}) {
+	s.Handle(command, func(map[string]interface{}) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// RespondError registers a canned error for the specified command.
+func (s *Server) RespondError(command string, class string, description string) {
+	s.Handle(command, func(map[string]interface{}) (interface{}, error) {
+		return nil, &qmp.Error{Class: class, Description: description}
+	})
+}
+
+// Commands returns the commands the Server received (excluding
+// qmp_capabilities) in the order they were received.
+func (s *Server) Commands() []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commands := make([]Command, len(s.commands))
+	copy(commands, s.commands)
+
+	return commands
+}
+
+// WaitForCommand waits until the Server receives the specified command (or
+// has already received it) and returns the first one received.
+func (s *Server) WaitForCommand(ctx context.Context, name string) (Command, error) {
+	for {
+		s.mu.Lock()
+		received := s.received
+
+		for _, command := range s.commands {
+			if command.Name == name {
+				s.mu.Unlock()
+
+				return command, nil
+			}
+		}
+
+		s.mu.Unlock()
+
+		select {
+		case <-received:
+
+		case <-ctx.Done():
+			return Command{}, fmt.Errorf("command %s not received: %w", name, ctx.Err())
+		}
+	}
+}
+
+// Emit sends an event with the specified name and data to all clients that
+// completed the capabilities negotiation.
+func (s *Server) Emit(name string, data interface{}) error {
+	now := time.Now()
+
+	event := map[string]interface{}{
+		"event": name,
+		"timestamp": map[string]int64{
+			"seconds":      now.Unix(),
+			"microseconds": int64(now.Nanosecond() / 1000),
+		},
+	}
+
+	if data != nil {
+		event["data"] = data
+	}
+
+	var firstErr error
+
+	for _, c := range s.connections() {
+		if !c.isNegotiated() {
+			continue
+		}
+
+		if err := c.send(event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Disconnect closes the connections to all clients, which simulates QEMU
+// exiting or crashing. The Server keeps accepting new connections.
+func (s *Server) Disconnect() {
+	for _, c := range s.connections() {
+		_ = c.Close()
+	}
+}
+
+// Listen listens for connections on the Unix socket at the specified path and
+// serves them in the background until the Server is closed.
+func (s *Server) Listen(path string) error {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.ServeConn(c)
+		}
+	}()
+
+	return nil
+}
+
+// Dial connects a qmp.Client to the Server in-process.
+func (s *Server) Dial(ctx context.Context) (*qmp.Client, error) {
+	server, client := net.Pipe()
+
+	go s.ServeConn(server)
+
+	return qmp.NewClient(ctx, client)
+}
+
+// Close stops listening and disconnects all clients.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+
+	s.Disconnect()
+
+	if listener != nil {
+		return listener.Close()
+	}
+
+	return nil
+}
+
+// ServeConn serves the specified connection until it is closed.
+func (s *Server) ServeConn(netConn net.Conn) {
+	c := &conn{Conn: netConn}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	greeting := s.greeting
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+
+		_ = c.Close()
+	}()
+
+	if err := c.send(greeting); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var req struct {
+			Execute   string                 `json:"execute"`
+			Arguments map[string]interface{} `json:"arguments"`
+			ID        interface{}            `json:"id"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = c.send(errorResponse(nil, &qmp.Error{Class: "GenericError", Description: "JSON parse error"}))
+
+			continue
+		}
+
+		if !s.handle(c, req.Execute, req.Arguments, req.ID) {
+			return
+		}
+	}
+}
+
+// handle executes a command and sends the response. It returns false if the
+// connection must be closed.
+func (s *Server) handle(c *conn, name string, args map[string]interface{}, id interface{}) bool {
+	if name == "qmp_capabilities" && !c.isNegotiated() {
+		c.setNegotiated()
+
+		return c.send(response(id, map[string]interface{}{})) == nil
+	}
+
+	if !c.isNegotiated() {
+		return c.send(errorResponse(id, &qmp.Error{
+			Class:       "CommandNotFound",
+			Description: "Expecting capabilities negotiation with 'qmp_capabilities'",
+		})) == nil
+	}
+
+	s.mu.Lock()
+	s.commands = append(s.commands, Command{Name: name, Arguments: args})
+	close(s.received)
+	s.received = make(chan struct{})
+	handler, ok := s.handlers[name]
+	s.mu.Unlock()
+
+	if ok {
+		result, err := handler(args)
+		if err != nil {
+			return c.send(errorResponse(id, err)) == nil
+		}
+
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+
+		return c.send(response(id, result)) == nil
+	}
+
+	return s.handleDefault(c, name, id)
+}
+
+func (s *Server) handleDefault(c *conn, name string, id interface{}) bool {
+	switch name {
+	case "query-status":
+		s.mu.Lock()
+		running := s.running
+		s.mu.Unlock()
+
+		status := "paused"
+		if running {
+			status = "running"
+		}
+
+		return c.send(response(id, map[string]interface{}{"running": running, "status": status})) == nil
+
+	case "stop", "cont":
+		s.mu.Lock()
+		s.running = name == "cont"
+		s.mu.Unlock()
+
+		if err := c.send(response(id, map[string]interface{}{})); err != nil {
+			return false
+		}
+
+		event := "STOP"
+		if name == "cont" {
+			event = "RESUME"
+		}
+
+		_ = s.Emit(event, nil)
+
+		return true
+
+	case "quit":
+		_ = c.send(response(id, map[string]interface{}{}))
+		_ = s.Emit("SHUTDOWN", map[string]interface{}{"guest": false, "reason": "host-qmp-quit"})
+
+		if s.OnQuit != nil {
+			s.OnQuit()
+		}
+
+		return false
+	}
+
+	return c.send(errorResponse(id, &qmp.Error{
+		Class:       "CommandNotFound",
+		Description: fmt.Sprintf("The command %s has not been found", name),
+	})) == nil
+}
+
+func (s *Server) connections() []*conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+
+	return conns
+}
+
+func response(id interface{}, result interface{}) map[string]interface{} {
+	resp := map[string]interface{}{"return": result}
+
+	if id != nil {
+		resp["id"] = id
+	}
+
+	return resp
+}
+
+func errorResponse(id interface{}, err error) map[string]interface{} {
+	var qmpErr *qmp.Error
+
+	if !errors.As(err, &qmpErr) {
+		qmpErr = &qmp.Error{Class: "GenericError", Description: err.Error()}
+	}
+
+	resp := map[string]interface{}{"error": qmpErr}
+
+	if id != nil {
+		resp["id"] = id
+	}
+
+	return resp
+}
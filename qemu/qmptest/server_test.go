@@ -0,0 +1,60 @@
+package qmptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	server.Respond("query-balloon", map[string]interface{}{"actual": 1024})
+	server.RespondError("migrate", "GenericError", "migration is disabled")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+	assert.True(t, client.Greeting().QMP.Version.AtLeast(8, 2, 0))
+
+	var balloon struct {
+		Actual int64 `json:"actual"`
+	}
+
+	assert.NoError(t, client.Execute(ctx, "query-balloon", nil, &balloon))
+	assert.Equal(t, int64(1024), balloon.Actual)
+
+	var qmpErr *qmp.Error
+
+	err = client.Execute(ctx, "migrate", map[string]interface{}{"uri": "tcp:host:4444"}, nil)
+	assert.True(t, errors.As(err, &qmpErr))
+	assert.Equal(t, "migration is disabled", qmpErr.Description)
+
+	err = client.Execute(ctx, "query-unknown", nil, nil)
+	assert.True(t, errors.As(err, &qmpErr))
+	assert.Equal(t, "CommandNotFound", qmpErr.Class)
+
+	events, cancelEvents := client.Subscribe("STOP")
+	defer cancelEvents()
+
+	assert.NoError(t, client.Execute(ctx, "stop", nil, nil))
+	assert.Equal(t, "STOP", (<-events).Name)
+
+	command, err := server.WaitForCommand(ctx, "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp:host:4444", command.Arguments["uri"])
+	assert.Len(t, server.Commands(), 4)
+
+	server.Disconnect()
+
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+		t.Fatal("client was not disconnected")
+	}
+}
@@ -0,0 +1,237 @@
+// Package qom is used to browse the QEMU Object Model (QOM) tree of a running
+// QEMU instance and to read and write the properties of its objects over QMP.
+// Many runtime settings are only available as QOM properties. See
+// https://qemu.readthedocs.io/en/latest/devel/qom.html for more details.
+package qom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+const (
+	// MachinePath is the root of the machine, which contains its devices.
+	MachinePath = "/machine"
+
+	// ObjectsPath contains the objects created with -object or object-add,
+	// such as the objects of the object package.
+	ObjectsPath = "/objects"
+
+	// PeripheralPath contains the devices created with an ID.
+	PeripheralPath = "/machine/peripheral"
+
+	// AnonymousPeripheralPath contains the devices created without an ID.
+	AnonymousPeripheralPath = "/machine/peripheral-anon"
+)
+
+// ErrNotFound is returned by Resolve if no device or object has the specified
+// ID.
+var ErrNotFound = errors.New("qom: not found")
+
+// Property describes a property of an object (see qom-list).
+type Property struct {
+	// Name is the name of the property.
+	Name string `json:"name"`
+
+	// Type is the type of the property, such as "bool", "uint32", "string",
+	// "child<pc-dimm>" or "link<memory-backend>".
+	Type string `json:"type"`
+
+	// Description is the description of the property, if available.
+	Description string `json:"description,omitempty"`
+
+	// DefaultValue is the default value of the property, if available.
+	DefaultValue interface{} `json:"default-value,omitempty"`
+}
+
+// IsChild returns true if the property is a child object, which is part of
+// the QOM tree below the object.
+func (p Property) IsChild() bool {
+	return strings.HasPrefix(p.Type, "child<")
+}
+
+// IsLink returns true if the property is a link to another object.
+func (p Property) IsLink() bool {
+	return strings.HasPrefix(p.Type, "link<")
+}
+
+// TargetType returns the type of the object a child or link property refers
+// to, or an empty string for other properties.
+func (p Property) TargetType() string {
+	if !p.IsChild() && !p.IsLink() {
+		return ""
+	}
+
+	start := strings.Index(p.Type, "<")
+
+	return strings.TrimSuffix(p.Type[start+1:], ">")
+}
+
+// List returns the properties of the object at the specified path (see
+// qom-list).
+func List(ctx context.Context, client *qmp.Client, objectPath string) ([]Property, error) {
+	var properties []Property
+
+	args := map[string]interface{}{"path": objectPath}
+
+	if err := client.Execute(ctx, "qom-list", args, &properties); err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+// Children returns the paths of the child objects of the object at the
+// specified path.
+func Children(ctx context.Context, client *qmp.Client, objectPath string) ([]string, error) {
+	properties, err := List(ctx, client, objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]string, 0)
+
+	for _, property := range properties {
+		if property.IsChild() {
+			children = append(children, path.Join(objectPath, property.Name))
+		}
+	}
+
+	return children, nil
+}
+
+// WalkFunc is called by Walk for each object with its path and properties.
+// If it returns SkipChildren, the children of the object are not visited. Any
+// other error stops the walk.
+type WalkFunc func(objectPath string, properties []Property) error
+
+// SkipChildren is returned by a WalkFunc to skip the children of an object.
+var SkipChildren = errors.New("skip children")
+
+// Walk visits the object at the specified path and all of its descendants
+// (following child properties, but not links) in depth-first order.
+//
+// Example
+//
+//	err := qom.Walk(ctx, q.QMP(), qom.MachinePath,
+//		func(objectPath string, properties []qom.Property) error {
+//			fmt.Println(objectPath)
+//			return nil
+//		})
+func Walk(ctx context.Context, client *qmp.Client, root string, fn WalkFunc) error {
+	properties, err := List(ctx, client, root)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", root, err)
+	}
+
+	if err := fn(root, properties); err != nil {
+		if errors.Is(err, SkipChildren) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, property := range properties {
+		if !property.IsChild() {
+			continue
+		}
+
+		if err := Walk(ctx, client, path.Join(root, property.Name), fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get reads the value of a property of the object at the specified path into
+// value, which must be a pointer (see qom-get).
+//
+// Example
+//
+//	var size int64
+//	err := qom.Get(ctx, q.QMP(), "/objects/mem0", "size", &size)
+func Get(ctx context.Context, client *qmp.Client, objectPath string, property string, value interface{}) error {
+	args := map[string]interface{}{
+		"path":     objectPath,
+		"property": property,
+	}
+
+	if err := client.Execute(ctx, "qom-get", args, value); err != nil {
+		return fmt.Errorf("failed to get %s of %s: %w", property, objectPath, err)
+	}
+
+	return nil
+}
+
+// Set changes the value of a property of the object at the specified path (see
+// qom-set). Only some properties can be changed while the VM is running.
+func Set(ctx context.Context, client *qmp.Client, objectPath string, property string, value interface{}) error {
+	args := map[string]interface{}{
+		"path":     objectPath,
+		"property": property,
+		"value":    value,
+	}
+
+	if err := client.Execute(ctx, "qom-set", args, nil); err != nil {
+		return fmt.Errorf("failed to set %s of %s: %w", property, objectPath, err)
+	}
+
+	return nil
+}
+
+// TypeOf returns the QOM type of the object at the specified path.
+func TypeOf(ctx context.Context, client *qmp.Client, objectPath string) (string, error) {
+	var typeName string
+
+	if err := Get(ctx, client, objectPath, "type", &typeName); err != nil {
+		return "", err
+	}
+
+	return typeName, nil
+}
+
+// DevicePath returns the QOM path of the device with the specified ID (see
+// device.Use and device.WithID).
+func DevicePath(id string) string {
+	return path.Join(PeripheralPath, id)
+}
+
+// ObjectPath returns the QOM path of the object with the specified ID (such as
+// an object.IOThread).
+func ObjectPath(id string) string {
+	return path.Join(ObjectsPath, id)
+}
+
+// Resolve returns the QOM path of the device or object with the specified ID.
+// Devices take precedence over objects, because they can share an ID. An
+// error wrapping ErrNotFound is returned if neither exists.
+//
+// Example
+//
+//	qemu.New("qemu-system-x86_64").SetOptions(
+//		object.IOThread("iothread0"))
+//
+//	objectPath, err := qom.Resolve(ctx, q.QMP(), "iothread0") // "/objects/iothread0"
+func Resolve(ctx context.Context, client *qmp.Client, id string) (string, error) {
+	for _, parent := range []string{PeripheralPath, ObjectsPath} {
+		properties, err := List(ctx, client, parent)
+		if err != nil {
+			return "", err
+		}
+
+		for _, property := range properties {
+			if property.Name == id && property.IsChild() {
+				return path.Join(parent, id), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: no device or object with ID %s", ErrNotFound, id)
+}
@@ -0,0 +1,82 @@
+package qom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTree maps the paths of a small QOM tree to the results of qom-list.
+var testTree = map[string][]Property{
+	"/machine": {{Name: "type", Type: "string"}, {Name: "peripheral", Type: "child<container>"}},
+	"/machine/peripheral": {
+		{Name: "type", Type: "string"},
+		{Name: "balloon0", Type: "child<virtio-balloon-pci>"},
+	},
+	"/machine/peripheral/balloon0": {
+		{Name: "type", Type: "string"},
+		{Name: "guest-stats-polling-interval", Type: "int"},
+	},
+	"/objects": {{Name: "type", Type: "string"}, {Name: "iothread0", Type: "child<iothread>"}},
+}
+
+// testServer returns a server that serves testTree.
+func testServer() *qmptest.Server {
+	server := qmptest.NewServer()
+
+	server.Handle("qom-list", func(args map[string]interface{}) (interface{}, error) {
+		properties, ok := testTree[args["path"].(string)]
+		if !ok {
+			return nil, &qmp.Error{Class: "DeviceNotFound", Description: "Device not found"}
+		}
+
+		return properties, nil
+	})
+
+	server.Respond("qom-get", "iothread")
+
+	return server
+}
+
+func TestQOM(t *testing.T) {
+	server := testServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	paths := make([]string, 0)
+
+	err = Walk(ctx, client, MachinePath, func(objectPath string, properties []Property) error {
+		paths = append(paths, objectPath)
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/machine", "/machine/peripheral", "/machine/peripheral/balloon0"}, paths)
+
+	objectPath, err := Resolve(ctx, client, "iothread0")
+	assert.NoError(t, err)
+	assert.Equal(t, "/objects/iothread0", objectPath)
+
+	_, err = Resolve(ctx, client, "missing")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	typeName, err := TypeOf(ctx, client, objectPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "iothread", typeName)
+}
+
+func TestPropertyTargetType(t *testing.T) {
+	assert.Equal(t, "memory-backend", Property{Type: "link<memory-backend>"}.TargetType())
+	assert.Equal(t, "", Property{Type: "bool"}.TargetType())
+}
@@ -0,0 +1,72 @@
+package qom
+
+import (
+	"context"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// TypeInfo describes a QOM type (see qom-list-types).
+type TypeInfo struct {
+	// Name is the name of the type, such as "virtio-blk-pci".
+	Name string `json:"name"`
+
+	// Parent is the name of the parent type.
+	Parent string `json:"parent,omitempty"`
+
+	// Abstract is true if objects of the type can't be created.
+	Abstract bool `json:"abstract,omitempty"`
+}
+
+// TypesOptions represent the options for Types.
+type TypesOptions struct {
+	// Implements only returns the types that implement the specified type or
+	// interface, such as "device" or "memory-backend".
+	Implements string
+
+	// Abstract includes abstract types.
+	Abstract bool
+}
+
+// Types returns the QOM types known to QEMU (see qom-list-types).
+func Types(ctx context.Context, client *qmp.Client, opts TypesOptions) ([]TypeInfo, error) {
+	var types []TypeInfo
+
+	args := map[string]interface{}{"abstract": opts.Abstract}
+
+	if opts.Implements != "" {
+		args["implements"] = opts.Implements
+	}
+
+	if err := client.Execute(ctx, "qom-list-types", args, &types); err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
+
+// DeviceProperties returns the properties of the device type with the
+// specified name (see device-list-properties), which are the properties that
+// can be passed to device.Use.
+func DeviceProperties(ctx context.Context, client *qmp.Client, typeName string) ([]Property, error) {
+	return typeProperties(ctx, client, "device-list-properties", typeName)
+}
+
+// ObjectProperties returns the properties of the object type with the
+// specified name (see qom-list-properties), such as the properties of a
+// "memory-backend-ram".
+func ObjectProperties(ctx context.Context, client *qmp.Client, typeName string) ([]Property, error) {
+	return typeProperties(ctx, client, "qom-list-properties", typeName)
+}
+
+func typeProperties(ctx context.Context, client *qmp.Client, command string, typeName string) ([]Property, error) {
+	var properties []Property
+
+	args := map[string]interface{}{"typename": typeName}
+
+	if err := client.Execute(ctx, command, args, &properties); err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,265 @@
+package qemu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mikerourke/queso"
+)
+
+const runtimeRecordName = "vm.json"
+
+// Record is a persistent record of a running QEMU instance. It contains the
+// information required to reattach to QEMU with Attach after the process that
+// launched it was restarted.
+type Record struct {
+	// Binary is the path to the QEMU executable.
+	Binary string `json:"binary"`
+
+	// Args are the args that were passed to QEMU.
+	Args []string `json:"args"`
+
+	// PID is the process ID of QEMU.
+	PID int `json:"pid"`
+
+	// QMPSocket is the path to the QMP socket (if any).
+	QMPSocket string `json:"qmpSocket,omitempty"`
+
+	// RuntimeDirectory is the path to the RuntimeDirectory (if any).
+	RuntimeDirectory string `json:"runtimeDirectory,omitempty"`
+
+	// KeepOnFailure indicates if the RuntimeDirectory should be preserved if
+	// QEMU fails.
+	KeepOnFailure bool `json:"keepOnFailure,omitempty"`
+
+	// StartTime is the time at which QEMU was started.
+	StartTime time.Time `json:"startTime"`
+}
+
+// RecordFile returns the path to the file the Record of the running QEMU
+// instance is saved to.
+func (d *RuntimeDirectory) RecordFile() string {
+	return d.File(runtimeRecordName)
+}
+
+// Record returns a Record of the running QEMU instance.
+func (q *QEMU) Record() (*Record, error) {
+	if q.process == nil {
+		return nil, errors.New("qemu has not been started")
+	}
+
+	binary := q.exePath
+	if q.cmd != nil {
+		binary = q.cmd.Path
+	}
+
+	if abs, err := filepath.Abs(binary); err == nil {
+		binary = abs
+	}
+
+	record := &Record{
+		Binary:    binary,
+		Args:      q.Args(),
+		PID:       q.PID(),
+		QMPSocket: q.QMPSocket(),
+		StartTime: q.startTime,
+	}
+
+	if q.runtimeDir != nil {
+		record.RuntimeDirectory = q.runtimeDir.Path()
+		record.KeepOnFailure = q.runtimeDir.opts.KeepOnFailure
+	}
+
+	return record, nil
+}
+
+// Save writes the Record to the specified file as JSON. The file is replaced
+// atomically, so a concurrent reader never sees a partially written Record.
+func (r *Record) Save(file string) error {
+	contents, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+
+	if err := os.WriteFile(tmp, contents, 0o600); err != nil {
+		return fmt.Errorf("failed to save record: %w", err)
+	}
+
+	return os.Rename(tmp, file)
+}
+
+// LoadRecord reads a Record from the specified file.
+func LoadRecord(file string) (*Record, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load record: %w", err)
+	}
+
+	record := &Record{}
+	if err := json.Unmarshal(contents, record); err != nil {
+		return nil, fmt.Errorf("invalid record %s: %w", file, err)
+	}
+
+	return record, nil
+}
+
+// Attach reattaches to the QEMU instance described by the specified Record,
+// which is typically a QEMU instance that was started with the Daemonize option.
+// The process with the PID in the Record is verified to be the same QEMU
+// instance by comparing its command line (from /proc/<pid>/cmdline, so this
+// is only supported on Linux) with the Record. If the Record has a QMP socket,
+// QMP is reconnected before the QEMU instance is returned.
+//
+// The options of the returned QEMU instance are parsed from the args of the
+// Record (see queso.ParseArgs), so it can be used to manage the lifecycle of
+// the process (e.g. Wait, Quit, Kill, SaveState or Clone) like the instance
+// that started it. The values of the parsed options are strings.
+func Attach(ctx context.Context, record *Record) (*QEMU, error) {
+	if err := verifyProcess(record); err != nil {
+		return nil, err
+	}
+
+	process, err := os.FindProcess(record.PID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &QEMU{
+		exePath:   record.Binary,
+		args:      record.Args,
+		options:   queso.ParseArgs(record.Args),
+		process:   process,
+		startTime: record.StartTime,
+		qmpSocket: record.QMPSocket,
+	}
+
+	if record.RuntimeDirectory != "" {
+		q.runtimeDir = &RuntimeDirectory{
+			path: record.RuntimeDirectory,
+			opts: RuntimeDirectoryOptions{KeepOnFailure: record.KeepOnFailure},
+		}
+
+		q.options = q.runtimeDir.detachOptions(q.options)
+	}
+
+	if q.QMPSocket() != "" {
+		if _, err := q.ConnectQMP(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// detachOptions removes the options added by the runtime directory from the
+// options parsed from the args of a Record, and enables the corresponding
+// RuntimeDirectoryOptions, so the options aren't duplicated by Options.
+func (d *RuntimeDirectory) detachOptions(options []*queso.Option) []*queso.Option {
+	parsed := make(map[string]bool)
+
+	for _, option := range options {
+		parsed[option.ArgsString()] = true
+	}
+
+	base := (&RuntimeDirectory{path: d.path}).Options()
+	removed := make(map[string]bool)
+
+	// The options of each feature are only removed if all of them are
+	// present, so options that happen to match (such as a virtio-serial
+	// device) are kept.
+	for _, opts := range []RuntimeDirectoryOptions{{}, {SerialLog: true}, {GuestAgent: true}} {
+		added := (&RuntimeDirectory{path: d.path, opts: opts}).Options()
+		if opts.SerialLog || opts.GuestAgent {
+			added = added[len(base):]
+		}
+
+		if !containsAll(parsed, added) {
+			continue
+		}
+
+		d.opts.SerialLog = d.opts.SerialLog || opts.SerialLog
+		d.opts.GuestAgent = d.opts.GuestAgent || opts.GuestAgent
+
+		for _, option := range added {
+			removed[option.ArgsString()] = true
+		}
+	}
+
+	remaining := make([]*queso.Option, 0, len(options))
+
+	for _, option := range options {
+		if !removed[option.ArgsString()] {
+			remaining = append(remaining, option)
+		}
+	}
+
+	return remaining
+}
+
+func containsAll(parsed map[string]bool, options []*queso.Option) bool {
+	for _, option := range options {
+		if !parsed[option.ArgsString()] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyProcess ensures that the process with the PID in the Record is still
+// the same QEMU instance and not an unrelated process that reused the PID.
+func verifyProcess(record *Record) error {
+	if record.PID <= 0 {
+		return fmt.Errorf("invalid PID %d", record.PID)
+	}
+
+	procDir := filepath.Join("/proc", strconv.Itoa(record.PID))
+
+	contents, err := os.ReadFile(filepath.Join(procDir, "cmdline"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("process %d no longer exists", record.PID)
+		}
+
+		return fmt.Errorf("failed to read command line of process %d: %w", record.PID, err)
+	}
+
+	argv := bytes.Split(bytes.TrimSuffix(contents, []byte{0}), []byte{0})
+	if len(argv) == 0 || filepath.Base(string(argv[0])) != filepath.Base(record.Binary) {
+		return fmt.Errorf("process %d is not %s", record.PID, record.Binary)
+	}
+
+	if len(argv)-1 != len(record.Args) {
+		return fmt.Errorf("process %d was started with different args", record.PID)
+	}
+
+	for i, arg := range record.Args {
+		if string(argv[i+1]) != arg {
+			return fmt.Errorf("process %d was started with different args", record.PID)
+		}
+	}
+
+	// The executable link is only readable if the process is owned by the
+	// current user, so it is only checked if it can be read.
+	if exe, err := os.Readlink(filepath.Join(procDir, "exe")); err == nil {
+		// The link is suffixed if the executable was replaced (e.g. by a
+		// package upgrade) after the process was started.
+		exe = strings.TrimSuffix(exe, " (deleted)")
+
+		if resolved, err := filepath.EvalSymlinks(record.Binary); err == nil && resolved != exe {
+			return fmt.Errorf("process %d is running %s instead of %s", record.PID, exe, resolved)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package qemu
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttach(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	assert.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	record := &Record{
+		Binary:    cmd.Path,
+		Args:      []string{"30"},
+		PID:       cmd.Process.Pid,
+		StartTime: time.Now(),
+	}
+
+	file := filepath.Join(t.TempDir(), "vm.json")
+	assert.NoError(t, record.Save(file))
+
+	loaded, err := LoadRecord(file)
+	assert.NoError(t, err)
+	assert.Equal(t, record.Args, loaded.Args)
+
+	q, err := Attach(context.Background(), loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, cmd.Process.Pid, q.PID())
+
+	loaded.Args = []string{"60"}
+	_, err = Attach(context.Background(), loaded)
+	assert.Error(t, err)
+}
+
+func TestAttachOptions(t *testing.T) {
+	dir := &RuntimeDirectory{path: t.TempDir(), opts: RuntimeDirectoryOptions{SerialLog: true}}
+	args := append([]string{"-name", "test-vm", "-drive", "file=my,,disk.qcow2,if=virtio"},
+		(&QEMU{runtimeDir: dir}).Args()...)
+
+	attached := &RuntimeDirectory{path: dir.path}
+	q := &QEMU{args: args, options: attached.detachOptions(queso.ParseArgs(args)), runtimeDir: attached}
+
+	assert.True(t, attached.opts.SerialLog)
+	assert.False(t, attached.opts.GuestAgent)
+	assert.Len(t, q.options, 2)
+	assert.Equal(t, "test-vm", q.GuestName())
+	assert.Equal(t, map[string]string{"file": "my,,disk.qcow2", "if": "virtio"}, q.FindOption("drive").Table())
+
+	args = make([]string, 0)
+	for _, option := range q.Options() {
+		args = append(args, option.Args()...)
+	}
+
+	assert.Equal(t, q.args, args)
+}
+
+func TestConnectQMPExited(t *testing.T) {
+	q := New("true")
+	q.SetQMPSocket(filepath.Join(t.TempDir(), "qmp.sock"))
+	assert.NoError(t, q.Start())
+
+	_, err := q.ConnectQMP(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, q.Wait())
+}
@@ -0,0 +1,271 @@
+package qemu
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/debug"
+)
+
+// ReplayOptions represent the options for EnableRecord and EnableReplay.
+type ReplayOptions struct {
+	// File is the file the events are recorded to or replayed from. It must
+	// not be inside the RuntimeDirectory, which is removed when QEMU exits.
+	File string
+
+	// Seed is passed to debug.UseSeed, so the guest gets the same random
+	// numbers when it is replayed.
+	Seed int
+
+	// Snapshot is the name of the VM snapshot that is created when recording
+	// starts and loaded when replaying starts (see debug.WithReplaySnapshot).
+	Snapshot string
+
+	// InstructionCount are additional properties of the InstructionCount
+	// option, such as debug.WithShift. If omitted, debug.WithAutoShift is used.
+	InstructionCount []*debug.InstructionCountProperty
+}
+
+// ReplayIncompatibility is an option that prevents deterministic record/replay.
+type ReplayIncompatibility struct {
+	// Option is the incompatible option.
+	Option *queso.Option
+
+	// Reason explains why the option is incompatible.
+	Reason string
+}
+
+// String returns the args of the option along with the reason.
+func (i ReplayIncompatibility) String() string {
+	return fmt.Sprintf("%s: %s", i.Option.ArgsString(), i.Reason)
+}
+
+// ReplayError is returned by CheckReplay if options are incompatible with
+// record/replay.
+type ReplayError struct {
+	// Incompatibilities are the incompatible options.
+	Incompatibilities []ReplayIncompatibility
+}
+
+// Error returns the incompatible options.
+func (e *ReplayError) Error() string {
+	messages := make([]string, 0, len(e.Incompatibilities))
+
+	for _, incompatibility := range e.Incompatibilities {
+		messages = append(messages, incompatibility.String())
+	}
+
+	return "options are incompatible with record/replay: " + strings.Join(messages, "; ")
+}
+
+// CheckReplay checks whether the specified options can be used for
+// deterministic record/replay, and returns a *ReplayError if they can't.
+// Record/replay requires the TCG accelerator, every disk must be attached to the
+// guest through a blockdev.BlkReplayDriver, every network backend must have an
+// object.FilterReplay, and host devices can't be passed through. See
+// https://qemu.readthedocs.io/en/latest/system/replay.html for more details.
+func CheckReplay(options []*queso.Option) error {
+	replayDrives := make(map[string]bool)
+	replayNetdevs := make(map[string]bool)
+
+	for _, option := range options {
+		table := option.Table()
+
+		switch {
+		case (option.Flag == "drive" || option.Flag == "blockdev") && table["driver"] == "blkreplay":
+			replayDrives[table["id"]] = true
+			replayDrives[table["node-name"]] = true
+
+		case option.Flag == "object" && option.Name == "filter-replay":
+			replayNetdevs[table["netdev"]] = true
+		}
+	}
+
+	incompatibilities := make([]ReplayIncompatibility, 0)
+
+	for _, option := range options {
+		if reason := replayIncompatibility(option, replayDrives, replayNetdevs); reason != "" {
+			incompatibilities = append(incompatibilities, ReplayIncompatibility{
+				Option: option,
+				Reason: reason,
+			})
+		}
+	}
+
+	if len(incompatibilities) != 0 {
+		return &ReplayError{Incompatibilities: incompatibilities}
+	}
+
+	return nil
+}
+
+// replayIncompatibility returns the reason the option is incompatible with
+// record/replay, or an empty string if it is compatible.
+func replayIncompatibility(
+	option *queso.Option,
+	replayDrives map[string]bool,
+	replayNetdevs map[string]bool,
+) string {
+	table := option.Table()
+
+	switch option.Flag {
+	case "enable-kvm":
+		return "record/replay requires the TCG accelerator"
+
+	case "accel":
+		if option.Name != AccelTCG {
+			return "record/replay requires the TCG accelerator"
+		}
+
+	case "machine":
+		if accel, ok := table["accel"]; ok && accel != AccelTCG {
+			return "record/replay requires the TCG accelerator"
+		}
+
+	case "hda", "hdb", "hdc", "hdd", "cdrom", "fda", "fdb", "mtdblock", "sd":
+		return "disks must be attached through a blkreplay driver"
+
+	case "drive":
+		if table["if"] != "none" {
+			return "disks must be attached through a blkreplay driver with if=none"
+		}
+
+	case "nic":
+		if option.Name != "none" {
+			return "network backends must be defined with -netdev and a filter-replay object"
+		}
+
+	case "net":
+		if option.Name != "none" {
+			return "network backends must be defined with -netdev and a filter-replay object"
+		}
+
+	case "netdev":
+		if option.Name == "vhost-user" || option.Name == "vhost-vdpa" || table["vhost"] == "on" ||
+			table["vhost"] == "true" {
+			return "vhost network backends can't be recorded"
+		}
+
+		if !replayNetdevs[table["id"]] {
+			return "the network backend requires a filter-replay object"
+		}
+
+	case "device":
+		if strings.HasPrefix(option.Name, "vfio-") || option.Name == "usb-host" {
+			return "host devices can't be passed through"
+		}
+
+		if drive, ok := table["drive"]; ok && !replayDrives[drive] {
+			return "the drive must be a blkreplay driver"
+		}
+	}
+
+	return ""
+}
+
+// EnableRecord records all non-deterministic events of the guest to the replay
+// file, so the run can be replayed with EnableReplay (or Replay). The options
+// of QEMU are checked with CheckReplay, and the InstructionCount and UseSeed
+// options of the debug package are added to them. EnableRecord must be called
+// after SetOptions and before Start.
+//
+// Example
+//
+//	q := qemu.New("qemu-system-x86_64")
+//	q.SetOptions(options...)
+//	err := q.EnableRecord(qemu.ReplayOptions{File: "replay.bin", Seed: 42})
+//
+// Invocation
+//
+//	qemu-system-x86_64 ... -icount shift=auto,rr=record,rrfile=replay.bin -seed 42
+func (q *QEMU) EnableRecord(opts ReplayOptions) error {
+	if err := q.enableRecordReplay(debug.ReplayModeRecord, opts); err != nil {
+		return err
+	}
+
+	q.recording = &opts
+
+	return nil
+}
+
+// EnableReplay replays the events recorded with EnableRecord from the replay
+// file. QEMU must have the same options as when the run was recorded, and opts
+// must match the options passed to EnableRecord.
+func (q *QEMU) EnableReplay(opts ReplayOptions) error {
+	return q.enableRecordReplay(debug.ReplayModeReplay, opts)
+}
+
+// Replay returns a new QEMU instance that replays the run recorded by this
+// instance (see EnableRecord) with the same options and environment. The
+// RuntimeDirectory isn't shared, so set a new one on the returned instance if
+// required.
+func (q *QEMU) Replay() (*QEMU, error) {
+	if q.recording == nil {
+		return nil, errors.New("recording has not been enabled with EnableRecord")
+	}
+
+	replay := New(q.exePath)
+	replay.SetOptions(q.options...)
+	replay.SetEnv(q.env...)
+
+	if err := replay.EnableReplay(*q.recording); err != nil {
+		return nil, err
+	}
+
+	return replay, nil
+}
+
+func (q *QEMU) enableRecordReplay(mode debug.ReplayMode, opts ReplayOptions) error {
+	if opts.File == "" {
+		return errors.New("a replay file is required")
+	}
+
+	if q.runtimeDir != nil {
+		if rel, err := filepath.Rel(q.runtimeDir.Path(), opts.File); err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("the replay file %s must not be inside the runtime directory", opts.File)
+		}
+	}
+
+	// Any previous InstructionCount and UseSeed options are replaced, so the
+	// options of a recorded instance can be reused for the replay.
+	options := make([]*queso.Option, 0, len(q.options)+2)
+
+	for _, option := range q.options {
+		if option.Flag != "icount" && option.Flag != "seed" {
+			options = append(options, option)
+		}
+	}
+
+	if err := CheckReplay(append(options, q.runtimeDirOptions()...)); err != nil {
+		return err
+	}
+
+	properties := opts.InstructionCount
+	if len(properties) == 0 {
+		properties = []*debug.InstructionCountProperty{debug.WithAutoShift()}
+	}
+
+	properties = append(properties, debug.WithReplayMode(mode), debug.WithReplayFile(opts.File))
+
+	if opts.Snapshot != "" {
+		properties = append(properties, debug.WithReplaySnapshot(opts.Snapshot))
+	}
+
+	q.options = append(options,
+		debug.InstructionCount(properties...),
+		debug.UseSeed(opts.Seed))
+
+	return nil
+}
+
+// runtimeDirOptions returns the options of the RuntimeDirectory, if any.
+func (q *QEMU) runtimeDirOptions() []*queso.Option {
+	if q.runtimeDir == nil {
+		return nil
+	}
+
+	return q.runtimeDir.Options()
+}
@@ -0,0 +1,68 @@
+package qemu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/blockdev"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/mikerourke/queso/qemu/network"
+	"github.com/mikerourke/queso/qemu/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func replayOptions() []*queso.Option {
+	return []*queso.Option{
+		blockdev.QCOW2Driver(
+			blockdev.WithNodeName("disk_format"),
+			blockdev.WithDriverProperty("file", blockdev.WithDriverType("file")),
+			blockdev.WithDriverProperty("file", blockdev.WithImageFile("disk.qcow2"))),
+		blockdev.BlkReplayDriver("disk_format", blockdev.WithNodeName("disk")),
+		device.Use("virtio-blk-pci", device.NewProperty("drive", "disk")),
+		network.UserBackend("net0"),
+		object.FilterReplay("replay0", "net0"),
+	}
+}
+
+func TestCheckReplay(t *testing.T) {
+	assert.NoError(t, CheckReplay(replayOptions()))
+
+	err := CheckReplay([]*queso.Option{
+		debug.EnableKVM(),
+		blockdev.DiskDrive(blockdev.HardDiskDriveA, "disk.img"),
+		network.NIC(network.BackendTypeUser),
+		network.UserBackend("net1"),
+		device.Use("vfio-pci", device.NewProperty("host", "01:00.0")),
+	})
+
+	var replayErr *ReplayError
+	assert.True(t, errors.As(err, &replayErr))
+	assert.Len(t, replayErr.Incompatibilities, 5)
+	assert.Equal(t, "-hda disk.img: disks must be attached through a blkreplay driver",
+		replayErr.Incompatibilities[1].String())
+}
+
+func TestRecordReplay(t *testing.T) {
+	q := New("qemu-system-x86_64")
+	q.SetOptions(replayOptions()...)
+
+	_, err := q.Replay()
+	assert.Error(t, err)
+
+	assert.NoError(t, q.EnableRecord(ReplayOptions{File: "replay.bin", Seed: 42, Snapshot: "init"}))
+	assert.Equal(t, []string{
+		"-icount", "shift=auto,rr=record,rrfile=replay.bin,rrsnapshot=init",
+		"-seed", "42",
+	}, q.Args()[len(q.Args())-4:])
+
+	replay, err := q.Replay()
+	assert.NoError(t, err)
+	assert.Equal(t, len(q.Args()), len(replay.Args()))
+	assert.Equal(t, "shift=auto,rr=replay,rrfile=replay.bin,rrsnapshot=init", replay.Args()[len(replay.Args())-3])
+
+	q = New("qemu-system-x86_64")
+	q.SetOptions(blockdev.DiskDrive(blockdev.HardDiskDriveA, "disk.img"))
+	assert.Error(t, q.EnableRecord(ReplayOptions{File: "replay.bin"}))
+}
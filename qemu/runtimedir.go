@@ -0,0 +1,250 @@
+package qemu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/mikerourke/queso/qemu/chardev"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/device"
+)
+
+const (
+	runtimeQMPSocketName        = "qmp.sock"
+	runtimeGuestAgentSocketName = "qga.sock"
+	runtimeSerialLogName        = "serial.log"
+	runtimePIDFileName          = "qemu.pid"
+
+	// GuestAgentCharDevID is the ID of the character device that is created
+	// for the guest agent socket in a RuntimeDirectory.
+	GuestAgentCharDevID = "qga0"
+
+	// GuestAgentPortName is the name of the virtio serial port the guest agent
+	// listens on inside the guest.
+	GuestAgentPortName = "org.qemu.guest_agent.0"
+)
+
+// RuntimeDirectoryOptions represent the options used to create a new
+// RuntimeDirectory.
+type RuntimeDirectoryOptions struct {
+	// Parent is the directory in which the runtime directory is created. If
+	// omitted, the default directory for temporary files is used.
+	Parent string
+
+	// Name is used as a prefix for the name of the runtime directory. A random
+	// suffix is always appended to prevent collisions between VMs.
+	Name string
+
+	// SerialLog redirects the first serial port of the guest to a log file in
+	// the runtime directory.
+	SerialLog bool
+
+	// GuestAgent adds a virtio serial port for the QEMU guest agent that is
+	// backed by a Unix socket in the runtime directory.
+	GuestAgent bool
+
+	// KeepOnFailure preserves the runtime directory (and all of its contents)
+	// when QEMU exits with an error, so the artifacts can be inspected.
+	KeepOnFailure bool
+}
+
+// RuntimeDirectory is a per-VM directory that holds the artifacts QEMU needs
+// (or produces) while it is running, such as the QMP socket, serial log, PID
+// file, guest agent socket, and overlay disk images. Each artifact has a
+// predictable name within the directory.
+//
+// Example
+//
+//	dir, err := qemu.NewRuntimeDirectory(qemu.RuntimeDirectoryOptions{
+//		Name:      "test-vm",
+//		SerialLog: true,
+//	})
+//
+//	q := qemu.New("qemu-system-x86_64")
+//	q.SetRuntimeDirectory(dir)
+//	q.SetOptions(qemu.Memory("1G"))
+//
+// Invocation
+//
+//	qemu-system-x86_64 -m 1G \
+//		-qmp unix:/tmp/test-vm-123/qmp.sock,server=on,wait=off \
+//		-pidfile /tmp/test-vm-123/qemu.pid \
+//		-serial file:/tmp/test-vm-123/serial.log
+type RuntimeDirectory struct {
+	path string
+	opts RuntimeDirectoryOptions
+}
+
+// NewRuntimeDirectory creates a new RuntimeDirectory with the specified options.
+// An error is returned if the paths of the Unix sockets placed in the directory
+// would exceed the maximum length allowed by the operating system. If that
+// happens, use a shorter Parent directory.
+func NewRuntimeDirectory(opts RuntimeDirectoryOptions) (*RuntimeDirectory, error) {
+	name := opts.Name
+	if name == "" {
+		name = "queso"
+	}
+
+	path, err := os.MkdirTemp(opts.Parent, name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime directory: %w", err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		_ = os.RemoveAll(path)
+
+		return nil, err
+	}
+
+	path = abs
+
+	dir := &RuntimeDirectory{
+		path: path,
+		opts: opts,
+	}
+
+	for _, socket := range []string{runtimeQMPSocketName, runtimeGuestAgentSocketName} {
+		if _, err := dir.SocketPath(socket); err != nil {
+			_ = dir.Remove()
+
+			return nil, err
+		}
+	}
+
+	return dir, nil
+}
+
+// Path returns the absolute path to the runtime directory.
+func (d *RuntimeDirectory) Path() string {
+	return d.path
+}
+
+// File returns the absolute path to the file with the specified name in the
+// runtime directory.
+func (d *RuntimeDirectory) File(name string) string {
+	return filepath.Join(d.path, name)
+}
+
+// SocketPath returns the absolute path to the Unix socket with the specified
+// name in the runtime directory. An error is returned if the path exceeds the
+// maximum length of a Unix socket path.
+func (d *RuntimeDirectory) SocketPath(name string) (string, error) {
+	path := d.File(name)
+
+	if limit := maxSocketPathLength(); len(path) > limit {
+		return "", fmt.Errorf("socket path %s is %d bytes, which exceeds the limit of %d",
+			path, len(path), limit)
+	}
+
+	return path, nil
+}
+
+// QMPSocket returns the path to the QMP socket.
+func (d *RuntimeDirectory) QMPSocket() string {
+	return d.File(runtimeQMPSocketName)
+}
+
+// GuestAgentSocket returns the path to the guest agent socket. The socket only
+// exists if the RuntimeDirectory was created with the GuestAgent option.
+func (d *RuntimeDirectory) GuestAgentSocket() string {
+	return d.File(runtimeGuestAgentSocketName)
+}
+
+// SerialLog returns the path to the serial log. The log only exists if the
+// RuntimeDirectory was created with the SerialLog option.
+func (d *RuntimeDirectory) SerialLog() string {
+	return d.File(runtimeSerialLogName)
+}
+
+// PIDFile returns the path to the file QEMU writes its PID to.
+func (d *RuntimeDirectory) PIDFile() string {
+	return d.File(runtimePIDFileName)
+}
+
+// Overlay returns the path to the overlay disk image with the specified name.
+func (d *RuntimeDirectory) Overlay(name string) string {
+	return d.File(name + ".qcow2")
+}
+
+// CreateOverlay creates a QCOW2 overlay disk image with the specified name on
+// top of the specified backing file and returns the path to the overlay. The
+// overlay is removed along with the rest of the runtime directory.
+func (d *RuntimeDirectory) CreateOverlay(
+	name string,
+	backingFile string,
+	backingFormat diskimage.FileFormat,
+) (string, error) {
+	path := d.Overlay(name)
+
+	err := diskimage.Create(diskimage.CreateOptions{
+		Format:        diskimage.FileFormatQCOW2,
+		File:          path,
+		BackingFile:   backingFile,
+		BackingFormat: backingFormat,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Options returns the options that need to be passed to QEMU to place the
+// artifacts in the runtime directory.
+func (d *RuntimeDirectory) Options() []*queso.Option {
+	options := []*queso.Option{
+		debug.HostRedirect(debug.RedirectSourceQMP,
+			fmt.Sprintf("unix:%s,server=on,wait=off", d.QMPSocket())),
+		debug.UsePIDFile(d.PIDFile()),
+	}
+
+	if d.opts.SerialLog {
+		options = append(options,
+			debug.HostRedirect(debug.RedirectSourceSerial, "file:"+d.SerialLog()))
+	}
+
+	if d.opts.GuestAgent {
+		options = append(options,
+			chardev.UnixSocketBackend(GuestAgentCharDevID, d.GuestAgentSocket(),
+				chardev.IsListeningSocket(true),
+				chardev.NewProperty("wait", false)),
+			device.Use("virtio-serial"),
+			device.Use("virtserialport",
+				device.NewProperty("chardev", GuestAgentCharDevID),
+				device.NewProperty("name", GuestAgentPortName)))
+	}
+
+	return options
+}
+
+// Remove removes the runtime directory and all of its contents.
+func (d *RuntimeDirectory) Remove() error {
+	return os.RemoveAll(d.path)
+}
+
+// cleanup removes the runtime directory after QEMU exits, unless the directory
+// should be preserved because QEMU failed.
+func (d *RuntimeDirectory) cleanup(exitErr error) error {
+	if exitErr != nil && d.opts.KeepOnFailure {
+		return nil
+	}
+
+	return d.Remove()
+}
+
+// maxSocketPathLength returns the maximum length of a Unix socket path, which
+// is the size of sun_path in sockaddr_un minus the terminating null byte.
+func maxSocketPathLength() int {
+	switch runtime.GOOS {
+	case "linux", "android":
+		return 107
+
+	default:
+		return 103
+	}
+}
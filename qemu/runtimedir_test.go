@@ -0,0 +1,35 @@
+package qemu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRuntimeDirectory(t *testing.T) {
+	dir, err := NewRuntimeDirectory(RuntimeDirectoryOptions{
+		Parent:    t.TempDir(),
+		Name:      "vm",
+		SerialLog: true,
+	})
+	assert.NoError(t, err)
+	defer dir.Remove()
+
+	q := New("qemu-system-x86_64")
+	q.SetRuntimeDirectory(dir)
+	q.SetOptions(Memory("1G"))
+
+	expected := "-m 1G -qmp unix:" + dir.QMPSocket() + ",server=on,wait=off" +
+		" -pidfile " + dir.PIDFile() + " -serial file:" + dir.SerialLog()
+
+	assert.Equal(t, expected, strings.Join(q.Args(), " "))
+}
+
+func TestNewRuntimeDirectoryLongPath(t *testing.T) {
+	_, err := NewRuntimeDirectory(RuntimeDirectoryOptions{
+		Parent: t.TempDir(),
+		Name:   strings.Repeat("x", 120),
+	})
+	assert.Error(t, err)
+}
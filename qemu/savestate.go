@@ -0,0 +1,442 @@
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// StateCompression represents the compression used for a VM state file saved
+// with SaveState.
+type StateCompression string
+
+const (
+	// StateCompressionNone saves the VM state uncompressed using the file
+	// migration transport, which requires QEMU 8.2 or newer.
+	StateCompressionNone StateCompression = ""
+
+	// StateCompressionGzip compresses the VM state with gzip.
+	StateCompressionGzip StateCompression = "gzip"
+
+	// StateCompressionZstd compresses the VM state with zstd.
+	StateCompressionZstd StateCompression = "zstd"
+)
+
+// SaveStateOptions represent the options for SaveState.
+type SaveStateOptions struct {
+	// Compression is the compression used for the state file. The compression
+	// tool must be installed on the host.
+	Compression StateCompression
+
+	// KeepRunning resumes the VM once the state is saved instead of stopping
+	// QEMU.
+	KeepRunning bool
+}
+
+// RestoreStateOptions represent the options for RestoreState.
+type RestoreStateOptions struct {
+	// Paused leaves the VM paused once the state is restored.
+	Paused bool
+
+	// IgnoreIncompatible skips the check that ensures the options of QEMU are
+	// compatible with the options that were used when the state was saved.
+	IgnoreIncompatible bool
+}
+
+// SavedState describes a VM state file saved with SaveState. It is stored next
+// to the state file in a file with the ".json" extension.
+type SavedState struct {
+	// File is the path to the state file.
+	File string `json:"file"`
+
+	// Compression is the compression used for the state file.
+	Compression StateCompression `json:"compression,omitempty"`
+
+	// QEMUVersion is the version of QEMU that saved the state.
+	QEMUVersion qmp.Version `json:"qemuVersion"`
+
+	// Options are the guest-visible options of the VM whose state was saved.
+	// They are used to check if the state can be restored.
+	Options []string `json:"options"`
+
+	// SavedAt is the time at which the state was saved.
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// LoadSavedState reads the description of the VM state file at the specified
+// path.
+func LoadSavedState(path string) (*SavedState, error) {
+	contents, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved state description: %w", err)
+	}
+
+	state := &SavedState{}
+	if err := json.Unmarshal(contents, state); err != nil {
+		return nil, fmt.Errorf("invalid saved state description: %w", err)
+	}
+
+	return state, nil
+}
+
+// CheckCompatible returns an error if the state can't be restored by QEMU,
+// because the guest-visible options differ from the options that were used
+// when the state was saved.
+func (s *SavedState) CheckCompatible(q *QEMU) error {
+	expected := s.Options
+	actual := stateOptions(q.options)
+
+	differences := make([]string, 0)
+
+	for _, option := range expected {
+		if !containsString(actual, option) {
+			differences = append(differences, "missing "+option)
+		}
+	}
+
+	for _, option := range actual {
+		if !containsString(expected, option) {
+			differences = append(differences, "unexpected "+option)
+		}
+	}
+
+	if len(differences) != 0 {
+		return fmt.Errorf("options are incompatible with the saved state: %s",
+			strings.Join(differences, ", "))
+	}
+
+	return nil
+}
+
+// SaveState saves the state of the running VM (i.e. RAM and device state) to
+// the file at the specified path. The VM is paused and its state is migrated to
+// the file. Unless the KeepRunning option is used, QEMU is stopped once the
+// state is saved. The disk images are not saved, so they must not be modified
+// until the state is restored.
+func (q *QEMU) SaveState(ctx context.Context, path string, opts SaveStateOptions) error {
+	resume, err := q.saveState(ctx, path, opts.Compression)
+	if err != nil {
+		return err
+	}
+
+	if opts.KeepRunning {
+		resume()
+
+		return nil
+	}
+
+	return q.Quit(ctx)
+}
+
+// saveState pauses the VM and saves its state to the file at the specified
+// path. The VM is left paused, so the caller can act on the paused VM (e.g.
+// snapshot its disks) before calling the returned resume function. If saving
+// fails, the VM is resumed before returning.
+func (q *QEMU) saveState(ctx context.Context, path string, compression StateCompression) (func(), error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := saveStateArgs(path, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	wasRunning, err := q.isRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resume := func() {
+		if wasRunning {
+			_ = client.Execute(context.Background(), "cont", nil, nil)
+		}
+	}
+
+	if err := client.Execute(ctx, "stop", nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err := client.Execute(ctx, "migrate", args, nil); err != nil {
+		resume()
+
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	if err := q.waitForMigration(ctx); err != nil {
+		resume()
+		_ = os.Remove(path)
+
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	state := &SavedState{
+		File:        path,
+		Compression: compression,
+		QEMUVersion: client.Greeting().QMP.Version,
+		Options:     stateOptions(q.options),
+		SavedAt:     time.Now(),
+	}
+
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		resume()
+
+		return nil, err
+	}
+
+	if err := os.WriteFile(path+".json", contents, 0o644); err != nil {
+		resume()
+
+		return nil, fmt.Errorf("failed to save state description: %w", err)
+	}
+
+	return resume, nil
+}
+
+// RestoreState starts QEMU and restores the VM state saved with SaveState from
+// the file at the specified path. QEMU must be configured with the same options
+// as the VM whose state was saved (see SavedState.CheckCompatible). QEMU is
+// started with the SkipCPUStartAtStartup and UseForIncomingMigration options,
+// and the VM is resumed once the state is restored (unless the Paused option
+// is used).
+func (q *QEMU) RestoreState(ctx context.Context, path string, opts RestoreStateOptions) error {
+	state, err := LoadSavedState(path)
+	if err != nil {
+		return err
+	}
+
+	if !opts.IgnoreIncompatible {
+		if err := state.CheckCompatible(q); err != nil {
+			return err
+		}
+	}
+
+	args, err := restoreStateArgs(path, state.Compression)
+	if err != nil {
+		return err
+	}
+
+	if err := q.startIncoming(); err != nil {
+		return err
+	}
+
+	fail := func(err error) error {
+		_ = q.Kill()
+		_ = q.Wait()
+
+		return fmt.Errorf("failed to restore state: %w", err)
+	}
+
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	saved := state.QEMUVersion.QEMU
+	if !client.Greeting().QMP.Version.AtLeast(saved.Major, saved.Minor, 0) {
+		return fail(fmt.Errorf("state was saved by QEMU %s, which is newer than QEMU %s",
+			state.QEMUVersion, client.Greeting().QMP.Version))
+	}
+
+	if err := client.Execute(ctx, "migrate-incoming", args, nil); err != nil {
+		return fail(err)
+	}
+
+	if err := q.waitForMigration(ctx); err != nil {
+		return fail(err)
+	}
+
+	if opts.Paused {
+		return nil
+	}
+
+	return client.Execute(ctx, "cont", nil, nil)
+}
+
+// startIncoming starts QEMU paused and waiting for an incoming migration. The
+// options are only added to the started process, so the options of q are left
+// unchanged.
+//
+// The exec.Cmd is always created again, since one created with Cmd beforehand
+// lacks the options and one that was already started (e.g. before SaveState)
+// can't be started twice. The standard streams of a Cmd that wasn't started
+// yet are kept.
+func (q *QEMU) startIncoming() error {
+	original := q.options
+
+	defer func() {
+		q.options = original
+	}()
+
+	options := append([]*queso.Option{}, q.options...)
+
+	if !q.HasOption("S") {
+		options = append(options, debug.SkipCPUStartAtStartup())
+	}
+
+	q.options = append(options, debug.UseForIncomingMigration("defer"))
+
+	previous := q.cmd
+	cmd := q.Cmd()
+
+	if previous != nil && previous.Process == nil {
+		cmd.Stdin = previous.Stdin
+		cmd.Stdout = previous.Stdout
+		cmd.Stderr = previous.Stderr
+	}
+
+	return q.Start()
+}
+
+// waitForMigration polls the migration status until the migration completes
+// (or fails).
+func (q *QEMU) waitForMigration(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		info, err := q.queryMigration(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch info.Status {
+		case MigrationStatusCompleted:
+			return nil
+
+		case MigrationStatusFailed, MigrationStatusCancelled:
+			if info.ErrorDesc != "" {
+				return errors.New(info.ErrorDesc)
+			}
+
+			return fmt.Errorf("migration %s", info.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// saveStateArgs returns the arguments of the migrate command that saves the VM
+// state to the file at the specified path.
+func saveStateArgs(path string, compression StateCompression) (map[string]interface{}, error) {
+	switch compression {
+	case StateCompressionNone:
+		return fileChannelArgs(path), nil
+
+	case StateCompressionGzip, StateCompressionZstd:
+		return uriArgs(fmt.Sprintf("exec:%s -c > %s", compression, shellQuote(path))), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+// restoreStateArgs returns the arguments of the migrate-incoming command that
+// restores the VM state from the file at the specified path.
+func restoreStateArgs(path string, compression StateCompression) (map[string]interface{}, error) {
+	switch compression {
+	case StateCompressionNone:
+		return fileChannelArgs(path), nil
+
+	case StateCompressionGzip, StateCompressionZstd:
+		return uriArgs(fmt.Sprintf("exec:%s -dc < %s", compression, shellQuote(path))), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+// fileChannelArgs returns migration arguments that use the file at the
+// specified path as the main channel. Unlike a "file:" URI, which QEMU splits
+// at ",offset=", the path is passed as-is, so it doesn't need to be escaped.
+func fileChannelArgs(path string) map[string]interface{} {
+	return map[string]interface{}{
+		"channels": []map[string]interface{}{
+			{
+				"channel-type": "main",
+				"addr": map[string]interface{}{
+					"transport": "file",
+					"filename":  path,
+					"offset":    0,
+				},
+			},
+		},
+	}
+}
+
+// stateFlags are the flags of options that affect the guest-visible state of
+// the VM, and therefore must match when the state is restored.
+var stateFlags = map[string]bool{
+	"machine": true,
+	"M":       true,
+	"cpu":     true,
+	"smp":     true,
+	"m":       true,
+	"device":  true,
+	"numa":    true,
+	"accel":   true,
+	"object":  true,
+}
+
+// hostProperties are properties that only affect the host side of a device,
+// so they can differ when the state is restored (e.g. the path to an overlay).
+var hostProperties = map[string]bool{
+	"file":     true,
+	"filename": true,
+	"path":     true,
+	"mem-path": true,
+	"dir":      true,
+}
+
+// stateOptions returns a normalized representation of the guest-visible
+// options.
+func stateOptions(options []*queso.Option) []string {
+	normalized := make([]string, 0)
+
+	for _, option := range options {
+		if !stateFlags[option.Flag] {
+			continue
+		}
+
+		parts := make([]string, 0)
+
+		if option.Name != "" {
+			parts = append(parts, option.Name)
+		}
+
+		for _, property := range option.Properties {
+			if !hostProperties[property.Key] {
+				parts = append(parts, property.Arg())
+			}
+		}
+
+		normalized = append(normalized, "-"+option.Flag+" "+strings.Join(parts, ","))
+	}
+
+	return normalized
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
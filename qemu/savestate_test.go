@@ -0,0 +1,136 @@
+package qemu
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/mikerourke/queso/qemu/network"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateOptions(t *testing.T) {
+	options := stateOptions([]*queso.Option{
+		Memory("1G"),
+		CPU("host"),
+		Name("vm1"),
+		network.UserBackend("n"),
+		device.Use("virtio-blk-pci",
+			device.NewProperty("drive", "disk0"),
+			device.NewProperty("serial", "disk-0001")),
+		device.Use("e1000",
+			device.NewProperty("netdev", "n"),
+			device.NewProperty("mac", "52:54:00:12:34:56")),
+		queso.NewOption("object", "memory-backend-file",
+			queso.NewProperty("id", "mem"),
+			queso.NewProperty("mem-path", "/dev/hugepages")),
+	})
+
+	assert.Equal(t, []string{
+		"-m 1G",
+		"-cpu host",
+		"-device virtio-blk-pci,drive=disk0,serial=disk-0001",
+		"-device e1000,netdev=n,mac=52:54:00:12:34:56",
+		"-object memory-backend-file,id=mem",
+	}, options)
+}
+
+func TestSavedStateCheckCompatible(t *testing.T) {
+	source := New("qemu-system-x86_64")
+	source.SetOptions(Memory("1G"), device.Use("e1000", device.NewProperty("mac", "52:54:00:12:34:56")))
+
+	state := &SavedState{Options: stateOptions(source.options)}
+
+	compatible := New("qemu-system-x86_64")
+	compatible.SetOptions(
+		Name("restored"),
+		Memory("1G"),
+		device.Use("e1000", device.NewProperty("mac", "52:54:00:12:34:56")))
+	assert.NoError(t, state.CheckCompatible(compatible))
+
+	incompatible := New("qemu-system-x86_64")
+	incompatible.SetOptions(Memory("2G"), device.Use("e1000", device.NewProperty("mac", "52:54:00:65:43:21")))
+	assert.EqualError(t, state.CheckCompatible(incompatible), "options are incompatible with the saved state: "+
+		"missing -m 1G, missing -device e1000,mac=52:54:00:12:34:56, "+
+		"unexpected -m 2G, unexpected -device e1000,mac=52:54:00:65:43:21")
+}
+
+func TestSaveStateArgs(t *testing.T) {
+	args, err := saveStateArgs("/tmp/vm,offset=1.state", StateCompressionNone)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"channels": []map[string]interface{}{
+			{
+				"channel-type": "main",
+				"addr": map[string]interface{}{
+					"transport": "file",
+					"filename":  "/tmp/vm,offset=1.state",
+					"offset":    0,
+				},
+			},
+		},
+	}, args)
+
+	args, err = saveStateArgs("/tmp/my vm.state", StateCompressionZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"uri": "exec:zstd -c > '/tmp/my vm.state'"}, args)
+
+	args, err = restoreStateArgs("/tmp/my vm.state", StateCompressionGzip)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"uri": "exec:gzip -dc < '/tmp/my vm.state'"}, args)
+
+	_, err = saveStateArgs("/tmp/vm.state", StateCompression("xz"))
+	assert.Error(t, err)
+}
+
+func TestStartIncomingKeepsOptions(t *testing.T) {
+	q := New("true")
+	q.SetOptions(Memory("1G"))
+
+	assert.NoError(t, q.startIncoming())
+	assert.NoError(t, q.Wait())
+
+	assert.Equal(t, []string{"-m", "1G"}, q.Args())
+	assert.Equal(t, []string{"true", "-m", "1G", "-S", "-incoming", "defer"}, q.cmd.Args)
+}
+
+func TestSaveStateThenRestoreState(t *testing.T) {
+	fake := qmptest.NewFakeQEMU(t)
+	assert.NoError(t, fake.Respond("migrate", map[string]interface{}{}))
+	assert.NoError(t, fake.Respond("migrate-incoming", map[string]interface{}{}))
+	assert.NoError(t, fake.Respond("query-migrate", map[string]interface{}{"status": "completed"}))
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "qmp.sock")
+
+	q := New(fake.Path)
+	q.SetEnv(fake.Env...)
+	q.SetQMPSocket(socket)
+	q.SetOptions(
+		Memory("1G"),
+		debug.HostRedirect(debug.RedirectSourceQMP, "unix:"+socket+",server=on,wait=off"))
+
+	// The Cmd created here lacks the options for the incoming migration.
+	q.Cmd()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.NoError(t, q.Start())
+
+	statePath := filepath.Join(dir, "vm.state")
+	assert.NoError(t, q.SaveState(ctx, statePath, SaveStateOptions{}))
+
+	assert.NoError(t, q.RestoreState(ctx, statePath, RestoreStateOptions{Paused: true}))
+
+	args, err := fake.Args()
+	assert.NoError(t, err)
+	assert.Equal(t, append(q.Args(), "-S", "-incoming", "defer"), args)
+
+	assert.NoError(t, q.Quit(ctx))
+}
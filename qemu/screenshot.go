@@ -0,0 +1,302 @@
+package qemu
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ScreenshotFormat represents the image format QEMU uses to write a
+// screenshot with screendump.
+type ScreenshotFormat string
+
+const (
+	// ScreenshotFormatPPM is the portable pixmap format, which is supported by
+	// all QEMU versions.
+	ScreenshotFormatPPM ScreenshotFormat = "ppm"
+
+	// ScreenshotFormatPNG is the PNG format, which requires QEMU 7.1 or newer.
+	ScreenshotFormatPNG ScreenshotFormat = "png"
+)
+
+// ScreenshotOptions represent the options for ScreenshotWithOptions.
+type ScreenshotOptions struct {
+	// Format is the image format QEMU writes the screenshot in. The default is
+	// ScreenshotFormatPPM.
+	Format ScreenshotFormat
+
+	// Device is the ID of the graphics device to capture. If omitted, the
+	// primary console is captured.
+	Device string
+
+	// Head is the head of the graphics Device to capture (for devices with
+	// multiple heads).
+	Head int
+}
+
+// Screenshot captures the primary console of the guest display in the specified
+// format (see screendump) and returns the decoded image. It works with any
+// display configured with the display or vnc packages, including headless
+// displays.
+func (q *QEMU) Screenshot(ctx context.Context, format ScreenshotFormat) (image.Image, error) {
+	return q.ScreenshotWithOptions(ctx, ScreenshotOptions{Format: format})
+}
+
+// ScreenshotWithOptions captures a console of the guest display with the
+// specified options and returns the decoded image.
+func (q *QEMU) ScreenshotWithOptions(ctx context.Context, opts ScreenshotOptions) (image.Image, error) {
+	client, err := q.ConnectQMP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = ScreenshotFormatPPM
+	}
+
+	// QEMU writes the screenshot to a file on the host, so it is placed in the
+	// runtime directory (if any) and removed once it is decoded.
+	dir := ""
+	if q.runtimeDir != nil {
+		dir = q.runtimeDir.Path()
+	}
+
+	file, err := os.CreateTemp(dir, "screenshot-*."+string(format))
+	if err != nil {
+		return nil, err
+	}
+
+	path, _ := filepath.Abs(file.Name())
+
+	_ = file.Close()
+	defer os.Remove(path)
+
+	args := map[string]interface{}{"filename": path}
+
+	if format != ScreenshotFormatPPM {
+		args["format"] = string(format)
+	}
+
+	if opts.Device != "" {
+		args["device"] = opts.Device
+		args["head"] = opts.Head
+	}
+
+	if err := client.Execute(ctx, "screendump", args, nil); err != nil {
+		return nil, fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+
+	file, err = os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	if format == ScreenshotFormatPNG {
+		return png.Decode(file)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePPM(file, info.Size())
+}
+
+// ScreenWaitOptions represent the options for WaitForScreen.
+type ScreenWaitOptions struct {
+	// ScreenshotOptions specify which console is captured.
+	ScreenshotOptions
+
+	// Interval is the time between screenshots. The default is 500ms.
+	Interval time.Duration
+
+	// Match returns true if the screenshot shows the expected screen. If
+	// specified, WaitForScreen returns once a screenshot matches.
+	Match func(img image.Image) bool
+
+	// StableFor is the duration for which the screen must not change. If
+	// specified (and Match is nil), WaitForScreen returns once the screen
+	// stops changing, which is useful to wait for a boot or an animation to
+	// finish.
+	StableFor time.Duration
+}
+
+// WaitForScreen takes screenshots of the guest display until the screen matches
+// (or stops changing) as specified in opts, and returns the last screenshot.
+//
+// Example
+//
+//	img, err := q.WaitForScreen(ctx, qemu.ScreenWaitOptions{
+//		StableFor: 5 * time.Second,
+//	})
+func (q *QEMU) WaitForScreen(ctx context.Context, opts ScreenWaitOptions) (image.Image, error) {
+	if opts.Match == nil && opts.StableFor == 0 {
+		return nil, errors.New("either Match or StableFor must be specified")
+	}
+
+	interval := opts.Interval
+	if interval == 0 {
+		interval = 500 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		previous image.Image
+		since    time.Time
+	)
+
+	for {
+		img, err := q.ScreenshotWithOptions(ctx, opts.ScreenshotOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.Match != nil {
+			if opts.Match(img) {
+				return img, nil
+			}
+		} else {
+			if previous == nil || !ImagesEqual(previous, img) {
+				previous = img
+				since = time.Now()
+			} else if time.Since(since) >= opts.StableFor {
+				return img, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return img, ctx.Err()
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// ImagesEqual returns true if the images have the same size and pixels.
+func ImagesEqual(a image.Image, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+
+	bounds := a.Bounds()
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := a.At(x, y).RGBA()
+			r2, g2, b2, a2 := b.At(x, y).RGBA()
+
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// decodePPM decodes a binary (P6) portable pixmap, which is the format QEMU
+// uses for screenshots by default. The size is the length of the data,
+// including the header.
+func decodePPM(r io.Reader, size int64) (image.Image, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]int, 0, 3)
+
+	magic, err := ppmToken(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if magic != "P6" {
+		return nil, fmt.Errorf("unsupported PPM format %q", magic)
+	}
+
+	for len(header) < 3 {
+		token, err := ppmToken(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PPM header: %w", err)
+		}
+
+		header = append(header, value)
+	}
+
+	width, height, maxValue := header[0], header[1], header[2]
+	if maxValue != 255 {
+		return nil, fmt.Errorf("unsupported PPM maximum value %d", maxValue)
+	}
+
+	// The dimensions are checked against the size of the data before the
+	// image is allocated, so a corrupt header can't allocate a huge image.
+	if width <= 0 || height <= 0 || int64(width) > size || int64(height) > size ||
+		int64(width)*int64(height)*3 > size {
+		return nil, fmt.Errorf("invalid PPM dimensions %dx%d for %d bytes of data", width, height, size)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	row := make([]byte, width*3)
+
+	for y := 0; y < height; y++ {
+		if _, err := io.ReadFull(reader, row); err != nil {
+			return nil, fmt.Errorf("truncated PPM data: %w", err)
+		}
+
+		pix := img.Pix[y*img.Stride : y*img.Stride+width*4]
+
+		for x := 0; x < width; x++ {
+			pix[x*4] = row[x*3]
+			pix[x*4+1] = row[x*3+1]
+			pix[x*4+2] = row[x*3+2]
+			pix[x*4+3] = 255
+		}
+	}
+
+	return img, nil
+}
+
+// ppmToken reads the next whitespace separated token of a PPM header, skipping
+// comments. The single whitespace character after the token is consumed.
+func ppmToken(reader *bufio.Reader) (string, error) {
+	token := make([]byte, 0, 8)
+
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", fmt.Errorf("invalid PPM header: %w", err)
+		}
+
+		switch {
+		case b == '#' && len(token) == 0:
+			if _, err := reader.ReadString('\n'); err != nil {
+				return "", fmt.Errorf("invalid PPM header: %w", err)
+			}
+
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(token) != 0 {
+				return string(token), nil
+			}
+
+		default:
+			token = append(token, b)
+		}
+	}
+}
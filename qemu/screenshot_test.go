@@ -0,0 +1,32 @@
+package qemu
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePPM(t *testing.T) {
+	data := []byte("P6\n# CREATOR: QEMU\n2 1\n255\n")
+	data = append(data, 255, 0, 0, 0, 0, 255)
+
+	img, err := decodePPM(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, img.Bounds().Dx())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, img.At(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, img.At(1, 0))
+
+	other, err := decodePPM(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.True(t, ImagesEqual(img, other))
+
+	_, err = decodePPM(bytes.NewReader(data[:len(data)-1]), int64(len(data)))
+	assert.Error(t, err)
+
+	huge := []byte("P6\n1000000000 1000000000\n255\n\x00\x00\x00")
+
+	_, err = decodePPM(bytes.NewReader(huge), int64(len(huge)))
+	assert.EqualError(t, err, "invalid PPM dimensions 1000000000x1000000000 for 32 bytes of data")
+}
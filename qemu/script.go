@@ -0,0 +1,153 @@
+package qemu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mikerourke/queso/diskimage"
+)
+
+// ScriptOptions represent the options used to export the QEMU invocation as a
+// shell script.
+type ScriptOptions struct {
+	// DiskImageCommands are the commands that prepared the disk images used by
+	// QEMU. They are run before QEMU is started. Pass a diskimage.History to
+	// the diskimage functions that prepare the images and use its Commands.
+	DiskImageCommands []diskimage.Command
+}
+
+// WriteScript writes a POSIX shell script that reproduces the QEMU invocation
+// to the specified writer. The script exports the environment variables set
+// with SetEnv, runs the DiskImageCommands, and then starts QEMU with the exact
+// same args. Every arg is quoted, and each option is preceded by a comment with
+// the name of the queso function that produced it (if queso.TrackBuilders was
+// enabled when the option was created). An error is returned if the name of an
+// environment variable can't be exported by the shell.
+//
+// Note that the debug.SetEnvVariable option sets OpenBIOS NVRAM variables
+// (passed to QEMU as -prom-env), not environment variables of the process.
+//
+// Example
+//
+//	q := qemu.New("qemu-system-x86_64")
+//	q.SetOptions(qemu.Memory("1G"), qemu.Name("my vm"))
+//	q.WriteScript(os.Stdout, qemu.ScriptOptions{})
+//
+// Output
+//
+//	#!/bin/sh
+//	set -eu
+//
+//	set --
+//
+//	# qemu.Memory
+//	set -- "$@" -m 1G
+//
+//	# qemu.Name
+//	set -- "$@" -name 'my vm'
+//
+//	exec qemu-system-x86_64 "$@"
+func (q *QEMU) WriteScript(w io.Writer, opts ScriptOptions) error {
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintln(buf, "#!/bin/sh")
+	fmt.Fprintln(buf, "set -eu")
+
+	if len(q.env) != 0 {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "# Environment variables (QEMU.SetEnv)")
+
+		for _, variable := range q.env {
+			name, value := variable, ""
+			if index := strings.Index(variable, "="); index != -1 {
+				name, value = variable[:index], variable[index+1:]
+			}
+
+			if !shellVariableName.MatchString(name) {
+				return fmt.Errorf("invalid environment variable name %q", name)
+			}
+
+			fmt.Fprintf(buf, "export %s=%s\n", name, shellQuote(value))
+		}
+	}
+
+	if len(opts.DiskImageCommands) != 0 {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "# Disk images (diskimage)")
+
+		for _, command := range opts.DiskImageCommands {
+			fmt.Fprintln(buf, shellJoin(append([]string{command.Tool}, command.Args...)))
+		}
+	}
+
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "set --")
+
+	if q.args != nil {
+		// An attached QEMU instance only knows its args, not the options that
+		// produced them.
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "set -- \"$@\" %s\n", shellJoin(q.args))
+	} else {
+		for _, option := range q.Options() {
+			fmt.Fprintln(buf)
+
+			if option.Builder != "" {
+				fmt.Fprintf(buf, "# %s\n", option.Builder)
+			}
+
+			fmt.Fprintf(buf, "set -- \"$@\" %s\n", shellJoin(option.Args()))
+		}
+	}
+
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "exec %s \"$@\"\n", shellQuote(q.exePath))
+
+	return buf.Flush()
+}
+
+// ExportScript writes the shell script produced by WriteScript to the specified
+// file and makes it executable.
+func (q *QEMU) ExportScript(file string, opts ScriptOptions) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create script: %w", err)
+	}
+
+	if err := q.WriteScript(f, opts); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+var (
+	safeShellArg      = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+	shellVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// shellQuote quotes the specified arg so it is interpreted literally by a POSIX
+// shell.
+func shellQuote(arg string) string {
+	if safeShellArg.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
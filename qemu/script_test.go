@@ -0,0 +1,66 @@
+package qemu
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/diskimage"
+	"github.com/mikerourke/queso/qemu/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteScript(t *testing.T) {
+	queso.TrackBuilders(true)
+	defer queso.TrackBuilders(false)
+
+	q := New("qemu-system-x86_64")
+	q.SetEnv("QEMU_AUDIO_DRV=none")
+	q.SetOptions(
+		Memory("1G"),
+		Name("Bob's VM"),
+		device.Use("e1000", device.NewProperty("netdev", "n")))
+
+	var buf bytes.Buffer
+
+	err := q.WriteScript(&buf, ScriptOptions{
+		DiskImageCommands: []diskimage.Command{
+			{Tool: "qemu-img", Args: []string{"create", "-f", "qcow2", "my disk.qcow2", "4G"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	expected := `#!/bin/sh
+set -eu
+
+# Environment variables (QEMU.SetEnv)
+export QEMU_AUDIO_DRV=none
+
+# Disk images (diskimage)
+qemu-img create -f qcow2 'my disk.qcow2' 4G
+
+set --
+
+# qemu.Memory
+set -- "$@" -m 1G
+
+# qemu.Name
+set -- "$@" -name 'Bob'\''s VM'
+
+# device.Use
+set -- "$@" -device e1000,netdev=n
+
+exec qemu-system-x86_64 "$@"
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteScriptInvalidEnv(t *testing.T) {
+	q := New("qemu-system-x86_64")
+	q.SetEnv("FOO;rm -rf /=bar")
+
+	var buf bytes.Buffer
+
+	assert.Error(t, q.WriteScript(&buf, ScriptOptions{}))
+}
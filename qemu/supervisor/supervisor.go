@@ -0,0 +1,830 @@
+// Package supervisor is used to manage the lifecycle of many named QEMU
+// instances concurrently, restarting them according to a RestartPolicy.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mikerourke/queso/internal/queue"
+	"github.com/mikerourke/queso/qemu"
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// RestartPolicy determines whether a VM is restarted after QEMU exits.
+type RestartPolicy string
+
+const (
+	// RestartNever never restarts the VM.
+	RestartNever RestartPolicy = "never"
+
+	// RestartOnFailure restarts the VM if QEMU fails to start or exits with an
+	// error. Restarts are delayed according to the Backoff of the Spec.
+	RestartOnFailure RestartPolicy = "on-failure"
+
+	// RestartAlways restarts the VM whenever QEMU exits, unless the VM was
+	// stopped with Supervisor.Stop.
+	RestartAlways RestartPolicy = "always"
+)
+
+// State represents the state of a VM managed by the Supervisor.
+type State string
+
+const (
+	// StateWaiting indicates that the VM is waiting for a boot slot, because
+	// the maximum number of concurrent boots was reached.
+	StateWaiting State = "waiting"
+
+	// StateStarting indicates that QEMU is being started and the VM hasn't
+	// passed the readiness check yet.
+	StateStarting State = "starting"
+
+	// StateRunning indicates that the VM is running.
+	StateRunning State = "running"
+
+	// StateShutdown indicates that the guest shut down, but QEMU is still
+	// running because the debug.NoShutdown option was used. VMs in this state
+	// are not restarted automatically. Use Supervisor.Restart to restart them.
+	StateShutdown State = "shutdown"
+
+	// StateBackoff indicates that QEMU exited and the VM will be restarted
+	// once the backoff delay has elapsed.
+	StateBackoff State = "backoff"
+
+	// StateExited indicates that QEMU exited successfully and the VM won't be
+	// restarted.
+	StateExited State = "exited"
+
+	// StateFailed indicates that QEMU failed and the VM won't be restarted.
+	StateFailed State = "failed"
+
+	// StateStopped indicates that the VM was stopped with Supervisor.Stop.
+	StateStopped State = "stopped"
+)
+
+// Backoff determines the delay before a failed VM is restarted. The delay
+// starts at Initial and is multiplied by Multiplier after each consecutive
+// failure, up to Max. The delay is reset once the VM runs for longer than Max.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultBackoff is the Backoff that is used if a Spec doesn't specify one.
+var DefaultBackoff = Backoff{
+	Initial:    time.Second,
+	Max:        time.Minute,
+	Multiplier: 2,
+}
+
+func (b Backoff) delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	delay := float64(b.Initial)
+	for i := 1; i < failures; i++ {
+		delay *= b.Multiplier
+	}
+
+	if max := float64(b.Max); b.Max > 0 && delay > max {
+		delay = max
+	}
+
+	return time.Duration(delay)
+}
+
+// Spec describes a VM managed by the Supervisor.
+type Spec struct {
+	// Name uniquely identifies the VM.
+	Name string
+
+	// New returns a new QEMU instance that hasn't been started yet. It is
+	// called every time the VM is (re)started. If the QEMU instance has a QMP
+	// socket (see qemu.QEMU.QMPSocket), the Supervisor connects to it to
+	// detect guest shutdowns and reboots.
+	New func() (*qemu.QEMU, error)
+
+	// Ready is an optional readiness check that is called after QEMU starts
+	// (and QMP is connected). The boot slot is held until Ready returns, so
+	// it can be used to wait for the guest to finish booting.
+	Ready func(ctx context.Context, q *qemu.QEMU) error
+
+	// Policy is the RestartPolicy of the VM. Defaults to RestartNever.
+	Policy RestartPolicy
+
+	// Backoff determines the delay before the VM is restarted after a failure.
+	// Defaults to DefaultBackoff.
+	Backoff *Backoff
+
+	// MaxRestarts is the maximum number of consecutive failed restarts before
+	// the VM is considered failed. A value of 0 means there is no limit.
+	MaxRestarts int
+
+	// BootTimeout is the maximum amount of time to wait for QMP to connect and
+	// for Ready to return. Defaults to 5 minutes.
+	BootTimeout time.Duration
+}
+
+// StateChange is sent to subscribers whenever the state of a VM changes.
+type StateChange struct {
+	// Name is the name of the VM.
+	Name string
+
+	// State is the new state of the VM.
+	State State
+
+	// Previous is the previous state of the VM.
+	Previous State
+
+	// Err is the error that caused the state change (if any).
+	Err error
+
+	// Restarts is the number of times the VM was restarted.
+	Restarts int
+
+	// Time is the time at which the state changed.
+	Time time.Time
+}
+
+// Options represent the options for a Supervisor.
+type Options struct {
+	// MaxConcurrentBoots is the maximum number of VMs that can boot at the same
+	// time. A value of 0 means there is no limit.
+	MaxConcurrentBoots int
+}
+
+// Supervisor manages the lifecycle of many named VMs concurrently.
+type Supervisor struct {
+	bootSlots chan struct{}
+
+	mu          sync.Mutex
+	vms         map[string]*vm
+	subscribers map[*subscriber]struct{}
+	wg          sync.WaitGroup
+}
+
+// New returns a new Supervisor with the specified options.
+func New(opts Options) *Supervisor {
+	s := &Supervisor{
+		vms:         make(map[string]*vm),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+
+	if opts.MaxConcurrentBoots > 0 {
+		s.bootSlots = make(chan struct{}, opts.MaxConcurrentBoots)
+	}
+
+	return s
+}
+
+// Add starts managing the VM described by the specified Spec. The VM is
+// started immediately (once a boot slot is available).
+func (s *Supervisor) Add(spec Spec) error {
+	if spec.Name == "" {
+		return errors.New("a name is required for the VM")
+	}
+
+	if spec.New == nil {
+		return fmt.Errorf("no New function specified for VM %s", spec.Name)
+	}
+
+	if spec.Policy == "" {
+		spec.Policy = RestartNever
+	}
+
+	if spec.Backoff == nil {
+		spec.Backoff = &DefaultBackoff
+	}
+
+	if spec.BootTimeout == 0 {
+		spec.BootTimeout = 5 * time.Minute
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vms[spec.Name]; ok {
+		return fmt.Errorf("VM %s already exists", spec.Name)
+	}
+
+	v := &vm{
+		spec:     spec,
+		commands: make(chan command),
+		done:     make(chan struct{}),
+	}
+
+	s.vms[spec.Name] = v
+	s.wg.Add(1)
+
+	go s.run(v)
+
+	return nil
+}
+
+// Names returns the names of all the VMs managed by the Supervisor.
+func (s *Supervisor) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.vms))
+	for name := range s.vms {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// State returns the current state of the VM with the specified name.
+func (s *Supervisor) State(name string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vms[name]
+	if !ok {
+		return "", false
+	}
+
+	return v.state, true
+}
+
+// QEMU returns the QEMU instance of the VM with the specified name, or nil if
+// the VM isn't running.
+func (s *Supervisor) QEMU(name string) *qemu.QEMU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.vms[name]; ok {
+		return v.q
+	}
+
+	return nil
+}
+
+// Stop stops the VM with the specified name and waits for QEMU to exit. The VM
+// remains known to the Supervisor (in StateStopped) until it is removed with
+// Remove. QEMU is asked to quit over QMP if possible and killed otherwise.
+func (s *Supervisor) Stop(ctx context.Context, name string) error {
+	return s.send(ctx, name, commandStop)
+}
+
+// Restart restarts the VM with the specified name immediately, regardless of
+// its RestartPolicy.
+func (s *Supervisor) Restart(ctx context.Context, name string) error {
+	return s.send(ctx, name, commandRestart)
+}
+
+// Remove stops the VM with the specified name (if it is running) and stops
+// managing it.
+func (s *Supervisor) Remove(ctx context.Context, name string) error {
+	return s.send(ctx, name, commandRemove)
+}
+
+// Shutdown stops and removes all the VMs managed by the Supervisor and waits
+// for them to exit.
+func (s *Supervisor) Shutdown(ctx context.Context) error {
+	var firstErr error
+
+	for _, name := range s.Names() {
+		if err := s.Remove(ctx, name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return firstErr
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Subscribe returns a channel that receives a StateChange whenever the state
+// of a VM changes. Notifications are queued, so a slow receiver doesn't block
+// the Supervisor. Call the returned function to stop receiving notifications.
+func (s *Supervisor) Subscribe() (<-chan StateChange, func()) {
+	sub := &subscriber{
+		out:   make(chan StateChange),
+		queue: queue.New(),
+	}
+
+	go func() {
+		defer close(sub.out)
+
+		sub.queue.Run(func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case sub.out <- value.(StateChange):
+				return true
+
+			case <-quit:
+				return false
+			}
+		})
+	}()
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+
+	return sub.out, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, sub)
+			s.mu.Unlock()
+
+			sub.queue.Cancel()
+		})
+	}
+}
+
+// subscriber queues state changes for a receiver, so the Supervisor never
+// blocks on a slow receiver.
+type subscriber struct {
+	out   chan StateChange
+	queue *queue.Queue
+}
+
+func (s *Supervisor) send(ctx context.Context, name string, kind commandKind) error {
+	s.mu.Lock()
+	v, ok := s.vms[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("VM %s does not exist", name)
+	}
+
+	cmd := command{kind: kind, done: make(chan struct{})}
+
+	select {
+	case v.commands <- cmd:
+
+	case <-v.done:
+		if kind == commandRestart {
+			return fmt.Errorf("VM %s is no longer managed", name)
+		}
+
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-cmd.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Supervisor) setState(v *vm, state State, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change := StateChange{
+		Name:     v.spec.Name,
+		State:    state,
+		Previous: v.state,
+		Err:      err,
+		Restarts: v.restarts,
+		Time:     time.Now(),
+	}
+
+	v.state = state
+
+	for sub := range s.subscribers {
+		sub.queue.Push(change)
+	}
+}
+
+func (s *Supervisor) setQEMU(v *vm, q *qemu.QEMU) {
+	s.mu.Lock()
+	v.q = q
+	s.mu.Unlock()
+}
+
+type commandKind int
+
+const (
+	commandStop commandKind = iota
+	commandRestart
+	commandRemove
+)
+
+type command struct {
+	kind commandKind
+	done chan struct{}
+}
+
+type vm struct {
+	spec     Spec
+	commands chan command
+	done     chan struct{}
+
+	// The following fields are protected by Supervisor.mu.
+	state    State
+	restarts int
+	q        *qemu.QEMU
+}
+
+// outcome describes how a single run of a VM ended.
+type outcome struct {
+	err        error
+	reboot     bool
+	pending    *command
+	runTime    time.Duration
+	notStarted bool
+}
+
+type action int
+
+const (
+	actionIdle action = iota
+	actionRestart
+	actionExit
+)
+
+func (s *Supervisor) run(v *vm) {
+	defer s.wg.Done()
+	defer close(v.done)
+
+	failures := 0
+
+	for {
+		result := s.runOnce(v)
+
+		next := actionIdle
+
+		if result.pending != nil {
+			next = s.handle(v, *result.pending)
+		} else if delay, restart := s.evaluate(v, result, &failures); restart {
+			s.setState(v, StateBackoff, result.err)
+
+			if cmd, ok := s.sleep(v, delay); ok {
+				next = s.handle(v, cmd)
+			} else {
+				s.incrementRestarts(v)
+				next = actionRestart
+			}
+		}
+
+		switch next {
+		case actionRestart:
+			continue
+
+		case actionExit:
+			return
+		}
+
+		if !s.idle(v) {
+			return
+		}
+
+		failures = 0
+	}
+}
+
+// evaluate determines whether the VM should be restarted after a run based on
+// its RestartPolicy. If the VM isn't restarted, it is moved to a final state.
+func (s *Supervisor) evaluate(v *vm, result outcome, failures *int) (time.Duration, bool) {
+	failed := result.err != nil
+
+	restart := false
+	switch v.spec.Policy {
+	case RestartAlways:
+		restart = true
+
+	case RestartOnFailure:
+		// A guest reboot with debug.NoReboot makes QEMU exit, which isn't a
+		// failure, but the guest still expects to come back up.
+		restart = failed || result.reboot
+	}
+
+	if failed {
+		if !result.notStarted && result.runTime > v.spec.Backoff.Max {
+			*failures = 0
+		}
+
+		*failures++
+
+		if v.spec.MaxRestarts > 0 && *failures > v.spec.MaxRestarts {
+			restart = false
+		}
+	} else {
+		*failures = 0
+	}
+
+	if !restart {
+		if failed {
+			s.setState(v, StateFailed, result.err)
+		} else {
+			s.setState(v, StateExited, nil)
+		}
+
+		return 0, false
+	}
+
+	// Even successful exits are delayed by the initial backoff, so a VM that
+	// exits right away doesn't restart in a tight loop.
+	if *failures == 0 {
+		return v.spec.Backoff.delay(1), true
+	}
+
+	return v.spec.Backoff.delay(*failures), true
+}
+
+// handle processes a command received while QEMU isn't running (or after it
+// was stopped) and returns what the VM should do next.
+func (s *Supervisor) handle(v *vm, cmd command) action {
+	defer close(cmd.done)
+
+	switch cmd.kind {
+	case commandRestart:
+		s.incrementRestarts(v)
+
+		return actionRestart
+
+	case commandRemove:
+		s.setState(v, StateStopped, nil)
+
+		s.mu.Lock()
+		delete(s.vms, v.spec.Name)
+		s.mu.Unlock()
+
+		return actionExit
+
+	default:
+		s.setState(v, StateStopped, nil)
+
+		return actionIdle
+	}
+}
+
+// idle handles commands for a VM that isn't running until it is restarted
+// (returns true) or removed (returns false).
+func (s *Supervisor) idle(v *vm) bool {
+	for cmd := range v.commands {
+		switch s.handle(v, cmd) {
+		case actionRestart:
+			return true
+
+		case actionExit:
+			return false
+		}
+	}
+
+	return false
+}
+
+// sleep waits for the specified delay, unless a command is received first.
+func (s *Supervisor) sleep(v *vm, delay time.Duration) (command, bool) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case cmd := <-v.commands:
+		return cmd, true
+
+	case <-timer.C:
+		return command{}, false
+	}
+}
+
+func (s *Supervisor) incrementRestarts(v *vm) {
+	s.mu.Lock()
+	v.restarts++
+	s.mu.Unlock()
+}
+
+func (s *Supervisor) acquireBootSlot(v *vm) (command, bool) {
+	if s.bootSlots == nil {
+		return command{}, false
+	}
+
+	select {
+	case s.bootSlots <- struct{}{}:
+		return command{}, false
+
+	default:
+	}
+
+	s.setState(v, StateWaiting, nil)
+
+	select {
+	case s.bootSlots <- struct{}{}:
+		return command{}, false
+
+	case cmd := <-v.commands:
+		return cmd, true
+	}
+}
+
+func (s *Supervisor) releaseBootSlot() {
+	if s.bootSlots != nil {
+		<-s.bootSlots
+	}
+}
+
+func (s *Supervisor) runOnce(v *vm) outcome {
+	if cmd, ok := s.acquireBootSlot(v); ok {
+		return outcome{pending: &cmd}
+	}
+
+	s.setState(v, StateStarting, nil)
+
+	q, proc, shutdowns, err := s.boot(v)
+
+	s.releaseBootSlot()
+
+	if err != nil {
+		return outcome{err: err, notStarted: true}
+	}
+
+	s.setQEMU(v, q)
+	defer s.setQEMU(v, nil)
+
+	s.setState(v, StateRunning, nil)
+
+	startTime := time.Now()
+
+	result := outcome{}
+	noShutdown := q.HasOption("no-shutdown")
+
+	for {
+		select {
+		case event, ok := <-shutdowns:
+			if !ok {
+				shutdowns = nil
+
+				continue
+			}
+
+			var data struct {
+				Guest  bool   `json:"guest"`
+				Reason string `json:"reason"`
+			}
+
+			_ = event.DecodeData(&data)
+
+			// With debug.NoReboot, QEMU exits on a reset requested by the
+			// guest or over QMP.
+			if data.Reason == "guest-reset" || data.Reason == "host-qmp-system-reset" {
+				result.reboot = true
+			}
+
+			if noShutdown && data.Guest && data.Reason == "guest-shutdown" {
+				s.setState(v, StateShutdown, nil)
+			}
+
+		case <-proc.exited:
+			result.err = proc.err
+			result.runTime = time.Since(startTime)
+
+			return result
+
+		case cmd := <-v.commands:
+			stopQEMU(q, proc)
+
+			result.pending = &cmd
+
+			return result
+		}
+	}
+}
+
+// process tracks the exit of a started QEMU instance.
+type process struct {
+	exited chan struct{}
+
+	// err is the error returned by qemu.QEMU.Wait, which is only set once
+	// exited is closed.
+	err error
+}
+
+func waitForExit(q *qemu.QEMU) *process {
+	proc := &process{exited: make(chan struct{})}
+
+	go func() {
+		proc.err = q.Wait()
+		close(proc.exited)
+	}()
+
+	return proc
+}
+
+// boot starts QEMU, connects to QMP (if possible) and waits for the VM to be
+// ready. The returned channel receives SHUTDOWN events if QMP is connected.
+// Booting is aborted as soon as QEMU exits, so a QEMU that fails right away
+// (e.g. because of an invalid option) doesn't hold a boot slot, and the error
+// returned by qemu.QEMU.Wait (such as a *qemu.Error) is returned.
+func (s *Supervisor) boot(v *vm) (*qemu.QEMU, *process, <-chan qmp.Event, error) {
+	q, err := v.spec.New()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := q.Start(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	proc := waitForExit(q)
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.spec.BootTimeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-proc.exited:
+			cancel()
+
+		case <-ctx.Done():
+		}
+	}()
+
+	var shutdowns <-chan qmp.Event
+
+	if q.QMPSocket() != "" {
+		client, err := q.ConnectQMP(ctx)
+		if err != nil {
+			return nil, nil, nil, abortBoot(q, proc, err)
+		}
+
+		shutdowns, _ = client.Subscribe("SHUTDOWN")
+	}
+
+	if v.spec.Ready != nil {
+		if err := v.spec.Ready(ctx, q); err != nil {
+			return nil, nil, nil, abortBoot(q, proc, fmt.Errorf("VM %s is not ready: %w", v.spec.Name, err))
+		}
+	}
+
+	return q, proc, shutdowns, nil
+}
+
+// abortBoot kills QEMU (if it is still running) and waits for it to exit. If
+// QEMU exited by itself, the error of the exit is returned instead of err.
+func abortBoot(q *qemu.QEMU, proc *process, err error) error {
+	_ = q.Kill()
+	<-proc.exited
+
+	if proc.err == nil {
+		return fmt.Errorf("qemu exited while booting: %w", err)
+	}
+
+	if killed(proc.err) {
+		return err
+	}
+
+	return proc.err
+}
+
+// killed returns true if the error returned by qemu.QEMU.Wait indicates that
+// QEMU was killed with SIGKILL (by Kill).
+func killed(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+
+	return ok && status.Signaled() && status.Signal() == syscall.SIGKILL
+}
+
+// stopQEMU asks QEMU to quit over QMP (or kills it if QMP isn't connected) and
+// waits for it to exit.
+func stopQEMU(q *qemu.QEMU, proc *process) {
+	if client := q.QMP(); client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := client.Execute(ctx, "quit", nil, nil)
+		cancel()
+
+		if err == nil || errors.Is(err, qmp.ErrClosed) {
+			select {
+			case <-proc.exited:
+				return
+
+			case <-time.After(30 * time.Second):
+			}
+		}
+	}
+
+	_ = q.Kill()
+	<-proc.exited
+}
@@ -0,0 +1,237 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso"
+	"github.com/mikerourke/queso/qemu"
+	"github.com/mikerourke/queso/qemu/debug"
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// shell returns a Spec function that runs the specified shell script in place
+// of QEMU.
+func shell(script string) func() (*qemu.QEMU, error) {
+	return func() (*qemu.QEMU, error) {
+		q := qemu.New("sh")
+		q.SetOptions(queso.NewOption("c", script))
+
+		return q, nil
+	}
+}
+
+func waitForState(t *testing.T, changes <-chan StateChange, name string, state State) StateChange {
+	t.Helper()
+
+	timeout := time.After(10 * time.Second)
+
+	for {
+		select {
+		case change := <-changes:
+			if change.Name == name && change.State == state {
+				return change
+			}
+
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s to be %s", name, state)
+		}
+	}
+}
+
+func TestSupervisorRestartOnFailure(t *testing.T) {
+	s := New(Options{MaxConcurrentBoots: 1})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	err := s.Add(Spec{
+		Name:        "failing",
+		New:         shell("exit 1"),
+		Policy:      RestartOnFailure,
+		Backoff:     &Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond, Multiplier: 2},
+		MaxRestarts: 2,
+	})
+	assert.NoError(t, err)
+
+	change := waitForState(t, changes, "failing", StateFailed)
+	assert.Equal(t, 2, change.Restarts)
+	assert.Error(t, change.Err)
+
+	assert.NoError(t, s.Shutdown(context.Background()))
+	assert.Empty(t, s.Names())
+}
+
+func TestSupervisorStop(t *testing.T) {
+	s := New(Options{})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	assert.NoError(t, s.Add(Spec{
+		Name:   "sleeping",
+		New:    shell("exec sleep 30"),
+		Policy: RestartAlways,
+	}))
+
+	waitForState(t, changes, "sleeping", StateRunning)
+
+	assert.NoError(t, s.Stop(context.Background(), "sleeping"))
+
+	state, ok := s.State("sleeping")
+	assert.True(t, ok)
+	assert.Equal(t, StateStopped, state)
+
+	assert.NoError(t, s.Restart(context.Background(), "sleeping"))
+	waitForState(t, changes, "sleeping", StateRunning)
+
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
+
+func TestMain(m *testing.M) {
+	qmptest.FakeQEMUMain()
+	os.Exit(m.Run())
+}
+
+// fake returns a Spec function that starts a qmptest.FakeQEMU with the
+// specified options and a RuntimeDirectory for its QMP socket.
+func fake(t *testing.T, options ...*queso.Option) func() (*qemu.QEMU, error) {
+	fakeQEMU := qmptest.NewFakeQEMU(t)
+	parent := t.TempDir()
+
+	return func() (*qemu.QEMU, error) {
+		dir, err := qemu.NewRuntimeDirectory(qemu.RuntimeDirectoryOptions{Parent: parent})
+		if err != nil {
+			return nil, err
+		}
+
+		q := qemu.New(fakeQEMU.Path)
+		q.SetRuntimeDirectory(dir)
+		q.SetEnv(fakeQEMU.Env...)
+		q.SetOptions(options...)
+
+		return q, nil
+	}
+}
+
+func TestSupervisorBootLimit(t *testing.T) {
+	s := New(Options{MaxConcurrentBoots: 1})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	release := make(chan struct{})
+
+	assert.NoError(t, s.Add(Spec{
+		Name: "first",
+		New:  shell("exec sleep 30"),
+		Ready: func(ctx context.Context, q *qemu.QEMU) error {
+			<-release
+
+			return nil
+		},
+	}))
+
+	waitForState(t, changes, "first", StateStarting)
+
+	assert.NoError(t, s.Add(Spec{Name: "second", New: shell("exec sleep 30")}))
+
+	waitForState(t, changes, "second", StateWaiting)
+
+	state, _ := s.State("first")
+	assert.Equal(t, StateStarting, state)
+
+	close(release)
+
+	waitForState(t, changes, "first", StateRunning)
+	waitForState(t, changes, "second", StateRunning)
+
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
+
+func TestSupervisorBootFailure(t *testing.T) {
+	s := New(Options{MaxConcurrentBoots: 1})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	start := time.Now()
+
+	assert.NoError(t, s.Add(Spec{
+		Name: "invalid",
+		New: func() (*qemu.QEMU, error) {
+			q := qemu.New("sh")
+			q.SetOptions(queso.NewOption("c", "echo 'sh: -foo: invalid option' >&2; exit 1"))
+			q.SetQMPSocket(filepath.Join(t.TempDir(), "qmp.sock"))
+
+			return q, nil
+		},
+	}))
+
+	change := waitForState(t, changes, "invalid", StateFailed)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	var qemuErr *qemu.Error
+	assert.True(t, errors.As(change.Err, &qemuErr))
+
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
+
+func TestSupervisorNoShutdown(t *testing.T) {
+	s := New(Options{})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	assert.NoError(t, s.Add(Spec{
+		Name:   "no-shutdown",
+		New:    fake(t, debug.NoShutdown()),
+		Policy: RestartAlways,
+	}))
+
+	waitForState(t, changes, "no-shutdown", StateRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.NoError(t, s.QEMU("no-shutdown").QMP().Execute(ctx, "system_powerdown", nil, nil))
+
+	waitForState(t, changes, "no-shutdown", StateShutdown)
+
+	assert.NoError(t, s.Shutdown(ctx))
+}
+
+func TestSupervisorNoReboot(t *testing.T) {
+	s := New(Options{})
+
+	changes, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	assert.NoError(t, s.Add(Spec{
+		Name:    "no-reboot",
+		New:     fake(t, debug.NoReboot()),
+		Policy:  RestartOnFailure,
+		Backoff: &Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond, Multiplier: 2},
+	}))
+
+	waitForState(t, changes, "no-reboot", StateRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.NoError(t, s.QEMU("no-reboot").QMP().Execute(ctx, "system_reset", nil, nil))
+
+	// QEMU exits successfully, but the VM is restarted, because the guest
+	// expects to come back up after a reboot.
+	waitForState(t, changes, "no-reboot", StateBackoff)
+
+	change := waitForState(t, changes, "no-reboot", StateRunning)
+	assert.Equal(t, 1, change.Restarts)
+
+	assert.NoError(t, s.Shutdown(ctx))
+}
@@ -0,0 +1,256 @@
+// Package throttle is used to change the I/O limits of the drives and throttle
+// groups of a running QEMU instance over QMP. The limits at startup are set
+// with the throttling properties of blockdev.Drive (such as
+// blockdev.WithBandwidthThrottling) or with object.ThrottleGroup.
+package throttle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// Limits represent the I/O limits of a drive or throttle group. A limit of
+// zero means the corresponding I/O is unlimited.
+type Limits struct {
+	// BPS is the total bandwidth limit in bytes per second.
+	BPS int64
+
+	// BPSRead is the read bandwidth limit in bytes per second.
+	BPSRead int64
+
+	// BPSWrite is the write bandwidth limit in bytes per second.
+	BPSWrite int64
+
+	// BPSMax is the total bandwidth burst in bytes per second.
+	BPSMax int64
+
+	// BPSReadMax is the read bandwidth burst in bytes per second.
+	BPSReadMax int64
+
+	// BPSWriteMax is the write bandwidth burst in bytes per second.
+	BPSWriteMax int64
+
+	// BPSMaxLength is the maximum length of a total bandwidth burst in seconds.
+	// Zero means the default of 1 second.
+	BPSMaxLength int64
+
+	// BPSReadMaxLength is the maximum length of a read bandwidth burst in
+	// seconds. Zero means the default of 1 second.
+	BPSReadMaxLength int64
+
+	// BPSWriteMaxLength is the maximum length of a write bandwidth burst in
+	// seconds. Zero means the default of 1 second.
+	BPSWriteMaxLength int64
+
+	// IOPS is the total request rate limit in requests per second.
+	IOPS int64
+
+	// IOPSRead is the read request rate limit in requests per second.
+	IOPSRead int64
+
+	// IOPSWrite is the write request rate limit in requests per second.
+	IOPSWrite int64
+
+	// IOPSMax is the total request rate burst in requests per second.
+	IOPSMax int64
+
+	// IOPSReadMax is the read request rate burst in requests per second.
+	IOPSReadMax int64
+
+	// IOPSWriteMax is the write request rate burst in requests per second.
+	IOPSWriteMax int64
+
+	// IOPSMaxLength is the maximum length of a total request rate burst in
+	// seconds. Zero means the default of 1 second.
+	IOPSMaxLength int64
+
+	// IOPSReadMaxLength is the maximum length of a read request rate burst in
+	// seconds. Zero means the default of 1 second.
+	IOPSReadMaxLength int64
+
+	// IOPSWriteMaxLength is the maximum length of a write request rate burst
+	// in seconds. Zero means the default of 1 second.
+	IOPSWriteMaxLength int64
+
+	// IOPSSize is the size of a request in bytes that counts as a new request
+	// for the request rate limits.
+	IOPSSize int64
+}
+
+// limitKey maps a field of Limits to the names of the corresponding arguments
+// of block_set_io_throttle (drive) and the limits of a throttle group (group).
+// The base limits are required by block_set_io_throttle. A zero limit is sent
+// to a throttle group as the zero value of the key, because QEMU rejects a
+// burst length of zero.
+type limitKey struct {
+	field    func(limits *Limits) *int64
+	drive    string
+	group    string
+	required bool
+	zero     int64
+}
+
+var limitKeys = []limitKey{
+	{func(l *Limits) *int64 { return &l.BPS }, "bps", "bps-total", true, 0},
+	{func(l *Limits) *int64 { return &l.BPSRead }, "bps_rd", "bps-read", true, 0},
+	{func(l *Limits) *int64 { return &l.BPSWrite }, "bps_wr", "bps-write", true, 0},
+	{func(l *Limits) *int64 { return &l.BPSMax }, "bps_max", "bps-total-max", false, 0},
+	{func(l *Limits) *int64 { return &l.BPSReadMax }, "bps_rd_max", "bps-read-max", false, 0},
+	{func(l *Limits) *int64 { return &l.BPSWriteMax }, "bps_wr_max", "bps-write-max", false, 0},
+	{func(l *Limits) *int64 { return &l.BPSMaxLength }, "bps_max_length", "bps-total-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.BPSReadMaxLength }, "bps_rd_max_length", "bps-read-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.BPSWriteMaxLength }, "bps_wr_max_length", "bps-write-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.IOPS }, "iops", "iops-total", true, 0},
+	{func(l *Limits) *int64 { return &l.IOPSRead }, "iops_rd", "iops-read", true, 0},
+	{func(l *Limits) *int64 { return &l.IOPSWrite }, "iops_wr", "iops-write", true, 0},
+	{func(l *Limits) *int64 { return &l.IOPSMax }, "iops_max", "iops-total-max", false, 0},
+	{func(l *Limits) *int64 { return &l.IOPSReadMax }, "iops_rd_max", "iops-read-max", false, 0},
+	{func(l *Limits) *int64 { return &l.IOPSWriteMax }, "iops_wr_max", "iops-write-max", false, 0},
+	{func(l *Limits) *int64 { return &l.IOPSMaxLength }, "iops_max_length", "iops-total-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.IOPSReadMaxLength }, "iops_rd_max_length", "iops-read-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.IOPSWriteMaxLength }, "iops_wr_max_length", "iops-write-max-length", false, 1},
+	{func(l *Limits) *int64 { return &l.IOPSSize }, "iops_size", "iops-size", false, 0},
+}
+
+// driveArgs returns the limits as arguments of block_set_io_throttle.
+func (l Limits) driveArgs() map[string]interface{} {
+	args := make(map[string]interface{})
+
+	for _, key := range limitKeys {
+		value := *key.field(&l)
+
+		if value != 0 || key.required {
+			args[key.drive] = value
+		}
+	}
+
+	return args
+}
+
+// groupArgs returns the limits as the "limits" property of a throttle group.
+// All limits are included, so limits that aren't set are removed (and burst
+// lengths are reset to their default).
+func (l Limits) groupArgs() map[string]interface{} {
+	args := make(map[string]interface{})
+
+	for _, key := range limitKeys {
+		value := *key.field(&l)
+		if value == 0 {
+			value = key.zero
+		}
+
+		args[key.group] = value
+	}
+
+	return args
+}
+
+// SetDriveLimits changes the I/O limits of the drive attached to the guest
+// device with the specified ID (see block_set_io_throttle). If group is not
+// empty, the drive is moved to the throttle group with that name (which is
+// created if it doesn't exist), and the limits apply to the whole group.
+func SetDriveLimits(ctx context.Context, client *qmp.Client, id string, group string, limits Limits) error {
+	args := limits.driveArgs()
+	args["id"] = id
+
+	if group != "" {
+		args["group"] = group
+	}
+
+	return client.Execute(ctx, "block_set_io_throttle", args, nil)
+}
+
+// DriveLimits returns the I/O limits of the drive attached to the guest device
+// with the specified ID, along with the name of the throttle group the drive
+// belongs to.
+func DriveLimits(ctx context.Context, client *qmp.Client, id string) (Limits, string, error) {
+	var devices []struct {
+		Device   string                 `json:"device"`
+		QDev     string                 `json:"qdev"`
+		Inserted map[string]interface{} `json:"inserted"`
+	}
+
+	if err := client.Execute(ctx, "query-block", nil, &devices); err != nil {
+		return Limits{}, "", err
+	}
+
+	for _, device := range devices {
+		if device.QDev != id && device.Device != id {
+			continue
+		}
+
+		limits := Limits{}
+
+		for _, key := range limitKeys {
+			if value, ok := device.Inserted[key.drive].(float64); ok {
+				*key.field(&limits) = int64(value)
+			}
+		}
+
+		group, _ := device.Inserted["group"].(string)
+
+		return limits, group, nil
+	}
+
+	return Limits{}, "", fmt.Errorf("device %s not found", id)
+}
+
+// AddGroup creates a throttle group with the specified ID and limits (see
+// object.ThrottleGroup). Block nodes can then be throttled by the group with
+// a blockdev.ThrottleDriver node.
+func AddGroup(ctx context.Context, client *qmp.Client, id string, limits Limits) error {
+	args := map[string]interface{}{
+		"qom-type": "throttle-group",
+		"id":       id,
+		"limits":   limits.groupArgs(),
+	}
+
+	return client.Execute(ctx, "object-add", args, nil)
+}
+
+// SetGroupLimits changes the limits of the throttle group with the specified
+// ID (see qom-set).
+func SetGroupLimits(ctx context.Context, client *qmp.Client, id string, limits Limits) error {
+	args := map[string]interface{}{
+		"path":     groupPath(id),
+		"property": "limits",
+		"value":    limits.groupArgs(),
+	}
+
+	return client.Execute(ctx, "qom-set", args, nil)
+}
+
+// GroupLimits returns the limits of the throttle group with the specified ID
+// (see qom-get).
+func GroupLimits(ctx context.Context, client *qmp.Client, id string) (Limits, error) {
+	args := map[string]interface{}{
+		"path":     groupPath(id),
+		"property": "limits",
+	}
+
+	values := make(map[string]int64)
+
+	if err := client.Execute(ctx, "qom-get", args, &values); err != nil {
+		return Limits{}, err
+	}
+
+	limits := Limits{}
+
+	for _, key := range limitKeys {
+		*key.field(&limits) = values[key.group]
+	}
+
+	return limits, nil
+}
+
+// RemoveGroup removes the throttle group with the specified ID. The group must
+// not be in use by any block node.
+func RemoveGroup(ctx context.Context, client *qmp.Client, id string) error {
+	return client.Execute(ctx, "object-del", map[string]interface{}{"id": id}, nil)
+}
+
+func groupPath(id string) string {
+	return "/objects/" + id
+}
@@ -0,0 +1,44 @@
+package throttle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitsArgs(t *testing.T) {
+	limits := Limits{
+		BPSRead:  10485760,
+		IOPS:     200,
+		IOPSMax:  300,
+		IOPSSize: 4096,
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"bps":       int64(0),
+		"bps_rd":    int64(10485760),
+		"bps_wr":    int64(0),
+		"iops":      int64(200),
+		"iops_rd":   int64(0),
+		"iops_wr":   int64(0),
+		"iops_max":  int64(300),
+		"iops_size": int64(4096),
+	}, limits.driveArgs())
+
+	group := limits.groupArgs()
+	assert.Len(t, group, len(limitKeys))
+	assert.Equal(t, int64(10485760), group["bps-read"])
+	assert.Equal(t, int64(300), group["iops-total-max"])
+	assert.Equal(t, int64(0), group["bps-total-max"])
+
+	// QEMU rejects burst lengths below 1 second.
+	for _, key := range []string{
+		"bps-total-max-length", "bps-read-max-length", "bps-write-max-length",
+		"iops-total-max-length", "iops-read-max-length", "iops-write-max-length",
+	} {
+		assert.Equal(t, int64(1), group[key], key)
+	}
+
+	limits.IOPSMaxLength = 10
+	assert.Equal(t, int64(10), limits.groupArgs()["iops-total-max-length"])
+}
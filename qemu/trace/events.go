@@ -0,0 +1,135 @@
+// Package trace reads the binary trace files written by the "simple" trace
+// backend of QEMU (see debug.Trace), and enables or disables trace events of a
+// running QEMU instance over QMP.
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ArgDef is an argument of a trace event definition.
+type ArgDef struct {
+	// Type is the C type of the argument, such as "uint64_t" or "const char *".
+	Type string
+
+	// Name is the name of the argument.
+	Name string
+}
+
+// IsString returns true if the argument is a string, which is recorded with
+// its length instead of as a 64-bit value.
+func (a ArgDef) IsString() bool {
+	t := strings.ReplaceAll(a.Type, " ", "")
+
+	return t == "constchar*" || t == "char*"
+}
+
+// EventDef is the definition of a trace event in a trace-events file.
+type EventDef struct {
+	// Name is the name of the event.
+	Name string
+
+	// Args are the arguments of the event.
+	Args []ArgDef
+
+	// Format is the printf-style format string of the event.
+	Format string
+
+	// Properties are the properties of the event, such as "disable".
+	Properties []string
+}
+
+// EventDefs maps the names of trace events to their definitions.
+type EventDefs map[string]*EventDef
+
+// ReadEventDefsFile reads the event definitions from a trace-events file, such
+// as the "trace-events-all" file installed with QEMU.
+func ReadEventDefsFile(path string) (EventDefs, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadEventDefs(file)
+}
+
+// ReadEventDefs reads the event definitions in the trace-events format. Each
+// definition has the form:
+//
+//	[properties] name(type1 arg1[, type2 arg2] ...) "format"
+func ReadEventDefs(r io.Reader) (EventDefs, error) {
+	defs := make(EventDefs)
+	scanner := bufio.NewScanner(r)
+	number := 0
+
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		def, err := parseEventDef(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		defs[def.Name] = def
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
+
+func parseEventDef(line string) (*EventDef, error) {
+	open := strings.Index(line, "(")
+	closing := strings.Index(line, ")")
+
+	if open == -1 || closing < open {
+		return nil, fmt.Errorf("invalid event definition %q", line)
+	}
+
+	words := strings.Fields(line[:open])
+	if len(words) == 0 {
+		return nil, fmt.Errorf("missing event name in %q", line)
+	}
+
+	def := &EventDef{
+		Name:       words[len(words)-1],
+		Properties: words[:len(words)-1],
+		Format:     strings.Trim(strings.TrimSpace(line[closing+1:]), `"`),
+	}
+
+	args := strings.TrimSpace(line[open+1 : closing])
+	if args == "" || args == "void" {
+		return def, nil
+	}
+
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+
+		// The name is the last identifier, and the type is everything before
+		// it (including any pointer stars).
+		split := strings.LastIndexAny(arg, " *")
+		if split == -1 {
+			return nil, fmt.Errorf("invalid argument %q of event %s", arg, def.Name)
+		}
+
+		def.Args = append(def.Args, ArgDef{
+			Type: strings.TrimSpace(arg[:split+1]),
+			Name: arg[split+1:],
+		})
+	}
+
+	return def, nil
+}
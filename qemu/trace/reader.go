@@ -0,0 +1,330 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Constants of the simpletrace binary format (version 4), as defined in
+// scripts/simpletrace.py of QEMU.
+const (
+	headerEventID  = 0xffffffffffffffff
+	headerMagic    = 0xf2b177cb0aa429b4
+	droppedEventID = 0xfffffffffffffffe
+
+	recordTypeMapping = 0
+	recordTypeEvent   = 1
+
+	// maxStringLength is the maximum length of a string in a record. QEMU
+	// truncates string arguments to 512 bytes and event names are short, so
+	// a longer string means the file is corrupt.
+	maxStringLength = 64 << 10
+
+	// FormatVersion is the version of the simpletrace format the Reader
+	// supports.
+	FormatVersion = 4
+)
+
+// DroppedEventName is the name of the Event that is recorded when QEMU drops
+// events because its trace buffer is full. Its only argument is
+// "dropped_events", the number of dropped events.
+const DroppedEventName = "dropped"
+
+// ErrInvalidHeader is returned when a file isn't a simpletrace file.
+var ErrInvalidHeader = errors.New("trace: not a simpletrace file")
+
+// Arg is an argument of a recorded Event.
+type Arg struct {
+	ArgDef
+
+	// Value is the value of the argument. It is a string for string arguments
+	// and an uint64 for all other arguments (see Int for signed values).
+	Value interface{}
+}
+
+// Uint returns the value of a numeric argument.
+func (a Arg) Uint() uint64 {
+	value, _ := a.Value.(uint64)
+
+	return value
+}
+
+// Int returns the value of a numeric argument as a signed integer. Signed
+// arguments narrower than 64 bits are sign-extended.
+func (a Arg) Int() int64 {
+	value := a.Uint()
+
+	switch a.Type {
+	case "int", "int32_t":
+		return int64(int32(value))
+	case "int16_t", "short":
+		return int64(int16(value))
+	case "int8_t":
+		return int64(int8(value))
+	}
+
+	return int64(value)
+}
+
+// String returns the value of a string argument, or the formatted value of a
+// numeric argument.
+func (a Arg) String() string {
+	if value, ok := a.Value.(string); ok {
+		return value
+	}
+
+	return fmt.Sprint(a.Value)
+}
+
+// Event is a trace event recorded by QEMU.
+type Event struct {
+	// Name is the name of the event.
+	Name string
+
+	// Timestamp is the time at which the event was recorded, relative to an
+	// arbitrary point in time (the monotonic clock of the host).
+	Timestamp time.Duration
+
+	// PID is the ID of the QEMU process that recorded the event.
+	PID uint32
+
+	// Args are the arguments of the event.
+	Args []Arg
+}
+
+// Arg returns the argument with the specified name.
+func (e Event) Arg(name string) (Arg, bool) {
+	for _, arg := range e.Args {
+		if arg.Name == name {
+			return arg, true
+		}
+	}
+
+	return Arg{}, false
+}
+
+// Reader reads the events of a simpletrace file, which QEMU writes when it is
+// built with the "simple" trace backend and tracing is enabled with debug.Trace.
+// Because the file only contains the values of the arguments, the definitions
+// of the events (see ReadEventDefs) are required to decode them.
+//
+// Example
+//
+//	defs, err := trace.ReadEventDefsFile("/usr/share/qemu/trace-events-all")
+//	reader, err := trace.NewReader(file, defs)
+//
+//	for {
+//		event, err := reader.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//	}
+type Reader struct {
+	r    *bufio.Reader
+	defs EventDefs
+	ids  map[uint64]string
+}
+
+// NewReader returns a Reader that reads the events from r, and reads the header
+// of the file.
+func NewReader(r io.Reader, defs EventDefs) (*Reader, error) {
+	reader := &Reader{
+		r:    bufio.NewReader(r),
+		defs: defs,
+		ids:  make(map[uint64]string),
+	}
+
+	var header struct {
+		EventID uint64
+		Magic   uint64
+		Version uint64
+	}
+
+	if err := binary.Read(reader.r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("trace: failed to read header: %w", err)
+	}
+
+	if header.EventID != headerEventID || header.Magic != headerMagic {
+		return nil, ErrInvalidHeader
+	}
+
+	if header.Version != FormatVersion {
+		return nil, fmt.Errorf("trace: unsupported simpletrace version %d", header.Version)
+	}
+
+	return reader, nil
+}
+
+// ReadFile reads all events of the simpletrace file at the specified path.
+func ReadFile(path string, defs EventDefs) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	reader, err := NewReader(file, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0)
+
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+
+		if err != nil {
+			return events, err
+		}
+
+		events = append(events, event)
+	}
+}
+
+// Next returns the next event. At the end of the file, it returns io.EOF. If
+// the file ends in the middle of a record (because QEMU was killed while
+// writing it), io.ErrUnexpectedEOF is returned.
+func (r *Reader) Next() (Event, error) {
+	for {
+		var recordType uint64
+
+		if err := binary.Read(r.r, binary.LittleEndian, &recordType); err != nil {
+			return Event{}, err
+		}
+
+		switch recordType {
+		case recordTypeMapping:
+			if err := r.readMapping(); err != nil {
+				return Event{}, err
+			}
+
+		case recordTypeEvent:
+			return r.readEvent()
+
+		default:
+			return Event{}, fmt.Errorf("trace: unknown record type %d", recordType)
+		}
+	}
+}
+
+// read reads a little-endian value of a record, which must not end the file.
+func (r *Reader) read(value interface{}) error {
+	err := binary.Read(r.r, binary.LittleEndian, value)
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+func (r *Reader) readString() (string, error) {
+	var length uint32
+
+	if err := r.read(&length); err != nil {
+		return "", err
+	}
+
+	if length > maxStringLength {
+		return "", fmt.Errorf("trace: string length %d exceeds %d bytes", length, maxStringLength)
+	}
+
+	buf := make([]byte, length)
+
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	return string(buf), nil
+}
+
+func (r *Reader) readMapping() error {
+	var id uint64
+
+	if err := r.read(&id); err != nil {
+		return err
+	}
+
+	name, err := r.readString()
+	if err != nil {
+		return err
+	}
+
+	r.ids[id] = name
+
+	return nil
+}
+
+func (r *Reader) readEvent() (Event, error) {
+	var header struct {
+		EventID   uint64
+		Timestamp uint64
+		Length    uint32
+		PID       uint32
+	}
+
+	if err := r.read(&header); err != nil {
+		return Event{}, err
+	}
+
+	event := Event{
+		Timestamp: time.Duration(header.Timestamp),
+		PID:       header.PID,
+	}
+
+	var def *EventDef
+
+	if header.EventID == droppedEventID {
+		event.Name = DroppedEventName
+		def = &EventDef{
+			Name: DroppedEventName,
+			Args: []ArgDef{{Type: "uint64_t", Name: "dropped_events"}},
+		}
+	} else {
+		name, ok := r.ids[header.EventID]
+		if !ok {
+			return Event{}, fmt.Errorf("trace: no mapping for event ID %d", header.EventID)
+		}
+
+		event.Name = name
+
+		if def, ok = r.defs[name]; !ok {
+			return Event{}, fmt.Errorf("trace: event %s is not defined", name)
+		}
+	}
+
+	event.Args = make([]Arg, 0, len(def.Args))
+
+	for _, argDef := range def.Args {
+		arg := Arg{ArgDef: argDef}
+
+		if argDef.IsString() {
+			value, err := r.readString()
+			if err != nil {
+				return Event{}, err
+			}
+
+			arg.Value = value
+		} else {
+			var value uint64
+
+			if err := r.read(&value); err != nil {
+				return Event{}, err
+			}
+
+			arg.Value = value
+		}
+
+		event.Args = append(event.Args, arg)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,61 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/mikerourke/queso/qemu/qmp"
+)
+
+// State is the state of a trace event in a running QEMU instance.
+type State string
+
+const (
+	// StateUnavailable means the event is disabled at compile time.
+	StateUnavailable State = "unavailable"
+
+	// StateDisabled means the event is disabled at runtime.
+	StateDisabled State = "disabled"
+
+	// StateEnabled means the event is enabled.
+	StateEnabled State = "enabled"
+)
+
+// EventState is the state of a trace event returned by EventStates.
+type EventState struct {
+	// Name is the name of the event.
+	Name string `json:"name"`
+
+	// State is the state of the event.
+	State State `json:"state"`
+}
+
+// SetEventState enables or disables the trace events matching the specified
+// name, which can be a globbing pattern (see trace-event-set-state). Events that
+// are disabled at compile time are ignored.
+//
+// Example
+//
+//	err := trace.SetEventState(ctx, q.QMP(), "qcow2_*", true)
+func SetEventState(ctx context.Context, client *qmp.Client, name string, enable bool) error {
+	return client.Execute(ctx, "trace-event-set-state", map[string]interface{}{
+		"name":               name,
+		"enable":             enable,
+		"ignore-unavailable": true,
+	}, nil)
+}
+
+// EventStates returns the states of the trace events matching the specified
+// name, which can be a globbing pattern (see trace-event-get-state).
+//
+// Example
+//
+//	states, err := trace.EventStates(ctx, q.QMP(), "qcow2_*")
+func EventStates(ctx context.Context, client *qmp.Client, name string) ([]EventState, error) {
+	states := make([]EventState, 0)
+
+	err := client.Execute(ctx, "trace-event-get-state", map[string]interface{}{
+		"name": name,
+	}, &states)
+
+	return states, err
+}
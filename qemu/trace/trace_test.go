@@ -0,0 +1,150 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mikerourke/queso/qemu/qmptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testEventDefs = `
+# qcow2.c
+qcow2_writev_start_req(void *co, int64_t offset, int bytes) "co %p offset 0x%" PRIx64 " bytes %d"
+disable vcpu guest_cpu_enter(void) "vcpu entered"
+qmp_enter_x_query(const char *name) "name %s"
+`
+
+type traceWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *traceWriter) write(values ...interface{}) {
+	for _, value := range values {
+		if text, ok := value.(string); ok {
+			_ = binary.Write(&w.buf, binary.LittleEndian, uint32(len(text)))
+			w.buf.WriteString(text)
+
+			continue
+		}
+
+		_ = binary.Write(&w.buf, binary.LittleEndian, value)
+	}
+}
+
+func TestReadEventDefs(t *testing.T) {
+	defs, err := ReadEventDefs(strings.NewReader(testEventDefs))
+	assert.NoError(t, err)
+	assert.Len(t, defs, 3)
+
+	assert.Equal(t, &EventDef{
+		Name: "qcow2_writev_start_req",
+		Args: []ArgDef{
+			{Type: "void *", Name: "co"},
+			{Type: "int64_t", Name: "offset"},
+			{Type: "int", Name: "bytes"},
+		},
+		Format:     `co %p offset 0x%" PRIx64 " bytes %d`,
+		Properties: []string{},
+	}, defs["qcow2_writev_start_req"])
+
+	assert.Equal(t, []string{"disable", "vcpu"}, defs["guest_cpu_enter"].Properties)
+	assert.Empty(t, defs["guest_cpu_enter"].Args)
+	assert.True(t, defs["qmp_enter_x_query"].Args[0].IsString())
+}
+
+func TestReader(t *testing.T) {
+	defs, err := ReadEventDefs(strings.NewReader(testEventDefs))
+	assert.NoError(t, err)
+
+	w := new(traceWriter)
+	w.write(uint64(headerEventID), uint64(headerMagic), uint64(FormatVersion))
+	w.write(uint64(recordTypeMapping), uint64(0), "qcow2_writev_start_req")
+	w.write(uint64(recordTypeEvent), uint64(0), uint64(1500), uint32(48), uint32(42),
+		uint64(0x1000), uint64(4096), uint64(0xffffffff))
+	w.write(uint64(recordTypeMapping), uint64(1), "qmp_enter_x_query")
+	w.write(uint64(recordTypeEvent), uint64(1), uint64(2000), uint32(35), uint32(42), "vm")
+	w.write(uint64(recordTypeEvent), uint64(droppedEventID), uint64(2500), uint32(32), uint32(42),
+		uint64(7))
+
+	reader, err := NewReader(&w.buf, defs)
+	assert.NoError(t, err)
+
+	event, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "qcow2_writev_start_req", event.Name)
+	assert.Equal(t, 1500*time.Nanosecond, event.Timestamp)
+	assert.Equal(t, uint32(42), event.PID)
+
+	offset, ok := event.Arg("offset")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4096), offset.Uint())
+
+	bytesArg, _ := event.Arg("bytes")
+	assert.Equal(t, int64(-1), bytesArg.Int())
+
+	event, err = reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "qmp_enter_x_query", event.Name)
+	assert.Equal(t, "vm", event.Args[0].String())
+
+	event, err = reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, DroppedEventName, event.Name)
+	assert.Equal(t, uint64(7), event.Args[0].Uint())
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = NewReader(strings.NewReader(strings.Repeat("x", 24)), defs)
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestReaderLongString(t *testing.T) {
+	w := new(traceWriter)
+	w.write(uint64(headerEventID), uint64(headerMagic), uint64(FormatVersion))
+	w.write(uint64(recordTypeMapping), uint64(0), uint32(0xffffffff))
+
+	reader, err := NewReader(&w.buf, map[string]*EventDef{})
+	assert.NoError(t, err)
+
+	_, err = reader.Next()
+	assert.EqualError(t, err, "trace: string length 4294967295 exceeds 65536 bytes")
+}
+
+func TestEventState(t *testing.T) {
+	server := qmptest.NewServer()
+	defer server.Close()
+
+	server.Respond("trace-event-set-state", struct{}{})
+	server.Respond("trace-event-get-state", []map[string]interface{}{
+		{"name": "qcow2_writev_start_req", "state": "enabled"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.Dial(ctx)
+	assert.NoError(t, err)
+
+	defer client.Close()
+
+	assert.NoError(t, SetEventState(ctx, client, "qcow2_*", true))
+
+	command, err := server.WaitForCommand(ctx, "trace-event-set-state")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":               "qcow2_*",
+		"enable":             true,
+		"ignore-unavailable": true,
+	}, command.Arguments)
+
+	states, err := EventStates(ctx, client, "qcow2_*")
+	assert.NoError(t, err)
+	assert.Equal(t, []EventState{{Name: "qcow2_writev_start_req", State: StateEnabled}}, states)
+}